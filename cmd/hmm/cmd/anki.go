@@ -1,20 +1,32 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"html"
+	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/f3rmion/hmm/internal/analyze"
 	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/cedict"
 	"github.com/f3rmion/hmm/internal/config"
-	"github.com/f3rmion/hmm/internal/decomp"
-	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
 	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/f3rmion/hmm/internal/tts"
+	"github.com/f3rmion/hmm/internal/xlsx"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // CharacterHMM holds HMM data for a single character.
@@ -22,6 +34,7 @@ type CharacterHMM struct {
 	Char       string   `json:"char"`
 	Pinyin     string   `json:"pinyin"`
 	Meaning    string   `json:"meaning,omitempty"`
+	Keyword    string   `json:"keyword,omitempty"`
 	Initial    string   `json:"initial"`
 	Final      string   `json:"final"`
 	Tone       int      `json:"tone"`
@@ -36,11 +49,13 @@ type CharacterHMM struct {
 
 // AugmentedNote holds the augmented data for a note.
 type AugmentedNote struct {
-	NoteID    int64             `json:"note_id"`
-	Character string            `json:"character"`
-	Original  map[string]string `json:"original_fields"`
-	HMM       []CharacterHMM    `json:"hmm"`
-	Prompt    string            `json:"prompt,omitempty"`
+	NoteID      int64             `json:"note_id"`
+	Character   string            `json:"character"`
+	Original    map[string]string `json:"original_fields"`
+	HMM         []CharacterHMM    `json:"hmm"`
+	Prompt      string            `json:"prompt,omitempty"`
+	WordPinyin  string            `json:"word_pinyin,omitempty"`
+	WordMeaning string            `json:"word_meaning,omitempty"`
 }
 
 var ankiCmd = &cobra.Command{
@@ -57,10 +72,16 @@ var ankiInspectCmd = &cobra.Command{
   - Note types (models) and their fields
   - Sample notes
 
-Example:
-  hmm anki inspect chinese.apkg`,
-	Args: cobra.ExactArgs(1),
-	RunE: runAnkiInspect,
+With --json, the same as a JSON object (counts, decks, note types with
+their fields, and sample notes with their fields as stored), for other
+tools to read.
+
+Examples:
+  hmm anki inspect chinese.apkg
+  hmm anki inspect chinese.apkg --json | jq '.models[].fields'`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeApkg,
+	RunE:              runAnkiInspect,
 }
 
 var ankiAugmentCmd = &cobra.Command{
@@ -72,22 +93,45 @@ This command:
 1. Reads the .apkg file
 2. Finds fields containing Chinese characters
 3. Generates HMM breakdown (actor, set, room, props)
-4. Outputs augmented data (JSON or CSV)
+4. Outputs augmented data (JSON, CSV, or an Excel workbook with one
+   sheet per deck)
+
+For decks of words rather than single characters, --words writes the
+word's pinyin and meaning and a table of its characters' breakdowns into
+one HMM_Breakdown field, instead of comma-joined lists of actors, sets,
+and props. Word readings and meanings come from CC-CEDICT: save
+cedict_ts.u8 from https://cc-cedict.org in the data directory. Without
+it, the pinyin is put together from the characters and the meaning is
+left out.
 
 Examples:
   hmm anki augment chinese.apkg
   hmm anki augment chinese.apkg --field "Hanzi"
-  hmm anki augment chinese.apkg --output augmented.json`,
-	Args: cobra.ExactArgs(1),
-	RunE: runAnkiAugment,
+  hmm anki augment chinese.apkg --output augmented.json
+  hmm anki augment chinese.apkg --format tsv --no-header
+  hmm anki augment chinese.apkg --format xlsx -o augmented.xlsx
+  hmm anki augment chinese.apkg --format apkg --with-audio
+  hmm anki augment vocab.apkg --format apkg --words`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeApkg,
+	PreRunE:           checkAnkiAugmentFlags,
+	RunE:              runAnkiAugment,
 }
 
 var (
 	ankiInspectLimit   int
+	ankiInspectJSON    bool
 	ankiAugmentField   string
 	ankiAugmentOutput  string
 	ankiAugmentFormat  string
 	ankiAugmentWritePkg bool
+	ankiAugmentAudio   bool
+	ankiAugmentVoice   string
+	ankiAugmentEngine  string
+	ankiAugmentSkip    bool
+	ankiAugmentNoHeader bool
+	ankiAugmentJobs    int
+	ankiAugmentWords   bool
 )
 
 func init() {
@@ -96,16 +140,47 @@ func init() {
 	ankiCmd.AddCommand(ankiAugmentCmd)
 
 	ankiInspectCmd.Flags().IntVarP(&ankiInspectLimit, "limit", "n", 5, "Number of sample notes to show")
+	ankiInspectCmd.Flags().BoolVar(&ankiInspectJSON, "json", false, "Print the deck structure as JSON")
 
 	ankiAugmentCmd.Flags().StringVarP(&ankiAugmentField, "field", "f", "", "Field name containing Chinese characters (auto-detect if not specified)")
 	ankiAugmentCmd.Flags().StringVarP(&ankiAugmentOutput, "output", "o", "", "Output file (stdout if not specified)")
-	ankiAugmentCmd.Flags().StringVarP(&ankiAugmentFormat, "format", "", "json", "Output format: json, csv, tsv, apkg")
+	ankiAugmentCmd.Flags().StringVarP(&ankiAugmentFormat, "format", "", "json", "Output format: json, csv, tsv, xlsx, apkg")
 	ankiAugmentCmd.Flags().BoolVar(&ankiAugmentWritePkg, "write-apkg", false, "Write augmented data back to a new .apkg file")
+	ankiAugmentCmd.Flags().BoolVar(&ankiAugmentAudio, "audio", false, "With apkg output, add a TTS pronunciation to each note in an HMM_Audio field, packaging the recordings (also --with-audio; see 'hmm tts')")
+	ankiAugmentCmd.Flags().StringVar(&ankiAugmentVoice, "voice", tts.DefaultVoice, "Voice for --audio")
+	ankiAugmentCmd.Flags().StringVar(&ankiAugmentEngine, "tts-engine", "", "TTS engine for --audio (first available if empty)")
+	ankiAugmentCmd.Flags().BoolVar(&ankiAugmentSkip, "skip-existing", false, "Leave out notes whose HMM fields are already filled")
+	ankiAugmentCmd.Flags().BoolVar(&ankiAugmentNoHeader, "no-header", false, "Leave out the header row of csv and tsv output")
+	ankiAugmentCmd.Flags().BoolVar(&ankiAugmentWords, "words", false, "Write word pinyin, meaning, and a table of the characters into one HMM_Breakdown field")
+	ankiAugmentCmd.Flags().IntVarP(&ankiAugmentJobs, "jobs", "j", runtime.NumCPU(), "Number of notes to augment at once")
+	ankiAugmentCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "with-audio" {
+			name = "audio"
+		}
+		return pflag.NormalizedName(name)
+	})
+	ankiAugmentCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"json", "csv", "tsv", "xlsx", "apkg"}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 func runAnkiInspect(cmd *cobra.Command, args []string) error {
 	path := args[0]
 
+	if ankiInspectJSON {
+		pkg, err := anki.OpenPackage(path)
+		if err != nil {
+			return fmt.Errorf("opening package: %w", err)
+		}
+		defer pkg.Close()
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(inspectPackage(pkg, ankiInspectLimit)); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		return nil
+	}
+
 	fmt.Printf("Opening: %s\n\n", path)
 
 	pkg, err := anki.OpenPackage(path)
@@ -120,7 +195,7 @@ func runAnkiInspect(cmd *cobra.Command, args []string) error {
 
 	// Show field details for each model
 	fmt.Println("Field Details:")
-	for _, model := range pkg.Models {
+	for _, model := range pkg.ModelList() {
 		fmt.Printf("  %s:\n", model.Name)
 		for _, field := range model.Fields {
 			fmt.Printf("    [%d] %s\n", field.Ord, field.Name)
@@ -149,13 +224,8 @@ func runAnkiInspect(cmd *cobra.Command, args []string) error {
 			if i < len(fieldNames) {
 				fieldName = fieldNames[i]
 			}
-			// Truncate long values
-			displayValue := value
-			if len(displayValue) > 100 {
-				displayValue = displayValue[:100] + "..."
-			}
-			// Strip HTML tags for display
-			displayValue = stripHTML(displayValue)
+			// Strip HTML tags, then truncate long values
+			displayValue := textutil.Truncate(htmlutil.Text(value), 100)
 			fmt.Printf("    %s: %s\n", fieldName, displayValue)
 		}
 		count++
@@ -164,6 +234,24 @@ func runAnkiInspect(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkAnkiAugmentFlags rejects flags that do not go together before the
+// deck is opened, which can take a while for a large one.
+func checkAnkiAugmentFlags(cmd *cobra.Command, args []string) error {
+	switch ankiAugmentFormat {
+	case "json", "csv", "tsv", "xlsx", "apkg":
+	default:
+		return fmt.Errorf("unknown format: %s", ankiAugmentFormat)
+	}
+	apkg := ankiAugmentFormat == "apkg" || ankiAugmentWritePkg
+	if ankiAugmentAudio && !apkg {
+		return fmt.Errorf("--audio needs --format apkg, as only decks can carry the recordings")
+	}
+	if ankiAugmentFormat == "xlsx" && !apkg && ankiAugmentOutput == "" {
+		return fmt.Errorf("--format xlsx needs an output file (-o results.xlsx)")
+	}
+	return nil
+}
+
 func runAnkiAugment(cmd *cobra.Command, args []string) error {
 	path := args[0]
 
@@ -181,7 +269,22 @@ func runAnkiAugment(cmd *cobra.Command, args []string) error {
 
 	// Create prompt generator
 	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
-	parser := pinyin.NewParser()
+
+	if ankiAugmentWords {
+		if err := loadWordDictionary(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not load CC-CEDICT: %v\n", err)
+		}
+		if wordDict == nil {
+			fmt.Fprintf(os.Stderr, "Note: no %s in the data directory, so word meanings are left out\n", cedict.FileName)
+		}
+	}
+
+	// Approved scenes take precedence over template prompts; drafts are
+	// not written until they have been reviewed
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 
 	// Open Anki package
 	pkg, err := anki.OpenPackage(path)
@@ -203,116 +306,11 @@ func runAnkiAugment(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Auto-detected Chinese field: %s\n", targetField)
 	}
 
-	// Process notes
-	var results []AugmentedNote
-
-	for _, note := range pkg.Notes {
-		chineseValue := pkg.GetFieldValue(note, targetField)
-		if chineseValue == "" {
-			continue
-		}
-
-		// Strip HTML
-		chineseValue = stripHTML(chineseValue)
-
-		// Extract Chinese characters
-		chars := extractChineseChars(chineseValue)
-		if len(chars) == 0 {
-			continue
-		}
-
-		augmented := AugmentedNote{
-			NoteID:    note.ID,
-			Character: chineseValue,
-			Original:  make(map[string]string),
-		}
-
-		// Store original fields
-		fieldNames := pkg.GetFieldNames(note)
-		for i, value := range note.Fields {
-			fieldName := fmt.Sprintf("field_%d", i)
-			if i < len(fieldNames) {
-				fieldName = fieldNames[i]
-			}
-			augmented.Original[fieldName] = stripHTML(value)
-		}
-
-		// Process each character
-		for _, char := range chars {
-			readings := parser.ParseChar(char)
-			if len(readings) == 0 {
-				continue
-			}
-
-			reading := readings[0] // Use first reading
-
-			// Get decomposition
-			var meaning string
-			var components []string
-			if dict != nil {
-				if entry := dict.Lookup(char); entry != nil {
-					meaning = entry.Definition
-					components = decomp.ExtractComponents(entry.Decomposition)
-				}
-			}
-
-			actorID := pinyin.GetActorID(reading.Initial)
-			setID := pinyin.GetSetID(reading.Final)
-
-			actor := gen.GetActor(actorID)
-			set := gen.GetSet(setID)
-
-			hmmData := CharacterHMM{
-				Char:       char,
-				Pinyin:     reading.Full,
-				Meaning:    meaning,
-				Initial:    reading.Initial,
-				Final:      reading.Final,
-				Tone:       int(reading.Tone),
-				ActorID:    actorID,
-				SetID:      setID,
-				ToneRoom:   gen.GetToneRoom(set, reading.Tone),
-				Components: components,
-			}
-
-			if actor != nil {
-				hmmData.ActorName = actor.Name
-			}
-			if set != nil {
-				hmmData.SetName = set.Name
-			}
-
-			// Get prop names
-			for _, comp := range components {
-				if p := gen.GetProp(comp); p != nil && p.Name != "" {
-					hmmData.Props = append(hmmData.Props, p.Name)
-				}
-			}
-
-			augmented.HMM = append(augmented.HMM, hmmData)
-		}
-
-		// Generate combined prompt if we have data
-		if len(augmented.HMM) > 0 && len(chars) == 1 {
-			// Single character - generate full prompt
-			hmmData := augmented.HMM[0]
-			sceneData := gen.BuildSceneData(
-				hmmData.Char,
-				hmmData.Pinyin,
-				hmmData.ActorID,
-				hmmData.SetID,
-				hmm.Tone(hmmData.Tone),
-				hmmData.Components,
-				hmmData.Meaning,
-				"",
-				"",
-			)
-			if p, err := gen.Generate(sceneData); err == nil {
-				augmented.Prompt = p
-			}
-		}
-
-		results = append(results, augmented)
+	results := augmentNotes(pkg, targetField, analyze.New(dict, gen), scenes)
+	if ankiAugmentSkip {
+		before := len(results)
+		results = withoutAugmented(pkg, results)
+		fmt.Fprintf(os.Stderr, "Skipped %d notes that already have HMM fields\n", before-len(results))
 	}
 
 	// Output results
@@ -330,6 +328,15 @@ func runAnkiAugment(cmd *cobra.Command, args []string) error {
 
 	// Handle apkg output format
 	if ankiAugmentFormat == "apkg" || ankiAugmentWritePkg {
+		if ankiAugmentAudio {
+			engine, err := tts.Get(ankiAugmentEngine)
+			if err != nil {
+				return err
+			}
+			if err := addAugmentedAudio(pkg, results, engine, ankiAugmentVoice); err != nil {
+				return err
+			}
+		}
 		return writeAugmentedApkg(pkg, results, gen, ankiAugmentOutput, path)
 	}
 
@@ -341,34 +348,12 @@ func runAnkiAugment(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("encoding JSON: %w", err)
 		}
 	case "csv", "tsv":
-		sep := ","
-		if ankiAugmentFormat == "tsv" {
-			sep = "\t"
-		}
-		// Header
-		fmt.Fprintf(output, "note_id%scharacter%spinyin%smeaning%sinitial%sfinal%stone%sactor_id%sactor_name%sset_id%sset_name%stone_room%scomponents%sprops%sprompt\n",
-			sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep, sep)
-		// Data
-		for _, r := range results {
-			for _, h := range r.HMM {
-				fmt.Fprintf(output, "%d%s%s%s%s%s%s%s%s%s%s%s%d%s%s%s%s%s%s%s%s%s%s%s%s%s%s\n",
-					r.NoteID, sep,
-					h.Char, sep,
-					h.Pinyin, sep,
-					h.Meaning, sep,
-					h.Initial, sep,
-					h.Final, sep,
-					h.Tone, sep,
-					h.ActorID, sep,
-					h.ActorName, sep,
-					h.SetID, sep,
-					h.SetName, sep,
-					h.ToneRoom, sep,
-					strings.Join(h.Components, ";"), sep,
-					strings.Join(h.Props, ";"), sep,
-					r.Prompt,
-				)
-			}
+		if err := writeAugmentCSV(output, results, ankiAugmentFormat == "tsv", !ankiAugmentNoHeader); err != nil {
+			return fmt.Errorf("writing %s: %w", strings.ToUpper(ankiAugmentFormat), err)
+		}
+	case "xlsx":
+		if err := augmentWorkbook(pkg, results).Write(output); err != nil {
+			return fmt.Errorf("writing workbook: %w", err)
 		}
 	default:
 		return fmt.Errorf("unknown format: %s", ankiAugmentFormat)
@@ -379,6 +364,246 @@ func runAnkiAugment(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// augmentHeader names the columns of tabular augment output.
+var augmentHeader = []string{
+	"note_id", "character", "pinyin", "meaning", "keyword", "initial", "final", "tone",
+	"actor_id", "actor_name", "set_id", "set_name", "tone_room",
+	"components", "props", "prompt",
+}
+
+// augmentRow returns the columns of one character of an augmented note.
+func augmentRow(r AugmentedNote, h CharacterHMM) []string {
+	return []string{
+		strconv.FormatInt(r.NoteID, 10), h.Char, h.Pinyin, h.Meaning, h.Keyword,
+		h.Initial, h.Final, strconv.Itoa(h.Tone),
+		h.ActorID, h.ActorName, h.SetID, h.SetName, h.ToneRoom,
+		strings.Join(h.Components, ";"), strings.Join(h.Props, ";"), r.Prompt,
+	}
+}
+
+// writeAugmentCSV writes one row per character of results to w, quoting
+// fields with separators, quotes, or line breaks. tsv separates fields with
+// tabs instead of commas.
+func writeAugmentCSV(w io.Writer, results []AugmentedNote, tsv, header bool) error {
+	cw := csv.NewWriter(w)
+	if tsv {
+		cw.Comma = '\t'
+	}
+	if header {
+		if err := cw.Write(augmentHeader); err != nil {
+			return err
+		}
+	}
+	for _, r := range results {
+		for _, h := range r.HMM {
+			if err := cw.Write(augmentRow(r, h)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// augmentWorkbook returns results as a workbook with one sheet per deck,
+// in the order the decks first appear.
+func augmentWorkbook(pkg *anki.Package, results []AugmentedNote) *xlsx.Workbook {
+	var decks []string
+	rows := make(map[string][][]string)
+	for _, r := range results {
+		deck := "Notes"
+		if note := pkg.GetNoteByID(r.NoteID); note != nil {
+			if d := pkg.GetNoteDeck(note); d != nil && d.Name != "" {
+				deck = d.Name
+			}
+		}
+		if _, ok := rows[deck]; !ok {
+			decks = append(decks, deck)
+		}
+		for _, h := range r.HMM {
+			rows[deck] = append(rows[deck], augmentRow(r, h))
+		}
+	}
+
+	wb := &xlsx.Workbook{}
+	for _, deck := range decks {
+		wb.AddSheet(deck, augmentHeader, rows[deck])
+	}
+	return wb
+}
+
+// augmentNotes returns the HMM data of every note of pkg with Chinese
+// characters in field, in deck order. Approved scenes in scenes, which may
+// be nil, provide the prompt before the template does. Notes are processed
+// by ankiAugmentJobs workers at once.
+func augmentNotes(pkg *anki.Package, field string, analyzer *analyze.Analyzer, scenes *scene.Store) []AugmentedNote {
+	workers := max(ankiAugmentJobs, 1)
+
+	// Each worker writes only its notes' slots, so the order is kept
+	augmented := make([]*AugmentedNote, len(pkg.Notes))
+	jobs := make(chan int)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				augmented[i] = augmentNote(pkg, pkg.Notes[i], field, analyzer, scenes)
+				done <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		for i := range pkg.Notes {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	progress := newProgressLine("Augmenting")
+	finished := 0
+	for range done {
+		finished++
+		progress.update(finished, len(pkg.Notes))
+	}
+	progress.clear()
+
+	var results []AugmentedNote
+	for _, a := range augmented {
+		if a != nil {
+			results = append(results, *a)
+		}
+	}
+	return results
+}
+
+// augmentNote returns the HMM data of note, or nil if field has no
+// Chinese characters.
+func augmentNote(pkg *anki.Package, note *anki.Note, field string, analyzer *analyze.Analyzer, scenes *scene.Store) *AugmentedNote {
+	chineseValue := pkg.GetFieldValue(note, field)
+	if chineseValue == "" {
+		return nil
+	}
+
+	// Strip HTML
+	chineseValue = htmlutil.Text(chineseValue)
+
+	// Extract Chinese characters
+	chars := analyze.ChineseChars(chineseValue)
+	if len(chars) == 0 {
+		return nil
+	}
+
+	augmented := &AugmentedNote{
+		NoteID:    note.ID,
+		Character: chineseValue,
+		Original:  make(map[string]string),
+	}
+
+	// Store original fields
+	fieldNames := pkg.GetFieldNames(note)
+	for i, value := range note.Fields {
+		fieldName := fmt.Sprintf("field_%d", i)
+		if i < len(fieldNames) {
+			fieldName = fieldNames[i]
+		}
+		augmented.Original[fieldName] = htmlutil.Text(value)
+	}
+
+	// Process each character
+	for _, char := range chars {
+		if hmmData, ok := characterHMM(char, analyzer); ok {
+			augmented.HMM = append(augmented.HMM, hmmData)
+		}
+	}
+
+	// Generate combined prompt if we have data
+	if len(augmented.HMM) > 0 && len(chars) == 1 {
+		// Single character - generate full prompt
+		augmented.Prompt = characterPrompt(chars[0], analyzer, scenes)
+	}
+
+	if ankiAugmentWords {
+		augmented.WordPinyin, augmented.WordMeaning = wordReading(strings.Join(chars, ""), augmented.HMM)
+	}
+
+	return augmented
+}
+
+// withoutAugmented returns results without the notes that already have
+// a value in one of the HMM fields, so they keep what they have.
+func withoutAugmented(pkg *anki.Package, results []AugmentedNote) []AugmentedNote {
+	var kept []AugmentedNote
+	for _, r := range results {
+		note := pkg.GetNoteByID(r.NoteID)
+		if note == nil || !hasHMMData(pkg, note) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// hasHMMData reports whether any HMM field of note has a value.
+func hasHMMData(pkg *anki.Package, note *anki.Note) bool {
+	if pkg.GetFieldValue(note, anki.BreakdownField) != "" {
+		return true
+	}
+	for _, field := range anki.HMMFields {
+		if pkg.GetFieldValue(note, field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// characterHMM returns the HMM breakdown of the first reading of char,
+// and false if char has no reading.
+func characterHMM(char string, analyzer *analyze.Analyzer) (CharacterHMM, bool) {
+	r := analyzer.Character(char)
+	if r == nil {
+		return CharacterHMM{}, false
+	}
+
+	return CharacterHMM{
+		Char:       char,
+		Pinyin:     r.Pinyin,
+		Meaning:    r.Meaning,
+		Keyword:    r.Keyword,
+		Initial:    r.Initial,
+		Final:      r.Final,
+		Tone:       int(r.Tone),
+		ActorID:    r.ActorID,
+		ActorName:  r.ActorName,
+		SetID:      r.SetID,
+		SetName:    r.SetName,
+		ToneRoom:   r.ToneRoom,
+		Components: r.Components,
+		Props:      r.PropNames,
+	}, true
+}
+
+// characterPrompt returns the image prompt of an approved scene for the
+// character, or else one generated from the template.
+func characterPrompt(char string, analyzer *analyze.Analyzer, scenes *scene.Store) string {
+	if sc := approvedScene(scenes, char); sc != nil && sc.ImagePrompt != "" {
+		return sc.ImagePrompt
+	}
+
+	r := analyzer.Character(char)
+	if r == nil {
+		return ""
+	}
+	p, err := analyzer.Prompt(*r)
+	if err != nil {
+		return ""
+	}
+	return p
+}
+
 // writeAugmentedApkg writes the augmented data back to a new .apkg file.
 func writeAugmentedApkg(pkg *anki.Package, results []AugmentedNote, gen *prompt.Generator, outputPath, inputPath string) error {
 	// Determine output path
@@ -389,28 +614,47 @@ func writeAugmentedApkg(pkg *anki.Package, results []AugmentedNote, gen *prompt.
 		outputPath = base + "_hmm" + ext
 	}
 
-	// Add HMM fields to all models that have notes with Chinese
-	modelsToUpdate := make(map[int64]bool)
+	// Add HMM fields to all models that have notes with Chinese, in ID
+	// order
+	seen := make(map[int64]bool)
+	var modelsToUpdate []int64
 	for _, r := range results {
 		note := pkg.GetNoteByID(r.NoteID)
-		if note != nil {
-			modelsToUpdate[note.ModelID] = true
+		if note != nil && !seen[note.ModelID] {
+			seen[note.ModelID] = true
+			modelsToUpdate = append(modelsToUpdate, note.ModelID)
 		}
 	}
+	sort.Slice(modelsToUpdate, func(i, j int) bool { return modelsToUpdate[i] < modelsToUpdate[j] })
 
-	for modelID := range modelsToUpdate {
-		if err := pkg.AddHMMFieldsToModel(modelID); err != nil {
+	fields := anki.HMMFields
+	if ankiAugmentWords {
+		fields = []string{anki.BreakdownField}
+	}
+	for _, modelID := range modelsToUpdate {
+		if err := pkg.AddFieldsToModel(modelID, fields...); err != nil {
 			return fmt.Errorf("adding HMM fields to model: %w", err)
 		}
 	}
 
 	// Update each note with HMM data
-	for _, r := range results {
+	progress := newProgressLine("Writing")
+	for i, r := range results {
+		progress.update(i, len(results))
+
 		note := pkg.GetNoteByID(r.NoteID)
 		if note == nil {
 			continue
 		}
 
+		if ankiAugmentWords {
+			if err := pkg.SetNoteField(note, anki.BreakdownField, breakdownHTML(r)); err != nil {
+				progress.clear()
+				fmt.Fprintf(os.Stderr, "Warning: could not set HMM data for note %d: %v\n", note.ID, err)
+			}
+			continue
+		}
+
 		// Combine HMM data for all characters in the note
 		var actors, sets, toneRooms, props []string
 		for _, h := range r.HMM {
@@ -435,10 +679,13 @@ func writeAugmentedApkg(pkg *anki.Package, results []AugmentedNote, gen *prompt.
 		}
 
 		if err := pkg.SetNoteHMMData(note, data); err != nil {
+			progress.clear()
 			fmt.Fprintf(os.Stderr, "Warning: could not set HMM data for note %d: %v\n", note.ID, err)
 		}
 	}
 
+	progress.clear()
+
 	// Save the augmented package
 	if err := pkg.SaveAs(outputPath); err != nil {
 		return fmt.Errorf("saving augmented package: %w", err)
@@ -447,13 +694,86 @@ func writeAugmentedApkg(pkg *anki.Package, results []AugmentedNote, gen *prompt.
 	fmt.Fprintf(os.Stderr, "Processed %d notes with Chinese characters\n", len(results))
 	fmt.Fprintf(os.Stderr, "Wrote augmented deck to: %s\n", outputPath)
 	fmt.Fprintf(os.Stderr, "\nNew fields added to notes:\n")
-	for _, field := range anki.HMMFields {
+	for _, field := range fields {
 		fmt.Fprintf(os.Stderr, "  - %s\n", field)
 	}
 
 	return nil
 }
 
+// wordReading returns the pinyin and meaning of word from CC-CEDICT. A
+// word CC-CEDICT does not have gets the readings of its characters, and
+// no meaning.
+func wordReading(word string, chars []CharacterHMM) (pinyinText, meaning string) {
+	if entries := wordDict.Lookup(word); len(entries) > 0 {
+		e := entries[0]
+		return pinyin.FromNumbers(e.Pinyin), strings.Join(e.Definitions, "; ")
+	}
+
+	readings := make([]string, len(chars))
+	for i, h := range chars {
+		readings[i] = h.Pinyin
+	}
+	return strings.Join(readings, " "), ""
+}
+
+// breakdownHTML returns the HMM_Breakdown field of r: the word with its
+// pinyin and meaning, then a table row per character.
+func breakdownHTML(r AugmentedNote) string {
+	var b strings.Builder
+	word := make([]string, len(r.HMM))
+	for i, h := range r.HMM {
+		word[i] = h.Char
+	}
+
+	b.WriteString(`<div class="hmm-word"><b>` + html.EscapeString(strings.Join(word, "")) + `</b>`)
+	if r.WordPinyin != "" {
+		b.WriteString(" " + html.EscapeString(r.WordPinyin))
+	}
+	if r.WordMeaning != "" {
+		b.WriteString(" – " + html.EscapeString(r.WordMeaning))
+	}
+	b.WriteString("</div>\n")
+
+	// The RTH keyword column is there only when a character has one
+	withKeywords := false
+	for _, h := range r.HMM {
+		withKeywords = withKeywords || h.Keyword != ""
+	}
+
+	b.WriteString(`<table class="hmm-breakdown">` + "\n")
+	b.WriteString("<tr><th>Character</th><th>Pinyin</th><th>Meaning</th>")
+	if withKeywords {
+		b.WriteString("<th>Keyword</th>")
+	}
+	b.WriteString("<th>Actor</th><th>Set</th><th>Room</th><th>Props</th></tr>\n")
+	for _, h := range r.HMM {
+		cells := []string{h.Char, h.Pinyin, h.Meaning}
+		if withKeywords {
+			cells = append(cells, h.Keyword)
+		}
+		cells = append(cells,
+			orID(h.ActorName, h.ActorID), orID(h.SetName, h.SetID), h.ToneRoom,
+			strings.Join(h.Props, ", "),
+		)
+		b.WriteString("<tr>")
+		for _, c := range cells {
+			b.WriteString("<td>" + html.EscapeString(c) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
+
+// orID returns name, or id if the user has not named it.
+func orID(name, id string) string {
+	if name != "" {
+		return name
+	}
+	return id
+}
+
 // unique removes duplicates from a string slice.
 func unique(s []string) []string {
 	seen := make(map[string]bool)
@@ -476,7 +796,7 @@ func detectChineseField(pkg *anki.Package) string {
 		}
 		fieldNames := pkg.GetFieldNames(note)
 		for j, value := range note.Fields {
-			if containsChinese(value) {
+			if analyze.ContainsChinese(value) {
 				if j < len(fieldNames) {
 					return fieldNames[j]
 				}
@@ -486,30 +806,3 @@ func detectChineseField(pkg *anki.Package) string {
 	}
 	return ""
 }
-
-// containsChinese checks if a string contains Chinese characters.
-func containsChinese(s string) bool {
-	for _, r := range s {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			return true
-		}
-	}
-	return false
-}
-
-// extractChineseChars extracts all Chinese characters from a string.
-func extractChineseChars(s string) []string {
-	var chars []string
-	for _, r := range s {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			chars = append(chars, string(r))
-		}
-	}
-	return chars
-}
-
-// stripHTML removes HTML tags from a string.
-func stripHTML(s string) string {
-	re := regexp.MustCompile(`<[^>]*>`)
-	return strings.TrimSpace(re.ReplaceAllString(s, ""))
-}
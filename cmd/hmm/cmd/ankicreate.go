@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tts"
+	"github.com/spf13/cobra"
+)
+
+// createNoteType is the note type of decks built by anki create.
+const createNoteType = "HMM Word"
+
+// createFields are the fields of createNoteType.
+var createFields = []string{
+	"Word",
+	"Pinyin",
+	"Meaning",
+	"Keyword",
+	"Decomposition",
+	"Actor",
+	"Set",
+	"ToneRoom",
+	"Props",
+	"Story",
+	"ImagePrompt",
+	"Image",
+	"Audio",
+}
+
+var ankiCreateCmd = &cobra.Command{
+	Use:   "create <wordlist or list name>",
+	Short: "Build an HMM study deck from a list of characters or words",
+	Long: `Build a new Anki deck from a word list, with the HMM breakdown of every
+entry: pinyin, meaning, decomposition, actor, set, room, props, and the
+image prompt (or story and prompt of an approved scene).
+
+The list can be:
+  - plain text, one or more words per line (lines starting with # are skipped)
+  - CSV or TSV (.csv/.tsv) with the word in the first column, then
+    optionally pinyin and meaning (word,meaning works too)
+  - the name of a word list, built in or your own (see 'hmm list')
+
+Missing pinyin and meanings come from the dictionary. Notes are tagged
+"hmm", and HSK1-HSK9 when the word is on the hsk<N> list (see 'hmm list'). With --images, images of approved scenes are included; with
+--audio, a TTS pronunciation (see 'hmm tts').
+
+Building the deck again after editing scenes updates the same notes on
+import.
+
+Examples:
+  hmm anki create hsk1.txt
+  hmm anki create hsk2         # A word list by name
+  hmm anki create words.csv --name "Week 3" -o week3.apkg --images --audio`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAnkiCreate,
+}
+
+var (
+	ankiCreateOut    string
+	ankiCreateName   string
+	ankiCreateImages bool
+	ankiCreateAudio  bool
+	ankiCreateVoice  string
+	ankiCreateEngine string
+)
+
+func init() {
+	ankiCmd.AddCommand(ankiCreateCmd)
+
+	ankiCreateCmd.Flags().StringVarP(&ankiCreateOut, "out", "o", "", "Output .apkg (default: <wordlist>.apkg)")
+	ankiCreateCmd.Flags().StringVar(&ankiCreateName, "name", "", "Deck name (default: word list file name)")
+	ankiCreateCmd.Flags().BoolVar(&ankiCreateImages, "images", false, "Include the images of approved scenes")
+	ankiCreateCmd.Flags().BoolVar(&ankiCreateAudio, "audio", false, "Include a TTS pronunciation of each word")
+	ankiCreateCmd.Flags().StringVar(&ankiCreateVoice, "voice", tts.DefaultVoice, "Voice for --audio")
+	ankiCreateCmd.Flags().StringVar(&ankiCreateEngine, "tts-engine", "", "TTS engine for --audio (first available if empty)")
+}
+
+// wordEntry is a line of a word list.
+type wordEntry struct {
+	Word    string
+	Pinyin  string   // From the list; "" to look it up
+	Meaning string   // From the list; "" to look it up
+	Tags    []string // Extra tags for its note
+}
+
+func runAnkiCreate(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	var entries []wordEntry
+	var err error
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if _, statErr := os.Stat(path); statErr == nil {
+		entries, err = readWordList(path)
+	} else {
+		// Not a file: a word list by name
+		var list scene.TargetList
+		if list, err = scene.FindTargetList(listsDir(), path); err == nil {
+			entries, err = parseWordList(strings.NewReader(list.Text), ".txt")
+			base = list.Name
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no Chinese words found in %s", path)
+	}
+
+	name := ankiCreateName
+	if name == "" {
+		name = base
+	}
+	out := ankiCreateOut
+	if out == "" {
+		out = base + ".apkg"
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	return writeWordDeck(entries, name, out, wordDeckOptions{
+		Images: ankiCreateImages,
+		Audio:  ankiCreateAudio,
+		Voice:  ankiCreateVoice,
+		Engine: ankiCreateEngine,
+	})
+}
+
+// wordDeckOptions are the extras of a deck built from a word list.
+type wordDeckOptions struct {
+	Images bool   // Include the images of approved scenes
+	Audio  bool   // Include TTS pronunciations
+	Voice  string // Voice for Audio
+	Engine string // TTS engine for Audio, "" for the first available
+}
+
+// writeWordDeck builds a deck of createNoteType notes called name from
+// entries and writes it to out. The dictionary should be loaded.
+func writeWordDeck(entries []wordEntry, name, out string, opts wordDeckOptions) error {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	analyzer := analyze.New(dict, prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props))
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	var engine tts.Engine
+	var audioDir string
+	if opts.Audio {
+		if engine, err = tts.Get(opts.Engine); err != nil {
+			return err
+		}
+		if audioDir, err = audioCacheDir(); err != nil {
+			return err
+		}
+	}
+
+	levels := hskLevels(listsDir())
+
+	builder, err := anki.NewDeckBuilder(name, createNoteType, createFields)
+	if err != nil {
+		return err
+	}
+	images := 0
+
+	progress := newProgressLine("Creating")
+	for i, entry := range entries {
+		progress.update(i, len(entries))
+
+		fields, hasImage := wordFields(entry, builder, analyzer, scenes, opts.Images)
+		if hasImage {
+			images++
+		}
+
+		if engine != nil {
+			audioPath, _, err := tts.Speak(engine, entry.Word, opts.Voice, audioDir, false)
+			if err != nil {
+				progress.clear()
+				return err
+			}
+			media := "hmm-" + tts.FileName(entry.Word)
+			builder.AddMedia(media, audioPath)
+			fields[len(fields)-1] = "[sound:" + media + "]"
+		}
+
+		tags := append([]string{"hmm"}, entry.Tags...)
+		if level, ok := levels[entry.Word]; ok {
+			tags = append(tags, fmt.Sprintf("HSK%d", level))
+		}
+
+		if err := builder.AddNote(anki.StableGUID("hmm-word:"+entry.Word), fields, tags...); err != nil {
+			progress.clear()
+			return err
+		}
+	}
+	progress.clear()
+
+	if err := builder.Write(out); err != nil {
+		return fmt.Errorf("writing deck: %w", err)
+	}
+
+	fmt.Printf("Wrote %d notes to %s", builder.Len(), out)
+	if opts.Images {
+		fmt.Printf(" (%d with scene images)", images)
+	}
+	fmt.Println()
+	return nil
+}
+
+// wordFields returns the values of createFields for entry, adding the
+// scene images of its characters to b if withImages is set. The Audio
+// field is left empty. It also reports whether an image was added.
+func wordFields(entry wordEntry, b *anki.DeckBuilder, analyzer *analyze.Analyzer, scenes *scene.Store, withImages bool) ([]string, bool) {
+	chars := analyze.UniqueChineseChars(entry.Word)
+	single := len(chars) == 1
+
+	var readings, meanings, keywords, decomps, stories, prompts, images []string
+	var actors, sets, rooms, props []string
+	for _, char := range chars {
+		// Prefix every line with its character when a word has several
+		label := func(s string) string {
+			if single || s == "" {
+				return s
+			}
+			return char + ": " + s
+		}
+
+		h, ok := characterHMM(char, analyzer)
+		if !ok {
+			continue
+		}
+		if h.Meaning != "" {
+			meanings = append(meanings, label(h.Meaning))
+		}
+		if h.Keyword != "" {
+			keywords = append(keywords, label(h.Keyword))
+		}
+		if dict != nil {
+			if e := dict.Lookup(char); e != nil && e.Decomposition != "？" {
+				decomps = append(decomps, label(decomp.FormatDecomposition(e.Decomposition)))
+			}
+		}
+		actors = append(actors, h.ActorName)
+		sets = append(sets, h.SetName)
+		rooms = append(rooms, h.ToneRoom)
+		props = append(props, h.Props...)
+
+		sc := approvedScene(scenes, char)
+		if sc != nil && sc.Script != "" {
+			stories = append(stories, label(sc.Script))
+		}
+		if p := characterPrompt(char, analyzer, scenes); p != "" {
+			prompts = append(prompts, label(p))
+		}
+		if withImages && sc != nil && sc.Image != "" {
+			path := scenes.ImagePath(*sc)
+			if _, err := os.Stat(path); err == nil {
+				media := "hmm-" + char + filepath.Ext(path)
+				b.AddMedia(media, path)
+				images = append(images, fmt.Sprintf(`<img src="%s">`, media))
+			}
+		}
+	}
+
+	pinyinText := entry.Pinyin
+	if pinyinText == "" {
+		// Every character is read, repeated ones too (谢谢)
+		for _, r := range entry.Word {
+			if h, ok := characterHMM(string(r), analyzer); ok {
+				readings = append(readings, h.Pinyin)
+			}
+		}
+		pinyinText = strings.Join(readings, " ")
+	}
+	meaning := entry.Meaning
+	if meaning == "" {
+		meaning = strings.Join(meanings, "<br>")
+	}
+
+	fields := []string{
+		entry.Word,
+		pinyinText,
+		meaning,
+		strings.Join(keywords, "<br>"),
+		strings.Join(decomps, "<br>"),
+		strings.Join(unique(actors), ", "),
+		strings.Join(unique(sets), ", "),
+		strings.Join(unique(rooms), ", "),
+		strings.Join(unique(props), ", "),
+		strings.Join(stories, "<br><br>"),
+		strings.Join(prompts, "<br><br>"),
+		strings.Join(images, ""),
+		"",
+	}
+	return fields, len(images) > 0
+}
+
+// readWordList reads the entries of a word list, in order and without
+// duplicates. CSV and TSV files give one entry per row; other files are
+// read as text, where every run of Chinese characters is an entry.
+func readWordList(path string) ([]wordEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading word list: %w", err)
+	}
+	defer f.Close()
+
+	return parseWordList(f, filepath.Ext(path))
+}
+
+// parseWordList reads the entries of a word list from r, as CSV or TSV
+// for those extensions and as text otherwise.
+func parseWordList(r io.Reader, ext string) ([]wordEntry, error) {
+	var entries []wordEntry
+	seen := make(map[string]bool)
+	add := func(e wordEntry) {
+		if e.Word != "" && !seen[e.Word] {
+			seen[e.Word] = true
+			entries = append(entries, e)
+		}
+	}
+
+	switch strings.ToLower(ext) {
+	case ".csv", ".tsv":
+		cr := csv.NewReader(r)
+		if strings.EqualFold(ext, ".tsv") {
+			cr.Comma = '\t'
+		}
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		cr.Comment = '#'
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("reading word list: %w", err)
+			}
+			add(rowEntry(row))
+		}
+	default:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading word list: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			for _, word := range analyze.ChineseRuns(line) {
+				add(wordEntry{Word: word})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// rowEntry reads a CSV row: the word, then pinyin and meaning, or only
+// the meaning. Rows without Chinese in the first column, such as a
+// header, give an empty entry.
+func rowEntry(row []string) wordEntry {
+	if len(row) == 0 {
+		return wordEntry{}
+	}
+	words := analyze.ChineseRuns(row[0])
+	if len(words) == 0 {
+		return wordEntry{}
+	}
+
+	e := wordEntry{Word: strings.Join(words, "")}
+	rest := row[1:]
+	for i := range rest {
+		rest[i] = strings.TrimSpace(rest[i])
+	}
+	switch {
+	case len(rest) >= 2:
+		e.Pinyin, e.Meaning = rest[0], rest[1]
+	case len(rest) == 1:
+		e.Meaning = rest[0]
+	}
+	return e
+}
+
+// hskLevels maps the words on the hsk<N> lists, of the user's own in dir
+// or built in, to the lowest level N they are on. A word's characters
+// count as on its level too.
+func hskLevels(dir string) map[string]int {
+	levels := make(map[string]int)
+	set := func(word string, level int) {
+		if l, ok := levels[word]; !ok || level < l {
+			levels[word] = level
+		}
+	}
+
+	lists, _ := scene.AllTargetLists(dir)
+	for _, list := range lists {
+		var level int
+		if _, err := fmt.Sscanf(list.Name, "hsk%d", &level); err != nil {
+			continue
+		}
+		for _, word := range list.Words() {
+			set(word, level)
+			for _, char := range word {
+				set(string(char), level)
+			}
+		}
+	}
+	return levels
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/f3rmion/hmm/internal/anki"
+)
+
+// InspectedPackage is the structure of a deck as printed by anki inspect
+// --json.
+type InspectedPackage struct {
+	Path    string            `json:"path"`
+	Counts  InspectedCounts   `json:"counts"`
+	Decks   []InspectedDeck   `json:"decks"`
+	Models  []InspectedModel  `json:"models"`
+	Samples []InspectedSample `json:"sample_notes"`
+}
+
+// InspectedCounts are the sizes of a deck.
+type InspectedCounts struct {
+	Decks  int `json:"decks"`
+	Models int `json:"models"`
+	Notes  int `json:"notes"`
+	Cards  int `json:"cards"`
+}
+
+// InspectedDeck is a deck of a package.
+type InspectedDeck struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Cards int    `json:"cards"`
+}
+
+// InspectedModel is a note type, with its fields in order.
+type InspectedModel struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Cloze  bool     `json:"cloze"`
+	Fields []string `json:"fields"`
+	Notes  int      `json:"notes"`
+}
+
+// InspectedSample is a sample note, with its fields as stored (HTML and
+// all) by name.
+type InspectedSample struct {
+	ID     int64             `json:"id"`
+	GUID   string            `json:"guid"`
+	Model  string            `json:"model"`
+	Tags   []string          `json:"tags"`
+	Fields map[string]string `json:"fields"`
+}
+
+// inspectPackage describes pkg, with up to limit sample notes. Decks and
+// note types are sorted by name, so the output is the same every time.
+func inspectPackage(pkg *anki.Package, limit int) InspectedPackage {
+	out := InspectedPackage{
+		Path: pkg.Path(),
+		Counts: InspectedCounts{
+			Decks:  len(pkg.Decks),
+			Models: len(pkg.Models),
+			Notes:  len(pkg.Notes),
+			Cards:  len(pkg.Cards),
+		},
+		Decks:   []InspectedDeck{},
+		Models:  []InspectedModel{},
+		Samples: []InspectedSample{},
+	}
+
+	cards := make(map[int64]int)
+	for _, card := range pkg.Cards {
+		cards[card.DeckID]++
+	}
+	for _, deck := range pkg.DeckList() {
+		out.Decks = append(out.Decks, InspectedDeck{ID: deck.ID, Name: deck.Name, Cards: cards[deck.ID]})
+	}
+
+	notes := make(map[int64]int)
+	for _, note := range pkg.Notes {
+		notes[note.ModelID]++
+	}
+	for _, model := range pkg.ModelList() {
+		fields := make([]string, len(model.Fields))
+		for i, f := range model.Fields {
+			fields[i] = f.Name
+		}
+		out.Models = append(out.Models, InspectedModel{
+			ID:     model.ID,
+			Name:   model.Name,
+			Cloze:  model.Type == 1,
+			Fields: fields,
+			Notes:  notes[model.ID],
+		})
+	}
+
+	for i, note := range pkg.Notes {
+		if i >= limit {
+			break
+		}
+
+		sample := InspectedSample{
+			ID:     note.ID,
+			GUID:   note.GUID,
+			Tags:   note.TagList(),
+			Fields: make(map[string]string),
+		}
+		if sample.Tags == nil {
+			sample.Tags = []string{}
+		}
+		if model := pkg.GetModel(note); model != nil {
+			sample.Model = model.Name
+		}
+		names := pkg.GetFieldNames(note)
+		for j, value := range note.Fields {
+			name := fmt.Sprintf("field_%d", j)
+			if j < len(names) {
+				name = names[j]
+			}
+			sample.Fields[name] = value
+		}
+		out.Samples = append(out.Samples, sample)
+	}
+
+	return out
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/spf13/cobra"
+)
+
+var ankiLintCmd = &cobra.Command{
+	Use:   "lint <file.apkg>",
+	Short: "Report notes in a deck that need fixing",
+	Long: `Check the notes of a deck before augmenting it, and list the IDs of the
+notes with each problem:
+  - No Chinese in the Chinese field
+  - The same Chinese in more than one note
+  - Fields that hold markup but no text, such as <div><br></div>
+  - Pinyin that does not match any reading of the characters
+  - Characters that are not in the dictionary, so have no meaning or
+    components
+
+The Chinese field is found as augment finds it unless --field is given,
+and the pinyin field is the first whose name has "pinyin" in it unless
+--pinyin-field is given. Pinyin may be written with tone marks or tone
+numbers; the neutral tone after the first syllable, erhua, and the tone
+changes of 一 and 不 are allowed. Chinese in brackets, such as the
+variants in 刀 (刂), is not expected in the pinyin.
+
+Exits with an error if a problem is found.
+
+Examples:
+  hmm anki lint deck.apkg
+  hmm anki lint vocab.apkg -f Hanzi --pinyin-field Reading
+  hmm anki lint deck.apkg --json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeApkg,
+	SilenceUsage:      true,
+	RunE:              runAnkiLint,
+}
+
+var (
+	ankiLintField       string
+	ankiLintPinyinField string
+	ankiLintJSON        bool
+)
+
+func init() {
+	ankiCmd.AddCommand(ankiLintCmd)
+
+	ankiLintCmd.Flags().StringVarP(&ankiLintField, "field", "f", "", "Field name containing Chinese characters (auto-detect if not specified)")
+	ankiLintCmd.Flags().StringVar(&ankiLintPinyinField, "pinyin-field", "", "Field name containing pinyin (the first with \"pinyin\" in its name if not specified)")
+	ankiLintCmd.Flags().BoolVar(&ankiLintJSON, "json", false, "Print the report as JSON")
+}
+
+// LintReport is the outcome of anki lint, as printed by --json.
+type LintReport struct {
+	Path            string          `json:"path"`
+	Notes           int             `json:"notes"`
+	Field           string          `json:"field"`
+	PinyinField     string          `json:"pinyin_field,omitempty"`
+	Dictionary      bool            `json:"dictionary"`
+	Empty           []int64         `json:"empty"`
+	Duplicates      []LintDuplicate `json:"duplicates"`
+	HTMLOnly        []LintField     `json:"html_only"`
+	Pinyin          []LintPinyin    `json:"pinyin_mismatches"`
+	NotInDictionary []LintChar      `json:"not_in_dictionary"`
+}
+
+// LintDuplicate is Chinese that more than one note holds.
+type LintDuplicate struct {
+	Text  string  `json:"text"`
+	Notes []int64 `json:"notes"`
+}
+
+// LintField is a field of a note with markup but no text.
+type LintField struct {
+	Note  int64  `json:"note"`
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// LintPinyin is a note whose pinyin matches no reading of its characters.
+type LintPinyin struct {
+	Note     int64    `json:"note"`
+	Text     string   `json:"text"`
+	Pinyin   string   `json:"pinyin"`
+	Readings []string `json:"readings"` // Readings of each character, joined by "/"
+}
+
+// LintChar is a character missing from the dictionary, with the notes it
+// is in.
+type LintChar struct {
+	Char  string  `json:"char"`
+	Notes []int64 `json:"notes"`
+}
+
+// Problems returns how many problems the report lists, counting each
+// note, field, or character once per kind of problem.
+func (r LintReport) Problems() int {
+	return len(r.Empty) + len(r.Duplicates) + len(r.HTMLOnly) + len(r.Pinyin) + len(r.NotInDictionary)
+}
+
+func runAnkiLint(cmd *cobra.Command, args []string) error {
+	pkg, err := anki.OpenPackage(args[0])
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	field := ankiLintField
+	if field == "" {
+		field = detectChineseField(pkg)
+		if field == "" {
+			return fmt.Errorf("could not find a field with Chinese characters; name it with --field")
+		}
+	}
+
+	if err := loadDictionary(); err != nil {
+		return fmt.Errorf("loading dictionary: %w", err)
+	}
+
+	report := lintPackage(pkg, field, ankiLintPinyinField, dictionaryPath() != "")
+
+	if ankiLintJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+	} else {
+		printLintReport(report)
+	}
+
+	if n := report.Problems(); n > 0 {
+		return fmt.Errorf("%d problems found", n)
+	}
+	return nil
+}
+
+// lintPackage checks the notes of pkg, with the Chinese in field and the
+// pinyin in pinyinField, or the first field named like pinyin if that is
+// empty. Characters are only looked up in the dictionary if withDict.
+func lintPackage(pkg *anki.Package, field, pinyinField string, withDict bool) LintReport {
+	report := LintReport{
+		Path:        pkg.Path(),
+		Notes:       len(pkg.Notes),
+		Field:       field,
+		PinyinField: pinyinField,
+		Dictionary:  withDict,
+
+		Empty:           []int64{},
+		Duplicates:      []LintDuplicate{},
+		HTMLOnly:        []LintField{},
+		Pinyin:          []LintPinyin{},
+		NotInDictionary: []LintChar{},
+	}
+	mandarin := pinyin.NewMandarin()
+
+	byText := make(map[string][]int64)
+	var texts []string
+	missing := make(map[string][]int64)
+	var missingOrder []string
+
+	for _, note := range pkg.Notes {
+		names := pkg.GetFieldNames(note)
+		for i, value := range note.Fields {
+			if i < len(names) && isHTMLOnly(value) {
+				report.HTMLOnly = append(report.HTMLOnly, LintField{Note: note.ID, Field: names[i], Value: value})
+			}
+		}
+
+		text := htmlutil.Text(pkg.GetFieldValue(note, field))
+		chars := analyze.ChineseChars(text)
+		if len(chars) == 0 {
+			report.Empty = append(report.Empty, note.ID)
+			continue
+		}
+
+		if byText[text] == nil {
+			texts = append(texts, text)
+		}
+		byText[text] = append(byText[text], note.ID)
+
+		pf := pinyinField
+		if pf == "" {
+			pf = pinyinFieldOf(names)
+		}
+		if pf != "" {
+			if report.PinyinField == "" {
+				report.PinyinField = pf
+			}
+			written := htmlutil.Text(pkg.GetFieldValue(note, pf))
+			spelled := analyze.ChineseChars(bracketed.ReplaceAllString(text, ""))
+			if written != "" && len(spelled) > 0 && !pinyinMatches(mandarin, spelled, written) {
+				report.Pinyin = append(report.Pinyin, LintPinyin{
+					Note:     note.ID,
+					Text:     text,
+					Pinyin:   written,
+					Readings: charReadings(mandarin, spelled),
+				})
+			}
+		}
+
+		if withDict {
+			seen := make(map[string]bool)
+			for _, c := range chars {
+				if seen[c] || dict.Lookup(c) != nil {
+					continue
+				}
+				seen[c] = true
+				if missing[c] == nil {
+					missingOrder = append(missingOrder, c)
+				}
+				missing[c] = append(missing[c], note.ID)
+			}
+		}
+	}
+
+	for _, text := range texts {
+		if ids := byText[text]; len(ids) > 1 {
+			report.Duplicates = append(report.Duplicates, LintDuplicate{Text: text, Notes: ids})
+		}
+	}
+	for _, c := range missingOrder {
+		report.NotInDictionary = append(report.NotInDictionary, LintChar{Char: c, Notes: missing[c]})
+	}
+	return report
+}
+
+// printLintReport prints each check with how many problems it found and
+// the notes they are in.
+func printLintReport(r LintReport) {
+	fmt.Printf("Linting %s: %d notes, Chinese in %q", r.Path, r.Notes, r.Field)
+	if r.PinyinField != "" {
+		fmt.Printf(", pinyin in %q", r.PinyinField)
+	}
+	fmt.Println()
+	fmt.Println()
+
+	lintHeading("Empty Chinese field", len(r.Empty), "notes")
+	if len(r.Empty) > 0 {
+		fmt.Printf("    %s\n", joinIDs(r.Empty))
+	}
+
+	lintHeading("Duplicate characters", len(r.Duplicates), "texts")
+	for _, d := range r.Duplicates {
+		fmt.Printf("    %s: %s\n", d.Text, joinIDs(d.Notes))
+	}
+
+	lintHeading("HTML-only fields", len(r.HTMLOnly), "fields")
+	for _, f := range r.HTMLOnly {
+		fmt.Printf("    %d %s: %s\n", f.Note, f.Field, f.Value)
+	}
+
+	if r.PinyinField == "" {
+		fmt.Println("- Pinyin: no pinyin field (name one with --pinyin-field)")
+	} else {
+		lintHeading("Pinyin mismatches", len(r.Pinyin), "notes")
+		for _, p := range r.Pinyin {
+			fmt.Printf("    %d %s: %s (readings %s)\n", p.Note, p.Text, p.Pinyin, strings.Join(p.Readings, " "))
+		}
+	}
+
+	if !r.Dictionary {
+		fmt.Println("- Not in dictionary: no dictionary found (see 'hmm doctor')")
+	} else {
+		lintHeading("Not in dictionary", len(r.NotInDictionary), "characters")
+		for _, c := range r.NotInDictionary {
+			fmt.Printf("    %s: %s\n", c.Char, joinIDs(c.Notes))
+		}
+	}
+}
+
+// lintHeading prints the name of a check, marked as passed or failed.
+func lintHeading(name string, n int, what string) {
+	if n == 0 {
+		fmt.Printf("✓ %s: none\n", name)
+		return
+	}
+	fmt.Printf("✗ %s: %d %s\n", name, n, what)
+}
+
+// joinIDs returns note IDs separated by commas.
+func joinIDs(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = fmt.Sprint(id)
+	}
+	return strings.Join(s, ", ")
+}
+
+// isHTMLOnly reports whether a field value holds markup that shows no
+// text. Images are content even without text, so fields with one are not.
+func isHTMLOnly(value string) bool {
+	if strings.TrimSpace(value) == "" || !strings.Contains(value, "<") && !strings.Contains(value, "&") {
+		return false
+	}
+	if strings.Contains(strings.ToLower(value), "<img") {
+		return false
+	}
+	return htmlutil.Text(value) == ""
+}
+
+// pinyinFieldOf returns the first of names with "pinyin" in it, or "".
+func pinyinFieldOf(names []string) string {
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), "pinyin") {
+			return name
+		}
+	}
+	return ""
+}
+
+// bracketed matches text in brackets, such as the variants of a radical
+// in 刀 (刂), which the pinyin does not spell.
+var bracketed = regexp.MustCompile(`[(（\[【][^)）\]】]*[)）\]】]`)
+
+// sandhi are the readings 一 and 不 take before other tones, which decks
+// often write instead of the dictionary reading.
+var sandhi = map[string][]string{
+	"一": {"yí", "yì"},
+	"不": {"bú"},
+}
+
+// pinyinMatches reports whether written, one or more readings separated
+// by commas, semicolons, or slashes, or put in brackets, spells chars in any of their
+// readings. Characters with no readings at all are taken to match
+// anything, as there is nothing to check them against.
+func pinyinMatches(m *pinyin.Mandarin, chars []string, written string) bool {
+	readings := make([][]string, len(chars))
+	for i, c := range chars {
+		rs := m.Readings(c)
+		if len(rs) == 0 {
+			return true
+		}
+		rs = append(append([]string(nil), rs...), sandhi[c]...)
+		for _, r := range rs {
+			full := strings.ToLower(r)
+			readings[i] = append(readings[i], full)
+			// Syllables after the first may be in the neutral tone
+			if toneless := strings.ToLower(m.Parse(r).Toneless); i > 0 && toneless != full {
+				readings[i] = append(readings[i], toneless)
+			}
+		}
+		if c == "儿" && i > 0 {
+			readings[i] = append(readings[i], "r")
+		}
+	}
+
+	for _, alt := range strings.FieldsFunc(written, func(r rune) bool { return strings.ContainsRune(",;/()，；（）", r) }) {
+		if spells(readings, normalizePinyin(alt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// spells reports whether s is a reading of each character in turn.
+func spells(readings [][]string, s string) bool {
+	if len(readings) == 0 {
+		return s == ""
+	}
+	for _, r := range readings[0] {
+		if strings.HasPrefix(s, r) && spells(readings[1:], s[len(r):]) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePinyin returns written pinyin in lower case with tone marks,
+// and without spaces, apostrophes, punctuation, or anything but Latin
+// letters.
+func normalizePinyin(s string) string {
+	s = strings.NewReplacer("v", "ü", "V", "Ü", "u:", "ü", "U:", "Ü").Replace(pinyin.FromNumbers(s))
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.Is(unicode.Latin, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// charReadings returns the readings of each of chars, joined by "/".
+func charReadings(m *pinyin.Mandarin, chars []string) []string {
+	out := make([]string, len(chars))
+	for i, c := range chars {
+		rs := m.Readings(c)
+		if len(rs) == 0 {
+			out[i] = "?"
+			continue
+		}
+		out[i] = strings.Join(rs, "/")
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var ankiScrubCmd = &cobra.Command{
+	Use:   "scrub <file.apkg>",
+	Short: "Remove your actor and set names from an augmented deck",
+	Long: `Write a copy of an augmented deck that can be shared without giving away
+your memory palace. In the HMM fields (and HMM_Breakdown), the names of
+your actors become their initial IDs, like "Actor [b]", the names of your
+sets their final IDs, like "Set [ang]", and your room descriptions their
+tones, like "Room [3]". With --props, prop names become their components
+too, like "Prop [木]". The other fields are left as they are.
+
+Names are taken from your current config, so scrub with the config the
+deck was augmented with. The copy is written next to the deck as
+<name>_shared.apkg unless --output is given.
+
+Examples:
+  hmm anki scrub deck_hmm.apkg
+  hmm anki scrub deck_hmm.apkg --props -o public.apkg`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeApkg,
+	RunE:              runAnkiScrub,
+}
+
+var (
+	ankiScrubOutput string
+	ankiScrubProps  bool
+)
+
+func init() {
+	ankiCmd.AddCommand(ankiScrubCmd)
+
+	ankiScrubCmd.Flags().StringVarP(&ankiScrubOutput, "output", "o", "", "Output file (default <name>_shared.apkg)")
+	ankiScrubCmd.Flags().BoolVar(&ankiScrubProps, "props", false, "Replace prop names with their components as well")
+}
+
+func runAnkiScrub(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+	outputPath := ankiScrubOutput
+	if outputPath == "" {
+		ext := filepath.Ext(inputPath)
+		outputPath = strings.TrimSuffix(inputPath, ext) + "_shared" + ext
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		return fmt.Errorf("scrubbing needs your actor and set names: %w", err)
+	}
+
+	pkg, err := anki.OpenPackage(inputPath)
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	replacer := scene.NewLongestFirstReplacer(scrubPairs(cfg.Actors, cfg.Sets, cfg.Props, ankiScrubProps))
+	fields := append([]string{anki.BreakdownField}, anki.HMMFields...)
+
+	scrubbed := 0
+	for _, note := range pkg.Notes {
+		changed := false
+		for _, field := range fields {
+			value := pkg.GetFieldValue(note, field)
+			if value == "" {
+				continue
+			}
+			if clean := replacer.Replace(value); clean != value {
+				if err := pkg.SetNoteField(note, field, clean); err != nil {
+					return fmt.Errorf("scrubbing note %d: %w", note.ID, err)
+				}
+				changed = true
+			}
+		}
+		if changed {
+			scrubbed++
+		}
+	}
+
+	if err := pkg.SaveAs(outputPath); err != nil {
+		return fmt.Errorf("saving scrubbed package: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Scrubbed %d of %d notes\n", scrubbed, len(pkg.Notes))
+	fmt.Fprintf(os.Stderr, "Wrote deck to share to: %s\n", outputPath)
+	return nil
+}
+
+// scrubPairs returns old, new pairs replacing the names of actors and
+// sets, the descriptions of rooms, and with props the names of props by
+// their IDs. Names are also matched HTML-escaped, as in HMM_Breakdown.
+func scrubPairs(actors []hmm.Actor, sets []hmm.Set, props []hmm.Prop, withProps bool) []string {
+	var pairs []string
+	add := func(name, id string) {
+		if name == "" {
+			return
+		}
+		pairs = append(pairs, name, id)
+		if escaped := html.EscapeString(name); escaped != name {
+			pairs = append(pairs, escaped, id)
+		}
+	}
+
+	for _, a := range actors {
+		add(a.Name, fmt.Sprintf("Actor [%s]", a.ID))
+	}
+	for _, s := range sets {
+		add(s.Name, fmt.Sprintf("Set [%s]", s.ID))
+		for _, room := range s.Rooms {
+			id := fmt.Sprintf("Room [%d]", room.Tone)
+			add(room.Description, id)
+			add(room.Name, id)
+		}
+	}
+	if withProps {
+		for _, p := range props {
+			add(p.Name, fmt.Sprintf("Prop [%s]", p.ID))
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchSettle is how long a new deck must go unchanged before it is
+// augmented, so downloads still being written are left alone.
+const watchSettle = 2 * time.Second
+
+var ankiWatchCmd = &cobra.Command{
+	Use:   "watch <dir>",
+	Short: "Augment every deck that appears in a directory",
+	Long: `Watch a directory, such as your downloads, and augment every .apkg that
+appears in it: once the file stops changing, the augmented deck is written
+next to it as <name>_hmm.apkg, as 'hmm anki augment --format apkg' does.
+Export a deck from Anki or download it from AnkiWeb, and the _hmm version
+is ready to import.
+
+Notes whose HMM fields are already filled, from an earlier round, keep
+them unless --skip-existing=false is given. Decks ending in _hmm.apkg are
+never augmented again. Stop watching with Ctrl+C.
+
+Examples:
+  hmm anki watch ~/Downloads
+  hmm anki watch ~/Downloads --field Hanzi`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAnkiWatch,
+}
+
+var (
+	ankiWatchField string
+	ankiWatchSkip  bool
+)
+
+func init() {
+	ankiCmd.AddCommand(ankiWatchCmd)
+
+	ankiWatchCmd.Flags().StringVarP(&ankiWatchField, "field", "f", "", "Field name containing Chinese characters (auto-detect if not specified)")
+	ankiWatchCmd.Flags().BoolVar(&ankiWatchSkip, "skip-existing", true, "Leave out notes whose HMM fields are already filled")
+}
+
+func runAnkiWatch(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	signalsHandled.Store(true)
+	defer signalsHandled.Store(false)
+
+	fmt.Fprintf(os.Stderr, "Watching %s for new decks (Ctrl+C to stop)\n", dir)
+
+	// Every event restarts the deck's timer; it is augmented when the
+	// timer runs out
+	var mu sync.Mutex
+	timers := make(map[string]*time.Timer)
+	ready := make(chan string)
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr, "Stopped watching")
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if !watchable(event.Name) {
+				continue
+			}
+
+			path := event.Name
+			mu.Lock()
+			if t, ok := timers[path]; ok {
+				t.Reset(watchSettle)
+			} else {
+				timers[path] = time.AfterFunc(watchSettle, func() {
+					mu.Lock()
+					delete(timers, path)
+					mu.Unlock()
+					select {
+					case ready <- path:
+					case <-ctx.Done():
+					}
+				})
+			}
+			mu.Unlock()
+
+		case path := <-ready:
+			out, err := augmentDeckFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", filepath.Base(path), err)
+				continue
+			}
+			if out == "" {
+				fmt.Fprintf(os.Stderr, "Nothing to augment in %s\n", filepath.Base(path))
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Augmented %s -> %s\n", filepath.Base(path), filepath.Base(out))
+		}
+	}
+}
+
+// watchable reports whether path is a deck to augment: an .apkg that is
+// not hidden and not itself augmented.
+func watchable(path string) bool {
+	name := filepath.Base(path)
+	return strings.EqualFold(filepath.Ext(name), ".apkg") &&
+		!strings.HasPrefix(name, ".") &&
+		!strings.HasSuffix(strings.ToLower(name), "_hmm.apkg")
+}
+
+// augmentDeckFile writes the augmented version of the deck at path next
+// to it and returns its path, or "" if no note needed augmenting. The
+// config and scenes are read again for every deck, so edits made while
+// watching are used.
+func augmentDeckFile(path string) (string, error) {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	pkg, err := anki.OpenPackage(path)
+	if err != nil {
+		return "", fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	field := ankiWatchField
+	if field == "" {
+		if field = detectChineseField(pkg); field == "" {
+			return "", fmt.Errorf("could not auto-detect field with Chinese characters. Use --field to specify")
+		}
+	}
+
+	results := augmentNotes(pkg, field, analyze.New(dict, gen), scenes)
+	if ankiWatchSkip {
+		results = withoutAugmented(pkg, results)
+	}
+	if len(results) == 0 {
+		return "", nil
+	}
+
+	out := strings.TrimSuffix(path, filepath.Ext(path)) + "_hmm" + filepath.Ext(path)
+	if err := writeAugmentedApkg(pkg, results, gen, out, path); err != nil {
+		return "", err
+	}
+	return out, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/bench"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the hot paths against their performance budgets",
+	Long: `Benchmark the paths every session goes through, and fail if one takes
+longer than its budget, so performance regressions are caught:
+  dictionary load         reading the Make Me a Hanzi dictionary
+  pinyin parse            parsing the reading of one character
+  decomposition extract   extracting the components of one character
+  apkg open               opening a generated deck of --notes notes
+
+The budgets are generous for a laptop; --scale multiplies them for slower
+machines such as CI runners. Benchmarks needing the dictionary are
+skipped if there is none. The same benchmarks run with
+go test -bench . ./internal/decomp ./internal/pinyin ./internal/anki.
+
+Examples:
+  hmm bench
+  hmm bench --run apkg --notes 100000
+  hmm bench --scale 3`,
+	Hidden:       true,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBench,
+}
+
+var (
+	benchNotes int
+	benchRun   string
+	benchScale float64
+)
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntVar(&benchNotes, "notes", bench.DeckNotes, "Notes in the generated deck apkg open reads")
+	benchCmd.Flags().StringVar(&benchRun, "run", "", "Only run the benchmarks whose name matches this regular expression")
+	benchCmd.Flags().Float64Var(&benchScale, "scale", 1, "Multiply the budgets by this")
+}
+
+// benchmark is a hot path and the most time one run of it may take.
+type benchmark struct {
+	name   string
+	budget time.Duration
+	op     func() error
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	var filter *regexp.Regexp
+	if benchRun != "" {
+		var err error
+		if filter, err = regexp.Compile(benchRun); err != nil {
+			return fmt.Errorf("invalid --run: %w", err)
+		}
+	}
+
+	dictPath := dictionaryPath()
+	var d *decomp.Dictionary
+	if dictPath != "" {
+		d = decomp.NewDictionary()
+		if err := d.LoadFromFile(dictPath); err != nil {
+			return fmt.Errorf("loading dictionary: %w", err)
+		}
+	}
+	chars, decomps := bench.Chars(d)
+
+	dir, err := os.MkdirTemp("", "hmm-bench-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	deckPath := filepath.Join(dir, "bench.apkg")
+	if filter == nil || filter.MatchString("apkg open") {
+		if err := bench.WriteDeck(deckPath, benchNotes, chars); err != nil {
+			return fmt.Errorf("generating the deck: %w", err)
+		}
+	}
+
+	var benchmarks []benchmark
+	if d != nil {
+		i := 0
+		benchmarks = append(benchmarks,
+			benchmark{"dictionary load", 500 * time.Millisecond, func() error {
+				return decomp.NewDictionary().LoadFromFile(dictPath)
+			}},
+			benchmark{"decomposition extract", time.Microsecond, func() error {
+				decomp.ExtractComponents(decomps[i%len(decomps)])
+				i++
+				return nil
+			}},
+		)
+	}
+	parser := pinyin.NewParser()
+	j := 0
+	benchmarks = append(benchmarks,
+		benchmark{"pinyin parse", 20 * time.Microsecond, func() error {
+			parser.ParseChar(chars[j%len(chars)])
+			j++
+			return nil
+		}},
+		benchmark{"apkg open", 3 * time.Second, func() error {
+			pkg, err := anki.OpenPackage(deckPath)
+			if err != nil {
+				return err
+			}
+			return pkg.Close()
+		}},
+	)
+
+	if d == nil {
+		fmt.Fprintln(os.Stderr, "Warning: no dictionary found, skipping the benchmarks that need it")
+	}
+
+	over := 0
+	fmt.Printf("%-24s %12s %12s %10s %12s\n", "BENCHMARK", "TIME/OP", "BUDGET", "ALLOCS/OP", "STATUS")
+	for _, bm := range benchmarks {
+		if filter != nil && !filter.MatchString(bm.name) {
+			continue
+		}
+
+		result, err := bench.Run(bm.op)
+		if err != nil {
+			fmt.Printf("%-24s %12s (%v)\n", bm.name, "failed", err)
+			over++
+			continue
+		}
+
+		budget := time.Duration(float64(bm.budget) * benchScale)
+		status := "ok"
+		if result.PerOp > budget {
+			status = "OVER BUDGET"
+			over++
+		}
+		fmt.Printf("%-24s %12s %12s %10d %12s\n", bm.name, roundDuration(result.PerOp), budget, result.AllocsPerOp, status)
+	}
+
+	if over > 0 {
+		return fmt.Errorf("%d benchmarks failed or went over budget", over)
+	}
+	return nil
+}
+
+// roundDuration rounds d to four or so significant digits for display.
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	case d >= time.Microsecond:
+		return d.Round(time.Nanosecond * 10)
+	}
+	return d
+}
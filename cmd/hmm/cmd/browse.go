@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/f3rmion/hmm/internal/anki"
@@ -29,12 +30,15 @@ Controls:
   g             Generate image prompt
   /             Search
   Esc           Quit`,
-	Args: cobra.ExactArgs(1),
-	RunE: runBrowse,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeApkg,
+	RunE:              runBrowse,
 }
 
 func init() {
 	rootCmd.AddCommand(browseCmd)
+
+	browseCmd.Flags().StringVar(&sessionLogDir, "session-log", "", "log the characters looked at and write a dated Markdown summary to this directory on quit")
 }
 
 func runBrowse(cmd *cobra.Command, args []string) error {
@@ -42,15 +46,10 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 
 	// Load dictionary
 	dict := decomp.NewDictionary()
-	dictPaths := []string{
-		"data/dictionary.jsonl",
-		"/usr/local/share/hmm/dictionary.jsonl",
-	}
-	for _, p := range dictPaths {
-		if _, err := os.Stat(p); err == nil {
-			if err := dict.LoadFromFile(p); err == nil {
-				break
-			}
+	loadKeywords(dict)
+	if path := dictionaryPath(); path != "" {
+		if err := dict.LoadFromFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 	}
 
@@ -73,15 +72,32 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 
 	fmt.Fprintf(os.Stderr, "Loaded: %s (%d notes)\n", path, len(pkg.Notes))
 
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	applyTheme(configDir)
+	quietLogging()
+
 	// Create and run unified TUI with pre-loaded package
+	app := tui.NewAppWithPackage(dict, cfg, scenes, pkg, path)
+	if err := app.LoadUIState(filepath.Join(configDir, config.UIStateFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if sessionLogDir != "" {
+		app.LogSession(sessionLogDir)
+	}
+
 	p := tea.NewProgram(
-		tui.NewAppWithPackage(dict, cfg, pkg, path),
+		app,
 		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	if err := runProgram(p); err != nil {
 		return fmt.Errorf("running TUI: %w", err)
 	}
 
-	return nil
+	return writeSessionLog(app)
 }
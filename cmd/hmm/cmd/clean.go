@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/spf13/cobra"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove temp directories left behind by killed processes",
+	Long: `Remove the temp directories that decks were opened or built in by hmm
+processes that are no longer running. hmm removes them itself when it
+exits, even when interrupted, but not when it is killed outright or
+crashes. Directories of hmm processes still running are left alone.
+
+With --cache, the cache of rendered big characters is removed too; it is
+rebuilt as characters are shown. --audio also removes the cached
+pronunciation recordings, which may cost API calls to make again.
+
+Examples:
+  hmm clean
+  hmm clean --cache --dry-run`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runClean,
+}
+
+var (
+	cleanCache  bool
+	cleanAudio  bool
+	cleanDryRun bool
+)
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().BoolVar(&cleanCache, "cache", false, "Also remove the big character cache")
+	cleanCmd.Flags().BoolVar(&cleanAudio, "audio", false, "Also remove the cached pronunciation recordings")
+	cleanCmd.Flags().BoolVarP(&cleanDryRun, "dry-run", "n", false, "Only list what would be removed")
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	dirs, err := anki.StaleTempDirs()
+	if err != nil {
+		return fmt.Errorf("listing temp directories: %w", err)
+	}
+
+	if cleanCache || cleanAudio {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("finding the cache directory: %w", err)
+		}
+		if cleanCache {
+			dirs = append(dirs, filepath.Join(cacheDir, "hmm", "bigchar"))
+		}
+		if cleanAudio {
+			dirs = append(dirs, filepath.Join(cacheDir, "hmm", "audio"))
+		}
+	}
+
+	removed := 0
+	var size int64
+	for _, dir := range dirs {
+		n, err := dirSize(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if cleanDryRun {
+			fmt.Printf("Would remove %s (%s)\n", dir, formatBytes(n))
+		} else {
+			if err := os.RemoveAll(dir); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			fmt.Printf("Removed %s (%s)\n", dir, formatBytes(n))
+		}
+		removed++
+		size += n
+	}
+
+	switch {
+	case removed == 0:
+		fmt.Println("Nothing to clean")
+	case cleanDryRun:
+		fmt.Printf("Would free %s in %d directories\n", formatBytes(size), removed)
+	default:
+		fmt.Printf("Freed %s in %d directories\n", formatBytes(size), removed)
+	}
+	return nil
+}
+
+// dirSize adds up the sizes of the files under dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// signalsHandled is set while a command that stops by itself when
+// interrupted or terminated runs (a TUI, 'anki watch'), so closing the
+// open decks is left to Execute once it has stopped.
+var signalsHandled atomic.Bool
+
+// runProgram runs a TUI program, which quits by itself on a signal.
+func runProgram(p *tea.Program) error {
+	signalsHandled.Store(true)
+	defer signalsHandled.Store(false)
+	_, err := p.Run()
+	return err
+}
+
+// closePackagesOnSignal closes the open decks, removing their temp
+// directories, when hmm is interrupted or terminated, then exits as the
+// signal would have.
+func closePackagesOnSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range sigs {
+			if signalsHandled.Load() {
+				continue
+			}
+			anki.CloseAll()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s)
+			}
+			os.Exit(code)
+		}
+	}()
+}
+
+// formatBytes formats a size in bytes as B, KB, MB, or GB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n) / unit
+	for _, suffix := range []string{"KB", "MB"} {
+		if size < unit {
+			return fmt.Sprintf("%.1f %s", size, suffix)
+		}
+		size /= unit
+	}
+	return fmt.Sprintf("%.1f GB", size)
+}
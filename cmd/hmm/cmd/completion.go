@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Besides commands and
+flags, it completes .apkg files for deck arguments and the characters in
+your scene store for commands that take a character.
+
+Bash:
+  source <(hmm completion bash)
+  # or, for every session:
+  hmm completion bash > /etc/bash_completion.d/hmm
+
+Zsh:
+  hmm completion zsh > "${fpath[1]}/_hmm"
+
+Fish:
+  hmm completion fish > ~/.config/fish/completions/hmm.fish
+
+PowerShell:
+  hmm completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+	return fmt.Errorf("unknown shell: %s (use bash, zsh, fish, or powershell)", args[0])
+}
+
+// completeApkg completes the first argument with .apkg files.
+func completeApkg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeApkgFlag(cmd, args, toComplete)
+}
+
+// completeApkgFlag completes a flag value with .apkg files.
+func completeApkgFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"apkg"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeSceneChars completes arguments with the characters that have a
+// scene, described by their pinyin and keyword. Up to max arguments are
+// completed; max <= 0 allows any number.
+func completeSceneChars(max int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if max > 0 && len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		// The config directory was set before --config was parsed
+		initConfig()
+		store, err := loadSceneStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		seen := make(map[string]bool)
+		var chars []string
+		for _, sc := range store.All() {
+			if seen[sc.Character] {
+				continue
+			}
+			seen[sc.Character] = true
+			chars = append(chars, cobra.CompletionWithDesc(sc.Character, sc.Pinyin+" "+sc.Keyword))
+		}
+		sort.Strings(chars)
+		return chars, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeListNames completes the first argument with the names of the
+// word lists.
+func completeListNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// The config directory was set before --config was parsed
+	initConfig()
+	lists, _ := scene.AllTargetLists(listsDir())
+	var names []string
+	for _, l := range lists {
+		names = append(names, l.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
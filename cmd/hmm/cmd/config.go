@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change config values",
+	Long: `Read and change values in the config directory by dotted path, for
+scripts or quick edits without opening the YAML files.
+
+The first part of a path names the file: actors, sets, props, ui, or
+theme. Items of a list are picked by their id, by their tone (set rooms),
+or by their position:
+  actors.b.name             name of the actor for b
+  sets.ao.rooms.3.name      third-tone room of the ao set
+  props.口.name              prop for the 口 radical
+
+Setting a value keeps the comments and layout of the file. With
+--romanization jyutping, actors and sets are the Cantonese ones.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <path>",
+	Short: "Print a config value",
+	Long: `Print the config value at a dotted path. Lists and mappings are printed
+as YAML.
+
+Examples:
+  hmm config get sets.ao.name
+  hmm config get actors.b`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <path> <value>",
+	Short: "Change a config value",
+	Long: `Set the config value at a dotted path. A missing last key is added to
+its item, so new fields can be set; items themselves are added with
+'hmm interactive' or by editing the file.
+
+Examples:
+  hmm config set actors.b.name "Brad Pitt"
+  hmm config set sets.ao.rooms.3.name "Kitchen"`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runConfigSet,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, err := config.GetValue(configPathDir(args[0]), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	return config.SetValue(configPathDir(args[0]), args[0], args[1])
+}
+
+// configPathDir returns the directory holding the file a dotted path
+// addresses, which for actors and sets depends on the romanization.
+func configPathDir(path string) string {
+	switch file, _, _ := strings.Cut(path, "."); file {
+	case "actors", "sets":
+		return tablesDir(getConfigDir())
+	}
+	return getConfigDir()
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/f3rmion/hmm/internal/photo"
+	"github.com/spf13/cobra"
+)
+
+var configPhotosCmd = &cobra.Command{
+	Use:   "photos [actor-id...]",
+	Short: "Fetch a reference photo of each actor from Wikipedia",
+	Long: `Fetch a thumbnail of each named actor, or only of the actors given by
+ID, from the Wikipedia page on their name, into the photos directory of
+the config directory. The Actors tab of Settings shows them.
+
+Actors who already have a photo are skipped unless --force is given. An
+actor whose name finds the wrong page can be given a photo by hand: save
+it as photos/<id>.jpg or .png.
+
+Examples:
+  hmm config photos
+  hmm config photos b p --force
+  hmm config photos --lang de`,
+	SilenceUsage: true,
+	RunE:         runConfigPhotos,
+}
+
+var (
+	photosForce bool
+	photosLang  string
+	photosSize  int
+)
+
+func init() {
+	configCmd.AddCommand(configPhotosCmd)
+
+	configPhotosCmd.Flags().BoolVar(&photosForce, "force", false, "Fetch photos again, replacing those there are")
+	configPhotosCmd.Flags().StringVar(&photosLang, "lang", "en", "Wikipedia language to look names up in")
+	configPhotosCmd.Flags().IntVar(&photosSize, "size", 240, "Thumbnail width in pixels")
+}
+
+func runConfigPhotos(cmd *cobra.Command, args []string) error {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, id := range args {
+		wanted[id] = true
+	}
+
+	dir := filepath.Join(getConfigDir(), photo.DirName)
+	client := photo.NewClient(photosLang)
+
+	var fetched, missing, kept int
+	for _, a := range cfg.Actors {
+		if len(wanted) > 0 && !wanted[a.ID] {
+			continue
+		}
+		delete(wanted, a.ID)
+		if a.Name == "" {
+			continue
+		}
+		if !photosForce && photo.Find(dir, a.ID) != "" {
+			kept++
+			continue
+		}
+
+		data, ext, err := client.Fetch(cmd.Context(), a.Name, photosSize)
+		if errors.Is(err, photo.ErrNotFound) {
+			fmt.Printf("  %-6s %s: %v\n", a.ID, a.Name, err)
+			missing++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("fetching photo of %s: %w", a.Name, err)
+		}
+		path, err := photo.Save(dir, a.ID, data, ext)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %-6s %s → %s\n", a.ID, a.Name, path)
+		fetched++
+	}
+	for _, id := range args {
+		if wanted[id] {
+			fmt.Printf("  %-6s no such actor\n", id)
+			delete(wanted, id)
+		}
+	}
+
+	fmt.Printf("\nFetched %d photos", fetched)
+	if missing > 0 {
+		fmt.Printf(", %d not found", missing)
+	}
+	if kept > 0 {
+		fmt.Printf(", %d already there (--force to fetch again)", kept)
+	}
+	fmt.Println()
+	return nil
+}
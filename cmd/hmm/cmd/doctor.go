@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/audio"
+	"github.com/f3rmion/hmm/internal/clipboard"
+	"github.com/f3rmion/hmm/internal/jyutping"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/tui/bigchar"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that hmm is set up correctly",
+	Long: `Check the setup hmm depends on and suggest fixes for what is missing:
+  - The config directory, and whether actors, sets, props, and scenes parse
+  - The data directory, and the Make Me a Hanzi dictionary in it with
+    how many entries it has
+  - A CJK font for the large character display
+  - A clipboard tool, and an audio player for pronunciations
+  - The Anthropic API key (with --ping, a live request checks it works)
+  - The SQLite driver Anki decks are read with
+
+Exits with an error if a check fails; warnings are for optional features.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+var doctorPing bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorPing, "ping", false, "Send a request to the Anthropic API to check the key")
+}
+
+// checkLevel is how a doctor check turned out.
+type checkLevel int
+
+const (
+	checkOK checkLevel = iota
+	checkWarn
+	checkFail
+)
+
+// doctorCheck is the outcome of one check, with a fix for anything but OK.
+type doctorCheck struct {
+	name   string
+	level  checkLevel
+	detail string
+	fix    string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := []doctorCheck{
+		checkConfig(),
+		checkScenes(),
+		checkDataDir(),
+		checkDictionary(),
+		checkRomanization(),
+		checkFont(),
+		checkClipboard(),
+		checkAudio(),
+		checkLLM(doctorPing),
+		checkSQLite(),
+	}
+
+	failed := 0
+	for _, c := range checks {
+		mark := "✓"
+		switch c.level {
+		case checkWarn:
+			mark = "!"
+		case checkFail:
+			mark = "✗"
+			failed++
+		}
+		fmt.Printf("%s %s: %s\n", mark, c.name, c.detail)
+		if c.level != checkOK && c.fix != "" {
+			fmt.Printf("    Fix: %s\n", c.fix)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+func checkConfig() doctorCheck {
+	c := doctorCheck{name: "Config"}
+	dir := getConfigDir()
+	if _, err := os.Stat(filepath.Join(tablesDir(dir), "actors.yaml")); err != nil {
+		c.level = checkWarn
+		c.detail = "no config in " + dir
+		c.fix = "run 'hmm init' (or start 'hmm', which copies the defaults)"
+		return c
+	}
+
+	cfg, err := loadUserConfig(dir)
+	if err != nil {
+		c.level = checkFail
+		c.detail = err.Error()
+		c.fix = "correct the YAML in " + dir + ", or move it aside and run 'hmm init'"
+		return c
+	}
+	c.detail = fmt.Sprintf("%s (%d actors, %d sets, %d props)", dir, len(cfg.Actors), len(cfg.Sets), len(cfg.Props))
+	return c
+}
+
+func checkScenes() doctorCheck {
+	c := doctorCheck{name: "Scenes"}
+	store, err := loadSceneStore()
+	if err != nil {
+		c.level = checkFail
+		c.detail = err.Error()
+		c.fix = "correct the YAML in the scene store, or restore it from a backup"
+		return c
+	}
+	c.detail = fmt.Sprintf("%s (%d scenes)", store.Path(), len(store.All()))
+	return c
+}
+
+func checkDataDir() doctorCheck {
+	c := doctorCheck{name: "Data directory"}
+	if dir := viper.GetString("data_dir"); dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			c.level = checkFail
+			c.detail = dir + " (set by --data-dir or HMM_DATA_DIR) is not a directory"
+			c.fix = "create it, or point --data-dir or HMM_DATA_DIR at the directory with dictionary.jsonl"
+			return c
+		}
+		c.detail = dir + " (set by --data-dir or HMM_DATA_DIR)"
+		return c
+	}
+	if path := dictionaryPath(); path != "" {
+		c.detail = filepath.Dir(path)
+		return c
+	}
+	c.detail = userDataDir() + " (empty)"
+	return c
+}
+
+func checkDictionary() doctorCheck {
+	c := doctorCheck{name: "Dictionary"}
+	path := dictionaryPath()
+	if path == "" {
+		c.level = checkWarn
+		c.detail = "not found; meanings and components are missing"
+		c.fix = "download dictionary.txt from https://github.com/skishore/makemeahanzi and save it as " +
+			filepath.Join(userDataDir(), "dictionary.jsonl")
+		return c
+	}
+	if err := loadDictionary(); err != nil {
+		c.level = checkFail
+		c.detail = fmt.Sprintf("%s: %v", path, err)
+		c.fix = "download a fresh copy of dictionary.txt from Make Me a Hanzi"
+		return c
+	}
+	c.detail = fmt.Sprintf("%s (%d entries)", path, dict.Size())
+	return c
+}
+
+func checkRomanization() doctorCheck {
+	c := doctorCheck{name: "Romanization"}
+	if !jyutpingMode() {
+		c.detail = "pinyin (Mandarin)"
+		return c
+	}
+	if jyutpingPath() == "" {
+		c.level = checkFail
+		c.detail = "jyutping (Cantonese), but no " + jyutping.FileName + " with the readings"
+		c.fix = "download Unihan.zip from https://www.unicode.org/Public/UCD/latest/ucd/ and save its Unihan_Readings.txt as " +
+			filepath.Join(userDataDir(), jyutping.FileName)
+		return c
+	}
+	if err := setupRomanization(); err != nil {
+		c.level = checkFail
+		c.detail = "jyutping (Cantonese): " + err.Error()
+		c.fix = "replace " + jyutpingPath() + " with Unihan_Readings.txt from Unihan.zip"
+		return c
+	}
+	c.detail = fmt.Sprintf("jyutping (Cantonese), %s (%d characters)", jyutpingPath(), jyutpingTable.Size())
+	return c
+}
+
+func checkFont() doctorCheck {
+	c := doctorCheck{name: "CJK font"}
+	if !bigchar.IsAvailable() {
+		c.level = checkWarn
+		c.detail = "not found; the large character is shown as text"
+		switch runtime.GOOS {
+		case "linux":
+			c.fix = "install Noto Sans CJK (e.g. fonts-noto-cjk or noto-fonts-cjk)"
+		case "windows":
+			c.fix = "add the Chinese (Simplified) supplemental fonts in Settings > Apps > Optional features, or install Noto Sans CJK"
+		default:
+			c.fix = "install a CJK font such as Noto Sans CJK"
+		}
+		return c
+	}
+	c.detail = bigchar.FontName()
+	return c
+}
+
+func checkClipboard() doctorCheck {
+	c := doctorCheck{name: "Clipboard"}
+	if !clipboard.Available() {
+		c.level = checkWarn
+		c.detail = "no clipboard tool found; copying fails"
+		c.fix = "install xclip or xsel"
+		switch {
+		case runtime.GOOS == "windows":
+			c.fix = "install PowerShell (powershell or pwsh on the PATH)"
+		case os.Getenv("WAYLAND_DISPLAY") != "":
+			c.fix = "install wl-clipboard (wl-copy and wl-paste)"
+		}
+		return c
+	}
+	c.detail = "available"
+	return c
+}
+
+func checkAudio() doctorCheck {
+	c := doctorCheck{name: "Audio player"}
+	if !audio.Available() {
+		c.level = checkWarn
+		c.detail = "none found; pronunciations cannot be played"
+		c.fix = "install mpv, ffplay, or mpg123"
+		return c
+	}
+	c.detail = "available"
+	return c
+}
+
+func checkLLM(ping bool) doctorCheck {
+	c := doctorCheck{name: "Anthropic API"}
+	client, err := llm.NewClient()
+	if err != nil {
+		c.level = checkWarn
+		c.detail = "ANTHROPIC_API_KEY not set; prompts cannot be generated with the LLM"
+		c.fix = "export ANTHROPIC_API_KEY with a key from https://console.anthropic.com"
+		return c
+	}
+	if !ping {
+		c.detail = "key set (use --ping to check it works)"
+		return c
+	}
+	if err := client.Ping(); err != nil {
+		c.level = checkFail
+		c.detail = err.Error()
+		c.fix = "check ANTHROPIC_API_KEY and your network connection"
+		return c
+	}
+	c.detail = "key works"
+	return c
+}
+
+func checkSQLite() doctorCheck {
+	c := doctorCheck{name: "SQLite"}
+	version, err := anki.SQLiteVersion()
+	if err != nil {
+		c.level = checkFail
+		c.detail = err.Error()
+		c.fix = "rebuild hmm for this platform; Anki decks cannot be read"
+		return c
+	}
+	c.detail = "driver works (SQLite " + version + ")"
+	return c
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var etymologyCmd = &cobra.Command{
+	Use:   "etymology <character>",
+	Short: "Show how a character was formed, with its phonetic series",
+	Long: `Show the etymology of each character in depth: its type and hint, its
+semantic component (the meaning) and phonetic component (the sound) with
+their own pinyin and meanings, and the phonetic series: the characters
+that share its phonetic component and so, more or less, its sound.
+
+Etymologies come from the Make Me a Hanzi dictionary (see 'hmm doctor').
+
+Examples:
+  hmm etymology 清
+  hmm etymology 媽 --format json`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSceneChars(1),
+	RunE:              runEtymology,
+}
+
+var etymologyFormat string
+
+func init() {
+	rootCmd.AddCommand(etymologyCmd)
+
+	etymologyCmd.Flags().StringVar(&etymologyFormat, "format", "text", "Output format: text, json")
+	etymologyCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// EtymologyResult is the etymology of one character printed by --format
+// json.
+type EtymologyResult struct {
+	Character string          `json:"character"`
+	Type      string          `json:"type,omitempty"`
+	Hint      string          `json:"hint,omitempty"`
+	Semantic  *EtymologyPart  `json:"semantic,omitempty"`
+	Phonetic  *EtymologyPart  `json:"phonetic,omitempty"`
+	Series    []EtymologyPart `json:"phonetic_series,omitempty"`
+	More      int             `json:"more_in_series,omitempty"`
+}
+
+// EtymologyPart is a component or character of an etymology.
+type EtymologyPart struct {
+	Character string `json:"character"`
+	Pinyin    string `json:"pinyin,omitempty"`
+	Meaning   string `json:"meaning,omitempty"`
+}
+
+func runEtymology(cmd *cobra.Command, args []string) error {
+	if etymologyFormat != "text" && etymologyFormat != "json" {
+		return fmt.Errorf("unknown format: %s", etymologyFormat)
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+	if dict.Size() == 0 {
+		return fmt.Errorf("no dictionary to take etymologies from: see 'hmm doctor'")
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	analyzer := analyze.New(dict, prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props))
+
+	var etymologies []analyze.Etymology
+	var missing []string
+	for _, char := range analyze.ChineseChars(strings.Join(args, "")) {
+		if e := analyzer.Etymology(char); e != nil {
+			etymologies = append(etymologies, *e)
+		} else {
+			missing = append(missing, char)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "No etymology for %s\n", strings.Join(missing, " "))
+	}
+
+	if etymologyFormat == "json" {
+		results := []EtymologyResult{}
+		for _, e := range etymologies {
+			results = append(results, etymologyResult(e))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		return nil
+	}
+
+	for i, e := range etymologies {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(e.Text())
+	}
+	return nil
+}
+
+// etymologyResult returns e as printed by --format json.
+func etymologyResult(e analyze.Etymology) EtymologyResult {
+	part := func(p *analyze.EtymologyPart) *EtymologyPart {
+		if p == nil {
+			return nil
+		}
+		return &EtymologyPart{Character: p.Character, Pinyin: p.Pinyin, Meaning: p.Meaning}
+	}
+
+	result := EtymologyResult{
+		Character: e.Character,
+		Type:      e.Type,
+		Hint:      e.Hint,
+		Semantic:  part(e.Semantic),
+		Phonetic:  part(e.Phonetic),
+		More:      e.More,
+	}
+	for _, s := range e.Series {
+		result.Series = append(result.Series, *part(&s))
+	}
+	return result
+}
@@ -0,0 +1,547 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/sheet"
+	"github.com/f3rmion/hmm/internal/site"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export study material",
+	Long:  `Export printable study material made from your configuration and scenes.`,
+}
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "markdown <list.txt|deck.apkg>",
+	Short: "Write a printable study sheet for a character list or deck",
+	Long: `Write a study sheet with every character of a word list or deck: the
+character in large print, its pinyin in tone colors, the HMM breakdown
+(actor, set, room, props), and the story and image of its approved scene.
+
+Characters are grouped by set and ordered by tone within each, so reading
+the sheet walks through each location room by room.
+
+The sheet is Markdown, or a self-contained HTML page laid out for printing
+with --format html or an -o file ending in .html. Image paths are relative
+to the output file.
+
+Examples:
+  hmm export markdown hsk1.txt -o hsk1.md
+  hmm export markdown deck.apkg --field Hanzi -o sheet.html`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportMarkdown,
+}
+
+var exportMapsCmd = &cobra.Command{
+	Use:   "maps [list.txt|deck.apkg]",
+	Short: "Write a printable map of each set with the characters in its rooms",
+	Long: `Write a map of each set of your memory palace: its tone rooms, and in
+each room every character whose scene plays there, in tone colors. Print
+it to see at a glance how full each location is.
+
+The characters come from a word list or deck, or without one from your
+saved scenes (rejected ones left out). With --all, every configured set
+gets a map, even one no character is placed in yet.
+
+The maps are Markdown, or a self-contained HTML page with one set to a
+printed page with --format html or an -o file ending in .html.
+
+Examples:
+  hmm export maps -o palace.html
+  hmm export maps hsk1.txt --all -o hsk1-maps.md`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runExportMaps,
+	SilenceUsage: true,
+}
+
+var exportCastingCmd = &cobra.Command{
+	Use:   "casting",
+	Short: "Write a printable reference poster of your actors, sets, and props",
+	Long: `Write a reference poster of your configuration: actors in a grid by
+category, sets with their tone rooms, and the props of the components
+that appear in the most characters.
+
+The poster is a self-contained HTML page laid out for landscape printing.
+With an -o file ending in .pdf it is printed to PDF with a headless
+Chrome or Chromium, if one is installed; otherwise print the HTML page
+to PDF from a browser.
+
+Examples:
+  hmm export casting -o casting.html
+  hmm export casting --props 100 -o casting.pdf`,
+	Args:         cobra.NoArgs,
+	RunE:         runExportCasting,
+	SilenceUsage: true,
+}
+
+var exportSiteCmd = &cobra.Command{
+	Use:   "site <dir>",
+	Short: "Write your scenes as a static website",
+	Long: `Write every approved scene as a self-contained static website in dir:
+an index by set, actor, and HSK level, and one page per scene with its
+image, story, and breakdown. Images are copied into the site, so the folder
+can be copied to a phone or any web host and read offline.
+
+HSK levels come from the hsk<level> word lists (see 'hmm list').
+
+Examples:
+  hmm export site ./out
+  hmm export site ~/Sync/hanzi --title "My Hanzi"`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runExportSite,
+	SilenceUsage: true,
+}
+
+var (
+	exportOut    string
+	exportFormat string
+	exportField  string
+	exportTitle  string
+	exportProps  int
+	exportAll    bool
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportMarkdownCmd)
+	exportCmd.AddCommand(exportMapsCmd)
+	exportCmd.AddCommand(exportCastingCmd)
+	exportCmd.AddCommand(exportSiteCmd)
+
+	exportMarkdownCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Output file (stdout if not specified)")
+	exportMarkdownCmd.Flags().StringVar(&exportFormat, "format", "", "Sheet format: markdown or html (default: from the -o extension, else markdown)")
+	exportMarkdownCmd.Flags().StringVarP(&exportField, "field", "f", "", "Field with the Chinese characters of a deck (auto-detect if not specified)")
+	exportMarkdownCmd.Flags().StringVar(&exportTitle, "title", "", "Sheet title (default: input file name)")
+	exportMapsCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Output file (stdout if not specified)")
+	exportMapsCmd.Flags().StringVar(&exportFormat, "format", "", "Map format: markdown or html (default: from the -o extension, else markdown)")
+	exportMapsCmd.Flags().StringVarP(&exportField, "field", "f", "", "Field with the Chinese characters of a deck (auto-detect if not specified)")
+	exportMapsCmd.Flags().StringVar(&exportTitle, "title", "", "Title (default: input file name, else \"Memory Palace\")")
+	exportMapsCmd.Flags().BoolVar(&exportAll, "all", false, "Include every configured set, even empty ones")
+	exportCastingCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Output file, .html or .pdf (stdout if not specified)")
+	exportCastingCmd.Flags().StringVar(&exportTitle, "title", "My HMM Casting", "Poster title")
+	exportCastingCmd.Flags().IntVar(&exportProps, "props", 60, "Number of props to show, most used components first (0 for all)")
+	exportSiteCmd.Flags().StringVar(&exportTitle, "title", "HMM Scenes", "Site title")
+
+	exportMarkdownCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"markdown", "html"}, cobra.ShellCompDirectiveNoFileComp))
+	exportMapsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"markdown", "html"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runExportMarkdown(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	format, err := sheetFormat()
+	if err != nil {
+		return err
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	chars, err := sheetChars(path)
+	if err != nil {
+		return err
+	}
+	if len(chars) == 0 {
+		return fmt.Errorf("no Chinese characters found in %s", path)
+	}
+
+	outDir := ""
+	if exportOut != "" {
+		outDir = filepath.Dir(exportOut)
+	}
+	entries := sheetEntries(chars, outDir)
+
+	title := exportTitle
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	var w io.Writer = os.Stdout
+	if exportOut != "" {
+		f, err := os.Create(exportOut)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "html" {
+		err = sheet.WriteHTML(w, title, entries)
+	} else {
+		err = sheet.WriteMarkdown(w, title, entries)
+	}
+	if err != nil {
+		return fmt.Errorf("writing sheet: %w", err)
+	}
+
+	if exportOut != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d characters to %s\n", len(entries), exportOut)
+	}
+	return nil
+}
+
+func runExportMaps(cmd *cobra.Command, args []string) error {
+	format, err := sheetFormat()
+	if err != nil {
+		return err
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	var chars []string
+	title := "Memory Palace"
+	if len(args) > 0 {
+		if chars, err = sheetChars(args[0]); err != nil {
+			return err
+		}
+		title = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+	} else {
+		scenes, err := loadSceneStore()
+		if err != nil {
+			return err
+		}
+		var saved strings.Builder
+		for _, sc := range scenes.All() {
+			if sc.Status != hmm.SceneRejected {
+				saved.WriteString(sc.Character)
+			}
+		}
+		chars = analyze.UniqueChineseChars(saved.String())
+	}
+	if cmd.Flags().Changed("title") {
+		title = exportTitle
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	locations := sheet.Locations(cfg.Sets, sheetEntries(chars, ""), pinyin.NewParser().Tones(), exportAll)
+	if len(locations) == 0 {
+		return fmt.Errorf("no characters to place; pass a word list or deck, or use --all")
+	}
+
+	var w io.Writer = os.Stdout
+	if exportOut != "" {
+		f, err := os.Create(exportOut)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "html" {
+		err = sheet.WriteMapsHTML(w, title, locations)
+	} else {
+		err = sheet.WriteMapsMarkdown(w, title, locations)
+	}
+	if err != nil {
+		return fmt.Errorf("writing maps: %w", err)
+	}
+
+	if exportOut != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d sets to %s\n", len(locations), exportOut)
+	}
+	return nil
+}
+
+// sheetFormat returns the format of a sheet: --format, or else html for an
+// -o file ending in .html and markdown otherwise.
+func sheetFormat() (string, error) {
+	format := exportFormat
+	if format == "" {
+		format = "markdown"
+		if ext := strings.ToLower(filepath.Ext(exportOut)); ext == ".html" || ext == ".htm" {
+			format = "html"
+		}
+	}
+	if format != "markdown" && format != "md" && format != "html" {
+		return "", fmt.Errorf("unknown format: %s", format)
+	}
+	return format, nil
+}
+
+// sheetChars returns the characters of a deck, or of a word list.
+func sheetChars(path string) ([]string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".apkg") {
+		pkg, err := anki.OpenPackage(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening package: %w", err)
+		}
+		defer pkg.Close()
+		return packageChars(pkg, exportField)
+	}
+
+	entries, err := readWordList(path)
+	if err != nil {
+		return nil, err
+	}
+	var words strings.Builder
+	for _, e := range entries {
+		words.WriteString(e.Word)
+	}
+	return analyze.UniqueChineseChars(words.String()), nil
+}
+
+// sheetEntries returns the study sheet entries of chars. Image paths are
+// made relative to outDir, or absolute if it is "".
+func sheetEntries(chars []string, outDir string) []sheet.Entry {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	analyzer := analyze.New(dict, gen)
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	var entries []sheet.Entry
+	for _, char := range chars {
+		h, ok := characterHMM(char, analyzer)
+		if !ok {
+			continue
+		}
+
+		e := sheet.Entry{
+			Character: char,
+			Pinyin:    h.Pinyin,
+			Tone:      h.Tone,
+			Meaning:   h.Meaning,
+			Actor:     h.ActorName,
+			Set:       h.SetName,
+			SetID:     h.SetID,
+			Room:      h.ToneRoom,
+			Props:     h.Props,
+		}
+
+		if sc := approvedScene(scenes, char); sc != nil {
+			e.Story = sc.Script
+			if sc.Image != "" {
+				e.Image = sheetImagePath(scenes.ImagePath(*sc), outDir)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// sheetImagePath returns the path of an image as the sheet links to it.
+func sheetImagePath(path, outDir string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if outDir != "" {
+		if dir, err := filepath.Abs(outDir); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(abs)
+}
+
+func runExportCasting(cmd *cobra.Command, args []string) error {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	props := cfg.Props
+	if exportProps > 0 && len(props) > exportProps {
+		if err := loadDictionary(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary, showing the first %d props: %v\n", exportProps, err)
+		} else {
+			props = topProps(props, dict.ComponentCounts())
+		}
+		props = props[:exportProps]
+	}
+
+	casting := sheet.Casting{
+		Title:  exportTitle,
+		Actors: cfg.Actors,
+		Sets:   cfg.Sets,
+		Props:  props,
+	}
+
+	if strings.EqualFold(filepath.Ext(exportOut), ".pdf") {
+		if err := writeCastingPDF(casting, exportOut); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", exportOut)
+		return nil
+	}
+
+	var w io.Writer = os.Stdout
+	if exportOut != "" {
+		f, err := os.Create(exportOut)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := sheet.WriteCastingHTML(w, casting); err != nil {
+		return fmt.Errorf("writing poster: %w", err)
+	}
+	if exportOut != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", exportOut)
+	}
+	return nil
+}
+
+// topProps returns props ordered by how many characters their component
+// appears in, most first.
+func topProps(props []hmm.Prop, counts map[string]int) []hmm.Prop {
+	sorted := append([]hmm.Prop(nil), props...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return counts[sorted[i].Component] > counts[sorted[j].Component]
+	})
+	return sorted
+}
+
+// pdfBrowsers are the browsers that can print a page to PDF headless.
+var pdfBrowsers = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// writeCastingPDF prints the casting poster to a PDF at out with the first
+// headless browser found.
+func writeCastingPDF(casting sheet.Casting, out string) error {
+	var browser string
+	for _, name := range pdfBrowsers {
+		if path, err := exec.LookPath(name); err == nil {
+			browser = path
+			break
+		}
+	}
+	if browser == "" {
+		return fmt.Errorf("no Chrome or Chromium found to print PDF; write .html and print it from a browser instead")
+	}
+
+	tmp, err := os.CreateTemp("", "hmm-casting-*.html")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := sheet.WriteCastingHTML(tmp, casting); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing poster: %w", err)
+	}
+	tmp.Close()
+
+	abs, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	page := url.URL{Scheme: "file", Path: filepath.ToSlash(tmp.Name())}
+	chrome := exec.Command(browser, "--headless", "--disable-gpu", "--no-pdf-header-footer", "--print-to-pdf="+abs, page.String())
+	if output, err := chrome.CombinedOutput(); err != nil {
+		return fmt.Errorf("printing PDF with %s: %w\n%s", filepath.Base(browser), err, output)
+	}
+	return nil
+}
+
+func runExportSite(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	levels := hskLevels(store.ListsDir())
+
+	var pages []site.Page
+	seen := make(map[string]bool)
+	for _, v := range store.All() {
+		if seen[v.Character] {
+			continue
+		}
+		sc := store.Approved(v.Character)
+		if sc == nil {
+			continue
+		}
+		seen[sc.Character] = true
+
+		p := site.Page{
+			Character: sc.Character,
+			Pinyin:    sc.Pinyin,
+			Tone:      int(sc.Tone),
+			Keyword:   sc.Keyword,
+			ActorID:   sc.ActorID,
+			SetID:     sc.SetID,
+			Story:     sc.Script,
+			Prompt:    sc.ImagePrompt,
+			HSK:       levels[sc.Character],
+		}
+		set := gen.GetSet(sc.SetID)
+		if actor := gen.GetActor(sc.ActorID); actor != nil {
+			p.Actor = actor.Name
+		}
+		if set != nil {
+			p.Set = set.Name
+		}
+		p.Room = gen.GetToneRoom(set, sc.Tone)
+		for _, id := range sc.PropIDs {
+			if prop := gen.GetProp(id); prop != nil && prop.Name != "" {
+				p.Props = append(p.Props, prop.Name)
+			} else {
+				p.Props = append(p.Props, id)
+			}
+		}
+		if image := store.ImagePath(*sc); image != "" {
+			if _, err := os.Stat(image); err == nil {
+				p.Image = image
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: image of %s not found: %s\n", sc.Character, image)
+			}
+		}
+		for _, l := range store.LinksFor(sc.Character) {
+			p.LookAlike = append(p.LookAlike, store.LinkedCharacter(l))
+		}
+		pages = append(pages, p)
+	}
+
+	if len(pages) == 0 {
+		return fmt.Errorf("no approved scenes to export")
+	}
+
+	images, err := site.Write(dir, exportTitle, pages)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d scenes (%d images) to %s\n", len(pages), images, filepath.Join(dir, "index.html"))
+	return nil
+}
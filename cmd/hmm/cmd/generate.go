@@ -7,8 +7,10 @@ import (
 
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
 	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
 	"github.com/spf13/cobra"
 )
 
@@ -26,22 +28,30 @@ The prompt can be used with DALL-E, Midjourney, Stable Diffusion, etc.
 Examples:
   hmm generate 好
   hmm generate 林 --style midjourney
-  hmm generate 中 --reading 1  # Use first reading if multiple`,
-	Args: cobra.MinimumNArgs(1),
-	RunE: runGenerate,
+  hmm generate 中 --reading 1  # Use first reading if multiple
+  hmm generate 行 --all-readings  # One prompt each for háng and xíng
+  hmm generate 好 --save       # Save the scene to the scene store`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSceneChars(1),
+	RunE:              runGenerate,
 }
 
 var (
 	generateStyle   string
 	generateReading int
 	generateVerbose bool
+	generateSave    bool
+	generateAll     bool
 )
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.Flags().StringVarP(&generateStyle, "style", "s", "default", "Prompt style: default, midjourney, dalle, sd")
+	generateCmd.RegisterFlagCompletionFunc("style", cobra.FixedCompletions([]string{"default", "midjourney", "dalle", "sd"}, cobra.ShellCompDirectiveNoFileComp))
 	generateCmd.Flags().IntVarP(&generateReading, "reading", "r", 0, "Which reading to use (0 = first, 1 = second, etc.)")
 	generateCmd.Flags().BoolVarP(&generateVerbose, "verbose", "v", false, "Show detailed breakdown")
+	generateCmd.Flags().BoolVar(&generateSave, "save", false, "Save generated scenes to the scene store")
+	generateCmd.Flags().BoolVar(&generateAll, "all-readings", false, "Generate a prompt for every reading of a polyphonic character")
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -64,37 +74,21 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
 
 	// Set template based on style
-	switch generateStyle {
-	case "midjourney", "mj":
-		if err := gen.SetTemplate(prompt.MidjourneyTemplate); err != nil {
-			return err
-		}
-		gen.SetStyle(prompt.Style{
-			Name:        "cinematic",
-			AspectRatio: "16:9",
-			Suffix:      "",
-		})
-	case "dalle", "openai":
-		if err := gen.SetTemplate(prompt.DALLETemplate); err != nil {
-			return err
-		}
-		gen.SetStyle(prompt.Style{
-			Name:   "digital art",
-			Suffix: "highly detailed, dramatic lighting",
-		})
-	case "sd", "stable-diffusion":
-		if err := gen.SetTemplate(prompt.StableDiffusionTemplate); err != nil {
-			return err
-		}
-		gen.SetStyle(prompt.Style{
-			Name:   "cinematic lighting",
-			Suffix: "8k uhd, detailed",
-		})
+	if err := setPromptStyle(gen, generateStyle); err != nil {
+		return err
 	}
 
 	parser := pinyin.NewParser()
 	input := args[0]
 
+	var store *scene.Store
+	if generateSave {
+		store, err = loadSceneStore()
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, char := range input {
 		charStr := string(char)
 
@@ -110,7 +104,10 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		if readingIdx >= len(readings) {
 			readingIdx = 0
 		}
-		reading := readings[readingIdx]
+		selected := readings[readingIdx : readingIdx+1]
+		if generateAll {
+			selected = readings
+		}
 
 		// Get decomposition info
 		var meaning, etymology, decompStr string
@@ -131,80 +128,171 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Build scene data
-		actorID := pinyin.GetActorID(reading.Initial)
-		setID := pinyin.GetSetID(reading.Final)
-
-		sceneData := gen.BuildSceneData(
-			charStr,
-			reading.Full,
-			actorID,
-			setID,
-			reading.Tone,
-			components,
-			meaning,
-			etymology,
-			decompStr,
-		)
-
-		// Show verbose breakdown if requested
-		if generateVerbose {
-			fmt.Printf("Character: %s (%s)\n", charStr, reading.Full)
-			fmt.Printf("Meaning: %s\n", meaning)
-			fmt.Printf("Components: %v\n", components)
-			fmt.Println()
-			fmt.Printf("HMM Breakdown:\n")
-			fmt.Printf("  Initial: %s → Actor ID: %s", displayInitial(reading.Initial), actorID)
-			if sceneData.Actor != nil && sceneData.Actor.Name != "" {
-				fmt.Printf(" → %s", sceneData.Actor.Name)
-			} else {
-				fmt.Printf(" → (not configured)")
+		for i, reading := range selected {
+			// Build scene data
+			actorID := pinyin.GetActorID(reading.Initial)
+			setID := pinyin.GetSetID(reading.Final)
+
+			sceneData := gen.BuildSceneData(
+				charStr,
+				reading.Full,
+				actorID,
+				setID,
+				reading.Tone,
+				components,
+				meaning,
+				etymology,
+				decompStr,
+			)
+
+			// Label each reading's prompt; verbose output names it anyway
+			if len(selected) > 1 && !generateVerbose {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s (%s):\n", charStr, reading.Full)
+			}
+
+			// Show verbose breakdown if requested
+			if generateVerbose {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("Character: %s (%s)\n", charStr, reading.Full)
+				fmt.Printf("Meaning: %s\n", meaning)
+				fmt.Printf("Components: %v\n", components)
+				fmt.Println()
+				fmt.Printf("HMM Breakdown:\n")
+				fmt.Printf("  Initial: %s → Actor ID: %s", displayInitial(reading.Initial), actorID)
+				if sceneData.Actor != nil && sceneData.Actor.Name != "" {
+					fmt.Printf(" → %s", sceneData.Actor.Name)
+				} else {
+					fmt.Printf(" → (not configured)")
+				}
+				fmt.Println()
+
+				fmt.Printf("  Final: %s → Set ID: %s", displayFinal(reading.Final), setID)
+				if sceneData.Set != nil && sceneData.Set.Name != "" {
+					fmt.Printf(" → %s", sceneData.Set.Name)
+				} else {
+					fmt.Printf(" → (not configured)")
+				}
+				fmt.Println()
+
+				fmt.Printf("  Tone: %d → Room: %s\n", reading.Tone, sceneData.ToneRoom)
+
+				fmt.Printf("  Props:\n")
+				for _, comp := range components {
+					prop := gen.GetProp(comp)
+					if prop != nil && prop.Name != "" {
+						fmt.Printf("    %s → %s\n", comp, prop.Name)
+					} else {
+						fmt.Printf("    %s → (not configured)\n", comp)
+					}
+				}
+				fmt.Println()
+				fmt.Println("Generated Prompt:")
+				fmt.Println("─────────────────")
 			}
-			fmt.Println()
 
-			fmt.Printf("  Final: %s → Set ID: %s", displayFinal(reading.Final), setID)
-			if sceneData.Set != nil && sceneData.Set.Name != "" {
-				fmt.Printf(" → %s", sceneData.Set.Name)
-			} else {
-				fmt.Printf(" → (not configured)")
+			// Generate prompt
+			promptText, err := gen.Generate(sceneData)
+			if err != nil {
+				return fmt.Errorf("generating prompt for %s: %w", charStr, err)
 			}
-			fmt.Println()
 
-			fmt.Printf("  Tone: %d → Room: %s\n", reading.Tone, sceneData.ToneRoom)
+			fmt.Println(promptText)
 
-			fmt.Printf("  Props:\n")
-			for _, comp := range components {
-				prop := gen.GetProp(comp)
-				if prop != nil && prop.Name != "" {
-					fmt.Printf("    %s → %s\n", comp, prop.Name)
-				} else {
-					fmt.Printf("    %s → (not configured)\n", comp)
+			if store != nil {
+				// The first reading is the character's primary scene; the
+				// others are variants keyed by their pinyin
+				var id string
+				if i > 0 {
+					id = charStr + "-" + reading.Full
 				}
+				store.Put(hmm.Scene{
+					ID:          id,
+					Character:   charStr,
+					Pinyin:      reading.Full,
+					Initial:     reading.Initial,
+					Final:       reading.Final,
+					Tone:        reading.Tone,
+					Keyword:     sceneKeyword(charStr, meaning),
+					ActorID:     actorID,
+					SetID:       setID,
+					PropIDs:     components,
+					Script:      gen.Describe(sceneData),
+					ImagePrompt: promptText,
+				})
 			}
+		}
+
+		if len(input) > 1 {
 			fmt.Println()
-			fmt.Println("Generated Prompt:")
-			fmt.Println("─────────────────")
 		}
+	}
 
-		// Generate prompt
-		promptText, err := gen.Generate(sceneData)
-		if err != nil {
-			return fmt.Errorf("generating prompt for %s: %w", charStr, err)
+	if store != nil {
+		if err := store.Save(); err != nil {
+			return err
 		}
+		fmt.Fprintf(os.Stderr, "Saved scenes to %s\n", store.Path())
+	}
 
-		fmt.Println(promptText)
+	return nil
+}
 
-		if len(input) > 1 {
-			fmt.Println()
-		}
+// userGenerator returns a prompt generator with the user's actors, sets,
+// and props, or placeholders if there is no config, in the given style.
+func userGenerator(style string) (*prompt.Generator, error) {
+	gen := prompt.NewGenerator(nil, nil, nil)
+	if cfg, err := loadUserConfig(getConfigDir()); err == nil {
+		gen = prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	} else {
+		fmt.Fprintf(os.Stderr, "Note: Config not found. Run 'hmm init' to create config.\n")
+	}
+	if err := setPromptStyle(gen, style); err != nil {
+		return nil, err
 	}
+	return gen, nil
+}
 
+// setPromptStyle switches gen to the template of an AI art style; other
+// styles keep the default template.
+func setPromptStyle(gen *prompt.Generator, style string) error {
+	switch style {
+	case "midjourney", "mj":
+		if err := gen.SetTemplate(prompt.MidjourneyTemplate); err != nil {
+			return err
+		}
+		gen.SetStyle(prompt.Style{
+			Name:        "cinematic",
+			AspectRatio: "16:9",
+			Suffix:      "",
+		})
+	case "dalle", "openai":
+		if err := gen.SetTemplate(prompt.DALLETemplate); err != nil {
+			return err
+		}
+		gen.SetStyle(prompt.Style{
+			Name:   "digital art",
+			Suffix: "highly detailed, dramatic lighting",
+		})
+	case "sd", "stable-diffusion":
+		if err := gen.SetTemplate(prompt.StableDiffusionTemplate); err != nil {
+			return err
+		}
+		gen.SetStyle(prompt.Style{
+			Name:   "cinematic lighting",
+			Suffix: "8k uhd, detailed",
+		})
+	}
 	return nil
 }
 
 func loadUserConfig(configDir string) (*config.Config, error) {
-	actorsPath := filepath.Join(configDir, "actors.yaml")
-	setsPath := filepath.Join(configDir, "sets.yaml")
+	actorsPath := filepath.Join(tablesDir(configDir), "actors.yaml")
+	setsPath := filepath.Join(tablesDir(configDir), "sets.yaml")
 	propsPath := filepath.Join(configDir, "props.yaml")
 
 	// Check if config files exist
@@ -228,8 +316,9 @@ func loadUserConfig(configDir string) (*config.Config, error) {
 	}
 
 	return &config.Config{
-		Actors: actors,
-		Sets:   sets,
-		Props:  props,
+		Actors:   actors,
+		Sets:     sets,
+		Props:    props,
+		Jyutping: jyutpingMode(),
 	}, nil
 }
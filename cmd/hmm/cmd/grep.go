@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/spf13/cobra"
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <file.apkg> [text]",
+	Short: "Print the notes of a deck that contain some text",
+	Long: `Search an Anki deck without opening the TUI, and print the matching
+notes one per line: the note ID, then its fields separated by tabs, with
+HTML removed. As in Browse, the text is matched without regard to case
+against every field, or only the one given with --field.
+
+--tag keeps notes with that tag; given more than once, notes need all of
+them. Without text, every note with the tags is printed. --json prints a
+JSON object per note instead, for jq and other tools.
+
+Exits with an error if no note matches, like grep.
+
+Examples:
+  hmm grep deck.apkg 好
+  hmm grep deck.apkg 好 --field Hanzi --tag hsk1 --json
+  hmm grep deck.apkg --tag hsk1 | cut -f2`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeApkg,
+	SilenceUsage:      true,
+	RunE:              runGrep,
+}
+
+var (
+	grepField string
+	grepTags  []string
+	grepJSON  bool
+)
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().StringVarP(&grepField, "field", "f", "", "Only search this field")
+	grepCmd.Flags().StringSliceVarP(&grepTags, "tag", "t", nil, "Only notes with this tag (repeatable)")
+	grepCmd.Flags().BoolVar(&grepJSON, "json", false, "Print a JSON object per note")
+}
+
+// grepNote is a matching note as printed by --json.
+type grepNote struct {
+	NoteID int64             `json:"note_id"`
+	Tags   []string          `json:"tags"`
+	Fields map[string]string `json:"fields"`
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	var text string
+	if len(args) > 1 {
+		text = strings.ToLower(args[1])
+	}
+
+	pkg, err := anki.OpenPackage(path)
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	if grepField != "" && !hasField(pkg, grepField) {
+		return fmt.Errorf("no field %q in %s", grepField, path)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+
+	matches := 0
+	for _, note := range pkg.Notes {
+		if !grepMatch(pkg, note, text) {
+			continue
+		}
+		matches++
+
+		names := pkg.GetFieldNames(note)
+		if grepJSON {
+			out := grepNote{NoteID: note.ID, Tags: note.TagList(), Fields: make(map[string]string)}
+			if out.Tags == nil {
+				out.Tags = []string{}
+			}
+			for i, value := range note.Fields {
+				name := fmt.Sprintf("field_%d", i)
+				if i < len(names) {
+					name = names[i]
+				}
+				out.Fields[name] = htmlutil.Text(value)
+			}
+			if err := encoder.Encode(out); err != nil {
+				return fmt.Errorf("encoding JSON: %w", err)
+			}
+			continue
+		}
+
+		// Tabs and line breaks inside a field would split it
+		values := []string{fmt.Sprint(note.ID)}
+		for _, value := range note.Fields {
+			values = append(values, htmlutil.Text(value))
+		}
+		fmt.Println(strings.Join(values, "\t"))
+	}
+
+	if matches == 0 {
+		return fmt.Errorf("no matching notes")
+	}
+	return nil
+}
+
+// grepMatch reports whether note has the --tag tags and contains text
+// (lower case) in the --field field, or in any field.
+func grepMatch(pkg *anki.Package, note *anki.Note, text string) bool {
+	for _, tag := range grepTags {
+		if !note.HasTag(tag) {
+			return false
+		}
+	}
+	if text == "" {
+		return true
+	}
+
+	if grepField != "" {
+		return strings.Contains(strings.ToLower(htmlutil.Text(pkg.GetFieldValue(note, grepField))), text)
+	}
+	for _, value := range note.Fields {
+		if strings.Contains(strings.ToLower(htmlutil.Text(value)), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasField reports whether any note type of pkg has the field name.
+func hasField(pkg *anki.Package, name string) bool {
+	for _, model := range pkg.Models {
+		for _, f := range model.Fields {
+			if strings.EqualFold(f.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
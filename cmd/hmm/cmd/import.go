@@ -0,0 +1,601 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/clipboard"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import vocabulary from other apps",
+	Long: `Import the vocabulary kept in other apps, as draft scenes in the scene
+store (--scenes) to approve in the TUI's Review tab, and/or as a new
+Anki deck (-o) like 'hmm anki create' builds.
+
+Characters that already have a scene are left alone. With --clipboard,
+the list is read from the clipboard instead of a file.`,
+}
+
+var importPlecoCmd = &cobra.Command{
+	Use:   "pleco [file.txt]",
+	Short: "Import a Pleco flashcard export",
+	Long: `Import flashcards exported from Pleco as text (Import / Export > Export
+Cards, "Text" format): one card per line with the headword, pinyin, and
+definition separated by tabs. Headwords written as 简体[繁體] use the
+simplified form; tone numbers (ni3hao3) become tone marks. Pleco category
+lines (//HSK 1) become tags on the deck's notes (Pleco::HSK_1).
+
+Examples:
+  hmm import pleco flashcards.txt --scenes
+  hmm import pleco flashcards.txt -o pleco.apkg --name "Pleco"
+  hmm import pleco --clipboard --scenes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportPleco,
+}
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv [file]",
+	Short: "Import a CSV or TSV vocabulary list",
+	Long: `Import a vocabulary list from a spreadsheet or an app's CSV/TSV export
+(Skritter, Du Chinese, ...). The --col flags say which column holds what,
+by number (1 is the first column) or by header name; only the Chinese
+column is required, missing pinyin and meanings come from the dictionary.
+Tone numbers (ni3hao3) become tone marks; a tags column holds tags
+separated by spaces.
+
+The separator is a tab for .tsv files and files whose first line has a
+tab, otherwise a comma; --delimiter sets another (such as ";"). Rows
+without Chinese in their Chinese column, such as a header, are skipped;
+with --header, or when a column is given by name, the first row always is.
+
+Examples:
+  hmm import csv skritter.csv --col-hanzi 1 --col-pinyin 2 --col-meaning 3 --scenes
+  hmm import csv words.tsv --col-hanzi Simplified --col-meaning English -o words.apkg
+  hmm import csv --clipboard --col-hanzi 1 --col-meaning 2 --scenes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportCSV,
+}
+
+var importRTHCmd = &cobra.Command{
+	Use:   "rth [file]",
+	Short: "Import Remembering the Hanzi keywords",
+	Long: `Import the keywords of Remembering the Hanzi (RTH), shown next to the
+dictionary definition in lookups, the TUI, and the web page, written into
+anki create decks and augment output, and given to new scenes as their
+keyword.
+
+Each line holds a character and its keyword, separated by tabs, commas,
+or spaces, in either order; frame numbers and other columns are skipped,
+so Heisig lists and Anki exports of RTH decks import as they are. Lines
+starting with # are comments. The keywords are merged into those already
+imported (--replace drops those first) and kept in keywords.yaml in the
+config directory.
+
+Examples:
+  hmm import rth rth-keywords.tsv
+  hmm import rth heisig.csv --replace
+  hmm import rth --clipboard`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportRTH,
+}
+
+var (
+	importScenes bool
+	importOut    string
+	importName   string
+	importClip   bool
+
+	importColHanzi   string
+	importColPinyin  string
+	importColMeaning string
+	importColTags    string
+	importHeader     bool
+	importDelimiter  string
+
+	importReplace bool
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importPlecoCmd)
+	importCmd.AddCommand(importCSVCmd)
+	importCmd.AddCommand(importRTHCmd)
+
+	importCmd.PersistentFlags().BoolVar(&importScenes, "scenes", false, "Add a draft scene for every new character to the scene store")
+	importCmd.PersistentFlags().StringVarP(&importOut, "out", "o", "", "Build an Anki deck of the words into this .apkg")
+	importCmd.PersistentFlags().StringVar(&importName, "name", "", "Deck name for -o (default: imported file name)")
+	importCmd.PersistentFlags().BoolVar(&importClip, "clipboard", false, "Read the list from the clipboard instead of a file")
+
+	importCSVCmd.Flags().StringVar(&importColHanzi, "col-hanzi", "1", "Column of the Chinese word (number or header name)")
+	importCSVCmd.Flags().StringVar(&importColPinyin, "col-pinyin", "", "Column of the pinyin (look it up if not given)")
+	importCSVCmd.Flags().StringVar(&importColMeaning, "col-meaning", "", "Column of the meaning (look it up if not given)")
+	importCSVCmd.Flags().StringVar(&importColTags, "col-tags", "", "Column of tags for the deck's notes, separated by spaces")
+	importCSVCmd.Flags().BoolVar(&importHeader, "header", false, "The first row is a header")
+	importCSVCmd.Flags().StringVar(&importDelimiter, "delimiter", "", "Column separator (default: tab for .tsv, else comma)")
+
+	importRTHCmd.Flags().BoolVar(&importReplace, "replace", false, "Replace the keywords imported before instead of adding to them")
+}
+
+func runImportPleco(cmd *cobra.Command, args []string) error {
+	if !importScenes && importOut == "" {
+		return fmt.Errorf("nothing to import into: give --scenes, -o deck.apkg, or both")
+	}
+
+	source, text, err := importInput(args)
+	if err != nil {
+		return err
+	}
+	entries, err := readPleco(text)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no cards found in %s", source)
+	}
+	return importWords(entries, source)
+}
+
+func runImportCSV(cmd *cobra.Command, args []string) error {
+	if !importScenes && importOut == "" {
+		return fmt.Errorf("nothing to import into: give --scenes, -o deck.apkg, or both")
+	}
+
+	source, text, err := importInput(args)
+	if err != nil {
+		return err
+	}
+	entries, err := readVocabCSV(text, source)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no Chinese words found in column %s of %s", importColHanzi, source)
+	}
+	return importWords(entries, source)
+}
+
+func runImportRTH(cmd *cobra.Command, args []string) error {
+	if importScenes || importOut != "" {
+		return fmt.Errorf("RTH keywords are imported into %s; --scenes and -o do not apply", config.KeywordsFile)
+	}
+
+	source, text, err := importInput(args)
+	if err != nil {
+		return err
+	}
+	imported, skipped := readRTH(text)
+	if len(imported) == 0 {
+		return fmt.Errorf("no characters with keywords found in %s", source)
+	}
+
+	path := filepath.Join(getConfigDir(), config.KeywordsFile)
+	keywords := make(map[string]string)
+	if !importReplace {
+		old, err := config.LoadKeywords(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		for char, keyword := range old {
+			keywords[char] = keyword
+		}
+	}
+	for char, keyword := range imported {
+		keywords[char] = keyword
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := config.SaveKeywords(path, keywords); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d keywords into %s (%d in all)\n", len(imported), path, len(keywords))
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d lines without a character and a keyword\n", skipped)
+	}
+	return nil
+}
+
+// importInput returns the text to import and where it came from: the file
+// given as the argument, or the clipboard with --clipboard.
+func importInput(args []string) (source, text string, err error) {
+	if importClip {
+		if len(args) > 0 {
+			return "", "", fmt.Errorf("give a file or --clipboard, not both")
+		}
+		text, err := clipboard.Read()
+		if err != nil {
+			return "", "", fmt.Errorf("reading clipboard: %w", err)
+		}
+		return "clipboard", text, nil
+	}
+
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("give a file to import, or --clipboard")
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return "", "", fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	return args[0], string(data), nil
+}
+
+// importWords adds entries read from source to the scene store and/or a
+// deck, as the import flags say.
+func importWords(entries []wordEntry, source string) error {
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	if importScenes {
+		if err := importSceneDrafts(entries); err != nil {
+			return err
+		}
+	}
+
+	if importOut != "" {
+		name := importName
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+		}
+		if err := writeWordDeck(entries, name, importOut, wordDeckOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importSceneDrafts stores a draft scene, with a template prompt, for
+// every character of entries without a scene yet. A single-character
+// word's pinyin and meaning are used as its reading and keyword; the
+// characters of longer words take them from the dictionary unless the
+// word's pinyin has a syllable for each.
+func importSceneDrafts(entries []wordEntry) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	parser := pinyin.NewParser()
+
+	added, skipped := 0, 0
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		chars := []rune(entry.Word)
+		syllables := strings.Fields(entry.Pinyin)
+
+		for i, r := range chars {
+			char := string(r)
+			if seen[char] {
+				continue
+			}
+			seen[char] = true
+			if store.Get(char) != nil {
+				skipped++
+				continue
+			}
+
+			sc := hmm.Scene{Character: char}
+			if len(syllables) == len(chars) {
+				sc.Pinyin = syllables[i]
+			}
+			if len(chars) == 1 {
+				sc.Keyword = entry.Meaning
+			}
+			completeScene(&sc, parser)
+
+			data := gen.BuildSceneData(sc.Character, sc.Pinyin, sc.ActorID, sc.SetID, sc.Tone, sc.PropIDs, sc.Keyword, "", "")
+			if p, err := gen.Generate(data); err == nil {
+				sc.ImagePrompt = p
+			}
+
+			if store.PutDraft(sc) {
+				added++
+			}
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Added %d draft scenes, %d characters already had a scene; review them in the TUI's Review tab\n", added, skipped)
+	return nil
+}
+
+// readPleco reads the cards of a Pleco text export, skipping repeated
+// words. Category lines tag the cards that follow them.
+func readPleco(text string) ([]wordEntry, error) {
+	var entries []wordEntry
+	seen := make(map[string]bool)
+	var tags []string
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if category, ok := strings.CutPrefix(line, "//"); ok {
+			tags = nil
+			if tag := plecoTag(category); tag != "" {
+				tags = []string{tag}
+			}
+			continue
+		}
+
+		cols := strings.Split(line, "\t")
+		// 简体[繁體]: keep the simplified form
+		headword, _, _ := strings.Cut(cols[0], "[")
+		word := strings.Join(analyze.ChineseRuns(headword), "")
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		e := wordEntry{Word: word, Tags: tags}
+		if len(cols) > 1 {
+			e.Pinyin = markedPinyin(cols[1])
+		}
+		if len(cols) > 2 {
+			e.Meaning = plecoText(strings.Join(cols[2:], " "))
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading Pleco export: %w", err)
+	}
+
+	return entries, nil
+}
+
+// readRTH reads the keywords of an RTH list, one character per line, and
+// counts the lines that have none. A later line for a character replaces
+// an earlier one.
+func readRTH(text string) (map[string]string, int) {
+	keywords := make(map[string]string)
+	skipped := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if char, keyword := rthEntry(line); char != "" && keyword != "" {
+			keywords[char] = keyword
+		} else {
+			skipped++
+		}
+	}
+	return keywords, skipped
+}
+
+// rthEntry returns the character and keyword on a line of an RTH list:
+// the column that is one Chinese character, and the first other column
+// that has neither Chinese nor only digits (frame numbers). On lines
+// separated by spaces, the keyword is all such words.
+func rthEntry(line string) (char, keyword string) {
+	var cols []string
+	spaced := false
+	switch {
+	case strings.Contains(line, "\t"):
+		cols = strings.Split(line, "\t")
+	case strings.Contains(line, ","):
+		r := csv.NewReader(strings.NewReader(line))
+		r.LazyQuotes = true
+		record, err := r.Read()
+		if err != nil {
+			return "", ""
+		}
+		cols = record
+	default:
+		cols = strings.Fields(line)
+		spaced = true
+	}
+
+	for i, col := range cols {
+		cols[i] = htmlutil.Text(col)
+	}
+	for _, col := range cols {
+		if runes := []rune(col); len(runes) == 1 && analyze.IsChinese(runes[0]) {
+			char = col
+			break
+		}
+	}
+	if char == "" {
+		return "", ""
+	}
+
+	var words []string
+	for _, col := range cols {
+		if col == "" || col == char || isDigits(col) || analyze.ContainsChinese(col) {
+			continue
+		}
+		if !spaced {
+			return char, col
+		}
+		words = append(words, col)
+	}
+	return char, strings.Join(words, " ")
+}
+
+// isDigits reports whether s is made of digits only.
+func isDigits(s string) bool {
+	return strings.TrimFunc(s, unicode.IsDigit) == ""
+}
+
+// readVocabCSV reads the words of CSV/TSV text from source with the columns
+// given by the --col flags, skipping repeated words.
+func readVocabCSV(text, source string) ([]wordEntry, error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	r := csv.NewReader(strings.NewReader(text))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	switch {
+	case importDelimiter != "":
+		d := []rune(importDelimiter)
+		if importDelimiter == `\t` {
+			d = []rune{'\t'}
+		}
+		if len(d) != 1 {
+			return nil, fmt.Errorf("delimiter must be a single character, not %q", importDelimiter)
+		}
+		r.Comma = d[0]
+	case strings.EqualFold(filepath.Ext(source), ".tsv"):
+		r.Comma = '\t'
+	default:
+		first, _, _ := strings.Cut(text, "\n")
+		if strings.Contains(first, "\t") {
+			r.Comma = '\t'
+		}
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reading word list: %w", err)
+	}
+
+	// Columns given by name are found in the header, which is skipped
+	var header []string
+	byName := false
+	for _, spec := range []string{importColHanzi, importColPinyin, importColMeaning, importColTags} {
+		if _, err := strconv.Atoi(spec); spec != "" && err != nil {
+			byName = true
+		}
+	}
+	if (importHeader || byName) && len(rows) > 0 {
+		header, rows = rows[0], rows[1:]
+	}
+
+	hanziCol, err := csvColumn(importColHanzi, header)
+	if err != nil {
+		return nil, err
+	}
+	if hanziCol < 0 {
+		return nil, fmt.Errorf("--col-hanzi is required")
+	}
+	pinyinCol, err := csvColumn(importColPinyin, header)
+	if err != nil {
+		return nil, err
+	}
+	meaningCol, err := csvColumn(importColMeaning, header)
+	if err != nil {
+		return nil, err
+	}
+	tagsCol, err := csvColumn(importColTags, header)
+	if err != nil {
+		return nil, err
+	}
+
+	cell := func(row []string, col int) string {
+		if col < 0 || col >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[col])
+	}
+
+	var entries []wordEntry
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		word := strings.Join(analyze.ChineseRuns(cell(row, hanziCol)), "")
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		entries = append(entries, wordEntry{
+			Word:    word,
+			Pinyin:  markedPinyin(cell(row, pinyinCol)),
+			Meaning: cell(row, meaningCol),
+			Tags:    strings.Fields(cell(row, tagsCol)),
+		})
+	}
+	return entries, nil
+}
+
+// csvColumn returns the index of the column spec names: a number counting
+// from 1, or a header name. An empty spec is no column, -1.
+func csvColumn(spec string, header []string) (int, error) {
+	if spec == "" {
+		return -1, nil
+	}
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 1 {
+			return -1, fmt.Errorf("column numbers start at 1, not %d", n)
+		}
+		return n - 1, nil
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), spec) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no column %q in the header (%s)", spec, strings.Join(header, ", "))
+}
+
+// markedPinyin returns pinyin with tone marks and a space between
+// syllables when it was written with tone numbers (ni3hao3 is nǐ hǎo).
+// Jyutping is written with tone numbers, so it is kept as it is.
+func markedPinyin(s string) string {
+	if jyutpingMode() {
+		return strings.TrimSpace(s)
+	}
+	if syllables := pinyin.NumberedSyllables(s); len(syllables) > 0 {
+		return pinyin.FromNumbers(strings.Join(syllables, " "))
+	}
+	return strings.TrimSpace(s)
+}
+
+// plecoText removes the formatting Pleco writes into definitions as
+// private-use characters, and collapses the space left behind.
+func plecoText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Co, r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// plecoTag turns a Pleco category path (HSK/HSK 1) into an Anki tag
+// (Pleco::HSK::HSK_1).
+func plecoTag(category string) string {
+	var parts []string
+	for _, p := range strings.Split(category, "/") {
+		if p = strings.Join(strings.Fields(p), "_"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Pleco::" + strings.Join(parts, "::")
+}
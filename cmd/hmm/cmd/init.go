@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/f3rmion/hmm/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +19,11 @@ This creates template YAML files for:
   - sets.yaml     (13 pinyin finals → locations)
   - props.yaml    (character components → objects)
 
+With --romanization jyutping, it also creates the Cantonese tables in
+jyutping/: actors.yaml (19 Jyutping initials) and sets.yaml (56 finals,
+with a room for each of the 6 tones), adding them to an existing config.
+Props are shared.
+
 You should then edit these files to add your personal actors, sets, and props.`,
 	RunE: runInit,
 }
@@ -31,6 +37,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
 	configDir := getConfigDir()
 
+	// Add the Cantonese tables to a config made for Mandarin
+	jyutpingDir := filepath.Join(configDir, config.JyutpingDir)
+	if _, err := os.Stat(jyutpingDir); jyutpingMode() && os.IsNotExist(err) && !force {
+		if _, err := os.Stat(configDir); err == nil {
+			if err := writeJyutpingTables(configDir, false); err != nil {
+				return err
+			}
+			fmt.Printf("Created the Cantonese actors and sets in %s\n", jyutpingDir)
+			return nil
+		}
+	}
+
 	// Check if config already exists
 	if _, err := os.Stat(configDir); err == nil && !force {
 		return fmt.Errorf("config directory already exists: %s\nUse --force to overwrite", configDir)
@@ -51,6 +69,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Created %s\n", file)
 	}
+	if jyutpingMode() {
+		if err := writeJyutpingTables(configDir, true); err != nil {
+			return err
+		}
+		fmt.Printf("  Created %s and %s\n",
+			filepath.Join(config.JyutpingDir, "actors.yaml"), filepath.Join(config.JyutpingDir, "sets.yaml"))
+	}
 
 	fmt.Println()
 	fmt.Println("Configuration initialized!")
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/f3rmion/hmm/internal/config"
@@ -36,15 +37,10 @@ func init() {
 func runInteractive(cmd *cobra.Command, args []string) error {
 	// Load dictionary
 	dict := decomp.NewDictionary()
-	dictPaths := []string{
-		"data/dictionary.jsonl",
-		"/usr/local/share/hmm/dictionary.jsonl",
-	}
-	for _, path := range dictPaths {
-		if _, err := os.Stat(path); err == nil {
-			if err := dict.LoadFromFile(path); err == nil {
-				break
-			}
+	loadKeywords(dict)
+	if path := dictionaryPath(); path != "" {
+		if err := dict.LoadFromFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 	}
 
@@ -59,13 +55,28 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 		cfg = &config.Config{}
 	}
 
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	applyTheme(configDir)
+	setCacheDirs()
+	quietLogging()
+
 	// Create and run unified TUI
+	app := tui.NewApp(dict, cfg, scenes)
+	if err := app.LoadUIState(filepath.Join(configDir, config.UIStateFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	p := tea.NewProgram(
-		tui.NewApp(dict, cfg),
+		app,
 		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	if err := runProgram(p); err != nil {
 		return fmt.Errorf("running TUI: %w", err)
 	}
 
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/spf13/viper"
+)
+
+// setupLanguage makes prompts, and the scenes and stories asked of the
+// LLM, come out in the language chosen with --language or HMM_LANGUAGE:
+// English by default, or German, Spanish, or French.
+func setupLanguage() error {
+	name := viper.GetString("language")
+	if name == "" {
+		return nil
+	}
+	lang, ok := prompt.LookupLanguage(name)
+	if !ok {
+		return fmt.Errorf("unknown language %q (use %s)", name, strings.Join(prompt.Languages(), ", "))
+	}
+	prompt.SetLanguage(lang)
+	llm.SetLanguage(lang.Name)
+	return nil
+}
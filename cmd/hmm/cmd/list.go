@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show and manage word lists",
+	Long: `Word lists are the characters and words to learn: lists built into hmm
+(hsk1 to hsk9 and frequency, from internal/scene/lists) and your own
+lists in the lists directory, one word per line.
+
+Without a subcommand, every list is shown with how many of its
+characters have a scene. The learn, quiz, today, anki create, and stats
+commands take a list by name (e.g. --list hsk1); a list of your own with
+the name of a built-in one replaces it.
+
+Examples:
+  hmm list
+  hmm list show hsk1 --missing
+  hmm list add week3 电脑 朋友 学生
+  hmm list remove week3 朋友`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+var listShowCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Show the words of a list and how many have scenes",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeListNames,
+	RunE:              runListShow,
+}
+
+var listAddCmd = &cobra.Command{
+	Use:   "add <name> [word...]",
+	Short: "Add words to a list of your own, creating it if needed",
+	Long: `Add words to a list in the lists directory, one per line, creating the
+list if it does not exist. Words already on the list are skipped. Without
+words on the command line, they are read from stdin.
+
+Examples:
+  hmm list add week3 电脑 朋友
+  pbpaste | hmm list add week3`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeListNames,
+	RunE:              runListAdd,
+}
+
+var listRemoveCmd = &cobra.Command{
+	Use:   "remove <name> [word...]",
+	Short: "Remove words from a list of your own, or the whole list",
+	Long: `Remove words from a list in the lists directory. Without words, the
+list itself is deleted. Built-in lists cannot be changed; add a list of
+the same name to replace one.
+
+Examples:
+  hmm list remove week3 朋友
+  hmm list remove week3`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeListNames,
+	RunE:              runListRemove,
+}
+
+var listShowMissing bool
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.AddCommand(listShowCmd)
+	listCmd.AddCommand(listAddCmd)
+	listCmd.AddCommand(listRemoveCmd)
+
+	listShowCmd.Flags().BoolVar(&listShowMissing, "missing", false, "Show only the characters without a scene")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+	lists, err := scene.AllTargetLists(store.ListsDir())
+	if err != nil {
+		return err
+	}
+	if len(lists) == 0 {
+		fmt.Printf("No word lists. Add one with 'hmm list add' or put .txt files into %s\n", store.ListsDir())
+		return nil
+	}
+
+	for _, l := range lists {
+		cov := store.Coverage(l.Chars)
+		kind := "yours"
+		if l.Builtin {
+			kind = "built-in"
+		}
+		fmt.Printf("%-12s %-8s %5d words %5d characters %3d%% with scenes\n",
+			l.Name, kind, len(l.Words()), cov.Total, cov.Percent())
+	}
+	return nil
+}
+
+func runListShow(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+	list, err := scene.FindTargetList(store.ListsDir(), args[0])
+	if err != nil {
+		return err
+	}
+
+	cov := store.Coverage(list.Chars)
+	if listShowMissing {
+		fmt.Println(strings.Join(cov.Missing, " "))
+		return nil
+	}
+
+	words := list.Words()
+	fmt.Printf("%s: %d words, %d characters (%d with scenes, %d drafts only, %d missing)\n\n",
+		list.Name, len(words), cov.Total, len(cov.Complete), len(cov.DraftOnly), len(cov.Missing))
+	for _, w := range words {
+		fmt.Println(w)
+	}
+	return nil
+}
+
+func runListAdd(cmd *cobra.Command, args []string) error {
+	path, err := ownListPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	var text string
+	if len(args) > 1 {
+		text = strings.Join(args[1:], "\n")
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+
+	var have []string
+	if data, err := os.ReadFile(path); err == nil {
+		have = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	onList := make(map[string]bool)
+	for _, line := range have {
+		onList[strings.TrimSpace(line)] = true
+	}
+
+	added := 0
+	for _, word := range analyze.ChineseRuns(text) {
+		if !onList[word] {
+			onList[word] = true
+			have = append(have, word)
+			added++
+		}
+	}
+	if added == 0 {
+		fmt.Println("No new words to add.")
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(have, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Added %d word(s) to %s\n", added, path)
+	return nil
+}
+
+func runListRemove(cmd *cobra.Command, args []string) error {
+	path, err := ownListPath(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no list of your own called %s", scene.ListName(args[0]))
+	}
+
+	if len(args) == 1 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", path)
+		return nil
+	}
+
+	remove := make(map[string]bool)
+	for _, word := range args[1:] {
+		remove[strings.TrimSpace(word)] = true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var kept []string
+	removed := 0
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if remove[strings.TrimSpace(line)] {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		fmt.Println("None of the words are on the list.")
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(kept, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d word(s) from %s\n", removed, path)
+	return nil
+}
+
+// ownListPath returns the file of the user's list called name, refusing
+// names that are only built in.
+func ownListPath(name string) (string, error) {
+	name = scene.ListName(name)
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid list name %q", name)
+	}
+	path := filepath.Join(listsDir(), name+".txt")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		for _, l := range scene.BuiltinLists() {
+			if l.Name == name {
+				return "", fmt.Errorf("%s is a built-in list; pick another name, or copy it with 'hmm list show %s | hmm list add <name>'", name, name)
+			}
+		}
+	}
+	return path, nil
+}
+
+// listsDir returns the directory target lists are kept in.
+func listsDir() string {
+	if store, err := loadSceneStore(); err == nil {
+		return store.ListsDir()
+	}
+	return filepath.Join(getConfigDir(), scene.ListsDirName)
+}
+
+// namedList returns the list given as nameOrPath: a file, or else a list
+// of that name in the lists directory or built in.
+func namedList(nameOrPath string) (scene.TargetList, error) {
+	if _, err := os.Stat(nameOrPath); err == nil {
+		return scene.ReadTargetList(nameOrPath)
+	}
+	return scene.FindTargetList(listsDir(), nameOrPath)
+}
+
+// hskList returns the list of HSK level, of your own or built in.
+func hskList(level int) (scene.TargetList, error) {
+	return scene.FindTargetList(listsDir(), fmt.Sprintf("hsk%d", level))
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// logFile is where --log-file sends the log, "" for stderr.
+var logFile string
+
+// setupLogging sets the default slog logger from -v and --log-file. The
+// log goes to stderr: warnings only, -v adds progress (dictionary, decks,
+// LLM requests), -vv debug details such as SQL statements. --log-file
+// writes everything, debug details included, to a file instead, for
+// attaching to bug reports.
+func setupLogging(cmd *cobra.Command, args []string) error {
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("opening log file: %w", err)
+		}
+		handler := slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})
+		slog.SetDefault(slog.New(handler).With("cmd", cmd.CommandPath()))
+		return nil
+	}
+
+	level := slog.LevelWarn
+	switch v := verbosity(cmd); {
+	case v >= 2:
+		level = slog.LevelDebug
+	case v == 1:
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	return nil
+}
+
+// verbosity returns how often -v was given. Commands with a -v flag of
+// their own (generate's detailed breakdown) count as -v when it is set.
+func verbosity(cmd *cobra.Command) int {
+	if f := cmd.Flags().Lookup("verbose"); f != nil && f.Value.Type() == "bool" {
+		if f.Changed && f.Value.String() == "true" {
+			return 1
+		}
+		return 0
+	}
+	return viper.GetInt("verbose")
+}
+
+// quietLogging stops logging to stderr while a full-screen TUI runs, where
+// it would garble the display. A --log-file keeps logging.
+func quietLogging() {
+	if logFile == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+	}
+}
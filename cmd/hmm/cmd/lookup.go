@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/f3rmion/hmm/internal/cedict"
+	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
 	"github.com/f3rmion/hmm/internal/hmm"
 	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
 	"github.com/spf13/cobra"
 )
 
@@ -23,17 +29,71 @@ var lookupCmd = &cobra.Command{
   - Character components (props)
   - Etymology
 
+With --format json, the full breakdown is printed as a JSON array with
+one object per character, including the names of your configured actors,
+sets, and props, for scripts and editors to use.
+
 Example:
   hmm lookup 好
-  hmm lookup 中国`,
-	Args: cobra.MinimumNArgs(1),
-	RunE: runLookup,
+  hmm lookup 中国
+  hmm lookup 好 --format json`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSceneChars(1),
+	RunE:              runLookup,
 }
 
 var dict *decomp.Dictionary
 
+// wordDict is CC-CEDICT, for the readings and meanings of words.
+var wordDict *cedict.Dictionary
+
+var lookupFormat string
+
 func init() {
 	rootCmd.AddCommand(lookupCmd)
+
+	lookupCmd.Flags().StringVar(&lookupFormat, "format", "text", "Output format: text, json")
+	lookupCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// LookupResult is the breakdown of one character printed by --format json.
+type LookupResult struct {
+	Character     string             `json:"character"`
+	Meaning       string             `json:"meaning,omitempty"`
+	Keyword       string             `json:"keyword,omitempty"`
+	Decomposition string             `json:"decomposition,omitempty"`
+	Radical       string             `json:"radical,omitempty"`
+	Etymology     *decomp.Etymology  `json:"etymology,omitempty"`
+	Components    []string           `json:"components,omitempty"`
+	Props         []LookupProp       `json:"props,omitempty"`
+	Confusables   []LookupConfusable `json:"confusables,omitempty"`
+	Readings      []LookupReading    `json:"readings"`
+}
+
+// LookupConfusable is a character easily mistaken for the one looked up.
+type LookupConfusable struct {
+	Character string `json:"character"`
+	Reason    string `json:"reason"`
+}
+
+// LookupReading is the HMM breakdown of one reading of a character.
+type LookupReading struct {
+	Pinyin    string `json:"pinyin"`
+	Toneless  string `json:"toneless"`
+	Initial   string `json:"initial"`
+	Final     string `json:"final"`
+	Tone      int    `json:"tone"`
+	ActorID   string `json:"actor_id"`
+	ActorName string `json:"actor_name,omitempty"`
+	SetID     string `json:"set_id"`
+	SetName   string `json:"set_name,omitempty"`
+	ToneRoom  string `json:"tone_room"`
+}
+
+// LookupProp is a component and the prop configured for it, if any.
+type LookupProp struct {
+	Component string `json:"component"`
+	Name      string `json:"name,omitempty"`
 }
 
 func loadDictionary() error {
@@ -42,25 +102,68 @@ func loadDictionary() error {
 	}
 
 	dict = decomp.NewDictionary()
+	loadKeywords(dict)
+	if path := dictionaryPath(); path != "" {
+		return dict.LoadFromFile(path)
+	}
+
+	// Dictionary not found - that's okay, we just won't show decomposition
+	return nil
+}
 
-	// Try to find dictionary file
-	paths := []string{
-		"data/dictionary.jsonl",
-		filepath.Join(getConfigDir(), "dictionary.jsonl"),
+// dictionaryPath returns the first dictionary file found in the data
+// directories, or "" if there is none.
+func dictionaryPath() string {
+	for _, dir := range getDataDirs() {
+		path := filepath.Join(dir, "dictionary.jsonl")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
 	}
+	return ""
+}
 
-	// Also check relative to executable
-	if exe, err := os.Executable(); err == nil {
-		paths = append(paths, filepath.Join(filepath.Dir(exe), "data", "dictionary.jsonl"))
+// loadKeywords sets the RTH keywords imported with 'hmm import rth' on d,
+// warning if they cannot be read.
+func loadKeywords(d *decomp.Dictionary) {
+	keywords, err := config.LoadKeywords(filepath.Join(getConfigDir(), config.KeywordsFile))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return
 	}
+	d.SetKeywords(keywords)
+}
 
-	for _, path := range paths {
-		if _, err := os.Stat(path); err == nil {
-			return dict.LoadFromFile(path)
+// sceneKeyword returns the keyword a new scene of char is given: its RTH
+// keyword, or else meaning.
+func sceneKeyword(char, meaning string) string {
+	if dict != nil {
+		if k := dict.Keyword(char); k != "" {
+			return k
 		}
 	}
+	return meaning
+}
 
-	// Dictionary not found - that's okay, we just won't show decomposition
+// loadWordDictionary loads CC-CEDICT into wordDict from the data
+// directories. wordDict stays nil if there is no CC-CEDICT file.
+func loadWordDictionary() error {
+	if wordDict != nil {
+		return nil
+	}
+	for _, dir := range getDataDirs() {
+		path := filepath.Join(dir, cedict.FileName)
+		if _, err := os.Stat(path); err == nil {
+			d := cedict.New()
+			if err := d.LoadFromFile(path); err != nil {
+				return err
+			}
+			wordDict = d
+			return nil
+		}
+	}
 	return nil
 }
 
@@ -74,6 +177,14 @@ func runLookup(cmd *cobra.Command, args []string) error {
 
 	input := args[0]
 
+	switch lookupFormat {
+	case "text":
+	case "json":
+		return printLookupJSON(input, parser)
+	default:
+		return fmt.Errorf("unknown format: %s", lookupFormat)
+	}
+
 	fmt.Printf("Looking up: %s\n\n", input)
 
 	for _, char := range input {
@@ -84,10 +195,14 @@ func runLookup(cmd *cobra.Command, args []string) error {
 
 		// Show dictionary info if available
 		if dict != nil {
-			if entry := dict.Lookup(charStr); entry != nil {
-				if entry.Definition != "" {
-					fmt.Printf("  Meaning: %s\n", entry.Definition)
-				}
+			entry := dict.Lookup(charStr)
+			if entry != nil && entry.Definition != "" {
+				fmt.Printf("  Meaning: %s\n", entry.Definition)
+			}
+			if keyword := dict.Keyword(charStr); keyword != "" {
+				fmt.Printf("  RTH keyword: %s\n", keyword)
+			}
+			if entry != nil {
 				if entry.Decomposition != "" && entry.Decomposition != "？" {
 					fmt.Printf("  Structure: %s\n", decomp.FormatDecomposition(entry.Decomposition))
 					components := decomp.ExtractComponents(entry.Decomposition)
@@ -112,11 +227,18 @@ func runLookup(cmd *cobra.Command, args []string) error {
 					fmt.Printf("  Radical: %s\n", entry.Radical)
 				}
 			}
+			if confusables := dict.Confusables(charStr, 5); len(confusables) > 0 {
+				var parts []string
+				for _, c := range confusables {
+					parts = append(parts, fmt.Sprintf("%s (%s)", c.Entry.Character, c.Reason))
+				}
+				fmt.Printf("  Don't confuse with: %s\n", strings.Join(parts, ", "))
+			}
 		}
 
 		// Show pinyin breakdown
 		if readings == nil {
-			fmt.Printf("  Pinyin: (not found)\n")
+			fmt.Printf("  %s: (not found)\n", readingLabel())
 		} else {
 			fmt.Println("  ---")
 			fmt.Println("  HMM Breakdown:")
@@ -124,7 +246,7 @@ func runLookup(cmd *cobra.Command, args []string) error {
 				if i > 0 {
 					fmt.Println("  ---")
 				}
-				fmt.Printf("    Pinyin:  %s\n", r.Full)
+				fmt.Printf("    %-8s %s\n", readingLabel()+":", r.Full)
 				fmt.Printf("    Initial: %s → Actor: %s\n", displayInitial(r.Initial), pinyin.GetActorID(r.Initial))
 				fmt.Printf("    Final:   %s → Set: %s\n", displayFinal(r.Final), pinyin.GetSetID(r.Final))
 				fmt.Printf("    Tone:    %d → Room: %s\n", r.Tone, toneRoomName(r.Tone))
@@ -136,6 +258,80 @@ func runLookup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printLookupJSON prints the breakdown of every character of input as a
+// JSON array.
+func printLookupJSON(input string, parser *pinyin.Parser) error {
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lookupResults(input, parser, gen)); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// lookupResults returns the breakdown of every character of input, with
+// the names gen has configured.
+func lookupResults(input string, parser *pinyin.Parser, gen *prompt.Generator) []LookupResult {
+	results := []LookupResult{}
+	for _, char := range input {
+		charStr := string(char)
+		result := LookupResult{Character: charStr, Readings: []LookupReading{}}
+
+		if dict != nil {
+			result.Keyword = dict.Keyword(charStr)
+			for _, c := range dict.Confusables(charStr, 5) {
+				result.Confusables = append(result.Confusables, LookupConfusable{Character: c.Entry.Character, Reason: c.Reason})
+			}
+			if entry := dict.Lookup(charStr); entry != nil {
+				result.Meaning = entry.Definition
+				result.Radical = entry.Radical
+				result.Etymology = entry.Etymology
+				if entry.Decomposition != "？" {
+					result.Decomposition = entry.Decomposition
+				}
+				result.Components = decomp.ExtractComponents(entry.Decomposition)
+			}
+		}
+		for _, comp := range result.Components {
+			prop := LookupProp{Component: comp}
+			if p := gen.GetProp(comp); p != nil {
+				prop.Name = p.Name
+			}
+			result.Props = append(result.Props, prop)
+		}
+
+		for _, r := range parser.ParseChar(charStr) {
+			reading := LookupReading{
+				Pinyin:   r.Full,
+				Toneless: r.Toneless,
+				Initial:  r.Initial,
+				Final:    r.Final,
+				Tone:     int(r.Tone),
+				ActorID:  pinyin.GetActorID(r.Initial),
+				SetID:    pinyin.GetSetID(r.Final),
+			}
+			if a := gen.GetActor(reading.ActorID); a != nil {
+				reading.ActorName = a.Name
+			}
+			set := gen.GetSet(reading.SetID)
+			if set != nil {
+				reading.SetName = set.Name
+			}
+			reading.ToneRoom = gen.GetToneRoom(set, r.Tone)
+			result.Readings = append(result.Readings, reading)
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
 func displayInitial(initial string) string {
 	if initial == "" {
 		return "Ø (null)"
@@ -162,6 +358,9 @@ func toneRoomName(tone hmm.Tone) string {
 		return "Bathroom/Backyard"
 	case 5:
 		return "Roof"
+	case 6:
+		// Jyutping's sixth tone
+		return "Basement"
 	default:
 		return "Unknown"
 	}
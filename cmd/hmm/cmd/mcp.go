@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/mcp"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve HMM tools to AI agents over the Model Context Protocol",
+	Long: `Run a Model Context Protocol server on stdin/stdout, so Claude Desktop
+and other agents can use your personal HMM configuration as tools:
+  - lookup:         pinyin and HMM breakdown (actor, set, room, props)
+  - decompose:      a character's component tree with meanings and props
+  - generate_scene: an image prompt from the template or the LLM,
+                    optionally saved to the scene store
+  - augment_deck:   write HMM fields into an Anki deck
+
+The agent starts this command itself. For Claude Desktop, add to
+claude_desktop_config.json:
+
+  "mcpServers": {
+    "hmm": { "command": "hmm", "args": ["mcp"] }
+  }
+
+Messages are logged to stderr; stdout carries only the protocol.`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+// decomposeMaxDepth limits how deep the decompose tool follows components.
+const decomposeMaxDepth = 5
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load dictionary: %v\n", err)
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; names of actors, sets, and props are missing\n", err)
+		cfg = &config.Config{}
+	}
+
+	t := &mcpTools{cfg: cfg, parser: pinyin.NewParser()}
+
+	server := mcp.NewServer("hmm", "1.0.0")
+	server.Add(mcp.Tool{
+		Name:        "lookup",
+		Description: "Look up Chinese characters: readings, meaning, decomposition, and the HMM breakdown of each reading (actor from the initial, set from the final, room from the tone, props from the components) in the user's configuration.",
+		InputSchema: objectSchema(map[string]any{
+			"characters": stringProp("One or more Chinese characters, e.g. 好 or 中国"),
+		}, "characters"),
+		Handler: t.lookup,
+	})
+	server.Add(mcp.Tool{
+		Name:        "decompose",
+		Description: "Break a Chinese character down into its components, recursively, with each component's meaning and the prop the user assigned to it.",
+		InputSchema: objectSchema(map[string]any{
+			"character": stringProp("A single Chinese character"),
+			"depth": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("How many levels to follow components (1-%d, default %d)", decomposeMaxDepth, decomposeMaxDepth),
+			},
+		}, "character"),
+		Handler: t.decompose,
+	})
+	server.Add(mcp.Tool{
+		Name:        "generate_scene",
+		Description: "Generate the image prompt for the HMM movie scene of each character, from the user's actors, sets, rooms, and props. Returns one prompt per character.",
+		InputSchema: objectSchema(map[string]any{
+			"characters": stringProp("One or more Chinese characters"),
+			"style": map[string]any{
+				"type":        "string",
+				"enum":        []string{"default", "midjourney", "dalle", "sd"},
+				"description": "Prompt template for an image generator",
+			},
+			"use_llm": map[string]any{
+				"type":        "boolean",
+				"description": "Have the Anthropic API write a vivid scene instead of filling in the template (needs ANTHROPIC_API_KEY)",
+			},
+			"save": map[string]any{
+				"type":        "boolean",
+				"description": "Save the scenes to the user's scene store",
+			},
+		}, "characters"),
+		Handler: t.generateScene,
+	})
+	server.Add(mcp.Tool{
+		Name:        "augment_deck",
+		Description: "Write HMM fields (actor, set, room, props, image prompt) into the notes of an Anki .apkg deck, saved as a new deck. Approved scenes provide the prompts where they exist.",
+		InputSchema: objectSchema(map[string]any{
+			"path":   stringProp("Path of the .apkg deck"),
+			"field":  stringProp("Field containing the Chinese characters (auto-detected if omitted)"),
+			"output": stringProp("Path of the deck to write (default: <deck>_hmm.apkg next to it)"),
+		}, "path"),
+		Handler: t.augmentDeck,
+	})
+
+	fmt.Fprintf(os.Stderr, "hmm MCP server ready (config: %s)\n", getConfigDir())
+	return server.Serve(os.Stdin, os.Stdout)
+}
+
+// objectSchema returns the JSON Schema of an object with properties, of
+// which required must be given.
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func stringProp(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// mcpTools are the handlers of the MCP tools.
+type mcpTools struct {
+	cfg    *config.Config
+	parser *pinyin.Parser
+}
+
+// generator returns a prompt generator for the user's configuration.
+func (t *mcpTools) generator() *prompt.Generator {
+	return prompt.NewGenerator(t.cfg.Actors, t.cfg.Sets, t.cfg.Props)
+}
+
+func (t *mcpTools) lookup(raw json.RawMessage) (string, error) {
+	var args struct {
+		Characters string `json:"characters"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Characters) == "" {
+		return "", fmt.Errorf("characters is required")
+	}
+	return toJSON(lookupResults(strings.TrimSpace(args.Characters), t.parser, t.generator()))
+}
+
+// DecompNode is a component in the tree returned by the decompose tool.
+type DecompNode struct {
+	Character     string       `json:"character"`
+	Meaning       string       `json:"meaning,omitempty"`
+	Decomposition string       `json:"decomposition,omitempty"`
+	Prop          string       `json:"prop,omitempty"`
+	Components    []DecompNode `json:"components,omitempty"`
+}
+
+func (t *mcpTools) decompose(raw json.RawMessage) (string, error) {
+	var args struct {
+		Character string `json:"character"`
+		Depth     int    `json:"depth"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	chars := []rune(strings.TrimSpace(args.Character))
+	if len(chars) != 1 {
+		return "", fmt.Errorf("character must be a single character")
+	}
+	if dict == nil || dict.Size() == 0 {
+		return "", fmt.Errorf("no dictionary loaded; run 'hmm doctor' for how to install one")
+	}
+	if args.Depth <= 0 || args.Depth > decomposeMaxDepth {
+		args.Depth = decomposeMaxDepth
+	}
+	return toJSON(t.decomposeNode(string(chars[0]), args.Depth, t.generator()))
+}
+
+// decomposeNode returns char with its components down to depth levels.
+func (t *mcpTools) decomposeNode(char string, depth int, gen *prompt.Generator) DecompNode {
+	node := DecompNode{Character: char}
+	if p := gen.GetProp(char); p != nil {
+		node.Prop = p.Name
+	}
+
+	entry := dict.Lookup(char)
+	if entry == nil {
+		return node
+	}
+	node.Meaning = entry.Definition
+	if entry.Decomposition != "？" {
+		node.Decomposition = decomp.FormatDecomposition(entry.Decomposition)
+	}
+	if depth <= 1 {
+		return node
+	}
+	for _, comp := range decomp.ExtractComponents(entry.Decomposition) {
+		// Radicals decompose into themselves
+		if comp == char {
+			continue
+		}
+		node.Components = append(node.Components, t.decomposeNode(comp, depth-1, gen))
+	}
+	return node
+}
+
+func (t *mcpTools) generateScene(raw json.RawMessage) (string, error) {
+	var args struct {
+		Characters string `json:"characters"`
+		Style      string `json:"style"`
+		UseLLM     bool   `json:"use_llm"`
+		Save       bool   `json:"save"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+
+	gen := t.generator()
+	if err := setPromptStyle(gen, args.Style); err != nil {
+		return "", err
+	}
+
+	var client *llm.Client
+	if args.UseLLM {
+		c, err := llm.NewClient()
+		if err != nil {
+			return "", err
+		}
+		client = c
+	}
+
+	analyzer := analyze.New(dict, gen)
+	var out strings.Builder
+	var scenes []hmm.Scene
+	for _, char := range strings.TrimSpace(args.Characters) {
+		sc, err := generatedScene(string(char), analyzer, client)
+		if err != nil {
+			return "", err
+		}
+		if sc == nil {
+			fmt.Fprintf(&out, "%s: no pinyin found\n\n", string(char))
+			continue
+		}
+
+		fmt.Fprintf(&out, "%s (%s):\n%s\n\n", sc.Character, sc.Pinyin, sc.ImagePrompt)
+		scenes = append(scenes, *sc)
+	}
+
+	if args.Save && len(scenes) > 0 {
+		store, err := loadSceneStore()
+		if err != nil {
+			return "", err
+		}
+		for _, sc := range scenes {
+			store.Put(sc)
+		}
+		if err := store.Save(); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&out, "Saved %d scene(s) to %s\n", len(scenes), store.Path())
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// generatedScene returns the scene of char with an image prompt from
+// client, or from the template if client is nil. It returns nil if char
+// has no pinyin.
+func generatedScene(char string, analyzer *analyze.Analyzer, client *llm.Client) (*hmm.Scene, error) {
+	r := analyzer.Character(char)
+	if r == nil {
+		return nil, nil
+	}
+
+	var promptText string
+	var err error
+	if client != nil {
+		promptText, err = client.GenerateScene(analyzer.SceneElements(*r))
+	} else {
+		promptText, err = analyzer.Prompt(*r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generating prompt for %s: %w", char, err)
+	}
+
+	sc := r.Scene(promptText)
+	return &sc, nil
+}
+
+func (t *mcpTools) augmentDeck(raw json.RawMessage) (string, error) {
+	var args struct {
+		Path   string `json:"path"`
+		Field  string `json:"field"`
+		Output string `json:"output"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", err
+	}
+	if args.Path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	pkg, err := anki.OpenPackage(args.Path)
+	if err != nil {
+		return "", fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	field := args.Field
+	if field == "" {
+		field = detectChineseField(pkg)
+		if field == "" {
+			return "", fmt.Errorf("could not auto-detect field with Chinese characters; pass field")
+		}
+	}
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	gen := t.generator()
+	results := augmentNotes(pkg, field, analyze.New(dict, gen), scenes)
+
+	output := args.Output
+	if output == "" {
+		ext := filepath.Ext(args.Path)
+		output = strings.TrimSuffix(args.Path, ext) + "_hmm" + ext
+	}
+	if err := writeAugmentedApkg(pkg, results, gen, output, args.Path); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Augmented %d of %d notes (field %q) and wrote %s with the fields: %s",
+		len(results), len(pkg.Notes), field, output, strings.Join(anki.HMMFields, ", ")), nil
+}
+
+// toJSON returns v as indented JSON.
+func toJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding JSON: %w", err)
+	}
+	return string(data), nil
+}
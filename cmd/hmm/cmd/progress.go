@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+	"github.com/spf13/viper"
+)
+
+// progressLine redraws a progress bar on stderr while a command works
+// through a deck. It stays quiet when stderr is not a terminal, so logs
+// and pipes only get the summary lines.
+type progressLine struct {
+	label string
+	bar   components.Progress
+	shown bool
+}
+
+// newProgressLine returns a progress line labelled label, drawn in the
+// configured theme.
+func newProgressLine(label string) *progressLine {
+	t, _ := theme.Resolve(viper.GetString("theme"), getConfigDir())
+	if viper.GetBool("no_color") || os.Getenv("NO_COLOR") != "" {
+		t.Plain = true
+	}
+	return &progressLine{label: label, bar: components.NewProgress(30, t)}
+}
+
+// update redraws the line with done of total finished.
+func (p *progressLine) update(done, total int) {
+	if !stderrIsTerminal() {
+		return
+	}
+	p.shown = true
+	fmt.Fprintf(os.Stderr, "\r%s %s", p.label, p.bar.View(done, total))
+}
+
+// clear erases the line, so the summary that follows starts clean.
+func (p *progressLine) clear() {
+	if p.shown {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		p.shown = false
+	}
+}
+
+// stderrIsTerminal reports whether stderr is a terminal rather than a file
+// or pipe.
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
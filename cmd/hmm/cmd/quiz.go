@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/quiz"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var quizCmd = &cobra.Command{
+	Use:   "quiz",
+	Short: "Quiz yourself on tones, pinyin, meanings, or look-alikes",
+	Long: `Quiz yourself on characters without opening the TUI.
+
+The characters come from one of:
+  --deck   an Anki .apkg (the Chinese field is auto-detected)
+  --list   a word list by name (see 'hmm list') or a file (any text; its
+           Chinese characters are used)
+  --hsk    an HSK level, the list hsk<level>
+
+Modes:
+  tone     the reading is shown without its tone; answer 1-5 (1-6 in Jyutping)
+  pinyin   answer the reading, as hao3 or hǎo
+  meaning  pick the meaning out of four
+  pair     the two-character words of the source are shown without their
+           tones; answer the tone pair, as 32 or 3-2, and see which two
+           tone rooms the scene moves between. Words are drawn from each
+           of the 20 tone pairs in turn
+  confusable  the reading and meaning are shown; pick the character out
+           of it and the characters that look like it
+
+Answer q to stop early; the score of the questions answered so far is
+shown at the end.
+
+Examples:
+  hmm quiz --hsk 1
+  hmm quiz --deck chinese.apkg --mode pinyin -n 10
+  hmm quiz --list words.txt --mode meaning
+  hmm quiz --hsk 2 --mode pair
+  hmm quiz --list week3 --mode confusable`,
+	Args: cobra.NoArgs,
+	RunE: runQuiz,
+}
+
+var (
+	quizDeck  string
+	quizField string
+	quizList  string
+	quizHSK   int
+	quizMode  string
+	quizCount int
+)
+
+func init() {
+	rootCmd.AddCommand(quizCmd)
+
+	quizCmd.Flags().StringVar(&quizDeck, "deck", "", "Anki .apkg to quiz on")
+	quizCmd.Flags().StringVarP(&quizField, "field", "f", "", "Deck field containing Chinese characters (auto-detect if not specified)")
+	quizCmd.Flags().StringVar(&quizList, "list", "", "Word list (name or file) to quiz on")
+	quizCmd.Flags().IntVar(&quizHSK, "hsk", 0, "HSK level to quiz on")
+	quizCmd.Flags().StringVarP(&quizMode, "mode", "m", "tone", "Quiz mode: tone, pinyin, meaning, pair, confusable")
+	quizCmd.Flags().IntVarP(&quizCount, "count", "n", 20, "Number of questions (0 for all)")
+
+	quizCmd.RegisterFlagCompletionFunc("deck", completeApkgFlag)
+	quizCmd.RegisterFlagCompletionFunc("list", completeListNames)
+	quizCmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions([]string{"tone", "pinyin", "meaning", "pair", "confusable"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runQuiz(cmd *cobra.Command, args []string) error {
+	mode, err := quiz.ParseMode(quizMode)
+	if err != nil {
+		return err
+	}
+
+	text, source, err := quizText()
+	if err != nil {
+		return err
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	var questions []quiz.Question
+	var gen *prompt.Generator
+	if mode == quiz.ModePair {
+		if gen, err = userGenerator("default"); err != nil {
+			return err
+		}
+		questions, err = quiz.Pairs(scene.HanWords(text, 2), pinyin.NewParser(), quizCount)
+	} else {
+		questions, err = quiz.New(mode, analyze.UniqueChineseChars(text), pinyin.NewParser(), dict, quizCount)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Quiz (%s): %d questions from %s\n\n", mode, len(questions), source)
+
+	in := bufio.NewScanner(os.Stdin)
+	var asked, correct int
+	var missed []quiz.Question
+	for i, q := range questions {
+		fmt.Printf("[%d/%d]", i+1, len(questions))
+		if !q.Hidden {
+			fmt.Printf(" %s", q.Char)
+		}
+		if q.Prompt != "" {
+			fmt.Printf("  %s", q.Prompt)
+		}
+		fmt.Println()
+		for j, c := range q.Choices {
+			fmt.Printf("  %d. %s\n", j+1, c)
+		}
+		fmt.Printf("%s: ", q.Ask)
+
+		if !in.Scan() {
+			fmt.Println()
+			break
+		}
+		answer := strings.TrimSpace(in.Text())
+		if answer == "q" {
+			break
+		}
+
+		asked++
+		if q.Check(answer) {
+			correct++
+			fmt.Print("✓ Correct\n")
+		} else {
+			missed = append(missed, q)
+			fmt.Printf("✗ %s\n", q.Solution)
+		}
+		if gen != nil && len(q.Readings) == 2 {
+			fmt.Printf("  Rooms: %s → %s\n", toneRoom(gen, q.Readings[0]), toneRoom(gen, q.Readings[1]))
+		}
+		fmt.Println()
+	}
+
+	printQuizScore(asked, correct, missed)
+	return nil
+}
+
+// quizText returns the text to quiz on from the one source given by the
+// flags, and a description of that source.
+func quizText() (string, string, error) {
+	sources := 0
+	for _, set := range []bool{quizDeck != "", quizList != "", quizHSK != 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return "", "", fmt.Errorf("give one of --deck, --list, or --hsk")
+	}
+
+	switch {
+	case quizDeck != "":
+		text, err := deckText(quizDeck, quizField)
+		return text, filepath.Base(quizDeck), err
+	case quizList != "":
+		list, err := namedList(quizList)
+		return list.Text, list.Name, err
+	}
+
+	list, err := hskList(quizHSK)
+	return list.Text, fmt.Sprintf("HSK %d", quizHSK), err
+}
+
+// deckText returns the text of the Chinese field of the deck at path,
+// one note per line.
+func deckText(path, field string) (string, error) {
+	pkg, err := anki.OpenPackage(path)
+	if err != nil {
+		return "", fmt.Errorf("opening package: %w", err)
+	}
+	defer pkg.Close()
+
+	return packageText(pkg, field)
+}
+
+// packageChars returns the unique Chinese characters in the field of
+// every note of pkg, detecting the field if it is "".
+func packageChars(pkg *anki.Package, field string) ([]string, error) {
+	text, err := packageText(pkg, field)
+	if err != nil {
+		return nil, err
+	}
+	return analyze.UniqueChineseChars(text), nil
+}
+
+// packageText returns the field of every note of pkg as text, one note
+// per line, detecting the field if it is "".
+func packageText(pkg *anki.Package, field string) (string, error) {
+	if field == "" {
+		field = detectChineseField(pkg)
+		if field == "" {
+			return "", fmt.Errorf("could not auto-detect field with Chinese characters. Use --field to specify")
+		}
+	}
+
+	var text strings.Builder
+	for _, note := range pkg.Notes {
+		text.WriteString(htmlutil.Text(pkg.GetFieldValue(note, field)))
+		text.WriteString("\n")
+	}
+	return text.String(), nil
+}
+
+// toneRoom returns the room of the set a reading's scene is in, for its
+// tone.
+func toneRoom(gen *prompt.Generator, r pinyin.ParsedPinyin) string {
+	return gen.GetToneRoom(gen.GetSet(pinyin.GetSetID(r.Final)), r.Tone)
+}
+
+// printQuizScore prints the score of the questions answered and the
+// characters to go over again.
+func printQuizScore(asked, correct int, missed []quiz.Question) {
+	if asked == 0 {
+		fmt.Println("No questions answered.")
+		return
+	}
+
+	fmt.Printf("Score: %d/%d (%d%%)\n", correct, asked, correct*100/asked)
+	if len(missed) > 0 {
+		fmt.Println("Go over again:")
+		for _, q := range missed {
+			fmt.Printf("  %s  %s\n", q.Char, q.Solution)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/recognize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var recognizeCmd = &cobra.Command{
+	Use:   "recognize [file]",
+	Short: "Look up a character drawn by hand, via an external recognizer",
+	Long: `Look up a character you cannot type by drawing it. The drawing is
+recognized by an external handwriting recognizer, such as hanzi-lookup,
+and the characters it may be are listed with their pinyin and meaning.
+
+Without --with, the input (file, or stdin) is what a recognizer wrote:
+hanzi-lookup JSON, a JSON list of characters, or plain text. Pipe the
+recognizer into hmm:
+
+  hanzi-lookup < strokes.json | hmm recognize
+
+With --with, the input is the drawing itself, a JSON list of strokes with
+each stroke a list of [x, y] points, and hmm hands it to the recognizer:
+a command, given the strokes on stdin, or an http(s):// endpoint, sent
+{"strokes": [...]} in a POST. --with can also be set with HMM_RECOGNIZER.
+
+With --lookup, the best candidate, or the one chosen with --pick, is
+looked up as by 'hmm lookup'.
+
+Examples:
+  hanzi-lookup < strokes.json | hmm recognize --lookup
+  hmm recognize strokes.json --with hanzi-lookup
+  hmm recognize strokes.json --with http://localhost:8080/recognize --pick 2 --lookup`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runRecognize,
+	SilenceUsage: true,
+}
+
+var (
+	recognizeLookup bool
+	recognizePick   int
+	recognizeFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(recognizeCmd)
+
+	recognizeCmd.Flags().String("with", "", "Recognizer to send strokes to: a command, or an http(s):// endpoint")
+	recognizeCmd.Flags().BoolVar(&recognizeLookup, "lookup", false, "Look up the chosen candidate")
+	recognizeCmd.Flags().IntVar(&recognizePick, "pick", 1, "Candidate to look up, 1 for the best")
+	recognizeCmd.Flags().StringVar(&recognizeFormat, "format", "text", "Output format: text, json")
+	recognizeCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	viper.BindPFlag("recognizer", recognizeCmd.Flags().Lookup("with"))
+}
+
+// RecognizeCandidate is a candidate printed by --format json.
+type RecognizeCandidate struct {
+	Character string  `json:"character"`
+	Score     float64 `json:"score,omitempty"`
+	Pinyin    string  `json:"pinyin,omitempty"`
+	Meaning   string  `json:"meaning,omitempty"`
+}
+
+func runRecognize(cmd *cobra.Command, args []string) error {
+	if recognizeFormat != "text" && recognizeFormat != "json" {
+		return fmt.Errorf("unknown format: %s", recognizeFormat)
+	}
+
+	var input []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		input, err = io.ReadAll(os.Stdin)
+	} else {
+		input, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+
+	var candidates []recognize.Candidate
+	if spec := viper.GetString("recognizer"); spec != "" {
+		strokes, err := recognize.ParseStrokes(input)
+		if err != nil {
+			return err
+		}
+		if len(strokes) == 0 {
+			return fmt.Errorf("no strokes to recognize")
+		}
+		provider, err := recognize.New(spec)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+		defer cancel()
+		if candidates, err = provider.Recognize(ctx, strokes); err != nil {
+			return fmt.Errorf("recognizing: %w", err)
+		}
+	} else if candidates, err = recognize.ParseCandidates(input); err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("the recognizer found no characters")
+	}
+
+	if recognizeLookup {
+		if recognizePick < 1 || recognizePick > len(candidates) {
+			return fmt.Errorf("--pick %d: there are %d candidates", recognizePick, len(candidates))
+		}
+		lookupFormat = recognizeFormat
+		return runLookup(cmd, []string{candidates[recognizePick-1].Char})
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+	parser := pinyin.NewParser()
+
+	var results []RecognizeCandidate
+	for _, c := range candidates {
+		r := RecognizeCandidate{Character: c.Char, Score: c.Score}
+		if readings := parser.ParseChar(c.Char); len(readings) > 0 {
+			r.Pinyin = readings[0].Full
+		}
+		if dict != nil {
+			if entry := dict.Lookup(c.Char); entry != nil {
+				r.Meaning = entry.Definition
+			}
+		}
+		results = append(results, r)
+	}
+
+	if recognizeFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	for i, r := range results {
+		line := fmt.Sprintf("%2d. %s  %-8s %s", i+1, r.Character, r.Pinyin, r.Meaning)
+		if r.Score != 0 {
+			line += fmt.Sprintf(" (%.2f)", r.Score)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/jyutping"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/spf13/viper"
+)
+
+// jyutpingMode reports whether Cantonese was chosen with --romanization
+// or HMM_ROMANIZATION.
+func jyutpingMode() bool {
+	return strings.EqualFold(viper.GetString("romanization"), "jyutping")
+}
+
+// jyutpingTable is the table of Cantonese readings in use, or nil.
+var jyutpingTable *jyutping.Table
+
+// setupRomanization makes characters read in the romanization chosen with
+// --romanization or HMM_ROMANIZATION: Mandarin pinyin by default, or
+// Jyutping from the jyutping.txt in the data directories.
+func setupRomanization() error {
+	switch strings.ToLower(viper.GetString("romanization")) {
+	case "", "pinyin":
+		return nil
+	case "jyutping":
+	default:
+		return fmt.Errorf("unknown romanization %q (use pinyin or jyutping)", viper.GetString("romanization"))
+	}
+
+	path := jyutpingPath()
+	if path == "" {
+		return fmt.Errorf("no %s with Cantonese readings found; save Unihan_Readings.txt from https://www.unicode.org/Public/UCD/latest/ucd/Unihan.zip as %s",
+			jyutping.FileName, filepath.Join(userDataDir(), jyutping.FileName))
+	}
+	t, err := jyutping.Load(path)
+	if err != nil {
+		return err
+	}
+	jyutpingTable = t
+	pinyin.SetBackend(t)
+	return nil
+}
+
+// jyutpingPath returns the first table of Cantonese readings found in the
+// data directories, or "" if there is none.
+func jyutpingPath() string {
+	for _, dir := range getDataDirs() {
+		path := filepath.Join(dir, jyutping.FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// readingLabel names the romanization readings are written in.
+func readingLabel() string {
+	if jyutpingMode() {
+		return "Jyutping"
+	}
+	return "Pinyin"
+}
+
+// tablesDir returns the directory actors.yaml and sets.yaml are read from:
+// the config directory, or its jyutping directory in Cantonese mode.
+func tablesDir(configDir string) string {
+	if jyutpingMode() {
+		return filepath.Join(configDir, config.JyutpingDir)
+	}
+	return configDir
+}
+
+// writeJyutpingTables writes empty Cantonese actors and sets, one per
+// Jyutping initial and final, to the jyutping directory of configDir.
+// With force unset, tables that exist are kept.
+func writeJyutpingTables(configDir string, force bool) error {
+	dir := filepath.Join(configDir, config.JyutpingDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	actorsPath := filepath.Join(dir, "actors.yaml")
+	if _, err := os.Stat(actorsPath); force || os.IsNotExist(err) {
+		actors := []hmm.Actor{{ID: "null", Category: hmm.ActorNull}}
+		for _, initial := range jyutping.Initials {
+			category := hmm.ActorMale
+			switch initial {
+			case "j":
+				// j is the y of yi-like syllables, like Mandarin y
+				category = hmm.ActorFemale
+			case "w", "gw", "kw":
+				// Initials with a u glide, like Mandarin w and gu
+				category = hmm.ActorFictional
+			}
+			actors = append(actors, hmm.Actor{ID: initial, Initial: initial, Category: category})
+		}
+		if err := config.SaveActors(actorsPath, actors); err != nil {
+			return err
+		}
+	}
+
+	setsPath := filepath.Join(dir, "sets.yaml")
+	if _, err := os.Stat(setsPath); force || os.IsNotExist(err) {
+		rooms := []string{"Outside entrance", "Kitchen", "Bedroom", "Bathroom", "Roof", "Basement"}
+		var sets []hmm.Set
+		for _, final := range jyutping.Finals {
+			set := hmm.Set{ID: final, Final: final}
+			for i, name := range rooms {
+				set.Rooms = append(set.Rooms, hmm.ToneRoom{Tone: hmm.Tone(i + 1), Name: name})
+			}
+			sets = append(sets, set)
+		}
+		if err := config.SaveSets(setsPath, sets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -8,9 +8,15 @@ import (
 	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/audio"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
 	"github.com/f3rmion/hmm/internal/tui"
+	"github.com/f3rmion/hmm/internal/tui/bigchar"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -32,22 +38,72 @@ The system maps:
 
 Each character becomes a memorable movie scene combining these elements.
 
-Running 'hmm' without arguments launches the interactive TUI.`,
-	RunE: runUnifiedTUI,
+Running 'hmm' without arguments launches the interactive TUI.
+
+With --stdio, hmm instead reads one JSON request per line from stdin and
+answers each with one JSON line on stdout, for editor plugins:
+  {"id": 1, "method": "lookup", "text": "好"}
+  {"id": 2, "method": "generate", "text": "好", "style": "midjourney"}
+  {"id": 3, "method": "ping"}`,
+	PersistentPreRunE: setup,
+	RunE:              runUnifiedTUI,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// A deck that cannot be opened gets a suggestion of what to do about it.
+// Decks still open are closed when it returns or hmm is interrupted, so
+// their temp directories do not pile up.
 func Execute() error {
-	return rootCmd.Execute()
+	closePackagesOnSignal()
+	defer anki.CloseAll()
+
+	err := rootCmd.Execute()
+	if suggestion := anki.Suggestion(err); suggestion != "" {
+		fmt.Fprintln(os.Stderr, suggestion)
+	}
+	return err
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config directory (default is $HOME/.config/hmm)")
-	rootCmd.PersistentFlags().Bool("verbose", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config directory (default is $XDG_CONFIG_HOME/hmm or ~/.config/hmm, %APPDATA%\\hmm on Windows)")
+	rootCmd.PersistentFlags().String("data-dir", "", "directory with the dictionary (default is $XDG_DATA_HOME/hmm, else the system data directories)")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "verbose output on stderr (-v progress and timings, -vv debug details)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "write a debug log to this file instead of stderr")
+	rootCmd.PersistentFlags().String("romanization", "", "readings the scenes are built from: pinyin (Mandarin, the default) or jyutping (Cantonese)")
+	rootCmd.PersistentFlags().String("language", "", "language of generated prompts, scenes, and stories: en (the default), de, es, or fr")
+	rootCmd.PersistentFlags().String("theme", "", "TUI theme: dark, light, high-contrast, or a theme file (default is theme.yaml in the config directory, else dark)")
+
+	rootCmd.PersistentFlags().Bool("no-color", false, "TUI without color or box drawing, for screen readers (also set by NO_COLOR)")
+	rootCmd.Flags().BoolVar(&stdioMode, "stdio", false, "answer JSON lookup/generate requests line by line on stdin/stdout, for editor plugins")
+	rootCmd.Flags().StringVar(&sessionLogDir, "session-log", "", "log the characters looked at and write a dated Markdown summary to this directory on quit")
 
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("data_dir", rootCmd.PersistentFlags().Lookup("data-dir"))
+	viper.BindPFlag("romanization", rootCmd.PersistentFlags().Lookup("romanization"))
+	viper.BindPFlag("language", rootCmd.PersistentFlags().Lookup("language"))
+	viper.BindPFlag("theme", rootCmd.PersistentFlags().Lookup("theme"))
+	viper.BindPFlag("no_color", rootCmd.PersistentFlags().Lookup("no-color"))
+}
+
+// setup prepares logging, the romanization, and the language of prompts
+// before any command runs.
+// doctor and init run without the Cantonese readings, so doctor can report
+// them missing and init can write the Cantonese tables first.
+func setup(cmd *cobra.Command, args []string) error {
+	if err := setupLogging(cmd, args); err != nil {
+		return err
+	}
+	if err := setupRomanization(); err != nil && cmd != doctorCmd && cmd != initCmd {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if err := setupLanguage(); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	return nil
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -55,13 +111,11 @@ func initConfig() {
 	if cfgFile != "" {
 		viper.Set("config_dir", cfgFile)
 	} else {
-		home, err := os.UserHomeDir()
+		configDir, err := config.GetConfigDir()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Error finding home directory:", err)
 			os.Exit(1)
 		}
-
-		configDir := filepath.Join(home, ".config", "hmm")
 		viper.Set("config_dir", configDir)
 	}
 
@@ -74,43 +128,134 @@ func getConfigDir() string {
 	return viper.GetString("config_dir")
 }
 
+// userDataDir returns the data directory to put data files such as the
+// dictionary in: the one set by --data-dir or HMM_DATA_DIR, or else the
+// user's own.
+func userDataDir() string {
+	if dir := viper.GetString("data_dir"); dir != "" {
+		return dir
+	}
+	if dir, err := config.GetDataDir(); err == nil {
+		return dir
+	}
+	return getConfigDir()
+}
+
+// getDataDirs returns the directories data files such as the dictionary
+// are looked for in, in order: only the one set by --data-dir or
+// HMM_DATA_DIR, or else ./data (a source checkout), the data directories
+// of config.DataDirs, the config directory (where older versions looked),
+// and data next to the executable.
+func getDataDirs() []string {
+	if dir := viper.GetString("data_dir"); dir != "" {
+		return []string{dir}
+	}
+
+	dirs := append([]string{"data"}, config.DataDirs()...)
+	dirs = append(dirs, getConfigDir())
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Join(filepath.Dir(exe), "data"))
+	}
+	return dirs
+}
+
+// applyTheme styles the TUI with the theme chosen by --theme or HMM_THEME,
+// or the user's theme file. With --no-color, HMM_NO_COLOR, or NO_COLOR
+// (https://no-color.org) it drops color and uses the plain layout.
+func applyTheme(configDir string) {
+	t, err := theme.Resolve(viper.GetString("theme"), configDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if viper.GetBool("no_color") || os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		t.Plain = true
+	}
+	tui.SetTheme(t)
+}
+
+// setCacheDirs points the TUI at its caches in the user cache directory
+// (~/.cache/hmm on Linux): rendered big characters, so they are only
+// rendered once, and pronunciation recordings named after their character.
+func setCacheDirs() {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return
+	}
+	bigchar.SetCacheDir(filepath.Join(dir, "hmm", "bigchar"))
+	audio.SetCacheDir(filepath.Join(dir, "hmm", "audio"))
+}
+
 // runUnifiedTUI launches the unified TUI application.
 func runUnifiedTUI(cmd *cobra.Command, args []string) error {
+	if stdioMode {
+		return runStdio()
+	}
+
 	// Ensure config directory is set up
 	configDir := getConfigDir()
 	ensureConfigSetup(configDir)
 
 	// Load dictionary
 	dict := decomp.NewDictionary()
-	dictPaths := []string{
-		"data/dictionary.jsonl",
-		"/usr/local/share/hmm/dictionary.jsonl",
-	}
-	for _, path := range dictPaths {
-		if _, err := os.Stat(path); err == nil {
-			if err := dict.LoadFromFile(path); err == nil {
-				break
-			}
+	loadKeywords(dict)
+	if path := dictionaryPath(); path != "" {
+		if err := dict.LoadFromFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 	}
 
-	// Load user config from ~/.config/hmm/
+	// Load user config from the config directory
 	cfg, err := loadUserConfig(configDir)
 	if err != nil {
 		// Config not available, use empty config
 		cfg = &config.Config{}
 	}
 
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	applyTheme(configDir)
+	setCacheDirs()
+	quietLogging()
+
 	// Create and run unified TUI
+	app := tui.NewApp(dict, cfg, scenes)
+	if err := app.LoadUIState(filepath.Join(configDir, config.UIStateFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if sessionLogDir != "" {
+		app.LogSession(sessionLogDir)
+	}
+
 	p := tea.NewProgram(
-		tui.NewApp(dict, cfg),
+		app,
 		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	if err := runProgram(p); err != nil {
 		return fmt.Errorf("running TUI: %w", err)
 	}
 
+	return writeSessionLog(app)
+}
+
+// sessionLogDir is set by --session-log, which logs the characters looked
+// at in the TUI for a summary of the session.
+var sessionLogDir string
+
+// writeSessionLog writes the summary of a logged session and says where.
+func writeSessionLog(app tui.AppModel) error {
+	path, err := app.WriteSessionLog()
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		fmt.Fprintf(os.Stderr, "Session log written to %s\n", path)
+	}
 	return nil
 }
 
@@ -138,6 +283,10 @@ func ensureConfigSetup(configDir string) {
 		}
 	}
 
+	if jyutpingMode() {
+		writeJyutpingTables(configDir, false)
+	}
+
 	// Copy example Anki deck if it doesn't exist
 	ankiDest := filepath.Join(ankiDir, "All_214_Chinese_Radicals.apkg")
 	if _, err := os.Stat(ankiDest); os.IsNotExist(err) {
@@ -0,0 +1,588 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var sceneCmd = &cobra.Command{
+	Use:   "scene",
+	Short: "Manage saved HMM scenes",
+	Long: `Commands for working with the scene store.
+
+Scenes are saved with 'hmm generate --save' or 'hmm scene import' and
+kept in scenes.yaml inside your config directory.`,
+}
+
+var sceneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved scenes",
+	Args:  cobra.NoArgs,
+	RunE:  runSceneList,
+}
+
+var sceneExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export saved scenes",
+	Long: `Export saved scenes to files.
+
+The markdown format writes one note per character with YAML frontmatter
+(pinyin, tone, actor, set, room, props), the story, the prompt, and an
+image embed. The output directory can be a folder inside an Obsidian vault.
+
+Examples:
+  hmm scene export --format markdown --out ./vault/hanzi/`,
+	Args: cobra.NoArgs,
+	RunE: runSceneExport,
+}
+
+var sceneImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import scenes from a YAML, CSV, or TSV file",
+	Long: `Import scenes written by hand or exported from another tool.
+
+YAML files use the same layout as scenes.yaml. CSV/TSV files need a header
+row; only the 'character' column is required, other columns (pinyin,
+keyword, script, image_prompt, prop_ids, ...) are optional. Missing pinyin,
+actor, set, and props are filled in from the dictionary.
+
+When a character already has a scene, --on-conflict decides what happens:
+  keep        keep the existing scene (default)
+  overwrite   replace it with the imported one
+  duplicate   store the import as an additional variant
+
+Examples:
+  hmm scene import my-scenes.yaml
+  hmm scene import scenes.csv --on-conflict overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSceneImport,
+}
+
+var sceneSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write approved scenes into an Anki deck",
+	Long: `Turn approved scenes into Anki notes.
+
+Without --deck a fresh .apkg is built with one "HMM Scene" note per scene.
+With --deck the HMM fields of an existing deck are updated: notes are
+matched by the character in --field (auto-detected if not given).
+
+Each scene remembers the GUID of its note, so after editing a scene run
+sync again and re-import the deck to update the same note in Anki.
+
+Examples:
+  hmm scene sync --out hmm-scenes.apkg
+  hmm scene sync --deck my-deck.apkg --out my-deck_hmm.apkg`,
+	Args: cobra.NoArgs,
+	RunE: runSceneSync,
+}
+
+var sceneLinkCmd = &cobra.Command{
+	Use:   "link <a> <b>",
+	Short: "Link two look-alike scenes",
+	Long: `Link two scenes that are easily confused, with a note on how to tell
+them apart. The link is shown in both characters' detail views and
+included in Markdown and Anki exports.
+
+Examples:
+  hmm scene link 未 末 --note "未: short top stroke, not yet grown; 末: long top stroke, the tip"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSceneChars(2),
+	RunE:              runSceneLink,
+}
+
+var sceneUnlinkCmd = &cobra.Command{
+	Use:               "unlink <a> <b>",
+	Short:             "Remove the link between two scenes",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSceneChars(2),
+	RunE:              runSceneUnlink,
+}
+
+var sceneSearchCmd = &cobra.Command{
+	Use:   "search <words>",
+	Short: "Search scene stories and prompts",
+	Long: `Find scenes by what happens in them, useful when you remember the
+image but not the character. Every word must appear in the scene's
+story, prompt, keyword, or look-alike notes (case-insensitive).
+
+Examples:
+  hmm scene search explosion
+  hmm scene search "red dress"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSceneSearch,
+}
+
+var scenePackCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Share scenes as packs",
+	Long: `Scene packs are zip archives of scenes keyed by character. Actor,
+set, room, and prop names are stored as placeholders ({{actor}}, {{set}},
+{{room}}, {{prop:女}}), so a pack never contains your personal
+configuration and is re-resolved against the installer's own actors and
+sets.`,
+}
+
+var scenePackCreateCmd = &cobra.Command{
+	Use:   "create <pack.zip>",
+	Short: "Create a pack from your approved scenes",
+	Long: `Create a scene pack from your approved scenes. The names of your
+actors, sets, rooms, and props are replaced by placeholders; images,
+review status, and Anki mappings are left out.
+
+Examples:
+  hmm scene pack create hsk1-starter.zip --name "HSK 1 starter" --author me`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenePackCreate,
+}
+
+var scenePackInstallCmd = &cobra.Command{
+	Use:   "install <pack.zip>",
+	Short: "Install a scene pack",
+	Long: `Install a scene pack. Each scene's actor and set are derived from
+its reading and the placeholders are filled with your own names.
+Installed scenes are drafts for the Review view unless --approve is given.
+
+Examples:
+  hmm scene pack install hsk1-starter.zip
+  hmm scene pack install hsk1-starter.zip --on-conflict duplicate`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenePackInstall,
+}
+
+var (
+	sceneExportFormat string
+	sceneExportOut    string
+	sceneImportPolicy string
+	sceneSyncDeck     string
+	sceneSyncOut      string
+	sceneSyncName     string
+	sceneSyncField    string
+	sceneLinkNote     string
+
+	scenePackName        string
+	scenePackAuthor      string
+	scenePackDescription string
+	scenePackPolicy      string
+	scenePackApprove     bool
+)
+
+func init() {
+	rootCmd.AddCommand(sceneCmd)
+	sceneCmd.AddCommand(sceneListCmd)
+	sceneCmd.AddCommand(sceneExportCmd)
+	sceneCmd.AddCommand(sceneImportCmd)
+	sceneCmd.AddCommand(sceneSyncCmd)
+	sceneCmd.AddCommand(sceneLinkCmd)
+	sceneCmd.AddCommand(sceneUnlinkCmd)
+	sceneCmd.AddCommand(sceneSearchCmd)
+	sceneCmd.AddCommand(scenePackCmd)
+	scenePackCmd.AddCommand(scenePackCreateCmd)
+	scenePackCmd.AddCommand(scenePackInstallCmd)
+
+	sceneExportCmd.Flags().StringVar(&sceneExportFormat, "format", "markdown", "Export format: markdown")
+	sceneExportCmd.Flags().StringVarP(&sceneExportOut, "out", "o", ".", "Output directory")
+
+	sceneImportCmd.Flags().StringVar(&sceneImportPolicy, "on-conflict", "keep", "Conflict resolution: keep, overwrite, duplicate")
+
+	sceneSyncCmd.Flags().StringVar(&sceneSyncDeck, "deck", "", "Existing .apkg to update instead of building a new one")
+	sceneSyncCmd.Flags().StringVarP(&sceneSyncOut, "out", "o", "", "Output .apkg (default: hmm-scenes.apkg, or <deck>_hmm.apkg with --deck)")
+	sceneSyncCmd.Flags().StringVar(&sceneSyncName, "name", "HMM Scenes", "Deck name for a new deck")
+	scenePackCreateCmd.Flags().StringVar(&scenePackName, "name", "", "Pack name (default: file name)")
+	scenePackCreateCmd.Flags().StringVar(&scenePackAuthor, "author", "", "Pack author")
+	scenePackCreateCmd.Flags().StringVar(&scenePackDescription, "description", "", "Pack description")
+
+	scenePackInstallCmd.Flags().StringVar(&scenePackPolicy, "on-conflict", "keep", "Conflict resolution: keep, overwrite, duplicate")
+	scenePackInstallCmd.Flags().BoolVar(&scenePackApprove, "approve", false, "Install scenes as approved instead of drafts")
+
+	sceneLinkCmd.Flags().StringVarP(&sceneLinkNote, "note", "n", "", "How the two characters differ")
+
+	sceneSyncCmd.Flags().StringVarP(&sceneSyncField, "field", "f", "", "Field with the character when updating a deck (auto-detect if not specified)")
+	sceneSyncCmd.RegisterFlagCompletionFunc("deck", completeApkgFlag)
+}
+
+// loadSceneStore opens the scene store in the config directory.
+func loadSceneStore() (*scene.Store, error) {
+	store, err := scene.Load(scene.DefaultPath(getConfigDir()))
+	if err != nil {
+		return nil, fmt.Errorf("loading scenes: %w", err)
+	}
+	return store, nil
+}
+
+// approvedScene returns the approved scene for char, tolerating a nil store.
+func approvedScene(store *scene.Store, char string) *hmm.Scene {
+	if store == nil {
+		return nil
+	}
+	return store.Approved(char)
+}
+
+func runSceneList(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	if store.Len() == 0 {
+		fmt.Println("No scenes saved yet. Use 'hmm generate <character> --save' to add one.")
+		return nil
+	}
+
+	for _, sc := range store.All() {
+		status := string(sc.Status)
+		if status == "" {
+			status = "-"
+		}
+		fmt.Printf("%s  %-8s %-9s %s\n", sc.Character, sc.Pinyin, status, sc.Keyword)
+	}
+
+	return nil
+}
+
+func runSceneSearch(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	query := strings.Join(args, " ")
+	results := store.Search(query)
+	if len(results) == 0 {
+		fmt.Fprintf(os.Stderr, "No scenes match %q\n", query)
+		return nil
+	}
+
+	for _, sc := range results {
+		fmt.Printf("%s  %-8s %s\n", sc.Character, sc.Pinyin, scene.Snippet(sc, query, 60))
+	}
+
+	return nil
+}
+
+func runSceneExport(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	switch sceneExportFormat {
+	case "markdown", "md", "obsidian":
+		n, err := scene.ExportMarkdown(sceneExportOut, store.All(), gen)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d scenes to %s\n", n, sceneExportOut)
+	default:
+		return fmt.Errorf("unknown format: %s", sceneExportFormat)
+	}
+
+	return nil
+}
+
+func runSceneImport(cmd *cobra.Command, args []string) error {
+	policy := scene.ConflictPolicy(sceneImportPolicy)
+	switch policy {
+	case scene.ConflictKeep, scene.ConflictOverwrite, scene.ConflictDuplicate:
+	default:
+		return fmt.Errorf("unknown conflict policy: %s", sceneImportPolicy)
+	}
+
+	scenes, err := scene.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+	parser := pinyin.NewParser()
+	for i := range scenes {
+		completeScene(&scenes[i], parser)
+	}
+
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	result := store.Merge(scenes, policy)
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported %d scenes: %d added, %d overwritten, %d duplicated, %d skipped\n",
+		len(scenes), result.Added, result.Overwritten, result.Duplicated, result.Skipped)
+
+	return nil
+}
+
+func runSceneSync(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	var result scene.SyncResult
+	outputPath := sceneSyncOut
+
+	if sceneSyncDeck == "" {
+		if outputPath == "" {
+			outputPath = "hmm-scenes.apkg"
+		}
+
+		builder, err := anki.NewDeckBuilder(sceneSyncName, scene.NoteTypeName, scene.NoteFields)
+		if err != nil {
+			return err
+		}
+		result, err = store.BuildDeck(builder, gen)
+		if err != nil {
+			return err
+		}
+		if result.Written == 0 {
+			return fmt.Errorf("no approved scenes to sync")
+		}
+		if err := builder.Write(outputPath); err != nil {
+			return fmt.Errorf("writing deck: %w", err)
+		}
+	} else {
+		if outputPath == "" {
+			ext := filepath.Ext(sceneSyncDeck)
+			outputPath = strings.TrimSuffix(sceneSyncDeck, ext) + "_hmm" + ext
+		}
+
+		pkg, err := anki.OpenPackage(sceneSyncDeck)
+		if err != nil {
+			return fmt.Errorf("opening package: %w", err)
+		}
+		defer pkg.Close()
+
+		field := sceneSyncField
+		if field == "" {
+			field = detectChineseField(pkg)
+			if field == "" {
+				return fmt.Errorf("could not auto-detect field with Chinese characters. Use --field to specify")
+			}
+			fmt.Fprintf(os.Stderr, "Auto-detected Chinese field: %s\n", field)
+		}
+
+		result, err = store.SyncPackage(pkg, field, gen)
+		if err != nil {
+			return err
+		}
+		if err := pkg.SaveAs(outputPath); err != nil {
+			return fmt.Errorf("saving package: %w", err)
+		}
+	}
+
+	// Persist the scene-to-note mapping
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Synced %d scenes to %s\n", result.Written, outputPath)
+	if result.Unmatched > 0 {
+		fmt.Fprintf(os.Stderr, "%d notes had no approved scene\n", result.Unmatched)
+	}
+
+	return nil
+}
+
+func runSceneLink(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Link(args[0], args[1], sceneLinkNote); err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Linked %s ↔ %s\n", args[0], args[1])
+	return nil
+}
+
+func runSceneUnlink(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	if !store.Unlink(args[0], args[1]) {
+		return fmt.Errorf("%s and %s are not linked", args[0], args[1])
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Unlinked %s ↔ %s\n", args[0], args[1])
+	return nil
+}
+
+func runScenePackCreate(cmd *cobra.Command, args []string) error {
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	name := scenePackName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+	}
+	pack := &scene.Pack{
+		Info: scene.PackInfo{
+			Name:        name,
+			Author:      scenePackAuthor,
+			Description: scenePackDescription,
+		},
+	}
+	for _, sc := range store.All() {
+		if sc.IsApproved() {
+			pack.Scenes = append(pack.Scenes, scene.Anonymize(sc, gen))
+		}
+	}
+	if len(pack.Scenes) == 0 {
+		return fmt.Errorf("no approved scenes to pack")
+	}
+
+	if err := scene.WritePack(args[0], pack); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Packed %d scenes into %s\n", len(pack.Scenes), args[0])
+	return nil
+}
+
+func runScenePackInstall(cmd *cobra.Command, args []string) error {
+	policy := scene.ConflictPolicy(scenePackPolicy)
+	switch policy {
+	case scene.ConflictKeep, scene.ConflictOverwrite, scene.ConflictDuplicate:
+	default:
+		return fmt.Errorf("unknown conflict policy: %s", scenePackPolicy)
+	}
+
+	pack, err := scene.ReadPack(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	parser := pinyin.NewParser()
+
+	status := hmm.SceneDraft
+	if scenePackApprove {
+		status = hmm.SceneApproved
+	}
+
+	scenes := make([]hmm.Scene, 0, len(pack.Scenes))
+	for _, sc := range pack.Scenes {
+		// Never trust a pack's actor or set; derive them from the reading
+		sc.ID = ""
+		sc.ActorID = ""
+		sc.SetID = ""
+		sc.Initial = ""
+		sc.Final = ""
+		completeScene(&sc, parser)
+
+		sc = scene.Resolve(sc, gen)
+		sc.Status = status
+		scenes = append(scenes, sc)
+	}
+
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	result := store.Merge(scenes, policy)
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Installed %q: %d added, %d overwritten, %d duplicated, %d skipped\n",
+		pack.Info.Name, result.Added, result.Overwritten, result.Duplicated, result.Skipped)
+	if !scenePackApprove && result.Added+result.Overwritten+result.Duplicated > 0 {
+		fmt.Fprintln(os.Stderr, "Review the new drafts in the TUI (view 6) before they are used in decks.")
+	}
+
+	return nil
+}
+
+// completeScene fills in reading, actor, set, keyword, and props for fields
+// a hand-written scene left empty.
+func completeScene(sc *hmm.Scene, parser *pinyin.Parser) {
+	if sc.Pinyin == "" {
+		if readings := parser.ParseChar(sc.Character); len(readings) > 0 {
+			sc.Pinyin = readings[0].Full
+		}
+	}
+	if sc.Pinyin != "" {
+		reading := parser.Parse(sc.Pinyin)
+		if sc.Initial == "" && sc.Final == "" {
+			sc.Initial = reading.Initial
+			sc.Final = reading.Final
+		}
+		if sc.Tone == hmm.ToneUnknown {
+			sc.Tone = reading.Tone
+		}
+		if sc.ActorID == "" {
+			sc.ActorID = pinyin.GetActorID(reading.Initial)
+		}
+		if sc.SetID == "" {
+			sc.SetID = pinyin.GetSetID(reading.Final)
+		}
+	}
+
+	if dict != nil {
+		if sc.Keyword == "" {
+			sc.Keyword = dict.Keyword(sc.Character)
+		}
+		if entry := dict.Lookup(sc.Character); entry != nil {
+			if sc.Keyword == "" {
+				sc.Keyword = entry.Definition
+			}
+			if len(sc.PropIDs) == 0 {
+				sc.PropIDs = decomp.ExtractComponents(entry.Decomposition)
+			}
+		}
+	}
+}
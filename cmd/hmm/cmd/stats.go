@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats <file.apkg>",
+	Short: "Show which actors, sets, and props a deck needs",
+	Long: `Print statistics for an Anki deck, or a word list with --list, to guide
+what to configure next:
+  - Number of notes, and how many already have HMM fields
+  - Number of unique characters
+  - How the characters spread over actors, sets, and tones
+  - How many of the actors, sets, and props they use are configured
+  - The components without a prop that the most characters use
+
+--props prints a report on props instead: the configured props the most
+characters use, and the ones no character uses, so you can spend effort
+on the props that pay off.
+
+Examples:
+  hmm stats chinese.apkg
+  hmm stats chinese.apkg --field Hanzi --top 20
+  hmm stats chinese.apkg --props
+  hmm stats --list hsk3`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeApkg,
+	RunE:              runStats,
+}
+
+var (
+	statsField string
+	statsTop   int
+	statsList  string
+	statsProps bool
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsField, "field", "f", "", "Field name containing Chinese characters (auto-detect if not specified)")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "Number of uncovered components (or with --props, used props) to list")
+	statsCmd.Flags().StringVar(&statsList, "list", "", "Word list (name or file) to show statistics for instead of a deck")
+	statsCmd.Flags().BoolVar(&statsProps, "props", false, "Report which configured props are used the most and which are never used")
+	statsCmd.RegisterFlagCompletionFunc("list", completeListNames)
+}
+
+// statsCount is how many characters use one actor, set, tone, or component.
+type statsCount struct {
+	id    string
+	name  string // Configured name, "" if not configured
+	count int
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if (len(args) == 1) == (statsList != "") {
+		return fmt.Errorf("give a deck or --list")
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+	parser := pinyin.NewParser()
+
+	var pkg *anki.Package
+	var chars []string
+	if statsList != "" {
+		list, err := namedList(statsList)
+		if err != nil {
+			return err
+		}
+		chars = list.Chars
+		fmt.Printf("List: %s\n", list.Name)
+	} else {
+		if pkg, err = anki.OpenPackage(args[0]); err != nil {
+			return fmt.Errorf("opening package: %w", err)
+		}
+		defer pkg.Close()
+
+		if chars, err = packageChars(pkg, statsField); err != nil {
+			return err
+		}
+		fmt.Printf("Deck: %s\n", args[0])
+		fmt.Printf("Notes: %d (%d with HMM fields)\n", len(pkg.Notes), notesWithHMMFields(pkg))
+	}
+
+	actors := make(map[string]*statsCount)
+	sets := make(map[string]*statsCount)
+	props := make(map[string]*statsCount)
+	tones := make([]int, parser.Tones()+1)
+	covered := 0
+
+	for _, char := range chars {
+		readings := parser.ParseChar(char)
+		if len(readings) == 0 {
+			continue
+		}
+		reading := readings[0]
+		tones[reading.Tone]++
+
+		full := true
+		actorID := pinyin.GetActorID(reading.Initial)
+		name := ""
+		if a := gen.GetActor(actorID); a != nil {
+			name = a.Name
+		}
+		full = tally(actors, actorID, name) && full
+
+		setID := pinyin.GetSetID(reading.Final)
+		name = ""
+		if s := gen.GetSet(setID); s != nil {
+			name = s.Name
+		}
+		full = tally(sets, setID, name) && full
+
+		for _, comp := range charComponents(char) {
+			name = ""
+			if p := gen.GetProp(comp); p != nil {
+				name = p.Name
+			}
+			full = tally(props, comp, name) && full
+		}
+
+		if full {
+			covered++
+		}
+	}
+
+	fmt.Printf("Unique characters: %d (%d with actor, set, and props all configured)\n", len(chars), covered)
+
+	if statsProps {
+		printPropUsage(props, cfg.Props, statsTop)
+		return nil
+	}
+
+	printStatsCounts("Actors", actors)
+	printStatsCounts("Sets", sets)
+
+	fmt.Println("\nTones:")
+	for tone := 1; tone <= parser.Tones(); tone++ {
+		fmt.Printf("  %d  %5d  %s\n", tone, tones[tone], statsShare(tones[tone], len(chars)))
+	}
+
+	fmt.Println("\nConfig coverage:")
+	for _, c := range []struct {
+		label  string
+		counts map[string]*statsCount
+	}{{"Actors", actors}, {"Sets", sets}, {"Props", props}} {
+		configured := 0
+		for _, sc := range c.counts {
+			if sc.name != "" {
+				configured++
+			}
+		}
+		fmt.Printf("  %-7s %d of %d used are configured\n", c.label, configured, len(c.counts))
+	}
+
+	printUncoveredProps(props, statsTop)
+	return nil
+}
+
+// tally counts one more character for id, and reports whether id is
+// configured.
+func tally(counts map[string]*statsCount, id, name string) bool {
+	sc, ok := counts[id]
+	if !ok {
+		sc = &statsCount{id: id, name: name}
+		counts[id] = sc
+	}
+	sc.count++
+	return name != ""
+}
+
+// charComponents returns the components of char in the dictionary.
+func charComponents(char string) []string {
+	if dict == nil {
+		return nil
+	}
+	entry := dict.Lookup(char)
+	if entry == nil {
+		return nil
+	}
+	return decomp.ExtractComponents(entry.Decomposition)
+}
+
+// notesWithHMMFields counts the notes with any HMM field filled in.
+func notesWithHMMFields(pkg *anki.Package) int {
+	n := 0
+	for _, note := range pkg.Notes {
+		if pkg.GetFieldValue(note, anki.BreakdownField) != "" {
+			n++
+			continue
+		}
+		for _, field := range anki.HMMFields {
+			if pkg.GetFieldValue(note, field) != "" {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// sortedCounts returns counts with the most used first.
+func sortedCounts(counts map[string]*statsCount) []*statsCount {
+	sorted := make([]*statsCount, 0, len(counts))
+	for _, sc := range counts {
+		sorted = append(sorted, sc)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].id < sorted[j].id
+	})
+	return sorted
+}
+
+// printStatsCounts prints how many characters use each actor or set.
+func printStatsCounts(title string, counts map[string]*statsCount) {
+	total := 0
+	for _, sc := range counts {
+		total += sc.count
+	}
+
+	fmt.Printf("\n%s:\n", title)
+	for _, sc := range sortedCounts(counts) {
+		name := sc.name
+		if name == "" {
+			name = "(not configured)"
+		}
+		printStatsRow(sc.id, name, sc.count, total)
+	}
+}
+
+// printStatsRow prints a row of a count table: the ID, the name cut to
+// fit its column, how many characters use it, and their share of total.
+// Columns are padded by display width, as Chinese takes two.
+func printStatsRow(id, name string, count, total int) {
+	fmt.Printf("  %s %s %5d  %s\n", textutil.PadRight(id, 5), textutil.PadRight(textutil.Truncate(name, 24), 24),
+		count, statsShare(count, total))
+}
+
+// printUncoveredProps prints the top components that have no prop yet.
+func printUncoveredProps(props map[string]*statsCount, top int) {
+	var uncovered []*statsCount
+	for _, sc := range sortedCounts(props) {
+		if sc.name == "" {
+			uncovered = append(uncovered, sc)
+		}
+	}
+	if len(uncovered) == 0 {
+		return
+	}
+
+	fmt.Printf("\nTop components without a prop (%d in all):\n", len(uncovered))
+	for i, sc := range uncovered {
+		if i == top {
+			break
+		}
+		meaning := ""
+		if dict != nil {
+			if entry := dict.Lookup(sc.id); entry != nil {
+				meaning, _, _ = strings.Cut(entry.Definition, ";")
+			}
+		}
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %s  %5d  %s", sc.id, sc.count, meaning), " "))
+	}
+}
+
+// printPropUsage prints the configured props the most characters use, and
+// the configured props that no character uses.
+func printPropUsage(props map[string]*statsCount, configured []hmm.Prop, top int) {
+	var used []*statsCount
+	total := 0
+	for _, sc := range sortedCounts(props) {
+		if sc.name != "" {
+			used = append(used, sc)
+			total += sc.count
+		}
+	}
+
+	fmt.Printf("\nMost used props (%d of %d configured are used):\n", len(used), len(configured))
+	if len(used) == 0 {
+		fmt.Println("  (none)")
+	}
+	for i, sc := range used {
+		if i == top {
+			break
+		}
+		printStatsRow(sc.id, sc.name, sc.count, total)
+	}
+
+	var unused []hmm.Prop
+	for _, p := range configured {
+		if _, ok := props[p.ID]; !ok {
+			unused = append(unused, p)
+		}
+	}
+	if len(unused) == 0 {
+		return
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].ID < unused[j].ID })
+
+	fmt.Printf("\nConfigured props never used (%d):\n", len(unused))
+	for _, p := range unused {
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %-5s %s", p.ID, p.Name), " "))
+	}
+}
+
+// statsShare renders n as a percentage of total.
+func statsShare(n, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%3d%%", n*100/total)
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+)
+
+// stdioMode is set by --stdio, which answers editor requests on stdin and
+// stdout instead of starting the TUI.
+var stdioMode bool
+
+// stdioRequest is a line of input in --stdio mode.
+type stdioRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Text   string          `json:"text"`
+	Style  string          `json:"style,omitempty"`
+	UseLLM bool            `json:"use_llm,omitempty"`
+}
+
+// stdioResponse is a line of output in --stdio mode: the result of a
+// request, or why it failed.
+type stdioResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Result any             `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// StdioScene is a character's generated scene in a generate response.
+type StdioScene struct {
+	Character string `json:"character"`
+	Pinyin    string `json:"pinyin"`
+	Prompt    string `json:"prompt"`
+	Approved  bool   `json:"approved"` // The prompt is from an approved scene, not generated
+}
+
+// stdioServer answers editor requests with the dictionary, configuration,
+// and scenes loaded once at start.
+type stdioServer struct {
+	cfg    *config.Config
+	parser *pinyin.Parser
+	scenes *scene.Store
+}
+
+func runStdio() error {
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load dictionary: %v\n", err)
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; names of actors, sets, and props are missing\n", err)
+		cfg = &config.Config{}
+	}
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	s := &stdioServer{cfg: cfg, parser: pinyin.NewParser(), scenes: scenes}
+	return s.serve(os.Stdin, os.Stdout)
+}
+
+// serve answers one request per line of r with one line on w, until r
+// ends.
+func (s *stdioServer) serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var req stdioRequest
+		resp := stdioResponse{}
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp.ID = req.ID
+			result, err := s.handle(req)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Result = result
+			}
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+		// Flush each answer: the editor waits for it
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *stdioServer) handle(req stdioRequest) (any, error) {
+	switch req.Method {
+	case "ping":
+		return "pong", nil
+	case "lookup":
+		text := strings.Join(analyze.ChineseChars(req.Text), "")
+		if text == "" {
+			return []LookupResult{}, nil
+		}
+		return lookupResults(text, s.parser, s.generator()), nil
+	case "generate":
+		return s.generate(req)
+	case "":
+		return nil, fmt.Errorf("method is required")
+	}
+	return nil, fmt.Errorf("unknown method: %s (use lookup, generate, or ping)", req.Method)
+}
+
+// generate returns the image prompt of each character in the request:
+// from its approved scene if it has one and no style or LLM is asked for,
+// else newly generated.
+func (s *stdioServer) generate(req stdioRequest) ([]StdioScene, error) {
+	gen := s.generator()
+	if err := setPromptStyle(gen, req.Style); err != nil {
+		return nil, err
+	}
+
+	var client *llm.Client
+	if req.UseLLM {
+		c, err := llm.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	}
+
+	analyzer := analyze.New(dict, gen)
+	results := []StdioScene{}
+	for _, char := range analyze.ChineseChars(req.Text) {
+		if sc := approvedScene(s.scenes, char); sc != nil && sc.ImagePrompt != "" && req.Style == "" && client == nil {
+			results = append(results, StdioScene{Character: char, Pinyin: sc.Pinyin, Prompt: sc.ImagePrompt, Approved: true})
+			continue
+		}
+
+		sc, err := generatedScene(char, analyzer, client)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil {
+			continue
+		}
+		results = append(results, StdioScene{Character: char, Pinyin: sc.Pinyin, Prompt: sc.ImagePrompt})
+	}
+	return results, nil
+}
+
+// generator returns a prompt generator for the user's configuration.
+func (s *stdioServer) generator() *prompt.Generator {
+	return prompt.NewGenerator(s.cfg.Actors, s.cfg.Sets, s.cfg.Props)
+}
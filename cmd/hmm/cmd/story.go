@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var storyCmd = &cobra.Command{
+	Use:   "story <word or sentence>",
+	Short: "Link the scenes of a word's characters into one story",
+	Long: `Turn a word or short sentence into a mini-movie: one story that moves
+through the scene of each character in order, with its actor, set, room,
+and props, so the word is remembered as a whole.
+
+With --llm the story is written by the LLM (requires ANTHROPIC_API_KEY);
+otherwise an outline of the scenes in order is printed to build on.
+
+Examples:
+  hmm story 你好
+  hmm story 电脑 --llm --meaning computer
+  hmm story 我爱你 --llm`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSceneChars(1),
+	RunE:              runStory,
+}
+
+var (
+	storyLLM     bool
+	storyMeaning string
+)
+
+func init() {
+	rootCmd.AddCommand(storyCmd)
+	storyCmd.Flags().BoolVar(&storyLLM, "llm", false, "Write the story with the LLM (requires ANTHROPIC_API_KEY)")
+	storyCmd.Flags().StringVarP(&storyMeaning, "meaning", "m", "", "Meaning of the whole word, for the story to hint at")
+}
+
+func runStory(cmd *cobra.Command, args []string) error {
+	text := strings.Join(args, "")
+
+	var client *llm.Client
+	if storyLLM {
+		c, err := llm.NewClient()
+		if err != nil {
+			return err
+		}
+		client = c
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	gen, err := userGenerator("default")
+	if err != nil {
+		return err
+	}
+	analyzer := analyze.New(dict, gen)
+
+	results := analyzer.Text(text)
+	if len(results) == 0 {
+		return fmt.Errorf("no Chinese characters with a %s reading in %q", strings.ToLower(readingLabel()), text)
+	}
+
+	var chars strings.Builder
+	readings := make([]string, len(results))
+	for i, r := range results {
+		chars.WriteString(r.Character)
+		readings[i] = r.Pinyin
+	}
+	fmt.Printf("%s (%s)", chars.String(), strings.Join(readings, " "))
+	if storyMeaning != "" {
+		fmt.Printf(": %s", storyMeaning)
+	}
+	fmt.Print("\n\n")
+
+	if client == nil {
+		fmt.Println(analyze.Story(results))
+		return nil
+	}
+
+	elements := make([]llm.SceneElements, len(results))
+	for i, r := range results {
+		elements[i] = analyzer.SceneElements(r)
+	}
+	story, err := client.GenerateStory(text, storyMeaning, elements)
+	if err != nil {
+		return fmt.Errorf("generating story: %w", err)
+	}
+	fmt.Println(story)
+	return nil
+}
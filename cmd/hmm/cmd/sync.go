@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Keep your scenes in sync with other apps",
+	Long:  `Keep your scenes in sync with note-taking apps.`,
+}
+
+var syncObsidianCmd = &cobra.Command{
+	Use:   "obsidian",
+	Short: "Write one note per character into an Obsidian vault",
+	Long: `Write one note per character with an approved scene into a folder of an
+Obsidian vault, and update them on every run.
+
+Each note has the same frontmatter keys (character, pinyin, tone, keyword,
+actor, actor_id, set, set_id, room, props, components, tags) for Dataview
+and other queries, the story and prompt, and wiki-links to the notes of
+characters sharing a component and to look-alikes.
+
+Re-syncing only replaces what lies between the <!-- hmm:begin --> and
+<!-- hmm:end --> markers and hmm's frontmatter keys. Your own sections,
+frontmatter keys, and tags are kept.
+
+Examples:
+  hmm sync obsidian --vault ~/Notes
+  hmm sync obsidian --vault ~/Notes --folder Chinese/Hanzi`,
+	Args:         cobra.NoArgs,
+	RunE:         runSyncObsidian,
+	SilenceUsage: true,
+}
+
+var (
+	syncVault  string
+	syncFolder string
+)
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncObsidianCmd)
+
+	syncObsidianCmd.Flags().StringVar(&syncVault, "vault", "", "Obsidian vault directory (required)")
+	syncObsidianCmd.Flags().StringVar(&syncFolder, "folder", "Hanzi", "Folder in the vault for the character notes")
+	syncObsidianCmd.MarkFlagRequired("vault")
+	syncObsidianCmd.MarkFlagDirname("vault")
+}
+
+func runSyncObsidian(cmd *cobra.Command, args []string) error {
+	vault := syncVault
+	if strings.HasPrefix(vault, "~/") || strings.HasPrefix(vault, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			vault = filepath.Join(home, vault[2:])
+		}
+	}
+	if info, err := os.Stat(vault); err != nil || !info.IsDir() {
+		return fmt.Errorf("vault not found: %s", vault)
+	}
+
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	dir := filepath.Join(vault, syncFolder)
+	result, err := store.SyncVault(dir, gen)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Synced %s: %d created, %d updated, %d unchanged\n",
+		dir, result.Created, result.Updated, result.Unchanged)
+	return nil
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/spf13/cobra"
+)
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Pick a character without a scene to learn today",
+	Long: `Pick the next character of your target lists that has no scene yet,
+show its HMM breakdown, and offer to generate and save a scene for it.
+
+Characters are taken in list order, so the pick stays the same until you
+make its scene. By default every list of your own (see 'hmm list') is
+used, in name order; --list or --hsk picks from one list instead, built-in
+lists included.
+
+Examples:
+  hmm today
+  hmm today --hsk 1
+  hmm today --list words.txt
+  hmm today --llm          # Have the LLM write the scene
+  hmm today --yes          # Save the scene without asking`,
+	Args: cobra.NoArgs,
+	RunE: runToday,
+}
+
+var (
+	todayList  string
+	todayHSK   int
+	todayLLM   bool
+	todayYes   bool
+	todayStyle string
+)
+
+func init() {
+	rootCmd.AddCommand(todayCmd)
+	todayCmd.Flags().StringVar(&todayList, "list", "", "Word list (name or file) to pick from")
+	todayCmd.Flags().IntVar(&todayHSK, "hsk", 0, "HSK level to pick from")
+	todayCmd.Flags().BoolVar(&todayLLM, "llm", false, "Generate the scene with the LLM (requires ANTHROPIC_API_KEY)")
+	todayCmd.Flags().BoolVarP(&todayYes, "yes", "y", false, "Generate and save the scene without asking")
+	todayCmd.Flags().StringVarP(&todayStyle, "style", "s", "default", "Prompt style: default, midjourney, dalle, sd")
+	todayCmd.RegisterFlagCompletionFunc("list", completeListNames)
+	todayCmd.RegisterFlagCompletionFunc("style", cobra.FixedCompletions([]string{"default", "midjourney", "dalle", "sd"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runToday(cmd *cobra.Command, args []string) error {
+	if todayList != "" && todayHSK != 0 {
+		return fmt.Errorf("give only one of --list or --hsk")
+	}
+
+	store, err := loadSceneStore()
+	if err != nil {
+		return err
+	}
+
+	lists, err := todayLists(store)
+	if err != nil {
+		return err
+	}
+
+	char, list := nextMissing(store, lists)
+	if char == "" {
+		names := make([]string, len(lists))
+		for i, l := range lists {
+			names[i] = l.Name
+		}
+		fmt.Printf("Every character of %s has a scene.\n", strings.Join(names, ", "))
+		return nil
+	}
+
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load dictionary: %v\n", err)
+	}
+
+	gen, err := userGenerator(todayStyle)
+	if err != nil {
+		return err
+	}
+
+	analyzer := analyze.New(dict, gen)
+	r := analyzer.Character(char)
+	if r == nil {
+		return fmt.Errorf("no %s reading found for %s", strings.ToLower(readingLabel()), char)
+	}
+
+	cov := store.Coverage(list.Chars)
+	fmt.Printf("Today's character from %s (%d of %d still without a scene):\n\n",
+		list.Name, len(cov.Missing), cov.Total)
+	fmt.Print(r.Breakdown())
+	fmt.Println()
+
+	if !todayYes && !confirm("Generate and save a scene?") {
+		return nil
+	}
+
+	var client *llm.Client
+	if todayLLM {
+		if client, err = llm.NewClient(); err != nil {
+			return err
+		}
+	}
+
+	sc, err := generatedScene(char, analyzer, client)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(sc.ImagePrompt)
+
+	store.Put(*sc)
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Saved scene to %s\n", store.Path())
+	return nil
+}
+
+// todayLists returns the target lists to pick from: the one given by
+// --list or --hsk, or every list of the user's own.
+func todayLists(store *scene.Store) ([]scene.TargetList, error) {
+	switch {
+	case todayList != "":
+		list, err := namedList(todayList)
+		return []scene.TargetList{list}, err
+	case todayHSK != 0:
+		list, err := hskList(todayHSK)
+		return []scene.TargetList{list}, err
+	}
+
+	lists, err := scene.LoadTargetLists(store.ListsDir())
+	if err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return nil, fmt.Errorf("no word lists of your own: add one with 'hmm list add', or pick from a built-in list with --hsk or --list")
+	}
+	return lists, nil
+}
+
+// nextMissing returns the first character of lists, in order, that has no
+// scene yet, and the list it is from. It returns "" if there is none.
+func nextMissing(store *scene.Store, lists []scene.TargetList) (string, scene.TargetList) {
+	for _, list := range lists {
+		if missing := store.Coverage(list.Chars).Missing; len(missing) > 0 {
+			return missing[0], list
+		}
+	}
+	return "", scene.TargetList{}
+}
+
+// confirm asks question on stdout and reports whether the answer read
+// from stdin is yes.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Println()
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/audio"
+	"github.com/f3rmion/hmm/internal/tts"
+	"github.com/spf13/cobra"
+)
+
+var ttsCmd = &cobra.Command{
+	Use:   "tts <text>...",
+	Short: "Generate pronunciation audio with a text-to-speech engine",
+	Long: `Speak Chinese text with a text-to-speech engine and keep the MP3 in the
+audio cache, where the TUI's 'v' key finds it.
+
+Engines (the first available is used unless --engine says otherwise):
+  edge     Microsoft Edge voices through edge-tts (pip install edge-tts)
+  openai   OpenAI speech API (needs OPENAI_API_KEY)
+  gtts     Google Translate voice through gTTS (pip install gTTS)
+  forvo    Recordings by native speakers from Forvo (needs FORVO_API_KEY);
+           only words someone has recorded, so choose it with --engine
+
+--voice takes a locale (zh-CN, zh-TW) or an engine's own voice name, such
+as zh-CN-YunxiNeural for edge or nova for openai.
+
+Recordings already in the cache are reused unless --force is given. To
+put them into a deck, use 'hmm anki augment --format apkg --audio'.
+
+Examples:
+  hmm tts 你好
+  hmm tts 好 中 国 --voice zh-TW
+  hmm tts 谢谢 --engine openai --voice nova --play
+  hmm tts 你好 --engine forvo --voice zh-TW`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTTS,
+}
+
+var (
+	ttsVoice  string
+	ttsEngine string
+	ttsForce  bool
+	ttsPlay   bool
+)
+
+func init() {
+	rootCmd.AddCommand(ttsCmd)
+
+	ttsCmd.Flags().StringVar(&ttsVoice, "voice", tts.DefaultVoice, "Voice: a locale such as zh-CN, or an engine's voice name")
+	ttsCmd.Flags().StringVar(&ttsEngine, "engine", "", "TTS engine: "+strings.Join(tts.Names(), ", ")+" (first available if empty)")
+	ttsCmd.Flags().BoolVar(&ttsForce, "force", false, "Generate again even if the recording is cached")
+	ttsCmd.Flags().BoolVar(&ttsPlay, "play", false, "Play each recording once it is ready")
+	ttsCmd.RegisterFlagCompletionFunc("engine", cobra.FixedCompletions(tts.Names(), cobra.ShellCompDirectiveNoFileComp))
+	ttsCmd.RegisterFlagCompletionFunc("voice", cobra.FixedCompletions([]string{"zh-CN", "zh-TW", "zh-HK"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runTTS(cmd *cobra.Command, args []string) error {
+	engine, err := tts.Get(ttsEngine)
+	if err != nil {
+		return err
+	}
+
+	dir, err := audioCacheDir()
+	if err != nil {
+		return err
+	}
+
+	for _, text := range args {
+		path, cached, err := tts.Speak(engine, text, ttsVoice, dir, ttsForce)
+		if err != nil {
+			return err
+		}
+		if cached {
+			fmt.Printf("%s: %s (cached)\n", text, path)
+		} else {
+			fmt.Printf("%s: %s\n", text, path)
+		}
+
+		if ttsPlay {
+			if err := audio.Play(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+// audioCacheDir returns the directory pronunciations are kept in.
+func audioCacheDir() (string, error) {
+	setCacheDirs()
+	dir := audio.CacheDir()
+	if dir == "" {
+		return "", fmt.Errorf("no cache directory for audio on this system")
+	}
+	return dir, nil
+}
+
+// addAugmentedAudio speaks the Chinese characters of every augmented note
+// and adds the recordings to pkg, referenced from the HMM_Audio field.
+func addAugmentedAudio(pkg *anki.Package, results []AugmentedNote, engine tts.Engine, voice string) error {
+	dir, err := audioCacheDir()
+	if err != nil {
+		return err
+	}
+
+	models := make(map[int64]bool)
+	for _, r := range results {
+		if note := pkg.GetNoteByID(r.NoteID); note != nil && !models[note.ModelID] {
+			models[note.ModelID] = true
+			// Keep the HMM fields ahead of the audio field
+			if err := pkg.AddHMMFieldsToModel(note.ModelID); err != nil {
+				return fmt.Errorf("adding HMM fields to model: %w", err)
+			}
+			if err := pkg.AddFieldsToModel(note.ModelID, anki.AudioField); err != nil {
+				return fmt.Errorf("adding audio field to model: %w", err)
+			}
+		}
+	}
+
+	progress := newProgressLine("Speaking")
+	defer progress.clear()
+
+	added := 0
+	for i, r := range results {
+		progress.update(i, len(results))
+
+		note := pkg.GetNoteByID(r.NoteID)
+		text := strings.Join(analyze.ChineseChars(r.Character), "")
+		if note == nil || text == "" {
+			continue
+		}
+
+		path, _, err := tts.Speak(engine, text, voice, dir, false)
+		if err != nil {
+			return err
+		}
+		name := "hmm-" + tts.FileName(text)
+		if err := pkg.AddMedia(name, path); err != nil {
+			return err
+		}
+		if err := pkg.SetNoteField(note, anki.AudioField, "[sound:"+name+"]"); err != nil {
+			return err
+		}
+		added++
+	}
+
+	progress.clear()
+	fmt.Fprintf(os.Stderr, "Added pronunciations to %d notes (%s)\n", added, engine.Name())
+	return nil
+}
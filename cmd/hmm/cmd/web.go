@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/web"
+	"github.com/spf13/cobra"
+)
+
+var webCmd = &cobra.Command{
+	Use:   "web [file.apkg]",
+	Short: "Serve the Lookup and Browse views as a local web page",
+	Long: `Start a local web server with a page mirroring the Lookup view and,
+when a deck is given, the Browse view.
+
+Click a component to look it up, and see the images of approved scenes
+and of the deck's cards. The page and its assets are built into hmm; the
+server only listens on localhost unless --addr says otherwise.
+
+The same data is offered to scripts and apps as a JSON-RPC 2.0 API at
+POST /rpc, described by api/hmm/v1/hmm.proto.
+
+Examples:
+  hmm web
+  hmm web deck.apkg --addr localhost:9000`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeApkg,
+	RunE:              runWeb,
+}
+
+var (
+	webAddr  string
+	webField string
+)
+
+func init() {
+	rootCmd.AddCommand(webCmd)
+
+	webCmd.Flags().StringVar(&webAddr, "addr", "localhost:8420", "Address to listen on")
+	webCmd.Flags().StringVarP(&webField, "field", "f", "", "Field containing Chinese characters (auto-detected if empty)")
+}
+
+func runWeb(cmd *cobra.Command, args []string) error {
+	if err := loadDictionary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load dictionary: %v\n", err)
+	}
+
+	cfg, err := loadUserConfig(getConfigDir())
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	gen := prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
+
+	scenes, err := loadSceneStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		scenes = nil
+	}
+
+	var pkg *anki.Package
+	field := webField
+	if len(args) == 1 {
+		pkg, err = anki.OpenPackage(args[0])
+		if err != nil {
+			return fmt.Errorf("opening package: %w", err)
+		}
+		defer pkg.Close()
+
+		if field == "" {
+			field = detectChineseField(pkg)
+			if field == "" {
+				return fmt.Errorf("could not auto-detect field with Chinese characters. Use --field to specify")
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Loaded: %s (%d notes)\n", args[0], len(pkg.Notes))
+	}
+
+	server := web.NewServer(dict, gen, scenes, pkg, field)
+	fmt.Printf("Serving on http://%s (Ctrl+C to stop)\n", webAddr)
+	return http.ListenAndServe(webAddr, server.Handler())
+}
@@ -0,0 +1,195 @@
+// Package analyze breaks Chinese characters down into the elements of
+// their HMM scene: the actor, set, and room of their reading and the props
+// of their components. The TUI views, the web server, and the commands
+// all analyze characters here, so they agree on what a scene holds.
+package analyze
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+)
+
+// Analyzer analyzes characters with a dictionary, which may be nil, and
+// the actors, sets, and props of a prompt generator.
+type Analyzer struct {
+	dict      *decomp.Dictionary
+	parser    *pinyin.Parser
+	generator *prompt.Generator
+}
+
+// New creates an analyzer.
+func New(dict *decomp.Dictionary, gen *prompt.Generator) *Analyzer {
+	return &Analyzer{
+		dict:      dict,
+		parser:    pinyin.NewParser(),
+		generator: gen,
+	}
+}
+
+// Character analyzes the first reading of char. It returns nil if char
+// has no reading.
+func (a *Analyzer) Character(char string) *CharacterResult {
+	readings := a.parser.ParseChar(char)
+	if len(readings) == 0 {
+		return nil
+	}
+
+	reading := readings[0]
+
+	result := &CharacterResult{
+		Character: char,
+		Pinyin:    reading.Full,
+		Initial:   reading.Initial,
+		Final:     reading.Final,
+		Tone:      reading.Tone,
+		ActorID:   pinyin.GetActorID(reading.Initial),
+		SetID:     pinyin.GetSetID(reading.Final),
+	}
+
+	if a.dict != nil {
+		result.Keyword = a.dict.Keyword(char)
+		if entry := a.dict.Lookup(char); entry != nil {
+			result.Meaning = entry.Definition
+			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
+			result.Components = decomp.ExtractComponents(entry.Decomposition)
+			if entry.Etymology != nil {
+				if entry.Etymology.Hint != "" {
+					result.Etymology = entry.Etymology.Hint
+				} else {
+					result.Etymology = entry.Etymology.Type
+				}
+			}
+		}
+	}
+
+	if actor := a.generator.GetActor(result.ActorID); actor != nil {
+		result.ActorName = actor.Name
+	}
+	set := a.generator.GetSet(result.SetID)
+	if set != nil {
+		result.SetName = set.Name
+	}
+	result.ToneRoom = a.generator.GetToneRoom(set, reading.Tone)
+
+	for _, comp := range result.Components {
+		if p := a.generator.GetProp(comp); p != nil && p.Name != "" {
+			result.PropNames = append(result.PropNames, p.Name)
+		}
+	}
+
+	return result
+}
+
+// Text analyzes every Chinese character of text that has a reading.
+func (a *Analyzer) Text(text string) []CharacterResult {
+	var results []CharacterResult
+	for _, char := range ChineseChars(text) {
+		if r := a.Character(char); r != nil {
+			results = append(results, *r)
+		}
+	}
+	return results
+}
+
+// SceneData returns the data the prompt template describes r's scene with.
+func (a *Analyzer) SceneData(r CharacterResult) prompt.SceneData {
+	return a.generator.BuildSceneData(
+		r.Character,
+		r.Pinyin,
+		r.ActorID,
+		r.SetID,
+		r.Tone,
+		r.Components,
+		r.Meaning,
+		r.Etymology,
+		r.Decomp,
+	)
+}
+
+// Prompt returns the image prompt of r's scene from the template.
+func (a *Analyzer) Prompt(r CharacterResult) (string, error) {
+	return a.generator.Generate(a.SceneData(r))
+}
+
+// SceneElements collects what the LLM needs to know to describe r's scene:
+// the names and descriptions of its actor, set, room, and props.
+func (a *Analyzer) SceneElements(r CharacterResult) llm.SceneElements {
+	elements := llm.SceneElements{
+		Character: r.Character,
+		Pinyin:    r.Pinyin,
+		Meaning:   r.Meaning,
+		ActorName: r.ActorName,
+		SetName:   r.SetName,
+		ToneRoom:  r.ToneRoom,
+	}
+
+	if actor := a.generator.GetActor(r.ActorID); actor != nil {
+		elements.ActorDesc = actor.Description
+	}
+	if set := a.generator.GetSet(r.SetID); set != nil {
+		elements.SetDesc = set.Description
+		for _, room := range set.Rooms {
+			if room.Tone == r.Tone {
+				elements.ToneRoomDesc = room.Description
+				break
+			}
+		}
+	}
+	// Props and their descriptions stay in step, one of each per prop
+	for _, comp := range r.Components {
+		if p := a.generator.GetProp(comp); p != nil && p.Name != "" {
+			elements.Props = append(elements.Props, p.Name)
+			elements.PropDescs = append(elements.PropDescs, p.Description)
+		}
+	}
+
+	return elements
+}
+
+// IsChinese reports whether r is a Chinese character: any Han ideograph,
+// including those of the extensions and the compatibility blocks. Every
+// part of hmm tells Chinese from other text with it.
+func IsChinese(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// ContainsChinese reports whether s has a Chinese character.
+func ContainsChinese(s string) bool {
+	return strings.IndexFunc(s, IsChinese) >= 0
+}
+
+// ChineseChars returns the Chinese characters of s, in order.
+func ChineseChars(s string) []string {
+	var chars []string
+	for _, r := range s {
+		if IsChinese(r) {
+			chars = append(chars, string(r))
+		}
+	}
+	return chars
+}
+
+// UniqueChineseChars returns the Chinese characters of s, in order and
+// without repeats.
+func UniqueChineseChars(s string) []string {
+	var chars []string
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		if IsChinese(r) && !seen[r] {
+			seen[r] = true
+			chars = append(chars, string(r))
+		}
+	}
+	return chars
+}
+
+// ChineseRuns returns the runs of Chinese characters in s: its words, if
+// s is a list of them.
+func ChineseRuns(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !IsChinese(r) })
+}
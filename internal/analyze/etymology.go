@@ -0,0 +1,134 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/decomp"
+)
+
+// maxSeries is how many characters of a phonetic series an etymology
+// lists.
+const maxSeries = 24
+
+// Etymology is how a character was formed, from Make Me a Hanzi: its type
+// and hint, its semantic and phonetic components, and the phonetic series
+// of the characters sharing its phonetic component.
+type Etymology struct {
+	Character string
+	Type      string // pictophonetic, pictographic, ideographic
+	Hint      string
+	Semantic  *EtymologyPart
+	Phonetic  *EtymologyPart
+	Series    []EtymologyPart // Other characters with the same phonetic, fewest strokes first
+	More      int             // Characters of the series left out of Series
+}
+
+// EtymologyPart is a component or character of an etymology, with its own
+// reading and meaning when the dictionary has them.
+type EtymologyPart struct {
+	Character string
+	Pinyin    string
+	Meaning   string
+}
+
+// Etymology returns the etymology of char, or nil if the dictionary has
+// none for it.
+func (a *Analyzer) Etymology(char string) *Etymology {
+	if a.dict == nil {
+		return nil
+	}
+	entry := a.dict.Lookup(char)
+	if entry == nil || entry.Etymology == nil {
+		return nil
+	}
+
+	e := &Etymology{
+		Character: char,
+		Type:      entry.Etymology.Type,
+		Hint:      entry.Etymology.Hint,
+	}
+	if s := entry.Etymology.Semantic; s != "" {
+		part := a.etymologyPart(s)
+		e.Semantic = &part
+	}
+	if p := entry.Etymology.Phonetic; p != "" {
+		part := a.etymologyPart(p)
+		e.Phonetic = &part
+
+		for _, s := range a.dict.PhoneticSeries(p) {
+			if s.Character == char {
+				continue
+			}
+			if len(e.Series) == maxSeries {
+				e.More++
+				continue
+			}
+			e.Series = append(e.Series, partOf(s))
+		}
+	}
+	return e
+}
+
+// etymologyPart returns the reading and meaning of a component, from the
+// dictionary or else the pinyin of the character.
+func (a *Analyzer) etymologyPart(char string) EtymologyPart {
+	part := EtymologyPart{Character: char}
+	if entry := a.dict.Lookup(char); entry != nil {
+		part = partOf(entry)
+	}
+	if part.Pinyin == "" {
+		if readings := a.parser.ParseChar(char); len(readings) > 0 {
+			part.Pinyin = readings[0].Full
+		}
+	}
+	return part
+}
+
+// partOf returns entry as part of an etymology.
+func partOf(entry *decomp.DictionaryEntry) EtymologyPart {
+	part := EtymologyPart{Character: entry.Character, Meaning: entry.Definition}
+	if len(entry.Pinyin) > 0 {
+		part.Pinyin = entry.Pinyin[0]
+	}
+	return part
+}
+
+// String renders the part as 青 (qīng): blue, green.
+func (p EtymologyPart) String() string {
+	s := p.Character
+	if p.Pinyin != "" {
+		s += " (" + p.Pinyin + ")"
+	}
+	if p.Meaning != "" {
+		s += ": " + p.Meaning
+	}
+	return s
+}
+
+// Text renders the etymology as plain text.
+func (e Etymology) Text() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%s: %s\n", e.Character, orNone(e.Type)))
+	if e.Hint != "" {
+		b.WriteString(fmt.Sprintf("Hint: %s\n", e.Hint))
+	}
+	if e.Semantic != nil {
+		b.WriteString(fmt.Sprintf("Semantic (meaning): %s\n", e.Semantic))
+	}
+	if e.Phonetic != nil {
+		b.WriteString(fmt.Sprintf("Phonetic (sound): %s\n", e.Phonetic))
+	}
+	if len(e.Series) > 0 {
+		b.WriteString(fmt.Sprintf("\nPhonetic series of %s:\n", e.Phonetic.Character))
+		for _, s := range e.Series {
+			b.WriteString("  " + s.String() + "\n")
+		}
+		if e.More > 0 {
+			b.WriteString(fmt.Sprintf("  … and %d more\n", e.More))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,158 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// CharacterResult holds the analysis of a character: its first reading,
+// its dictionary entry, and the elements of its scene.
+type CharacterResult struct {
+	Character  string
+	Pinyin     string
+	Meaning    string
+	Keyword    string // Remembering the Hanzi keyword, if imported
+	Decomp     string
+	Components []string
+	Etymology  string
+	Initial    string
+	Final      string
+	Tone       hmm.Tone
+	ActorID    string
+	ActorName  string
+	SetID      string
+	SetName    string
+	ToneRoom   string
+	PropNames  []string
+}
+
+// Scene converts the analysis into a scene carrying the given image prompt.
+// Its keyword is the RTH keyword, or else the meaning.
+func (r CharacterResult) Scene(imagePrompt string) hmm.Scene {
+	keyword := r.Keyword
+	if keyword == "" {
+		keyword = r.Meaning
+	}
+	return hmm.Scene{
+		Character:   r.Character,
+		Pinyin:      r.Pinyin,
+		Initial:     r.Initial,
+		Final:       r.Final,
+		Tone:        r.Tone,
+		Keyword:     keyword,
+		ActorID:     r.ActorID,
+		SetID:       r.SetID,
+		PropIDs:     r.Components,
+		ImagePrompt: imagePrompt,
+	}
+}
+
+// Breakdown renders the HMM breakdown as plain text, laid out like the
+// breakdown box in the TUI.
+func (r CharacterResult) Breakdown() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%s (%s)", r.Character, r.Pinyin))
+	if r.Meaning != "" {
+		b.WriteString(": " + r.Meaning)
+	}
+	b.WriteString("\n")
+	if r.Keyword != "" {
+		b.WriteString(fmt.Sprintf("RTH keyword: %s\n", r.Keyword))
+	}
+	b.WriteString(fmt.Sprintf("Initial: %s → %s\n", orNone(r.Initial), nameOrID("Actor", r.ActorID, r.ActorName)))
+	b.WriteString(fmt.Sprintf("Final: %s → %s\n", orNone(r.Final), nameOrID("Set", r.SetID, r.SetName)))
+	b.WriteString(fmt.Sprintf("Tone: %d → %s\n", r.Tone, r.ToneRoom))
+	if r.Decomp != "" {
+		b.WriteString(fmt.Sprintf("Components: %s\n", r.Decomp))
+	}
+	if len(r.PropNames) > 0 {
+		b.WriteString(fmt.Sprintf("Props: %s\n", strings.Join(r.PropNames, ", ")))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Markdown renders the whole card as a Markdown block, with imagePrompt
+// included if it is not empty.
+func (r CharacterResult) Markdown(imagePrompt string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("## %s (%s)\n\n", r.Character, r.Pinyin))
+	if r.Meaning != "" {
+		b.WriteString(fmt.Sprintf("> %s\n\n", r.Meaning))
+	}
+	if r.Keyword != "" {
+		b.WriteString(fmt.Sprintf("- **RTH keyword:** %s\n", r.Keyword))
+	}
+
+	b.WriteString(fmt.Sprintf("- **Actor:** %s (%s)\n", nameOrID("Actor", r.ActorID, r.ActorName), orNone(r.Initial)))
+	b.WriteString(fmt.Sprintf("- **Set:** %s (%s)\n", nameOrID("Set", r.SetID, r.SetName), orNone(r.Final)))
+	b.WriteString(fmt.Sprintf("- **Room:** %s (tone %d)\n", r.ToneRoom, r.Tone))
+	if r.Decomp != "" {
+		b.WriteString(fmt.Sprintf("- **Components:** %s\n", r.Decomp))
+	}
+	if len(r.PropNames) > 0 {
+		b.WriteString(fmt.Sprintf("- **Props:** %s\n", strings.Join(r.PropNames, ", ")))
+	}
+	if r.Etymology != "" {
+		b.WriteString(fmt.Sprintf("- **Etymology:** %s\n", r.Etymology))
+	}
+
+	if imagePrompt != "" {
+		b.WriteString("\n### Prompt\n\n")
+		b.WriteString(imagePrompt)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Story chains the scenes of results, in order, into a plain outline of
+// one story: each character's actor at its set and room with its props,
+// the props of a scene carried on into the next. It stands in for the
+// story the LLM would write.
+func Story(results []CharacterResult) string {
+	var b strings.Builder
+
+	for i, r := range results {
+		switch {
+		case i == 0:
+			b.WriteString("First, ")
+		case i == len(results)-1:
+			b.WriteString("Finally, ")
+		default:
+			b.WriteString("Then ")
+		}
+		b.WriteString(fmt.Sprintf("[%s %s] %s is at %s (%s)",
+			r.Character, r.Pinyin, nameOrID("Actor", r.ActorID, r.ActorName),
+			nameOrID("Set", r.SetID, r.SetName), r.ToneRoom))
+		if len(r.PropNames) > 0 {
+			b.WriteString(", with " + strings.Join(r.PropNames, ", "))
+		}
+		if i > 0 && len(results[i-1].PropNames) > 0 {
+			b.WriteString(fmt.Sprintf(", still holding the %s from the scene before", results[i-1].PropNames[0]))
+		}
+		b.WriteString(".\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// orNone shows an empty initial or final as Ø, as the TUI does.
+func orNone(s string) string {
+	if s == "" {
+		return "Ø"
+	}
+	return s
+}
+
+// nameOrID falls back to "Actor [id]" or "Set [id]" for unnamed elements.
+func nameOrID(kind, id, name string) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s [%s]", kind, id)
+}
@@ -0,0 +1,36 @@
+package analyze
+
+import "strings"
+
+// maxConfusables is how many look-alike characters are shown for one.
+const maxConfusables = 5
+
+// Confusable is a character easily mistaken for another, and why.
+type Confusable struct {
+	EtymologyPart
+	Reason string
+}
+
+// Confusables returns the characters most easily mistaken for char, from
+// their shape in the dictionary, or nil without one.
+func (a *Analyzer) Confusables(char string) []Confusable {
+	if a.dict == nil {
+		return nil
+	}
+
+	var out []Confusable
+	for _, c := range a.dict.Confusables(char, maxConfusables) {
+		out = append(out, Confusable{partOf(c.Entry), c.Reason})
+	}
+	return out
+}
+
+// ConfusablesLine renders confusables on one line, as 已 yǐ (a stroke
+// apart) · 巳 sì (a stroke apart).
+func ConfusablesLine(confusables []Confusable) string {
+	parts := make([]string, len(confusables))
+	for i, c := range confusables {
+		parts[i] = strings.TrimSpace(c.Character+" "+c.Pinyin) + " (" + c.Reason + ")"
+	}
+	return strings.Join(parts, " · ")
+}
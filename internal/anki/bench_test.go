@@ -0,0 +1,26 @@
+package anki_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/bench"
+)
+
+func BenchmarkOpenPackage(b *testing.B) {
+	chars, _ := bench.Chars(nil)
+	path := filepath.Join(b.TempDir(), "bench.apkg")
+	if err := bench.WriteDeck(path, bench.DeckNotes, chars); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		pkg, err := anki.OpenPackage(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pkg.Close()
+	}
+}
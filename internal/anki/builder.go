@@ -0,0 +1,459 @@
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/htmlutil"
+)
+
+// collectionSchema is the Anki 2.1 (schema 11) collection layout.
+const collectionSchema = `
+CREATE TABLE col (
+    id              integer primary key,
+    crt             integer not null,
+    mod             integer not null,
+    scm             integer not null,
+    ver             integer not null,
+    dty             integer not null,
+    usn             integer not null,
+    ls              integer not null,
+    conf            text not null,
+    models          text not null,
+    decks           text not null,
+    dconf           text not null,
+    tags            text not null
+);
+CREATE TABLE notes (
+    id              integer primary key,
+    guid            text not null,
+    mid             integer not null,
+    mod             integer not null,
+    usn             integer not null,
+    tags            text not null,
+    flds            text not null,
+    sfld            integer not null,
+    csum            integer not null,
+    flags           integer not null,
+    data            text not null
+);
+CREATE TABLE cards (
+    id              integer primary key,
+    nid             integer not null,
+    did             integer not null,
+    ord             integer not null,
+    mod             integer not null,
+    usn             integer not null,
+    type            integer not null,
+    queue           integer not null,
+    due             integer not null,
+    ivl             integer not null,
+    factor          integer not null,
+    reps            integer not null,
+    lapses          integer not null,
+    left            integer not null,
+    odue            integer not null,
+    odid            integer not null,
+    flags           integer not null,
+    data            text not null
+);
+CREATE TABLE revlog (
+    id              integer primary key,
+    cid             integer not null,
+    usn             integer not null,
+    ease            integer not null,
+    ivl             integer not null,
+    lastIvl         integer not null,
+    factor          integer not null,
+    time            integer not null,
+    type            integer not null
+);
+CREATE TABLE graves (
+    usn             integer not null,
+    oid             integer not null,
+    type            integer not null
+);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+const defaultCSS = `.card {
+ font-family: arial;
+ font-size: 20px;
+ text-align: center;
+ color: black;
+ background-color: white;
+}
+`
+
+// DeckBuilder creates a new .apkg file with a single deck and note type.
+type DeckBuilder struct {
+	DeckName  string
+	ModelName string
+	Fields    []string
+	Front     string // Question template
+	Back      string // Answer template
+	CSS       string
+
+	notes []builderNote
+	media []builderMedia
+}
+
+type builderMedia struct {
+	name string
+	path string
+}
+
+type builderNote struct {
+	guid   string
+	fields []string
+	tags   []string
+}
+
+// NewDeckBuilder creates a builder for a deck whose notes have the given fields.
+// The default card shows the first field on the front and every field on the back,
+// so there must be at least one.
+func NewDeckBuilder(deckName, modelName string, fields []string) (*DeckBuilder, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("note type %s has no fields", modelName)
+	}
+
+	var back strings.Builder
+	back.WriteString("{{FrontSide}}\n\n<hr id=answer>\n")
+	for _, f := range fields[1:] {
+		back.WriteString(fmt.Sprintf("\n<div>{{%s}}</div>\n", f))
+	}
+
+	return &DeckBuilder{
+		DeckName:  deckName,
+		ModelName: modelName,
+		Fields:    fields,
+		Front:     fmt.Sprintf("{{%s}}", fields[0]),
+		Back:      back.String(),
+		CSS:       defaultCSS,
+	}, nil
+}
+
+// AddNote adds a note with one value per field. Notes with the same GUID
+// as a note already in the user's collection replace it on import.
+func (b *DeckBuilder) AddNote(guid string, fields []string, tags ...string) error {
+	if len(fields) != len(b.Fields) {
+		return fmt.Errorf("note has %d fields, note type has %d", len(fields), len(b.Fields))
+	}
+	if guid == "" {
+		return fmt.Errorf("note GUID is empty")
+	}
+	b.notes = append(b.notes, builderNote{guid: guid, fields: fields, tags: tags})
+	return nil
+}
+
+// AddMedia adds the file at path to the deck as the media file name, for
+// fields to reference as [sound:name] or <img src="name">. Adding a name
+// twice keeps the first file.
+func (b *DeckBuilder) AddMedia(name, path string) {
+	for _, m := range b.media {
+		if m.name == name {
+			return
+		}
+	}
+	b.media = append(b.media, builderMedia{name: name, path: path})
+}
+
+// Len returns the number of notes added so far.
+func (b *DeckBuilder) Len() int {
+	return len(b.notes)
+}
+
+// Write creates the .apkg file at path.
+func (b *DeckBuilder) Write(path string) error {
+	tempDir, err := newTempDir("build")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dbPath := filepath.Join(tempDir, "collection.anki2")
+	if err := b.writeCollection(dbPath); err != nil {
+		return err
+	}
+
+	outFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outFile.Close()
+
+	slog.Info("writing Anki deck", "path", path, "deck", b.DeckName, "notes", len(b.notes), "media", len(b.media))
+	zipWriter := zip.NewWriter(outFile)
+
+	writer, err := zipWriter.Create("collection.anki2")
+	if err != nil {
+		return fmt.Errorf("creating zip: %w", err)
+	}
+	db, err := os.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, db)
+	db.Close()
+	if err != nil {
+		return fmt.Errorf("creating zip: %w", err)
+	}
+
+	// Media files are numbered in the zip; the manifest maps the numbers to
+	// their names, and Anki expects it even when there are none
+	manifest := make(map[string]string, len(b.media))
+	for i, m := range b.media {
+		if err := addZipFile(zipWriter, strconv.Itoa(i), m.path); err != nil {
+			return fmt.Errorf("adding media %s: %w", m.name, err)
+		}
+		manifest[strconv.Itoa(i)] = m.name
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	writer, err = zipWriter.Create("media")
+	if err != nil {
+		return fmt.Errorf("creating zip: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("creating zip: %w", err)
+	}
+
+	return zipWriter.Close()
+}
+
+// addZipFile copies the file at path into the zip as name.
+func addZipFile(zipWriter *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, f)
+	return err
+}
+
+// writeCollection creates the SQLite collection with all notes and cards.
+func (b *DeckBuilder) writeCollection(dbPath string) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("creating database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(collectionSchema); err != nil {
+		return fmt.Errorf("creating schema: %w", err)
+	}
+
+	now := time.Now()
+	deckID := StableID("deck:" + b.DeckName)
+	modelID := StableID("model:" + b.ModelName)
+
+	models, decks, dconf, conf, err := b.collectionJSON(deckID, modelID, now.Unix())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), conf, models, decks, dconf)
+	if err != nil {
+		return fmt.Errorf("writing collection: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	baseID := now.UnixMilli()
+	for i, n := range b.notes {
+		id := baseID + int64(i)
+		sfld := htmlutil.Text(n.fields[0])
+		tags := ""
+		if len(n.tags) > 0 {
+			tags = " " + strings.Join(n.tags, " ") + " "
+		}
+
+		_, err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			id, n.guid, modelID, now.Unix(), tags, strings.Join(n.fields, "\x1f"), sfld, checksum(sfld))
+		if err != nil {
+			return fmt.Errorf("writing note: %w", err)
+		}
+
+		_, err = tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			id, id, deckID, now.Unix(), i+1)
+		if err != nil {
+			return fmt.Errorf("writing card: %w", err)
+		}
+	}
+
+	slog.Debug("SQL exec", "sql", "INSERT INTO notes, cards", "rows", len(b.notes), "duration", time.Since(start))
+	return tx.Commit()
+}
+
+// collectionJSON builds the JSON blobs stored in the col table.
+func (b *DeckBuilder) collectionJSON(deckID, modelID, mod int64) (models, decks, dconf, conf string, err error) {
+	flds := make([]map[string]interface{}, len(b.Fields))
+	for i, name := range b.Fields {
+		flds[i] = map[string]interface{}{
+			"name":   name,
+			"ord":    i,
+			"sticky": false,
+			"rtl":    false,
+			"font":   "Arial",
+			"size":   20,
+			"media":  []string{},
+		}
+	}
+
+	model := map[string]interface{}{
+		"id":        modelID,
+		"name":      b.ModelName,
+		"type":      0,
+		"mod":       mod,
+		"usn":       -1,
+		"sortf":     0,
+		"did":       deckID,
+		"flds":      flds,
+		"css":       b.CSS,
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"tags":      []string{},
+		"vers":      []string{},
+		"req":       []interface{}{[]interface{}{0, "any", []int{0}}},
+		"tmpls": []map[string]interface{}{{
+			"name":  "Card 1",
+			"ord":   0,
+			"qfmt":  b.Front,
+			"afmt":  b.Back,
+			"did":   nil,
+			"bqfmt": "",
+			"bafmt": "",
+		}},
+	}
+
+	deck := func(id int64, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":        id,
+			"name":      name,
+			"desc":      "",
+			"mod":       mod,
+			"usn":       -1,
+			"collapsed": false,
+			"dyn":       0,
+			"conf":      1,
+			"extendNew": 10,
+			"extendRev": 50,
+			"newToday":  []int{0, 0},
+			"revToday":  []int{0, 0},
+			"lrnToday":  []int{0, 0},
+			"timeToday": []int{0, 0},
+		}
+	}
+
+	deckConf := map[string]interface{}{
+		"id":       1,
+		"name":     "Default",
+		"mod":      0,
+		"usn":      0,
+		"maxTaken": 60,
+		"timer":    0,
+		"autoplay": true,
+		"replayq":  true,
+		"new": map[string]interface{}{
+			"perDay": 20, "delays": []int{1, 10}, "ints": []int{1, 4, 7},
+			"initialFactor": 2500, "separate": true, "order": 1, "bury": true,
+		},
+		"rev": map[string]interface{}{
+			"perDay": 100, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1,
+			"maxIvl": 36500, "minSpace": 1, "bury": true,
+		},
+		"lapse": map[string]interface{}{
+			"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0,
+		},
+	}
+
+	collConf := map[string]interface{}{
+		"nextPos":       len(b.notes) + 1,
+		"estTimes":      true,
+		"activeDecks":   []int64{deckID},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       deckID,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      strconv.FormatInt(modelID, 10),
+		"collapseTime":  1200,
+	}
+
+	parts := []interface{}{
+		map[string]interface{}{strconv.FormatInt(modelID, 10): model},
+		map[string]interface{}{
+			"1":                           deck(1, "Default"),
+			strconv.FormatInt(deckID, 10): deck(deckID, b.DeckName),
+		},
+		map[string]interface{}{"1": deckConf},
+		collConf,
+	}
+	out := make([]string, len(parts))
+	for i, p := range parts {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("marshaling collection: %w", err)
+		}
+		out[i] = string(data)
+	}
+
+	return out[0], out[1], out[2], out[3], nil
+}
+
+// StableID derives a positive ID from a name so that rebuilding a deck
+// reuses the same deck and note type IDs in the user's collection.
+func StableID(name string) int64 {
+	h := sha256.Sum256([]byte(name))
+	// Keep it in the millisecond-timestamp range Anki uses for IDs
+	return 1_000_000_000_000 + int64(binary.BigEndian.Uint64(h[:8])%1_000_000_000_000)
+}
+
+// StableGUID derives a note GUID from a key, so that a rebuilt deck
+// updates the existing notes instead of adding duplicates.
+func StableGUID(key string) string {
+	h := sha256.Sum256([]byte("hmm:" + key))
+	return base64.RawURLEncoding.EncodeToString(h[:8])
+}
+
+// checksum returns the note checksum for a sort field
+// (first 8 hex digits of its SHA256).
+func checksum(sfld string) int64 {
+	h := sha256.Sum256([]byte(sfld))
+	csum, _ := strconv.ParseInt(fmt.Sprintf("%x", h[:4]), 16, 64)
+	return csum
+}
@@ -0,0 +1,51 @@
+package anki
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors OpenPackage returns, wrapped, when a file cannot be read as a
+// deck, so callers can tell the user what to do about it with Suggestion.
+var (
+	// ErrNotApkg means the file is not a zip holding an Anki collection,
+	// or the collection in it is damaged.
+	ErrNotApkg = errors.New("not an Anki package")
+
+	// ErrUnsupportedSchema means the collection is in a format this
+	// reader does not know, such as the compressed one newer Anki
+	// versions export by default.
+	ErrUnsupportedSchema = errors.New("unsupported Anki collection format")
+
+	// ErrEncrypted means the package is password protected.
+	ErrEncrypted = errors.New("package is password protected")
+)
+
+// Suggestion returns what the user can do about an error OpenPackage
+// returned, or "" if there is nothing to suggest.
+func Suggestion(err error) string {
+	switch {
+	case errors.Is(err, ErrEncrypted):
+		return "Export the deck again from Anki (File > Export) without a password."
+	case errors.Is(err, ErrUnsupportedSchema):
+		return `Export the deck again from Anki (File > Export) with "Support older Anki versions" checked.`
+	case errors.Is(err, ErrNotApkg):
+		return "Check that the file is an .apkg exported from Anki and was copied or downloaded in full."
+	}
+	return ""
+}
+
+// queryError wraps an error from reading the collection database, telling
+// a damaged collection and one without the tables this reader expects
+// from other failures.
+func queryError(what string, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "no such table"), strings.Contains(msg, "no such column"):
+		return fmt.Errorf("%w: %s: %v", ErrUnsupportedSchema, what, err)
+	case strings.Contains(msg, "not a database"), strings.Contains(msg, "malformed"):
+		return fmt.Errorf("%w: %s: %v", ErrNotApkg, what, err)
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
@@ -5,11 +5,17 @@ import (
 	"archive/zip"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -18,11 +24,23 @@ import (
 type Package struct {
 	path    string
 	tempDir string
+	archive *zip.ReadCloser
+	files   map[string]*zip.File // The files in the package by name
 	db      *sql.DB
 	Models  map[int64]*Model
 	Decks   map[int64]*Deck
 	Notes   []*Note
 	Cards   []*Card
+
+	// Only the collection and the media manifest are extracted when the
+	// package is opened; media files are extracted as they are asked for
+	mediaMu    sync.Mutex
+	media      map[string]string // Media file name to its file in the package
+	extracted  map[string]bool   // Files extracted into tempDir
+	mediaJSON  bool              // Whether the manifest is JSON, which AddMedia can extend
+	mediaAdded bool              // Whether AddMedia changed the manifest
+
+	closeOnce sync.Once
 }
 
 // Model represents an Anki note type (model).
@@ -89,34 +107,80 @@ type Card struct {
 	Data   string
 }
 
+// ProgressFunc is told which step of opening a package has started, and
+// how many of the total steps are done before it.
+type ProgressFunc func(step string, done, total int)
+
+// openSteps is how many steps opening a package reports.
+const openSteps = 4
+
+// SQLiteVersion returns the version of the SQLite driver packages are read
+// with, which also checks that it works on this system.
+func SQLiteVersion() (string, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRow("SELECT sqlite_version()").Scan(&version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
 // OpenPackage opens an Anki .apkg file for reading.
 func OpenPackage(path string) (*Package, error) {
+	return OpenPackageWithProgress(path, nil)
+}
+
+// OpenPackageWithProgress opens an Anki .apkg file for reading, calling
+// progress (if not nil) as each step starts: extracting, loading the
+// collection, loading notes, and loading cards.
+func OpenPackageWithProgress(path string, progress ProgressFunc) (*Package, error) {
+	if progress == nil {
+		progress = func(string, int, int) {}
+	}
+	start := time.Now()
+
 	pkg := &Package{
-		path:   path,
-		Models: make(map[int64]*Model),
-		Decks:  make(map[int64]*Deck),
+		path:      path,
+		Models:    make(map[int64]*Model),
+		Decks:     make(map[int64]*Deck),
+		extracted: make(map[string]bool),
 	}
 
 	// Create temp directory
-	tempDir, err := os.MkdirTemp("", "anki-*")
+	tempDir, err := newTempDir("open")
 	if err != nil {
 		return nil, fmt.Errorf("creating temp dir: %w", err)
 	}
 	pkg.tempDir = tempDir
+	openMu.Lock()
+	openPackages[pkg] = true
+	openMu.Unlock()
 
-	// Extract .apkg (it's a zip file)
-	if err := pkg.extract(); err != nil {
+	// Extract the collection from the .apkg (it's a zip file)
+	progress("Extracting", 0, openSteps)
+	if err := pkg.openArchive(); err != nil {
 		pkg.Close()
 		return nil, err
 	}
-
-	// Open the SQLite database
-	dbPath := filepath.Join(tempDir, "collection.anki2")
-	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
-		// Try anki21 format
-		dbPath = filepath.Join(tempDir, "collection.anki21")
+	name, err := pkg.collectionName()
+	if err != nil {
+		pkg.Close()
+		return nil, err
 	}
+	dbPath, err := pkg.extractFile(name)
+	if err != nil {
+		pkg.Close()
+		return nil, err
+	}
+
+	pkg.loadMedia()
 
+	slog.Debug("opening Anki database", "path", dbPath)
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
 		pkg.Close()
@@ -125,87 +189,140 @@ func OpenPackage(path string) (*Package, error) {
 	pkg.db = db
 
 	// Load collection metadata
+	progress("Loading collection", 1, openSteps)
 	if err := pkg.loadCollection(); err != nil {
 		pkg.Close()
 		return nil, err
 	}
 
 	// Load notes
+	progress("Loading notes", 2, openSteps)
 	if err := pkg.loadNotes(); err != nil {
 		pkg.Close()
 		return nil, err
 	}
 
 	// Load cards
+	progress("Loading cards", 3, openSteps)
 	if err := pkg.loadCards(); err != nil {
 		pkg.Close()
 		return nil, err
 	}
 
+	slog.Info("opened Anki package", "path", path, "models", len(pkg.Models), "notes", len(pkg.Notes),
+		"cards", len(pkg.Cards), "media", len(pkg.media), "duration", time.Since(start))
 	return pkg, nil
 }
 
-// extract unzips the .apkg file.
-func (p *Package) extract() error {
+// openArchive opens the .apkg as a zip and lists its files.
+func (p *Package) openArchive() error {
 	r, err := zip.OpenReader(p.path)
+	if errors.Is(err, zip.ErrFormat) || errors.Is(err, zip.ErrAlgorithm) {
+		return fmt.Errorf("%w: opening zip: %v", ErrNotApkg, err)
+	}
 	if err != nil {
 		return fmt.Errorf("opening zip: %w", err)
 	}
-	defer r.Close()
+	p.archive = r
 
+	p.files = make(map[string]*zip.File, len(r.File))
 	for _, f := range r.File {
-		fpath := filepath.Join(p.tempDir, f.Name)
-
-		// Prevent zip slip
-		if !strings.HasPrefix(fpath, filepath.Clean(p.tempDir)+string(os.PathSeparator)) {
-			return fmt.Errorf("illegal file path: %s", fpath)
+		// Bit 0 of the general purpose flags marks an encrypted entry
+		if f.Flags&0x1 != 0 {
+			return ErrEncrypted
 		}
-
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
-			continue
+		if !f.FileInfo().IsDir() {
+			p.files[f.Name] = f
 		}
+	}
+	return nil
+}
 
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
-		}
+// extractFile extracts the named file of the package into the temp
+// directory, unless it already is, and returns its path there.
+func (p *Package) extractFile(name string) (string, error) {
+	fpath := filepath.Join(p.tempDir, name)
+	if p.extracted[name] {
+		return fpath, nil
+	}
 
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
+	// Prevent zip slip
+	if !strings.HasPrefix(fpath, filepath.Clean(p.tempDir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path: %s", fpath)
+	}
 
-		rc, err := f.Open()
-		if err != nil {
-			outFile.Close()
-			return err
-		}
+	f, ok := p.files[name]
+	if !ok {
+		return "", fmt.Errorf("no file %s in package", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		return "", err
+	}
 
-		_, err = io.Copy(outFile, rc)
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return "", err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
 		outFile.Close()
-		rc.Close()
+		return "", err
+	}
 
-		if err != nil {
-			return err
-		}
+	_, err = io.Copy(outFile, rc)
+	outFile.Close()
+	rc.Close()
+
+	if errors.Is(err, zip.ErrChecksum) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("%w: extracting %s: %v", ErrNotApkg, name, err)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	p.extracted[name] = true
+	return fpath, nil
+}
+
+// collectionName returns the name of the collection database in the
+// package. Newer Anki versions put a collection.anki2 holding only a note
+// asking to update Anki next to the real collection, which is
+// collection.anki21 or, from Anki 2.1.50 unless asked to support older
+// versions, a zstd-compressed collection.anki21b.
+func (p *Package) collectionName() (string, error) {
+	switch {
+	case p.hasFile("collection.anki21"):
+		return "collection.anki21", nil
+	case p.hasFile("collection.anki21b"):
+		return "", fmt.Errorf("%w: the collection is compressed (collection.anki21b)", ErrUnsupportedSchema)
+	case p.hasFile("collection.anki2"):
+		return "collection.anki2", nil
+	}
+	return "", fmt.Errorf("%w: no collection in the zip", ErrNotApkg)
+}
+
+// hasFile reports whether the package has a file called name.
+func (p *Package) hasFile(name string) bool {
+	_, ok := p.files[name]
+	return ok
 }
 
 // loadCollection loads models and decks from the col table.
 func (p *Package) loadCollection() error {
 	var models, decks string
 
+	slog.Debug("SQL query", "sql", "SELECT models, decks FROM col")
 	row := p.db.QueryRow("SELECT models, decks FROM col")
 	if err := row.Scan(&models, &decks); err != nil {
-		return fmt.Errorf("reading collection: %w", err)
+		return queryError("reading collection", err)
 	}
 
 	// Parse models
 	var modelsMap map[string]json.RawMessage
 	if err := json.Unmarshal([]byte(models), &modelsMap); err != nil {
-		return fmt.Errorf("parsing models: %w", err)
+		return fmt.Errorf("%w: parsing models: %v", ErrUnsupportedSchema, err)
 	}
 
 	for _, modelJSON := range modelsMap {
@@ -219,7 +336,7 @@ func (p *Package) loadCollection() error {
 	// Parse decks
 	var decksMap map[string]json.RawMessage
 	if err := json.Unmarshal([]byte(decks), &decksMap); err != nil {
-		return fmt.Errorf("parsing decks: %w", err)
+		return fmt.Errorf("%w: parsing decks: %v", ErrUnsupportedSchema, err)
 	}
 
 	for _, deckJSON := range decksMap {
@@ -235,12 +352,13 @@ func (p *Package) loadCollection() error {
 
 // loadNotes loads all notes from the database.
 func (p *Package) loadNotes() error {
+	start := time.Now()
 	rows, err := p.db.Query(`
 		SELECT id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data
-		FROM notes
+		FROM notes ORDER BY id
 	`)
 	if err != nil {
-		return fmt.Errorf("querying notes: %w", err)
+		return queryError("querying notes", err)
 	}
 	defer rows.Close()
 
@@ -258,17 +376,19 @@ func (p *Package) loadNotes() error {
 		p.Notes = append(p.Notes, &note)
 	}
 
+	slog.Debug("SQL query", "sql", "SELECT ... FROM notes", "rows", len(p.Notes), "duration", time.Since(start))
 	return rows.Err()
 }
 
 // loadCards loads all cards from the database.
 func (p *Package) loadCards() error {
+	start := time.Now()
 	rows, err := p.db.Query(`
 		SELECT id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses, left, odue, odid, flags, data
-		FROM cards
+		FROM cards ORDER BY id
 	`)
 	if err != nil {
-		return fmt.Errorf("querying cards: %w", err)
+		return queryError("querying cards", err)
 	}
 	defer rows.Close()
 
@@ -284,6 +404,7 @@ func (p *Package) loadCards() error {
 		p.Cards = append(p.Cards, &card)
 	}
 
+	slog.Debug("SQL query", "sql", "SELECT ... FROM cards", "rows", len(p.Cards), "duration", time.Since(start))
 	return rows.Err()
 }
 
@@ -292,11 +413,54 @@ func (p *Package) GetModel(note *Note) *Model {
 	return p.Models[note.ModelID]
 }
 
+// DeckList returns the decks sorted by name, then ID, so they are listed
+// the same way on every run.
+func (p *Package) DeckList() []*Deck {
+	decks := make([]*Deck, 0, len(p.Decks))
+	for _, d := range p.Decks {
+		decks = append(decks, d)
+	}
+	sort.Slice(decks, func(i, j int) bool {
+		if decks[i].Name != decks[j].Name {
+			return decks[i].Name < decks[j].Name
+		}
+		return decks[i].ID < decks[j].ID
+	})
+	return decks
+}
+
+// ModelList returns the note types sorted by name, then ID, so they are
+// listed the same way on every run.
+func (p *Package) ModelList() []*Model {
+	models := make([]*Model, 0, len(p.Models))
+	for _, m := range p.Models {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Name != models[j].Name {
+			return models[i].Name < models[j].Name
+		}
+		return models[i].ID < models[j].ID
+	})
+	return models
+}
+
 // GetDeck returns the deck for a card.
 func (p *Package) GetDeck(card *Card) *Deck {
 	return p.Decks[card.DeckID]
 }
 
+// GetNoteDeck returns the deck of a note's first card, or nil if the note
+// has no cards.
+func (p *Package) GetNoteDeck(note *Note) *Deck {
+	for _, card := range p.Cards {
+		if card.NoteID == note.ID {
+			return p.GetDeck(card)
+		}
+	}
+	return nil
+}
+
 // GetNoteByID finds a note by ID.
 func (p *Package) GetNoteByID(id int64) *Note {
 	for _, note := range p.Notes {
@@ -337,14 +501,116 @@ func (p *Package) GetFieldNames(note *Note) []string {
 	return names
 }
 
-// Close cleans up resources.
-func (p *Package) Close() error {
-	if p.db != nil {
-		p.db.Close()
+// TagList returns the tags of a note.
+func (n *Note) TagList() []string {
+	return strings.Fields(n.Tags)
+}
+
+// HasTag reports whether a note has tag, ignoring case as Anki does.
+func (n *Note) HasTag(tag string) bool {
+	for _, t := range n.TagList() {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMedia reads the media manifest, which maps the numbered files in the
+// package to their names. Packages without one, or with the newer binary
+// manifest, have no media as far as hmm is concerned.
+func (p *Package) loadMedia() {
+	p.media = make(map[string]string)
+
+	if !p.hasFile("media") {
+		p.mediaJSON = true
+		return
+	}
+	path, err := p.extractFile("media")
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return
 	}
-	if p.tempDir != "" {
-		os.RemoveAll(p.tempDir)
+	p.mediaJSON = true
+	for file, name := range manifest {
+		p.media[name] = file
 	}
+}
+
+// Path returns the path the package was opened from.
+func (p *Package) Path() string {
+	return p.path
+}
+
+// Media returns the path of the media file name, extracting it if it has
+// not been yet, and whether the package has it.
+func (p *Package) Media(name string) (string, bool) {
+	p.mediaMu.Lock()
+	defer p.mediaMu.Unlock()
+
+	file, ok := p.media[name]
+	if !ok {
+		return "", false
+	}
+	path, err := p.extractFile(file)
+	if err != nil {
+		slog.Warn("could not extract media file", "name", name, "error", err)
+		return "", false
+	}
+	return path, true
+}
+
+// HasMedia reports whether the package has the media file name, without
+// extracting it.
+func (p *Package) HasMedia(name string) bool {
+	p.mediaMu.Lock()
+	defer p.mediaMu.Unlock()
+
+	_, ok := p.media[name]
+	return ok
+}
+
+// soundTag matches the [sound:file.mp3] references Anki puts in fields.
+var soundTag = regexp.MustCompile(`\[sound:([^\]]+)\]`)
+
+// NoteAudio returns the path of the first sound file referenced in the
+// note's fields, extracting it, or "" if the package has none for it.
+func (p *Package) NoteAudio(note *Note) string {
+	for _, field := range note.Fields {
+		for _, match := range soundTag.FindAllStringSubmatch(field, -1) {
+			if path, ok := p.Media(match[1]); ok {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// Close cleans up resources, removing the temp directory. It is safe to
+// call more than once.
+func (p *Package) Close() error {
+	p.closeOnce.Do(func() {
+		openMu.Lock()
+		delete(openPackages, p)
+		openMu.Unlock()
+
+		if p.db != nil {
+			p.db.Close()
+		}
+		if p.archive != nil {
+			p.archive.Close()
+		}
+		if p.tempDir != "" {
+			os.RemoveAll(p.tempDir)
+		}
+	})
 	return nil
 }
 
@@ -354,11 +620,11 @@ func (p *Package) Summary() string {
 
 	sb.WriteString(fmt.Sprintf("Anki Package: %s\n", p.path))
 	sb.WriteString(fmt.Sprintf("  Decks: %d\n", len(p.Decks)))
-	for _, deck := range p.Decks {
+	for _, deck := range p.DeckList() {
 		sb.WriteString(fmt.Sprintf("    - %s\n", deck.Name))
 	}
 	sb.WriteString(fmt.Sprintf("  Models (Note Types): %d\n", len(p.Models)))
-	for _, model := range p.Models {
+	for _, model := range p.ModelList() {
 		sb.WriteString(fmt.Sprintf("    - %s (%d fields)\n", model.Name, len(model.Fields)))
 	}
 	sb.WriteString(fmt.Sprintf("  Notes: %d\n", len(p.Notes)))
@@ -0,0 +1,117 @@
+package anki
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Packages are read and built in temp directories named after the
+// process using them, so StaleTempDirs can tell the directories a killed
+// process left behind from those still in use.
+var tempDirName = regexp.MustCompile(`^hmm-anki-(?:open|build)-(\d+)-\d+$`)
+
+// legacyTempDirName matches the temp directories of versions that did not
+// name them after their process.
+var legacyTempDirName = regexp.MustCompile(`^anki-(?:build-)?\d+$`)
+
+// legacyTempDirAge is how long a legacy temp directory is left alone, in
+// case the process that made it is still running.
+const legacyTempDirAge = 24 * time.Hour
+
+// newTempDir creates a temp directory for this process to open or build
+// a package in.
+func newTempDir(kind string) (string, error) {
+	return os.MkdirTemp("", fmt.Sprintf("hmm-anki-%s-%d-*", kind, os.Getpid()))
+}
+
+// openPackages are the packages not closed yet, which CloseAll closes.
+var (
+	openMu       sync.Mutex
+	openPackages = make(map[*Package]bool)
+)
+
+// CloseAll closes every package still open, removing their temp
+// directories. Call it before exiting, including on a signal.
+func CloseAll() {
+	openMu.Lock()
+	pkgs := make([]*Package, 0, len(openPackages))
+	for p := range openPackages {
+		pkgs = append(pkgs, p)
+	}
+	openMu.Unlock()
+
+	for _, p := range pkgs {
+		p.Close()
+	}
+}
+
+// StaleTempDirs returns the temp directories that packages were opened or
+// built in by processes that are no longer running.
+func StaleTempDirs() ([]string, error) {
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(os.TempDir(), e.Name())
+		if m := tempDirName.FindStringSubmatch(e.Name()); m != nil {
+			pid, err := strconv.Atoi(m[1])
+			if err == nil && pid != os.Getpid() && !processRunning(pid) {
+				stale = append(stale, path)
+			}
+			continue
+		}
+		if legacyTempDirName.MatchString(e.Name()) && isLegacyTempDir(path) {
+			stale = append(stale, path)
+		}
+	}
+	return stale, nil
+}
+
+// isLegacyTempDir reports whether the directory at path holds a
+// collection and has not been touched for legacyTempDirAge, so it is an
+// old package's rather than another program's or one in use.
+func isLegacyTempDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) < legacyTempDirAge {
+		return false
+	}
+	for _, name := range []string{"collection.anki2", "collection.anki21"} {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// processRunning reports whether the process pid is running.
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	defer p.Release()
+
+	switch runtime.GOOS {
+	case "windows":
+		// FindProcess only finds processes that are running
+		return true
+	default:
+		// Signal 0 checks the process exists without signalling it
+		err := p.Signal(syscall.Signal(0))
+		return err == nil || errors.Is(err, os.ErrPermission)
+	}
+}
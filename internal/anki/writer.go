@@ -2,10 +2,10 @@ package anki
 
 import (
 	"archive/zip"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -31,8 +31,27 @@ type AugmentedData struct {
 	ImagePrompt string
 }
 
+// Values returns the field values in the order of HMMFields.
+func (d AugmentedData) Values() []string {
+	return []string{d.Actor, d.Set, d.ToneRoom, d.Props, d.ImagePrompt}
+}
+
+// BreakdownField is the single field notes of words get instead of the
+// HMM fields: the word's reading and meaning and an HTML table of the
+// HMM breakdown of its characters.
+const BreakdownField = "HMM_Breakdown"
+
+// AudioField is the field augmented notes get when their pronunciation is
+// added, holding a [sound:...] reference to it.
+const AudioField = "HMM_Audio"
+
 // AddHMMFieldsToModel adds HMM fields to a model if they don't exist.
 func (p *Package) AddHMMFieldsToModel(modelID int64) error {
+	return p.AddFieldsToModel(modelID, HMMFields...)
+}
+
+// AddFieldsToModel adds the named fields to a model if they don't exist.
+func (p *Package) AddFieldsToModel(modelID int64, names ...string) error {
 	model, ok := p.Models[modelID]
 	if !ok {
 		return fmt.Errorf("model %d not found", modelID)
@@ -44,9 +63,9 @@ func (p *Package) AddHMMFieldsToModel(modelID int64) error {
 		existingFields[f.Name] = true
 	}
 
-	// Add missing HMM fields
+	// Add missing fields
 	nextOrd := len(model.Fields)
-	for _, fieldName := range HMMFields {
+	for _, fieldName := range names {
 		if !existingFields[fieldName] {
 			model.Fields = append(model.Fields, Field{
 				Name:   fieldName,
@@ -107,6 +126,88 @@ func (p *Package) SetNoteHMMData(note *Note, data AugmentedData) error {
 	return nil
 }
 
+// SetNoteField sets the value of the named field of a note.
+func (p *Package) SetNoteField(note *Note, name, value string) error {
+	model := p.GetModel(note)
+	if model == nil {
+		return fmt.Errorf("model not found for note %d", note.ID)
+	}
+
+	for len(note.Fields) < len(model.Fields) {
+		note.Fields = append(note.Fields, "")
+	}
+	for _, f := range model.Fields {
+		if f.Name == name {
+			note.Fields[f.Ord] = value
+			note.RawFlds = strings.Join(note.Fields, "\x1f")
+			note.Mod = time.Now().Unix()
+			return nil
+		}
+	}
+	return fmt.Errorf("note %d has no field %s", note.ID, name)
+}
+
+// AddMedia copies the file at src into the package as the media file
+// name, for fields to reference as [sound:name] or <img src="name">. A
+// file of that name already in the package is kept.
+func (p *Package) AddMedia(name, src string) error {
+	p.mediaMu.Lock()
+	defer p.mediaMu.Unlock()
+
+	if _, ok := p.media[name]; ok {
+		return nil
+	}
+	if !p.mediaJSON {
+		return fmt.Errorf("adding media to packages with a binary media manifest is not supported")
+	}
+
+	// Media files are numbered in the package; take the next free number
+	next := len(p.media)
+	for _, file := range p.media {
+		if n, err := strconv.Atoi(file); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	file := strconv.Itoa(next)
+	dst := filepath.Join(p.tempDir, file)
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	p.media[name] = file
+	p.extracted[file] = true
+	p.mediaAdded = true
+	return nil
+}
+
+// writeMediaManifest writes the manifest of the package's media files.
+func (p *Package) writeMediaManifest() error {
+	manifest := make(map[string]string, len(p.media))
+	for name, file := range p.media {
+		manifest[file] = name
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(p.tempDir, "media"), data, 0644)
+}
+
 // SaveAs writes the modified package to a new .apkg file.
 func (p *Package) SaveAs(outputPath string) error {
 	// Update the database first
@@ -114,6 +215,14 @@ func (p *Package) SaveAs(outputPath string) error {
 		return fmt.Errorf("updating database: %w", err)
 	}
 
+	if p.mediaAdded {
+		if err := p.writeMediaManifest(); err != nil {
+			return fmt.Errorf("writing media manifest: %w", err)
+		}
+	}
+
+	slog.Info("writing Anki package", "path", outputPath, "notes", len(p.Notes), "media", len(p.media))
+
 	// Create the output file
 	outFile, err := os.Create(outputPath)
 	if err != nil {
@@ -125,7 +234,9 @@ func (p *Package) SaveAs(outputPath string) error {
 	zipWriter := zip.NewWriter(outFile)
 	defer zipWriter.Close()
 
-	// Walk the temp directory and add all files to the zip
+	// Walk the temp directory and add all files to the zip, then copy the
+	// files never extracted from the package as they are
+	written := make(map[string]bool)
 	err = filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -140,6 +251,7 @@ func (p *Package) SaveAs(outputPath string) error {
 		if err != nil {
 			return err
 		}
+		written[filepath.ToSlash(relPath)] = true
 
 		// Create zip entry
 		writer, err := zipWriter.Create(relPath)
@@ -162,6 +274,15 @@ func (p *Package) SaveAs(outputPath string) error {
 		return fmt.Errorf("creating zip: %w", err)
 	}
 
+	for _, f := range p.archive.File {
+		if written[f.Name] || f.FileInfo().IsDir() {
+			continue
+		}
+		if err := zipWriter.Copy(f); err != nil {
+			return fmt.Errorf("creating zip: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -201,6 +322,7 @@ func (p *Package) updateModels() error {
 		return fmt.Errorf("marshaling models: %w", err)
 	}
 
+	slog.Debug("SQL exec", "sql", "UPDATE col SET models", "models", len(p.Models))
 	_, err = p.db.Exec("UPDATE col SET models = ?", string(modelsJSON))
 	if err != nil {
 		return fmt.Errorf("updating models: %w", err)
@@ -211,15 +333,9 @@ func (p *Package) updateModels() error {
 
 // updateNotes updates all modified notes in the database.
 func (p *Package) updateNotes() error {
+	start := time.Now()
 	for _, note := range p.Notes {
-		// Calculate new checksum (first 8 digits of SHA256 of sort field)
-		h := sha256.New()
-		h.Write([]byte(note.SFLD))
-		hashStr := fmt.Sprintf("%x", h.Sum(nil))
-		if len(hashStr) >= 8 {
-			csum, _ := strconv.ParseInt(hashStr[:8], 16, 64)
-			note.CSum = csum
-		}
+		note.CSum = checksum(note.SFLD)
 
 		_, err := p.db.Exec(`
 			UPDATE notes SET
@@ -235,5 +351,6 @@ func (p *Package) updateNotes() error {
 		}
 	}
 
+	slog.Debug("SQL exec", "sql", "UPDATE notes", "rows", len(p.Notes), "duration", time.Since(start))
 	return nil
 }
@@ -0,0 +1,107 @@
+// Package audio plays pronunciation recordings through the system's own
+// audio player.
+package audio
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// extensions are the audio file types looked for in the cache, in order.
+var extensions = []string{".mp3", ".ogg", ".wav", ".m4a"}
+
+var (
+	cacheMu  sync.Mutex
+	cacheDir string
+)
+
+// SetCacheDir sets the directory of pronunciation recordings, such as
+// those of a TTS tool, named after the character they say (好.mp3).
+func SetCacheDir(dir string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cacheDir = dir
+}
+
+// CacheDir returns the directory of pronunciation recordings, or "" if
+// none was set.
+func CacheDir() string {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return cacheDir
+}
+
+// Cached returns the recording of char in the cache directory, or "" if
+// there is none.
+func Cached(char string) string {
+	cacheMu.Lock()
+	dir := cacheDir
+	cacheMu.Unlock()
+
+	if dir == "" || char == "" {
+		return ""
+	}
+	for _, ext := range extensions {
+		path := filepath.Join(dir, char+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// player returns the command that plays path, or nil if no player was
+// found.
+func player(path string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("afplay", path)
+	case "windows":
+		// MediaPlayer plays mp3 as well as wav; wait for it to finish
+		script := fmt.Sprintf(`Add-Type -AssemblyName presentationCore; `+
+			`$p = New-Object System.Windows.Media.MediaPlayer; $p.Open('%s'); `+
+			`while (-not $p.NaturalDuration.HasTimeSpan) { Start-Sleep -Milliseconds 50 }; `+
+			`$p.Play(); Start-Sleep -Seconds $p.NaturalDuration.TimeSpan.TotalSeconds`,
+			strings.ReplaceAll(path, "'", "''"))
+		return exec.Command("powershell", "-NoProfile", "-Command", script)
+	}
+
+	// aplay only plays wav, so prefer players that handle any format
+	players := [][]string{
+		{"mpv", "--no-video", "--really-quiet"},
+		{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"},
+		{"paplay"},
+		{"aplay", "-q"},
+	}
+	if strings.EqualFold(filepath.Ext(path), ".mp3") {
+		players = append([][]string{{"mpg123", "-q"}}, players...)
+	}
+	for _, p := range players {
+		if _, err := exec.LookPath(p[0]); err == nil {
+			return exec.Command(p[0], append(p[1:], path)...)
+		}
+	}
+	return nil
+}
+
+// Available reports whether a player was found to play recordings with.
+func Available() bool {
+	return player("x.mp3") != nil
+}
+
+// Play plays the recording at path and waits until it has finished.
+func Play(path string) error {
+	cmd := player(path)
+	if cmd == nil {
+		return fmt.Errorf("no audio player found (install mpv, ffplay, or mpg123)")
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("playing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
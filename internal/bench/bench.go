@@ -0,0 +1,130 @@
+// Package bench holds the fixtures of the benchmarks of the hot paths,
+// shared by the Benchmark functions of the packages they measure and by
+// hmm bench, and times them for hmm bench without the testing package.
+package bench
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/decomp"
+)
+
+// DeckNotes is how many notes the generated deck has by default.
+const DeckNotes = 50000
+
+// benchTime is how long Run keeps calling an operation for.
+const benchTime = time.Second
+
+// Chars returns the characters to benchmark on, those of the dictionary
+// d or, if d is nil, the most common block of CJK ideographs, with the
+// decompositions of those d has.
+func Chars(d *decomp.Dictionary) (chars, decomps []string) {
+	for r := rune(0x4E00); r <= 0x9FFF; r++ {
+		char := string(r)
+		if d == nil {
+			chars = append(chars, char)
+			continue
+		}
+		if entry := d.Lookup(char); entry != nil {
+			chars = append(chars, char)
+			decomps = append(decomps, entry.Decomposition)
+		}
+	}
+	return chars, decomps
+}
+
+// Dictionary returns the path of the dictionary checked into the data
+// directory of the repository, looking up from dir, or "" if there is
+// none.
+func Dictionary(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, "data", "dictionary.jsonl")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// WriteDeck writes a deck of n notes, one per character of chars in
+// turn, to path.
+func WriteDeck(path string, n int, chars []string) error {
+	builder, err := anki.NewDeckBuilder("Bench", "Bench", []string{"Hanzi", "Pinyin", "Meaning"})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		char := chars[i%len(chars)]
+		fields := []string{char, "pinyin " + strconv.Itoa(i), "meaning of " + char}
+		if err := builder.AddNote("bench-"+strconv.Itoa(i), fields, "bench"); err != nil {
+			return err
+		}
+	}
+	return builder.Write(path)
+}
+
+// Result is how long an operation took and how much it allocated, on
+// average over N calls.
+type Result struct {
+	N           int
+	PerOp       time.Duration
+	AllocsPerOp int64
+}
+
+// Run calls op more and more times, as go test -bench does, until the
+// calls take about a second, and returns the average. It stops at the
+// first error.
+func Run(op func() error) (Result, error) {
+	n := 1
+	for {
+		elapsed, allocs, err := runN(op, n)
+		if err != nil {
+			return Result{}, err
+		}
+		if elapsed >= benchTime || n >= 1e9 {
+			return Result{
+				N:           n,
+				PerOp:       elapsed / time.Duration(n),
+				AllocsPerOp: int64(allocs) / int64(n),
+			}, nil
+		}
+
+		// Aim for a second from the time taken so far, growing by at most
+		// 100x and at least by one
+		next := n * 100
+		if elapsed > 0 {
+			next = min(next, int(int64(benchTime)*int64(n)/int64(elapsed)*6/5))
+		}
+		n = max(next, n+1)
+	}
+}
+
+// runN calls op n times and returns how long that took and how many
+// allocations it made.
+func runN(op func() error, n int) (time.Duration, uint64, error) {
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		if err := op(); err != nil {
+			return 0, 0, err
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return elapsed, after.Mallocs - before.Mallocs, nil
+}
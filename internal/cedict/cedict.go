@@ -0,0 +1,110 @@
+// Package cedict looks up the readings and meanings of words in
+// CC-CEDICT (https://cc-cedict.org), the dictionary of Chinese words.
+package cedict
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileName is the name the CC-CEDICT file is looked for under.
+const FileName = "cedict_ts.u8"
+
+// Entry is one reading of a word.
+type Entry struct {
+	Traditional string
+	Simplified  string
+	Pinyin      string // With tone numbers, as CC-CEDICT writes it
+	Definitions []string
+}
+
+// Dictionary holds the entries of CC-CEDICT by word, in both scripts.
+type Dictionary struct {
+	entries map[string][]*Entry
+}
+
+// New creates an empty dictionary.
+func New() *Dictionary {
+	return &Dictionary{entries: make(map[string][]*Entry)}
+}
+
+// LoadFromFile loads the entries of a cedict_ts.u8 file.
+func (d *Dictionary) LoadFromFile(path string) error {
+	start := time.Now()
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening CC-CEDICT file: %w", err)
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry, ok := parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		d.entries[entry.Simplified] = append(d.entries[entry.Simplified], entry)
+		if entry.Traditional != entry.Simplified {
+			d.entries[entry.Traditional] = append(d.entries[entry.Traditional], entry)
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading CC-CEDICT file: %w", err)
+	}
+
+	slog.Info("loaded CC-CEDICT", "path", path, "entries", count, "duration", time.Since(start))
+	return nil
+}
+
+// Lookup returns the entries of word, in dictionary order, or nil if it
+// has none.
+func (d *Dictionary) Lookup(word string) []*Entry {
+	if d == nil {
+		return nil
+	}
+	return d.entries[word]
+}
+
+// parseLine parses a line such as
+//
+//	電腦 电脑 [dian4 nao3] /computer/CL:臺|台[tai2]/
+//
+// skipping comments and malformed lines.
+func parseLine(line string) (*Entry, bool) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, false
+	}
+
+	trad, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return nil, false
+	}
+	simp, rest, ok := strings.Cut(rest, " [")
+	if !ok {
+		return nil, false
+	}
+	reading, rest, ok := strings.Cut(rest, "] /")
+	if !ok {
+		return nil, false
+	}
+
+	var defs []string
+	for _, def := range strings.Split(strings.TrimSuffix(strings.TrimSpace(rest), "/"), "/") {
+		if def = strings.TrimSpace(def); def != "" {
+			defs = append(defs, def)
+		}
+	}
+
+	return &Entry{
+		Traditional: trad,
+		Simplified:  simp,
+		Pinyin:      reading,
+		Definitions: defs,
+	}, true
+}
@@ -2,6 +2,7 @@
 package clipboard
 
 import (
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -15,14 +16,18 @@ func Write(text string) error {
 	case "darwin":
 		cmd = exec.Command("pbcopy")
 	case "linux":
-		// Try xclip first, fall back to xsel
-		if _, err := exec.LookPath("xclip"); err == nil {
+		// Try wl-copy on Wayland, then xclip, then xsel
+		if wayland() {
+			cmd = exec.Command("wl-copy")
+		} else if _, err := exec.LookPath("xclip"); err == nil {
 			cmd = exec.Command("xclip", "-selection", "clipboard")
 		} else {
 			cmd = exec.Command("xsel", "--clipboard", "--input")
 		}
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "clip")
+		// clip reads its input in the console's code page, which garbles
+		// Chinese, so PowerShell reads it as UTF-8 instead
+		cmd = powershell(`[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())`)
 	default:
 		// Try xclip as fallback
 		cmd = exec.Command("xclip", "-selection", "clipboard")
@@ -32,6 +37,43 @@ func Write(text string) error {
 	return cmd.Run()
 }
 
+// Read returns the text on the system clipboard.
+func Read() (string, error) {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbpaste")
+	case "linux":
+		// Try wl-paste on Wayland, then xclip, then xsel
+		if wayland() {
+			cmd = exec.Command("wl-paste", "--no-newline")
+		} else if _, err := exec.LookPath("xclip"); err == nil {
+			cmd = exec.Command("xclip", "-selection", "clipboard", "-o")
+		} else {
+			cmd = exec.Command("xsel", "--clipboard", "--output")
+		}
+	case "windows":
+		// -Raw keeps the text in one piece instead of a line per item,
+		// written as UTF-8 rather than in the console's code page
+		cmd = powershell(`[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw`)
+	default:
+		// Try xclip as fallback
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-o")
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		// PowerShell ends its output with a line break of its own
+		text := strings.TrimSuffix(string(out), "\r\n")
+		return strings.ReplaceAll(text, "\r\n", "\n"), nil
+	}
+	return string(out), nil
+}
+
 // Available checks if clipboard functionality is available.
 func Available() bool {
 	switch runtime.GOOS {
@@ -39,14 +81,46 @@ func Available() bool {
 		_, err := exec.LookPath("pbcopy")
 		return err == nil
 	case "linux":
+		if wayland() {
+			return true
+		}
 		if _, err := exec.LookPath("xclip"); err == nil {
 			return true
 		}
 		_, err := exec.LookPath("xsel")
 		return err == nil
 	case "windows":
-		return true // clip is always available on Windows
+		// PowerShell reads and writes the clipboard on Windows
+		if _, err := exec.LookPath("powershell"); err == nil {
+			return true
+		}
+		_, err := exec.LookPath("pwsh")
+		return err == nil
 	default:
 		return false
 	}
 }
+
+// wayland reports whether this is a Wayland session with wl-clipboard
+// installed. xclip and xsel only reach the clipboard of X11 windows there,
+// so copies made with them silently go missing.
+func wayland() bool {
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		return false
+	}
+	if _, err := exec.LookPath("wl-copy"); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("wl-paste")
+	return err == nil
+}
+
+// powershell returns a command running script in Windows PowerShell, or
+// in PowerShell 7 (pwsh) where only that is installed.
+func powershell(script string) *exec.Cmd {
+	name := "powershell"
+	if _, err := exec.LookPath(name); err != nil {
+		name = "pwsh"
+	}
+	return exec.Command(name, "-NoProfile", "-NonInteractive", "-Command", script)
+}
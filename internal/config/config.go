@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/f3rmion/hmm/internal/hmm"
 	"gopkg.in/yaml.v3"
@@ -15,6 +16,61 @@ type Config struct {
 	Actors []hmm.Actor `yaml:"actors"`
 	Sets   []hmm.Set   `yaml:"sets"`
 	Props  []hmm.Prop  `yaml:"props"`
+
+	// Jyutping is set when the actors and sets are the Cantonese ones,
+	// one per Jyutping initial and final, loaded from JyutpingDir.
+	Jyutping bool `yaml:"-"`
+}
+
+// Sizes of a complete configuration: one actor per pinyin initial, one
+// set per final, and one prop per Kangxi radical.
+const (
+	FullActors = 55
+	FullSets   = 38
+	FullProps  = 214
+)
+
+// Sizes of a complete Cantonese configuration: one actor per Jyutping
+// initial and the null initial, and one set per Jyutping final.
+const (
+	FullJyutpingActors = 20
+	FullJyutpingSets   = 56
+)
+
+// JyutpingDir is the directory inside the config directory that holds the
+// Cantonese actors.yaml and sets.yaml. Props are shared with Mandarin.
+const JyutpingDir = "jyutping"
+
+// Completeness returns how much of a complete configuration has been
+// filled in, as the percentage of actors, sets, and props that have a name.
+func (c *Config) Completeness() int {
+	if c == nil {
+		return 0
+	}
+
+	var actors, sets, props int
+	for _, a := range c.Actors {
+		if a.Name != "" {
+			actors++
+		}
+	}
+	for _, s := range c.Sets {
+		if s.Name != "" {
+			sets++
+		}
+	}
+	for _, p := range c.Props {
+		if p.Name != "" {
+			props++
+		}
+	}
+
+	fullActors, fullSets := FullActors, FullSets
+	if c.Jyutping {
+		fullActors, fullSets = FullJyutpingActors, FullJyutpingSets
+	}
+	done := min(actors, fullActors) + min(sets, fullSets) + min(props, FullProps)
+	return done * 100 / (fullActors + fullSets + FullProps)
 }
 
 // PromptConfig holds settings for image prompt generation.
@@ -155,12 +211,64 @@ func SaveProps(path string, props []hmm.Prop) error {
 	return nil
 }
 
-// GetConfigDir returns the default configuration directory.
+// KeywordsFile is the file in the config directory that holds the
+// Remembering the Hanzi keywords imported with 'hmm import rth'.
+const KeywordsFile = "keywords.yaml"
+
+// LoadKeywords loads the RTH keywords of characters from a YAML file.
+func LoadKeywords(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keywords file: %w", err)
+	}
+
+	var keywords struct {
+		Keywords map[string]string `yaml:"keywords"`
+	}
+	if err := yaml.Unmarshal(data, &keywords); err != nil {
+		return nil, fmt.Errorf("parsing keywords file: %w", err)
+	}
+
+	return keywords.Keywords, nil
+}
+
+// SaveKeywords saves the RTH keywords of characters to a YAML file.
+func SaveKeywords(path string, keywords map[string]string) error {
+	data := struct {
+		Keywords map[string]string `yaml:"keywords"`
+	}{Keywords: keywords}
+
+	out, err := yaml.Marshal(&data)
+	if err != nil {
+		return fmt.Errorf("marshaling keywords: %w", err)
+	}
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("writing keywords file: %w", err)
+	}
+
+	return nil
+}
+
+// GetConfigDir returns the default configuration directory:
+// %APPDATA%\hmm on Windows, and elsewhere $XDG_CONFIG_HOME/hmm, which is
+// ~/.config/hmm by default.
 func GetConfigDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("APPDATA"); dir != "" {
+			return filepath.Join(dir, "hmm"), nil
+		}
+	} else if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "hmm"), nil
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "AppData", "Roaming", "hmm"), nil
+	}
 	return filepath.Join(home, ".config", "hmm"), nil
 }
 
@@ -175,3 +283,55 @@ func EnsureConfigDir() (string, error) {
 	}
 	return dir, nil
 }
+
+// GetDataDir returns the default directory for data files such as the
+// dictionary: $XDG_DATA_HOME/hmm (~/.local/share/hmm) on Linux and other
+// Unix systems, ~/Library/Application Support/hmm on macOS, and
+// %LOCALAPPDATA%\hmm on Windows.
+func GetDataDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "hmm"), nil
+		}
+	} else if dir := os.Getenv("XDG_DATA_HOME"); runtime.GOOS != "darwin" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "hmm"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(home, "AppData", "Local", "hmm"), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "hmm"), nil
+	default:
+		return filepath.Join(home, ".local", "share", "hmm"), nil
+	}
+}
+
+// DataDirs returns the directories data files are looked for in, the
+// user's first: GetDataDir, then the system's data directories
+// ($XDG_DATA_DIRS, /usr/local/share/hmm and /usr/share/hmm by default)
+// except on Windows.
+func DataDirs() []string {
+	var dirs []string
+	if dir, err := GetDataDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if runtime.GOOS == "windows" {
+		return dirs
+	}
+
+	system := os.Getenv("XDG_DATA_DIRS")
+	if system == "" {
+		system = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range filepath.SplitList(system) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, filepath.Join(dir, "hmm"))
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,410 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PathFiles are the files in the config directory that dotted paths can
+// address, named by their first segment (actors.b.name is in actors.yaml).
+var PathFiles = []string{"actors", "sets", "props", "ui", "theme"}
+
+// GetValue returns the value at a dotted path such as actors.b.name or
+// sets.ao.rooms.3.name. Items of a list are addressed by their id, by
+// their tone (rooms), or by their index. A scalar is returned as is;
+// anything else as YAML.
+func GetValue(dir, path string) (string, error) {
+	file, segments, err := splitPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	doc, _, err := readDocument(filepath.Join(dir, file+".yaml"))
+	if err != nil {
+		return "", err
+	}
+
+	node, _, err := resolve(root(doc, file), segments)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if node == nil {
+		return "", fmt.Errorf("%s: not set", path)
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// SetValue sets the scalar at a dotted path to value, adding the last key
+// to its mapping if it is missing. Only the value changes in the file:
+// comments, blank lines, and the rest of the layout are kept. The file is
+// only written if it still loads afterwards.
+func SetValue(dir, path, value string) error {
+	file, segments, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("%s: name a value inside %s.yaml", path, file)
+	}
+
+	filePath := filepath.Join(dir, file+".yaml")
+	doc, data, err := readDocument(filePath)
+	if err != nil {
+		return err
+	}
+
+	node, parent, err := resolve(root(doc, file), segments)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var out []byte
+	if node == nil {
+		out, err = insertKey(doc, data, parent, segments[len(segments)-1], value)
+	} else {
+		if node.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s: is a %s; set one of its values instead", path, kindName(node))
+		}
+		out, err = replaceScalar(doc, data, node, value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := validate(file, out); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(filePath, out, 0644)
+}
+
+// splitPath splits a dotted path into its file and the path inside it.
+func splitPath(path string) (string, []string, error) {
+	segments := strings.Split(path, ".")
+	for _, s := range segments {
+		if s == "" {
+			return "", nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+	for _, f := range PathFiles {
+		if segments[0] == f {
+			return f, segments[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown config file %q in %q (use %s)", segments[0], path, strings.Join(PathFiles, ", "))
+}
+
+// readDocument parses the YAML file at path into a node tree. A missing
+// file is an empty mapping, so values can be set in it.
+func readDocument(path string) (*yaml.Node, []byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data = nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	return &doc, data, nil
+}
+
+// root returns the node paths start from: the list under the file's own
+// key for actors, sets, and props (actors.b is actors.yaml's actors: b),
+// otherwise the top-level mapping.
+func root(doc *yaml.Node, file string) *yaml.Node {
+	top := doc.Content[0]
+	if _, value := mappingValue(top, file); value != nil {
+		return value
+	}
+	return top
+}
+
+// resolve follows segments from node. It returns the node found, or nil
+// with the mapping to add it to when only the last key is missing.
+func resolve(node *yaml.Node, segments []string) (*yaml.Node, *yaml.Node, error) {
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		switch node.Kind {
+		case yaml.MappingNode:
+			_, value := mappingValue(node, seg)
+			if value == nil {
+				if last {
+					return nil, node, nil
+				}
+				return nil, nil, fmt.Errorf("no %q", seg)
+			}
+			node = value
+		case yaml.SequenceNode:
+			item := sequenceItem(node, seg)
+			if item == nil {
+				return nil, nil, fmt.Errorf("no item %q", seg)
+			}
+			node = item
+		default:
+			return nil, nil, fmt.Errorf("%q is inside a single value", seg)
+		}
+	}
+	return node, nil, nil
+}
+
+// mappingValue returns the key and value nodes of key in a mapping.
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// sequenceItem returns the item of a list whose id or tone is seg, or
+// else the item at index seg.
+func sequenceItem(node *yaml.Node, seg string) *yaml.Node {
+	for _, field := range []string{"id", "tone"} {
+		for _, item := range node.Content {
+			if _, v := mappingValue(item, field); v != nil && v.Value == seg {
+				return item
+			}
+		}
+	}
+	if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(node.Content) {
+		return node.Content[i]
+	}
+	return nil
+}
+
+func kindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	}
+	return "value"
+}
+
+// scalarText returns value as YAML for the place of old, keeping its
+// quoting. Values that would read back as another type are quoted.
+func scalarText(value string, old *yaml.Node) (string, error) {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	if old != nil {
+		n.Style = old.Style &^ (yaml.LiteralStyle | yaml.FoldedStyle)
+		// Strings stay strings; null, numbers, and booleans take whatever
+		// type the new value has
+		if old.Tag == "!!str" {
+			n.Tag = "!!str"
+		}
+	}
+	if strings.Contains(value, "\n") {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+
+	out, err := yaml.Marshal(n)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// replaceScalar returns data with the text of node replaced by value.
+// Block scalars span several lines and are re-encoded with the document.
+func replaceScalar(doc *yaml.Node, data []byte, node *yaml.Node, value string) ([]byte, error) {
+	text, err := scalarText(value, node)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, ok := scalarSpan(data, node)
+	if !ok {
+		node.Value, node.Style = value, 0
+		if node.Tag != "!!str" {
+			node.Tag = ""
+		}
+		return encode(doc)
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:start])
+	out.WriteString(text)
+	out.Write(data[end:])
+	return out.Bytes(), nil
+}
+
+// scalarSpan returns the byte range of a single-line scalar in data.
+func scalarSpan(data []byte, node *yaml.Node) (int, int, bool) {
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return 0, 0, false
+	}
+
+	lineStart := lineOffset(data, node.Line)
+	if lineStart < 0 {
+		return 0, 0, false
+	}
+	lineEnd := bytes.IndexByte(data[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data)
+	} else {
+		lineEnd += lineStart
+	}
+	line := string(data[lineStart:lineEnd])
+
+	// Columns count runes, not bytes
+	col := node.Column - 1
+	runes := []rune(line)
+	if col < 0 || col > len(runes) {
+		return 0, 0, false
+	}
+	start := lineStart + len(string(runes[:col]))
+	rest := string(runes[col:])
+
+	var length int
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		length = quotedLength(rest, '"')
+	case yaml.SingleQuotedStyle:
+		length = quotedLength(rest, '\'')
+	default:
+		// A plain scalar runs to a comment or the end of the line
+		length = len(rest)
+		if i := strings.Index(rest, " #"); i >= 0 {
+			length = i
+		}
+		length = len(strings.TrimRight(rest[:length], " \t\r"))
+		// Plain scalars inside flow collections end at , ] or }
+		if i := strings.IndexAny(rest[:length], ",]}"); i >= 0 && node.Value != rest[:length] {
+			return 0, 0, false
+		}
+	}
+	if length <= 0 {
+		return 0, 0, false
+	}
+	return start, start + length, true
+}
+
+// quotedLength returns the length of the quoted scalar s starts with, or
+// 0 if it does not end on this line.
+func quotedLength(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			if quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// lineOffset returns the byte offset of the 1-based line in data.
+func lineOffset(data []byte, line int) int {
+	offset := 0
+	for n := 1; n < line; n++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	return offset
+}
+
+// insertKey returns data with key: value added at the end of mapping.
+// Flow mappings and empty documents are re-encoded with the document.
+func insertKey(doc *yaml.Node, data []byte, mapping *yaml.Node, key, value string) ([]byte, error) {
+	text, err := scalarText(value, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Style&yaml.FlowStyle != 0 || len(mapping.Content) == 0 {
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		return encode(doc)
+	}
+
+	// Insert after the last line of the mapping, as deep as its keys
+	indent := strings.Repeat(" ", mapping.Content[0].Column-1)
+	at := lineOffset(data, lastLine(mapping)+1)
+	if at < 0 {
+		at = len(data)
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:at])
+	if at > 0 && data[at-1] != '\n' {
+		out.WriteByte('\n')
+	}
+	fmt.Fprintf(&out, "%s%s: %s\n", indent, key, text)
+	out.Write(data[at:])
+	return out.Bytes(), nil
+}
+
+// lastLine returns the last line a node's content is on.
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	for _, child := range node.Content {
+		line = max(line, lastLine(child))
+	}
+	if node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line += strings.Count(strings.TrimRight(node.Value, "\n"), "\n") + 1
+	}
+	return line
+}
+
+func encode(doc *yaml.Node) ([]byte, error) {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// validate checks that data still loads as the config file it replaces.
+func validate(file string, data []byte) error {
+	var err error
+	switch file {
+	case "actors", "sets", "props":
+		err = yaml.Unmarshal(data, &Config{})
+	case "ui":
+		err = yaml.Unmarshal(data, &UIState{})
+	default:
+		var v any
+		err = yaml.Unmarshal(data, &v)
+	}
+	if err != nil {
+		return fmt.Errorf("the value does not fit: %w", err)
+	}
+	return nil
+}
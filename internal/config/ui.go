@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UIStateFile is the file in the config directory that remembers TUI
+// preferences across sessions.
+const UIStateFile = "ui.yaml"
+
+// UIState holds TUI preferences remembered across sessions.
+type UIState struct {
+	Sidebar       string   `yaml:"sidebar,omitempty"`        // "full", "icons", or "hidden"
+	LookupHistory []string `yaml:"lookup_history,omitempty"` // Oldest first
+	BigChar       string   `yaml:"big_char,omitempty"`       // "halfblock" or "braille"
+	Session       *Session `yaml:"session,omitempty"`        // Where the last session left off
+	TourSeen      bool     `yaml:"tour_seen,omitempty"`      // Onboarding tour taken or skipped
+
+	// Romanization shown under pinyin in Lookup: "wade-giles",
+	// "gwoyeu-romatzyh", "ipa", or "" for none
+	AlsoRomanize string `yaml:"also_romanize,omitempty"`
+}
+
+// Session is where a TUI session left off, so the next one can resume
+// there.
+type Session struct {
+	View         string `yaml:"view,omitempty"` // Sidebar label, e.g. "browse"
+	Deck         string `yaml:"deck,omitempty"` // Path of the open deck
+	BrowseCard   int    `yaml:"browse_card,omitempty"`
+	BrowseSearch string `yaml:"browse_search,omitempty"`
+	BrowseSort   string `yaml:"browse_sort,omitempty"`
+	LearnCard    int    `yaml:"learn_card,omitempty"`
+}
+
+// LoadUIState loads TUI preferences from a YAML file. A missing file
+// yields the defaults.
+func LoadUIState(path string) (UIState, error) {
+	var state UIState
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("reading ui state file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("parsing ui state file: %w", err)
+	}
+	return state, nil
+}
+
+// SaveUIState saves TUI preferences to a YAML file.
+func SaveUIState(path string, state UIState) error {
+	out, err := yaml.Marshal(&state)
+	if err != nil {
+		return fmt.Errorf("marshaling ui state: %w", err)
+	}
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("writing ui state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package decomp_test
+
+import (
+	"testing"
+
+	"github.com/f3rmion/hmm/internal/bench"
+	"github.com/f3rmion/hmm/internal/decomp"
+)
+
+// dictionary returns the path of the dictionary in the data directory of
+// the repository, skipping b if there is none.
+func dictionary(b *testing.B) string {
+	path := bench.Dictionary(".")
+	if path == "" {
+		b.Skip("no data/dictionary.jsonl")
+	}
+	return path
+}
+
+func BenchmarkLoadFromFile(b *testing.B) {
+	path := dictionary(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := decomp.NewDictionary().LoadFromFile(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExtractComponents(b *testing.B) {
+	d := decomp.NewDictionary()
+	if err := d.LoadFromFile(dictionary(b)); err != nil {
+		b.Fatal(err)
+	}
+	_, decomps := bench.Chars(d)
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		decomp.ExtractComponents(decomps[i%len(decomps)])
+		i++
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/f3rmion/hmm/internal/hmm"
@@ -33,7 +36,9 @@ type Etymology struct {
 
 // Dictionary holds all character data.
 type Dictionary struct {
-	entries map[string]*DictionaryEntry
+	entries  map[string]*DictionaryEntry
+	keywords map[string]string // Remembering the Hanzi keywords
+	similar  similarIndex      // Built on first use by Confusables
 }
 
 // NewDictionary creates an empty dictionary.
@@ -45,6 +50,7 @@ func NewDictionary() *Dictionary {
 
 // LoadFromFile loads the dictionary from a Make Me a Hanzi dictionary.jsonl file.
 func (d *Dictionary) LoadFromFile(path string) error {
+	start := time.Now()
 	file, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("opening dictionary file: %w", err)
@@ -73,6 +79,7 @@ func (d *Dictionary) LoadFromFile(path string) error {
 		return fmt.Errorf("reading dictionary file: %w", err)
 	}
 
+	slog.Info("loaded dictionary", "path", path, "entries", len(d.entries), "duration", time.Since(start))
 	return nil
 }
 
@@ -81,11 +88,146 @@ func (d *Dictionary) Lookup(char string) *DictionaryEntry {
 	return d.entries[char]
 }
 
+// SetKeywords sets the Remembering the Hanzi keyword of each character,
+// shown alongside its definition.
+func (d *Dictionary) SetKeywords(keywords map[string]string) {
+	d.keywords = keywords
+}
+
+// Keyword returns the Remembering the Hanzi keyword of a character, or ""
+// if it has none.
+func (d *Dictionary) Keyword(char string) string {
+	return d.keywords[char]
+}
+
 // Size returns the number of entries in the dictionary.
 func (d *Dictionary) Size() int {
 	return len(d.entries)
 }
 
+// ComponentCounts returns how many characters each component appears in.
+func (d *Dictionary) ComponentCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, e := range d.entries {
+		for _, c := range unique(ExtractComponents(e.Decomposition)) {
+			counts[c]++
+		}
+	}
+	return counts
+}
+
+// unique returns s without repeated elements, in order.
+func unique(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	var out []string
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SearchDefinitions returns up to limit entries whose definition contains
+// query, ignoring case. Entries with query as one of their senses come
+// first, then those with it as a whole word, then the rest; ties go to
+// the character with fewer strokes.
+func (d *Dictionary) SearchDefinitions(query string, limit int) []*DictionaryEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	type hit struct {
+		entry *DictionaryEntry
+		rank  int
+	}
+	var hits []hit
+	for _, e := range d.entries {
+		def := strings.ToLower(e.Definition)
+		if !strings.Contains(def, query) {
+			continue
+		}
+		rank := 2
+		if hasWord(def, query) {
+			rank = 1
+		}
+		for _, sense := range strings.FieldsFunc(def, func(r rune) bool { return r == ';' || r == ',' }) {
+			if strings.TrimSpace(sense) == query {
+				rank = 0
+				break
+			}
+		}
+		hits = append(hits, hit{e, rank})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.entry.StrokeCount() != b.entry.StrokeCount() {
+			return a.entry.StrokeCount() < b.entry.StrokeCount()
+		}
+		return a.entry.Character < b.entry.Character
+	})
+
+	var entries []*DictionaryEntry
+	for i := 0; i < len(hits) && i < limit; i++ {
+		entries = append(entries, hits[i].entry)
+	}
+	return entries
+}
+
+// PhoneticSeries returns the entries whose etymology gives phonetic as
+// their sound component, fewest strokes first: the characters that share
+// its sound, more or less.
+func (d *Dictionary) PhoneticSeries(phonetic string) []*DictionaryEntry {
+	if phonetic == "" {
+		return nil
+	}
+
+	var series []*DictionaryEntry
+	for _, e := range d.entries {
+		if e.Etymology != nil && e.Etymology.Phonetic == phonetic {
+			series = append(series, e)
+		}
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i], series[j]
+		if a.StrokeCount() != b.StrokeCount() {
+			return a.StrokeCount() < b.StrokeCount()
+		}
+		return a.Character < b.Character
+	})
+	return series
+}
+
+// hasWord reports whether word appears in s with no letter on either side.
+func hasWord(s, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		before := start == 0 || !unicode.IsLetter(rune(s[start-1]))
+		after := end == len(s) || !unicode.IsLetter(rune(s[end]))
+		if before && after {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// StrokeCount returns the number of strokes, which Make Me a Hanzi
+// records as one match per stroke.
+func (e *DictionaryEntry) StrokeCount() int {
+	return len(e.Matches)
+}
+
 // ToHanziEntry converts a DictionaryEntry to an hmm.HanziEntry.
 func (e *DictionaryEntry) ToHanziEntry() *hmm.HanziEntry {
 	var etymology *hmm.Etymology
@@ -106,6 +248,7 @@ func (e *DictionaryEntry) ToHanziEntry() *hmm.HanziEntry {
 		Components:    ExtractComponents(e.Decomposition),
 		Radical:       e.Radical,
 		Etymology:     etymology,
+		StrokeCount:   e.StrokeCount(),
 	}
 }
 
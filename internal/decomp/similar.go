@@ -0,0 +1,199 @@
+package decomp
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Confusable is a character that is easily mistaken for another, and why.
+type Confusable struct {
+	Entry  *DictionaryEntry
+	Reason string
+}
+
+// strokeApart are sets of characters that differ by about one stroke,
+// which their decompositions cannot show: most of them have no
+// components at all.
+var strokeApart = []string{
+	"己已巳", "未末", "日曰", "土士", "人入八", "千干于", "大太犬", "天夭夫",
+	"王玉主", "戊戌戍戎", "白自", "刀力刃", "免兔", "鸟乌", "午牛", "木本术",
+	"贝见", "今令", "田由甲申", "住往", "休体", "折拆", "侯候", "目且",
+	"拔拨", "辨辩辫", "米来", "子孑孓", "丐丏", "壶壸",
+}
+
+// smallComponent is the most strokes a component can have for two
+// characters differing only in it to count as near-identical.
+const smallComponent = 4
+
+// similarIndex maps each component to the characters it appears in.
+type similarIndex struct {
+	once   sync.Once
+	byComp map[string][]*DictionaryEntry
+	apart  map[string][]string
+}
+
+// Confusables returns up to n characters that are easily mistaken for
+// char, most alike first: ones a stroke apart, ones made of the same
+// components arranged differently, ones where only a smaller component
+// differs (清 and 请), and ones sharing several components.
+func (d *Dictionary) Confusables(char string, n int) []Confusable {
+	d.similar.once.Do(d.indexSimilar)
+
+	e := d.entries[char]
+	type hit struct {
+		Confusable
+		score int
+		apart int // Difference in strokes
+	}
+	var hits []hit
+	seen := map[string]bool{char: true}
+
+	for _, c := range d.similar.apart[char] {
+		if other := d.entries[c]; other != nil && !seen[c] {
+			seen[c] = true
+			hits = append(hits, hit{Confusable{other, "a stroke apart"}, 5, 0})
+		}
+	}
+
+	if e != nil {
+		comps := ExtractComponents(e.Decomposition)
+		for _, comp := range unique(comps) {
+			for _, other := range d.similar.byComp[comp] {
+				if seen[other.Character] {
+					continue
+				}
+				seen[other.Character] = true
+				if score, reason := d.likeness(e, comps, other); score > 0 {
+					apart := max(e.StrokeCount()-other.StrokeCount(), other.StrokeCount()-e.StrokeCount())
+					hits = append(hits, hit{Confusable{other, reason}, score, apart})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.apart != b.apart {
+			return a.apart < b.apart
+		}
+		return a.Entry.Character < b.Entry.Character
+	})
+
+	var out []Confusable
+	for i := 0; i < len(hits) && i < n; i++ {
+		out = append(out, hits[i].Confusable)
+	}
+	return out
+}
+
+// likeness scores how alike other looks to e, whose components are
+// comps, and says why; 0 if not alike enough to mention.
+func (d *Dictionary) likeness(e *DictionaryEntry, comps []string, other *DictionaryEntry) (int, string) {
+	otherComps := ExtractComponents(other.Decomposition)
+	onlyE, onlyOther, shared := compareComponents(comps, otherComps)
+
+	switch {
+	case len(onlyE) == 0 && len(onlyOther) == 0 && len(shared) >= 2:
+		return 4, "same components, arranged differently"
+	case len(onlyE) != 1 || len(onlyOther) != 1:
+		if len(shared) >= 2 {
+			return 1, "shares " + strings.Join(shared, " and ")
+		}
+		return 0, ""
+	case layout(e.Decomposition) != layout(other.Decomposition) ||
+		slices.Index(comps, onlyE[0]) != slices.Index(otherComps, onlyOther[0]):
+		return 0, ""
+	}
+
+	// One component differs, in the same place: alike at a glance when the
+	// part they share is the larger one, or the part that differs small
+	reason := fmt.Sprintf("%s instead of %s", onlyOther[0], onlyE[0])
+	switch {
+	case d.strokes(shared) > d.strokes(onlyE)+d.strokes(onlyOther):
+		return 3, reason
+	case max(d.strokes(onlyE), d.strokes(onlyOther)) <= smallComponent:
+		return 2, reason
+	}
+	return 0, ""
+}
+
+// strokes returns the strokes of comps, counting 3 for a component the
+// dictionary does not have.
+func (d *Dictionary) strokes(comps []string) int {
+	n := 0
+	for _, c := range comps {
+		if e := d.entries[c]; e != nil && e.StrokeCount() > 0 {
+			n += e.StrokeCount()
+		} else {
+			n += 3
+		}
+	}
+	return n
+}
+
+// indexSimilar builds the component index Confusables searches.
+func (d *Dictionary) indexSimilar() {
+	d.similar.byComp = make(map[string][]*DictionaryEntry)
+	for _, e := range d.entries {
+		for _, c := range unique(ExtractComponents(e.Decomposition)) {
+			d.similar.byComp[c] = append(d.similar.byComp[c], e)
+		}
+	}
+	// Map order is random; keep the results the same from run to run
+	for _, entries := range d.similar.byComp {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Character < entries[j].Character })
+	}
+
+	d.similar.apart = make(map[string][]string)
+	for _, set := range strokeApart {
+		for _, r := range set {
+			for _, other := range set {
+				if other != r {
+					d.similar.apart[string(r)] = append(d.similar.apart[string(r)], string(other))
+				}
+			}
+		}
+	}
+}
+
+// compareComponents splits two component lists into the components only
+// in a, only in b, and in both, keeping repeated components apart.
+func compareComponents(a, b []string) (onlyA, onlyB, shared []string) {
+	left := make(map[string]int)
+	for _, c := range b {
+		left[c]++
+	}
+	for _, c := range a {
+		if left[c] > 0 {
+			left[c]--
+			shared = append(shared, c)
+		} else {
+			onlyA = append(onlyA, c)
+		}
+	}
+	for _, c := range b {
+		if left[c] > 0 {
+			left[c]--
+			onlyB = append(onlyB, c)
+		}
+	}
+	return onlyA, onlyB, shared
+}
+
+// layout returns the arrangement a decomposition starts with, such as ⿰
+// for left-right, or "" if it has none.
+func layout(decomposition string) string {
+	for _, r := range decomposition {
+		if r >= 0x2FF0 && r <= 0x2FFF {
+			return string(r)
+		}
+		break
+	}
+	return ""
+}
@@ -1,6 +1,8 @@
 // Package hmm provides core types and logic for the Hanzi Movie Method.
 package hmm
 
+import "time"
+
 // ActorCategory represents the four categories of actors in HMM.
 type ActorCategory string
 
@@ -23,7 +25,8 @@ type Actor struct {
 	ImagePrompt string        `yaml:"image_prompt,omitempty" json:"image_prompt,omitempty"` // Description for image generation
 }
 
-// Tone represents the four tones of Mandarin plus neutral tone.
+// Tone represents the four tones of Mandarin plus neutral tone, or the
+// six tones of Cantonese in Jyutping.
 type Tone int
 
 const (
@@ -32,6 +35,7 @@ const (
 	Tone3      Tone = 3 // Third tone (dipping) - ˇ
 	Tone4      Tone = 4 // Fourth tone (falling) - ˋ
 	Tone5      Tone = 5 // Fifth tone (neutral)
+	Tone6      Tone = 6 // Sixth tone of Jyutping (low level); Mandarin has none
 	ToneUnknown Tone = 0
 )
 
@@ -95,8 +99,18 @@ type HanziEntry struct {
 	StrokeCount   int        `json:"stroke_count,omitempty"`
 }
 
+// SceneStatus tracks where a scene is in the review workflow.
+type SceneStatus string
+
+const (
+	SceneDraft    SceneStatus = "draft"    // Generated in bulk, awaiting review
+	SceneApproved SceneStatus = "approved" // Reviewed and accepted
+	SceneRejected SceneStatus = "rejected" // Reviewed and discarded
+)
+
 // Scene represents a complete HMM mnemonic scene for a character.
 type Scene struct {
+	ID          string   `yaml:"id,omitempty" json:"id,omitempty"` // Unique key when a character has more than one scene
 	Character   string   `yaml:"character" json:"character"`
 	Pinyin      string   `yaml:"pinyin" json:"pinyin"`           // Selected reading
 	Initial     string   `yaml:"initial" json:"initial"`         // Extracted initial
@@ -108,6 +122,33 @@ type Scene struct {
 	PropIDs     []string `yaml:"prop_ids" json:"prop_ids"`       // References to props
 	Script      string   `yaml:"script" json:"script"`           // The mnemonic story
 	ImagePrompt string   `yaml:"image_prompt,omitempty" json:"image_prompt,omitempty"` // Full prompt for image generation
+	Image       string   `yaml:"image,omitempty" json:"image,omitempty"`               // Path to the generated image, if any
+	Status      SceneStatus `yaml:"status,omitempty" json:"status,omitempty"`         // Review status; empty means created by hand
+	NoteGUID    string   `yaml:"note_guid,omitempty" json:"note_guid,omitempty"`       // GUID of the Anki note this scene was synced to
+	Links       []SceneLink `yaml:"links,omitempty" json:"links,omitempty"`           // Look-alike scenes to tell apart from this one
+	Review      *ReviewState `yaml:"review,omitempty" json:"review,omitempty"`        // Built-in spaced repetition state; nil until first reviewed
+}
+
+// ReviewState is a scene's spaced repetition schedule, kept independent
+// of Anki so scenes can be studied without it.
+type ReviewState struct {
+	Ease     float64   `yaml:"ease" json:"ease"`         // Interval multiplier (SM-2 ease factor)
+	Interval int       `yaml:"interval" json:"interval"` // Days between the last and next review
+	Due      time.Time `yaml:"due" json:"due"`           // When the scene is next due
+	Reps     int       `yaml:"reps" json:"reps"`         // Successful reviews in a row
+	Lapses   int       `yaml:"lapses" json:"lapses"`     // Times the scene was forgotten
+}
+
+// SceneLink connects a scene to an easily confused scene (e.g. 未 and 末).
+type SceneLink struct {
+	Key  string `yaml:"key" json:"key"`                       // Store key of the linked scene
+	Note string `yaml:"note,omitempty" json:"note,omitempty"` // How the two differ
+}
+
+// IsApproved reports whether the scene may be written into decks.
+// Scenes without a status were created deliberately and count as approved.
+func (s Scene) IsApproved() bool {
+	return s.Status == "" || s.Status == SceneApproved
 }
 
 // SpecialEffect represents a memory enhancement technique.
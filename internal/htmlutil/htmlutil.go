@@ -0,0 +1,180 @@
+// Package htmlutil turns the HTML of Anki fields into plain text.
+//
+// Fields are tokenized rather than matched with a pattern, so entities
+// such as &nbsp; and &amp; are decoded, the contents of <style> and
+// <script> never show up as text, and a > inside a quoted attribute does
+// not end its tag.
+package htmlutil
+
+import (
+	"html"
+	"strings"
+)
+
+// Text returns the text of s without tags, with entities decoded and
+// whitespace collapsed as a browser would. Ruby annotations, such as the
+// pinyin of <ruby>好<rt>hǎo</rt></ruby>, are left out.
+func Text(s string) string {
+	return text(s, false)
+}
+
+// TextWithRuby is like Text but keeps ruby annotations after their base
+// text in brackets, as Anki writes furigana: 好[hǎo].
+func TextWithRuby(s string) string {
+	return text(s, true)
+}
+
+// rawText are the elements whose contents are not markup, and not text
+// either.
+var rawText = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// breaks are the elements that start a new line, so their text does not
+// run into that of their neighbours.
+var breaks = map[string]bool{
+	"br": true, "div": true, "p": true, "li": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "table": true, "hr": true, "blockquote": true, "pre": true,
+}
+
+func text(s string, ruby bool) string {
+	var b strings.Builder
+	inRT, inRP := false, false
+
+	// endRT closes an open ruby annotation, whose end tag may be left out
+	endRT := func() {
+		if inRT && ruby {
+			b.WriteString("]")
+		}
+		inRT, inRP = false, false
+	}
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			i = len(s)
+		}
+		if i > 0 && !inRP && (!inRT || ruby) {
+			b.WriteString(html.UnescapeString(s[:i]))
+		}
+		s = s[i:]
+		if s == "" {
+			break
+		}
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				break
+			}
+			s = s[4+end+3:]
+			continue
+		}
+
+		name, closing, n := tag(s)
+		if n == 0 {
+			// A < that starts no tag is text
+			if !inRP && (!inRT || ruby) {
+				b.WriteByte('<')
+			}
+			s = s[1:]
+			continue
+		}
+		s = s[n:]
+
+		switch {
+		case rawText[name] && !closing:
+			if end := indexFold(s, "</"+name); end >= 0 {
+				s = s[end:]
+			} else {
+				s = ""
+			}
+		case name == "rt" && !closing:
+			endRT()
+			inRT = true
+			if ruby {
+				b.WriteString("[")
+			}
+		case name == "rp" && !closing:
+			endRT()
+			inRP = true
+		case name == "rt" || name == "rp" || name == "ruby" || name == "rb":
+			endRT()
+		case breaks[name]:
+			b.WriteByte(' ')
+		}
+	}
+	endRT()
+
+	// Fields splits on no-break spaces too, so &nbsp; becomes a plain space
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// tag parses the tag at the start of s. It returns its lower-case name,
+// whether it is an end tag, and its length, which is 0 if s does not start
+// with a tag. Declarations such as <!DOCTYPE> have no name.
+func tag(s string) (name string, closing bool, n int) {
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		closing = true
+		i++
+	}
+	if i >= len(s) {
+		return "", false, 0
+	}
+	if c := s[i]; c == '!' || c == '?' {
+		if end := strings.IndexByte(s[i:], '>'); end >= 0 {
+			return "", false, i + end + 1
+		}
+		return "", false, len(s)
+	}
+	if !isLetter(s[i]) {
+		return "", false, 0
+	}
+
+	start := i
+	for i < len(s) && !isSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	name = strings.ToLower(s[start:i])
+
+	// Skip the attributes, where quoted values may hold < and >. A quote
+	// only starts a value right after its =, as in alt=don't it doesn't.
+	var quote, prev byte
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && prev == '=':
+			quote = c
+		case c == '>':
+			return name, closing, i + 1
+		}
+		if !isSpace(c) {
+			prev = c
+		}
+	}
+	// An unterminated tag swallows the rest, as in a browser
+	return name, closing, len(s)
+}
+
+// indexFold returns the index of the first instance of substr in s,
+// ignoring ASCII case, or -1.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,143 @@
+// Package jyutping is the Cantonese romanization backend: it reads the
+// Jyutping readings of characters from a table and splits syllables into
+// the initials, finals, and tones Cantonese scenes are built from.
+package jyutping
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+)
+
+// FileName is the file in a data directory that holds the readings.
+const FileName = "jyutping.txt"
+
+// Initials are the Jyutping initials, each played by an actor. Syllables
+// without one (aa, ng) have the null actor.
+var Initials = []string{
+	"b", "p", "m", "f", "d", "t", "n", "l", "g", "k", "ng", "h",
+	"gw", "kw", "w", "z", "c", "s", "j",
+}
+
+// Finals are the Jyutping finals, each a set. m and ng are the syllabic
+// nasals of 唔 (m4) and 五 (ng5).
+var Finals = []string{
+	"aa", "aai", "aau", "aam", "aan", "aang", "aap", "aat", "aak",
+	"ai", "au", "am", "an", "ang", "ap", "at", "ak",
+	"e", "ei", "eu", "em", "eng", "ep", "ek",
+	"i", "iu", "im", "in", "ing", "ip", "it", "ik",
+	"o", "oi", "ou", "on", "ong", "ot", "ok",
+	"oe", "oeng", "oek", "eoi", "eon", "eot",
+	"u", "ui", "un", "ung", "ut", "uk",
+	"yu", "yun", "yut",
+	"m", "ng",
+}
+
+// Tones is how many tones Jyutping numbers, one room each.
+const Tones = 6
+
+// Table is the Jyutping backend: the readings of each character it knows.
+type Table struct {
+	readings map[string][]string
+}
+
+// Load reads a table of readings. Each line is either a character and its
+// readings separated by a tab ("好	hou2 hou3"), or the kCantonese line
+// of Unihan_Readings.txt ("U+597D	kCantonese	hou2 hou3"), so the
+// Unihan file can be used as it is. Other lines and # comments are
+// skipped.
+func Load(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading jyutping table: %w", err)
+	}
+	defer f.Close()
+
+	t := &Table{readings: make(map[string][]string)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		var char, readings string
+		switch {
+		case len(fields) == 3 && strings.HasPrefix(fields[0], "U+"):
+			if fields[1] != "kCantonese" {
+				continue
+			}
+			code, err := strconv.ParseInt(fields[0][2:], 16, 32)
+			if err != nil {
+				continue
+			}
+			char, readings = string(rune(code)), fields[2]
+		case len(fields) == 2:
+			char, readings = strings.TrimSpace(fields[0]), fields[1]
+		default:
+			continue
+		}
+		t.readings[char] = append(t.readings[char], strings.Fields(readings)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading jyutping table: %w", err)
+	}
+	if len(t.readings) == 0 {
+		return nil, fmt.Errorf("%s has no readings", path)
+	}
+	return t, nil
+}
+
+// Size returns how many characters have readings.
+func (t *Table) Size() int {
+	return len(t.readings)
+}
+
+// Readings returns the Jyutping readings of char, the most common first.
+func (t *Table) Readings(char string) []string {
+	return t.readings[char]
+}
+
+// Tones returns the 6 tones Jyutping numbers.
+func (t *Table) Tones() int {
+	return Tones
+}
+
+// Parse splits a Jyutping syllable such as hou2 into its initial, final,
+// and tone. A syllable without a tone number has an unknown tone.
+func (t *Table) Parse(syllable string) pinyin.ParsedPinyin {
+	result := pinyin.ParsedPinyin{Full: syllable}
+
+	s := strings.ToLower(strings.TrimSpace(syllable))
+	if n := len(s); n > 0 && s[n-1] >= '1' && s[n-1] <= '0'+Tones {
+		result.Tone = hmm.Tone(s[n-1] - '0')
+		s = s[:n-1]
+	}
+	result.Toneless = s
+	result.Initial, result.Final = split(s)
+	return result
+}
+
+// split splits a toneless syllable into its initial and final.
+func split(s string) (initial, final string) {
+	if s == "m" || s == "ng" {
+		// A syllabic nasal, not an initial
+		return "", s
+	}
+	// Two-letter initials first, so ng, gw, and kw are not read as n, g,
+	// and k
+	for _, size := range []int{2, 1} {
+		for _, i := range Initials {
+			if len(i) == size && strings.HasPrefix(s, i) && len(s) > size {
+				return i, s[size:]
+			}
+		}
+	}
+	return "", s
+}
@@ -4,8 +4,10 @@ package llm
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -15,13 +17,58 @@ import (
 const (
 	anthropicAPIURL = "https://api.anthropic.com/v1/messages"
 	defaultModel    = "claude-sonnet-4-20250514"
+
+	// variationTemperature is used when asking for a different take on a
+	// scene, so alternatives differ more than the default would allow.
+	variationTemperature = 1.0
+
+	// sceneMaxTokens bounds the reply for one scene; a story gets this
+	// much per character.
+	sceneMaxTokens = 300
 )
 
+// ErrRateLimited is returned, wrapped, when the API turns a request away
+// for sending too many, so callers can tell the user to slow down rather
+// than report a failure.
+var ErrRateLimited = errors.New("rate limited")
+
+// FailureSummary sums up the failures of total requests, by request
+// index, such as "3 of 5 failed (rate limited)" with the reason of the
+// first that failed, or returns "" if none did.
+func FailureSummary(failures map[int]error, total int) string {
+	if len(failures) == 0 {
+		return ""
+	}
+	first := -1
+	for i := range failures {
+		if first < 0 || i < first {
+			first = i
+		}
+	}
+	reason := failures[first].Error()
+	if errors.Is(failures[first], ErrRateLimited) {
+		reason = "rate limited"
+	}
+	return fmt.Sprintf("%d of %d failed (%s)", len(failures), total, reason)
+}
+
 // Client is an Anthropic API client.
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	model      string
+	language   string // Language scenes and stories are written in
+}
+
+// language is the language new clients ask for scenes and stories in, by
+// its English name.
+var language = "English"
+
+// SetLanguage makes the clients created from now on ask for scenes and
+// stories in the language named, in English, by name, such as "German".
+// Call it before creating any.
+func SetLanguage(name string) {
+	language = name
 }
 
 // SceneElements contains all the elements for generating an HMM scene.
@@ -47,9 +94,10 @@ type message struct {
 
 // request represents an Anthropic API request.
 type request struct {
-	Model     string    `json:"model"`
-	MaxTokens int       `json:"max_tokens"`
-	Messages  []message `json:"messages"`
+	Model       string    `json:"model"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	Messages    []message `json:"messages"`
 }
 
 // response represents an Anthropic API response.
@@ -58,6 +106,10 @@ type response struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Type    string `json:"type"`
 		Message string `json:"message"`
@@ -80,17 +132,44 @@ func NewClient() (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		model: defaultModel,
+		model:    defaultModel,
+		language: language,
 	}, nil
 }
 
 // GenerateScene generates a vivid scene description for the given HMM elements.
 func (c *Client) GenerateScene(elements SceneElements) (string, error) {
-	prompt := buildPrompt(elements)
+	return c.complete(buildPrompt(elements, c.language), nil, sceneMaxTokens)
+}
+
+// GenerateVariation generates a different take on a scene whose earlier
+// takes are given in previous, at a higher temperature than GenerateScene.
+func (c *Client) GenerateVariation(elements SceneElements, previous []string) (string, error) {
+	temperature := variationTemperature
+	return c.complete(buildVariationPrompt(elements, previous, c.language), &temperature, sceneMaxTokens)
+}
+
+// GenerateStory generates one connected narrative for a word or sentence,
+// passing through the scene of each of its characters in order. meaning
+// is the meaning of the whole text, if known.
+func (c *Client) GenerateStory(text, meaning string, elements []SceneElements) (string, error) {
+	return c.complete(buildStoryPrompt(text, meaning, elements, c.language), nil, sceneMaxTokens*len(elements))
+}
 
+// Ping sends the smallest request the API accepts, to check that the key
+// works.
+func (c *Client) Ping() error {
+	_, err := c.complete("Reply with OK.", nil, sceneMaxTokens)
+	return err
+}
+
+// complete sends prompt as a single user message and returns the reply of
+// at most maxTokens. A nil temperature leaves the API default.
+func (c *Client) complete(prompt string, temperature *float64, maxTokens int) (string, error) {
 	req := request{
-		Model:     c.model,
-		MaxTokens: 300,
+		Model:       c.model,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
 		Messages: []message{
 			{Role: "user", Content: prompt},
 		},
@@ -110,8 +189,15 @@ func (c *Client) GenerateScene(elements SceneElements) (string, error) {
 	httpReq.Header.Set("x-api-key", c.apiKey)
 	httpReq.Header.Set("anthropic-version", "2023-06-01")
 
+	attrs := []any{"model", c.model, "prompt_chars", len(prompt)}
+	if temperature != nil {
+		attrs = append(attrs, "temperature", *temperature)
+	}
+	slog.Debug("LLM request", attrs...)
+	start := time.Now()
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		slog.Warn("LLM request failed", "error", err, "duration", time.Since(start))
 		return "", fmt.Errorf("making request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -120,15 +206,25 @@ func (c *Client) GenerateScene(elements SceneElements) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("reading response: %w", err)
 	}
+	slog.Info("LLM response", "model", c.model, "status", resp.StatusCode, "duration", time.Since(start))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("API error: %w", ErrRateLimited)
+	}
 
 	var apiResp response
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
 		return "", fmt.Errorf("unmarshaling response: %w", err)
 	}
 
+	if apiResp.Error != nil && apiResp.Error.Type == "rate_limit_error" {
+		return "", fmt.Errorf("API error: %w", ErrRateLimited)
+	}
 	if apiResp.Error != nil {
+		slog.Debug("LLM error", "type", apiResp.Error.Type, "message", apiResp.Error.Message)
 		return "", fmt.Errorf("API error: %s", apiResp.Error.Message)
 	}
+	slog.Debug("LLM usage", "input_tokens", apiResp.Usage.InputTokens, "output_tokens", apiResp.Usage.OutputTokens)
 
 	if len(apiResp.Content) == 0 {
 		return "", fmt.Errorf("empty response from API")
@@ -137,8 +233,9 @@ func (c *Client) GenerateScene(elements SceneElements) (string, error) {
 	return strings.TrimSpace(apiResp.Content[0].Text), nil
 }
 
-// buildPrompt creates the prompt for the LLM.
-func buildPrompt(e SceneElements) string {
+// buildPrompt creates the prompt for the LLM, asking for a reply in
+// language.
+func buildPrompt(e SceneElements, language string) string {
 	var sb strings.Builder
 
 	sb.WriteString("You are helping create memorable mnemonic images for learning Chinese characters using the Hanzi Movie Method.\n\n")
@@ -149,6 +246,24 @@ func buildPrompt(e SceneElements) string {
 	sb.WriteString("- PROPS represent the character's components\n")
 	sb.WriteString("- The scene should be bizarre, emotional, and unforgettable\n\n")
 
+	writeElements(&sb, e)
+
+	sb.WriteString("\n=== YOUR TASK ===\n")
+	sb.WriteString("Generate an image prompt for an AI image generator (DALL-E, Midjourney, Stable Diffusion).\n\n")
+	sb.WriteString("Requirements:\n")
+	sb.WriteString("1. The actor must be clearly recognizable and doing something memorable\n")
+	sb.WriteString("2. The location must be clearly the specified place and area\n")
+	sb.WriteString("3. ALL props must be prominently featured and interacting with the actor\n")
+	sb.WriteString("4. The scene should be slightly absurd or exaggerated to be memorable\n")
+	sb.WriteString("5. Include visual style keywords at the end (e.g., 'digital art, cinematic lighting, detailed')\n\n")
+	sb.WriteString("Output ONLY the image prompt, nothing else. Make it 2-4 sentences maximum.")
+	writeLanguage(&sb, "image prompt", language)
+
+	return sb.String()
+}
+
+// writeElements writes the character info and scene elements of e.
+func writeElements(sb *strings.Builder, e SceneElements) {
 	sb.WriteString("=== CHARACTER INFO ===\n")
 	sb.WriteString(fmt.Sprintf("Character: %s\n", e.Character))
 	sb.WriteString(fmt.Sprintf("Pronunciation: %s\n", e.Pinyin))
@@ -185,16 +300,70 @@ func buildPrompt(e SceneElements) string {
 			sb.WriteString("\n")
 		}
 	}
+}
+
+// buildStoryPrompt creates the prompt for a story that links the scenes
+// of the characters of text, given in order in elements, asking for it in
+// language.
+func buildStoryPrompt(text, meaning string, elements []SceneElements, language string) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are helping create memorable mnemonic stories for learning Chinese vocabulary using the Hanzi Movie Method.\n\n")
+
+	sb.WriteString("The system works like this:\n")
+	sb.WriteString("- Each character becomes a vivid SCENE in a specific LOCATION\n")
+	sb.WriteString("- An ACTOR (real or fictional person) performs an action\n")
+	sb.WriteString("- PROPS represent the character's components\n")
+	sb.WriteString("- A word is remembered as a mini-movie that moves through the scenes of its characters in order\n\n")
+
+	sb.WriteString(fmt.Sprintf("=== WORD ===\n%s", text))
+	if meaning != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", meaning))
+	}
+	sb.WriteString("\n")
+
+	for i, e := range elements {
+		sb.WriteString(fmt.Sprintf("\n=== SCENE %d ===\n", i+1))
+		writeElements(&sb, e)
+	}
 
 	sb.WriteString("\n=== YOUR TASK ===\n")
-	sb.WriteString("Generate an image prompt for an AI image generator (DALL-E, Midjourney, Stable Diffusion).\n\n")
+	sb.WriteString("Write one connected story that plays the scenes above in order, like the shots of a short film.\n\n")
 	sb.WriteString("Requirements:\n")
-	sb.WriteString("1. The actor must be clearly recognizable and doing something memorable\n")
-	sb.WriteString("2. The location must be clearly the specified place and area\n")
-	sb.WriteString("3. ALL props must be prominently featured and interacting with the actor\n")
-	sb.WriteString("4. The scene should be slightly absurd or exaggerated to be memorable\n")
-	sb.WriteString("5. Include visual style keywords at the end (e.g., 'digital art, cinematic lighting, detailed')\n\n")
-	sb.WriteString("Output ONLY the image prompt, nothing else. Make it 2-4 sentences maximum.")
+	sb.WriteString("1. Each scene takes place at its own location and area, with its own actor doing something memorable\n")
+	sb.WriteString("2. ALL props of a scene must appear in that scene\n")
+	sb.WriteString("3. Link the scenes: an object, action, or consequence carries over from one scene into the next\n")
+	sb.WriteString("4. The story as a whole should hint at the meaning of the word\n")
+	sb.WriteString("5. Keep it slightly absurd or exaggerated to be memorable\n\n")
+	sb.WriteString("Output ONLY the story, nothing else. Give each scene 2-3 sentences and start it with its character in brackets, e.g. [好].")
+	writeLanguage(&sb, "story", language)
 
 	return sb.String()
 }
+
+// buildVariationPrompt extends the scene prompt with the takes the user
+// has already seen and asks for one that is clearly different.
+func buildVariationPrompt(e SceneElements, previous []string, language string) string {
+	var sb strings.Builder
+
+	sb.WriteString(buildPrompt(e, language))
+	sb.WriteString("\n\n=== EARLIER TAKES ===\n")
+	sb.WriteString("These image prompts were already suggested for this character:\n")
+	for i, p := range previous {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, p))
+	}
+	sb.WriteString("\nGive me a different take: keep the same actor, location, and props, ")
+	sb.WriteString("but choose a new action, mood, and composition that does not repeat any earlier take.")
+
+	return sb.String()
+}
+
+// writeLanguage asks for what to be written in language, unless that is
+// English. The meanings and names given stay as they are, so the reply
+// translates them where it uses them.
+func writeLanguage(sb *strings.Builder, what, language string) {
+	if language == "" || strings.EqualFold(language, "English") {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\nWrite the %s in %s, translating the meaning where you use it; keep the names of the actor, location, and props as given.", what, language))
+}
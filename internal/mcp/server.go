@@ -0,0 +1,170 @@
+// Package mcp implements the parts of the Model Context Protocol needed to
+// offer tools to an agent such as Claude Desktop: JSON-RPC 2.0 messages,
+// one per line, over stdin and stdout.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// protocolVersion is the MCP revision this server speaks.
+const protocolVersion = "2025-06-18"
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function an agent can call.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments.
+	InputSchema map[string]any
+	// Handler runs the tool with the arguments as sent. The text it returns
+	// is the result; an error is reported to the agent as a failed call.
+	Handler func(args json.RawMessage) (string, error)
+}
+
+// Server answers an agent's requests with its tools.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+// NewServer creates a server that introduces itself as name and version.
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version}
+}
+
+// Add offers tool to agents.
+func (s *Server) Add(tool Tool) {
+	s.tools = append(s.tools, tool)
+}
+
+// request is a JSON-RPC request, or a notification when ID is absent.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response with either a result or an error.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// content is a block of a tool result.
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp := response{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: codeParseError, Message: err.Error()}}
+			if err := encoder.Encode(resp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Notifications, such as notifications/initialized, get no answer
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		resp := response{JSONRPC: "2.0", ID: req.ID}
+		resp.Result, resp.Error = s.handle(req)
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools := make([]map[string]any, len(s.tools))
+		for i, t := range s.tools {
+			tools[i] = map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": t.InputSchema,
+			}
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.call(req.Params)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "unknown method: " + req.Method}
+}
+
+// call runs the tool named in params. Failures of the tool itself are
+// results, so the agent sees them; only a malformed call is an error.
+func (s *Server) call(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	if len(p.Arguments) == 0 {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	for _, t := range s.tools {
+		if t.Name != p.Name {
+			continue
+		}
+		text, err := t.Handler(p.Arguments)
+		if err != nil {
+			return toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		return toolResult{Content: []content{{Type: "text", Text: text}}}, nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+}
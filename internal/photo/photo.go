@@ -0,0 +1,171 @@
+// Package photo fetches reference photos of actors from Wikipedia and
+// keeps them in the config directory, so the casting has faces to it.
+package photo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Wikipedia thumbnails are JPEG or PNG
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DirName is the directory inside the config directory photos are kept
+// in, one per actor named by its ID.
+const DirName = "photos"
+
+// ErrNotFound is returned, wrapped, when Wikipedia has no page for a name
+// or the page has no image.
+var ErrNotFound = errors.New("no photo found")
+
+// extensions are the image files a photo may be saved as.
+var extensions = []string{".jpg", ".jpeg", ".png"}
+
+// userAgent identifies hmm to Wikipedia, which asks API clients for one.
+const userAgent = "hmm/1.0 (https://github.com/f3rmion/hmm)"
+
+// Client fetches thumbnails from one language edition of Wikipedia.
+type Client struct {
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the Wikipedia of lang, such as "en".
+func NewClient(lang string) *Client {
+	if lang == "" {
+		lang = "en"
+	}
+	return &Client{
+		apiURL:     fmt.Sprintf("https://%s.wikipedia.org/w/api.php", lang),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// pageImagesResponse is the part of a pageimages query reply that is used.
+type pageImagesResponse struct {
+	Query struct {
+		Pages []struct {
+			Title     string `json:"title"`
+			Missing   bool   `json:"missing"`
+			Thumbnail struct {
+				Source string `json:"source"`
+			} `json:"thumbnail"`
+		} `json:"pages"`
+	} `json:"query"`
+}
+
+// Fetch downloads the lead image of the Wikipedia page on name as a
+// thumbnail about size pixels wide. It returns the image and its file
+// extension.
+func (c *Client) Fetch(ctx context.Context, name string, size int) ([]byte, string, error) {
+	q := url.Values{
+		"action":        {"query"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+		"prop":          {"pageimages"},
+		"piprop":        {"thumbnail"},
+		"pithumbsize":   {fmt.Sprint(size)},
+		"redirects":     {"1"},
+		"titles":        {name},
+	}
+	body, err := c.get(ctx, c.apiURL+"?"+q.Encode())
+	if err != nil {
+		return nil, "", err
+	}
+
+	var resp pageImagesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, "", fmt.Errorf("parsing Wikipedia reply: %w", err)
+	}
+	if len(resp.Query.Pages) == 0 || resp.Query.Pages[0].Missing {
+		return nil, "", fmt.Errorf("%w: no Wikipedia page on %s", ErrNotFound, name)
+	}
+	source := resp.Query.Pages[0].Thumbnail.Source
+	if source == "" {
+		return nil, "", fmt.Errorf("%w: the page on %s has no image", ErrNotFound, name)
+	}
+
+	data, err := c.get(ctx, source)
+	if err != nil {
+		return nil, "", err
+	}
+	ext := strings.ToLower(path.Ext(source))
+	if ext == ".jpeg" {
+		ext = ".jpg"
+	}
+	if ext != ".jpg" && ext != ".png" {
+		return nil, "", fmt.Errorf("%w: the image of %s is %s, not JPEG or PNG", ErrNotFound, name, ext)
+	}
+	return data, ext, nil
+}
+
+// get returns the body of a GET of u.
+func (c *Client) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: status %d", req.URL.Host, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+}
+
+// Save writes the photo of actor id to dir, replacing one saved before,
+// and returns its path.
+func Save(dir, id string, data []byte, ext string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	for _, e := range extensions {
+		os.Remove(filepath.Join(dir, id+e))
+	}
+	p := filepath.Join(dir, id+ext)
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return "", fmt.Errorf("saving photo: %w", err)
+	}
+	return p, nil
+}
+
+// Find returns the path of the photo of actor id in dir, or "" if there
+// is none.
+func Find(dir, id string) string {
+	for _, e := range extensions {
+		p := filepath.Join(dir, id+e)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// Load decodes the photo at p.
+func Load(p string) (image.Image, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", filepath.Base(p), err)
+	}
+	return img, nil
+}
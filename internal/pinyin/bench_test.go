@@ -0,0 +1,20 @@
+package pinyin_test
+
+import (
+	"testing"
+
+	"github.com/f3rmion/hmm/internal/bench"
+	"github.com/f3rmion/hmm/internal/pinyin"
+)
+
+func BenchmarkParseChar(b *testing.B) {
+	chars, _ := bench.Chars(nil)
+	parser := pinyin.NewParser()
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		parser.ParseChar(chars[i%len(chars)])
+		i++
+	}
+}
@@ -0,0 +1,140 @@
+package pinyin
+
+import (
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// gwoyeuRomatzyh is Gwoyeu Romatzyh, which spells the tone into the
+// syllable instead of marking it: ba, bar, baa, bah.
+type gwoyeuRomatzyh struct{}
+
+func (gwoyeuRomatzyh) Name() string  { return "gwoyeu-romatzyh" }
+func (gwoyeuRomatzyh) Label() string { return "Gwoyeu Romatzyh" }
+
+// gwoyeuInitials are the Gwoyeu Romatzyh spellings of the pinyin
+// initials. j, q, and x share their spelling with zh, ch, and sh; the i
+// or iu after them tells them apart.
+var gwoyeuInitials = map[string]string{
+	"j": "j", "q": "ch", "x": "sh",
+	"zh": "j", "ch": "ch", "sh": "sh",
+	"z": "tz", "c": "ts",
+}
+
+// gwoyeuFinals are the first-tone (basic) spellings of the finals. y is
+// the buzzed vowel of zi to ri.
+var gwoyeuFinals = map[string]string{
+	"ao": "au", "er": "el", "iao": "iau",
+	"ü": "iu", "üe": "iue", "üan": "iuan", "ün": "iun",
+}
+
+// gwoyeuThird are the third-tone spellings of the basic finals: single
+// vowels doubled, medial i and u written e and o.
+var gwoyeuThird = map[string]string{
+	"y": "yy", "a": "aa", "o": "oo", "e": "ee", "ai": "ae", "ei": "eei", "au": "ao", "ou": "oou",
+	"an": "aan", "en": "een", "ang": "aang", "eng": "eeng", "ong": "oong", "el": "eel",
+	"i": "ii", "ia": "ea", "ie": "iee", "iau": "eau", "iou": "eou", "ian": "ean",
+	"in": "iin", "iang": "eang", "ing": "iing", "iong": "eong",
+	"u": "uu", "ua": "oa", "uo": "uoo", "uai": "oai", "uei": "oei", "uan": "oan",
+	"uen": "oen", "uang": "oang", "ueng": "oeng",
+	"iu": "eu", "iue": "eue", "iuan": "euan", "iun": "eun",
+}
+
+// gwoyeuFourth are the fourth-tone spellings of the basic finals: final
+// i and u written y and w, n and ng written nn and nq, and h added to
+// the rest.
+var gwoyeuFourth = map[string]string{
+	"y": "yh", "a": "ah", "o": "oh", "e": "eh", "ai": "ay", "ei": "ey", "au": "aw", "ou": "ow",
+	"an": "ann", "en": "enn", "ang": "anq", "eng": "enq", "ong": "onq", "el": "ell",
+	"i": "ih", "ia": "iah", "ie": "ieh", "iau": "iaw", "iou": "iow", "ian": "iann",
+	"in": "inn", "iang": "ianq", "ing": "inq", "iong": "ionq",
+	"u": "uh", "ua": "uah", "uo": "uoh", "uai": "uay", "uei": "uey", "uan": "uann",
+	"uen": "uenn", "uang": "uanq", "ueng": "uenq",
+	"iu": "iuh", "iue": "iueh", "iuan": "iuann", "iun": "iunn",
+}
+
+func (gwoyeuRomatzyh) Syllable(initial, final string, tone hmm.Tone) string {
+	basic := final
+	if f, ok := gwoyeuFinals[final]; ok {
+		basic = f
+	}
+	if final == "i" && apical(initial) {
+		basic = "y"
+	}
+	spelled := initial
+	if i, ok := gwoyeuInitials[initial]; ok {
+		spelled = i
+	}
+	// m, n, l, and r take the basic form in the second tone, and an h in
+	// the first
+	sonorant := initial == "m" || initial == "n" || initial == "l" || initial == "r"
+
+	switch tone {
+	case hmm.Tone1:
+		if sonorant {
+			spelled += "h"
+		}
+		return spelled + basic
+	case hmm.Tone2:
+		if sonorant {
+			return spelled + basic
+		}
+		return spelled + gwoyeuSecond(basic)
+	case hmm.Tone3:
+		third := gwoyeuThird[basic]
+		if initial == "" {
+			third = gwoyeuGlide(basic, third)
+		}
+		return spelled + third
+	case hmm.Tone4:
+		fourth := gwoyeuFourth[basic]
+		if initial == "" {
+			fourth = gwoyeuGlide(basic, fourth)
+		}
+		return spelled + fourth
+	}
+	return spelled + basic
+}
+
+// gwoyeuSecond spells a basic final in the second tone: a leading i or u
+// is written y or w, and other finals get an r after their vowels.
+func gwoyeuSecond(basic string) string {
+	switch basic {
+	case "y":
+		return "yr"
+	case "i", "u":
+		return gwoyeuGlide(basic, basic)
+	case "in", "ing":
+		return "y" + basic[1:]
+	}
+	if strings.HasPrefix(basic, "i") || strings.HasPrefix(basic, "u") {
+		return gwoyeuGlide(basic, basic)
+	}
+	vowels := len(basic) - len(strings.TrimLeft(basic, "aeiou"))
+	return basic[:vowels] + "r" + basic[vowels:]
+}
+
+// gwoyeuGlide writes the leading i or u of spelled, the tonal spelling of
+// basic, as y or w, as Gwoyeu Romatzyh does without an initial. A lone
+// vowel keeps it and gets the y or w in front: yi, wu.
+func gwoyeuGlide(basic, spelled string) string {
+	switch {
+	case strings.HasPrefix(basic, "iu"):
+		if strings.HasPrefix(spelled, "iu") {
+			return "y" + spelled[1:]
+		}
+		return "y" + spelled
+	case strings.HasPrefix(basic, "i"):
+		if basic == "i" || basic == "in" || basic == "ing" || !strings.HasPrefix(spelled, "i") {
+			return "y" + spelled
+		}
+		return "y" + spelled[1:]
+	case strings.HasPrefix(basic, "u"):
+		if basic == "u" || !strings.HasPrefix(spelled, "u") {
+			return "w" + spelled
+		}
+		return "w" + spelled[1:]
+	}
+	return spelled
+}
@@ -0,0 +1,50 @@
+package pinyin
+
+import "github.com/f3rmion/hmm/internal/hmm"
+
+// ipa writes syllables in the International Phonetic Alphabet, broadly,
+// with Chao tone letters for the pitch contour.
+type ipa struct{}
+
+func (ipa) Name() string  { return "ipa" }
+func (ipa) Label() string { return "IPA" }
+
+// ipaInitials are the sounds of the pinyin initials.
+var ipaInitials = map[string]string{
+	"b": "p", "p": "pʰ", "m": "m", "f": "f",
+	"d": "t", "t": "tʰ", "n": "n", "l": "l",
+	"g": "k", "k": "kʰ", "h": "x",
+	"j": "tɕ", "q": "tɕʰ", "x": "ɕ",
+	"zh": "ʈʂ", "ch": "ʈʂʰ", "sh": "ʂ", "r": "ʐ",
+	"z": "ts", "c": "tsʰ", "s": "s",
+}
+
+// ipaFinals are the sounds of the finals.
+var ipaFinals = map[string]string{
+	"a": "a", "o": "wo", "e": "ɤ", "ai": "ai̯", "ei": "ei̯", "ao": "ɑu̯", "ou": "ou̯",
+	"an": "an", "en": "ən", "ang": "ɑŋ", "eng": "əŋ", "ong": "ʊŋ", "er": "aɚ̯",
+	"i": "i", "ia": "ja", "ie": "jɛ", "iao": "jɑu̯", "iou": "jou̯", "ian": "jɛn",
+	"in": "in", "iang": "jɑŋ", "ing": "iŋ", "iong": "jʊŋ",
+	"u": "u", "ua": "wa", "uo": "wo", "uai": "wai̯", "uei": "wei̯", "uan": "wan",
+	"uen": "wən", "uang": "wɑŋ", "ueng": "wəŋ",
+	"ü": "y", "üe": "ɥɛ", "üan": "ɥɛn", "ün": "yn",
+}
+
+// toneLetters are the Chao tone letters of the tones; the neutral tone
+// takes its pitch from the tone before it and has none.
+var toneLetters = map[hmm.Tone]string{
+	hmm.Tone1: "˥", hmm.Tone2: "˧˥", hmm.Tone3: "˨˩˦", hmm.Tone4: "˥˩",
+}
+
+func (ipa) Syllable(initial, final string, tone hmm.Tone) string {
+	f := ipaFinals[final]
+	switch {
+	case final == "i" && (initial == "z" || initial == "c" || initial == "s"):
+		f = "ɹ̩"
+	case final == "i" && apical(initial):
+		f = "ɻ̩"
+	case final == "o" && initial == "":
+		f = "o"
+	}
+	return ipaInitials[initial] + f + toneLetters[tone]
+}
@@ -0,0 +1,77 @@
+package pinyin
+
+import (
+	"regexp"
+	"strings"
+)
+
+// numberedSyllable matches a syllable written with a tone number, as
+// dictionaries and flashcard apps export pinyin (hao3, lu:4, lv4).
+var numberedSyllable = regexp.MustCompile(`(?i)[a-zü]+(?::)?[a-zü]*[1-5]`)
+
+// toneMarks are the marked forms of each vowel, for tones 1 to 4.
+var toneMarks = map[rune][4]rune{
+	'a': {'ā', 'á', 'ǎ', 'à'}, 'A': {'Ā', 'Á', 'Ǎ', 'À'},
+	'e': {'ē', 'é', 'ě', 'è'}, 'E': {'Ē', 'É', 'Ě', 'È'},
+	'i': {'ī', 'í', 'ǐ', 'ì'}, 'I': {'Ī', 'Í', 'Ǐ', 'Ì'},
+	'o': {'ō', 'ó', 'ǒ', 'ò'}, 'O': {'Ō', 'Ó', 'Ǒ', 'Ò'},
+	'u': {'ū', 'ú', 'ǔ', 'ù'}, 'U': {'Ū', 'Ú', 'Ǔ', 'Ù'},
+	'ü': {'ǖ', 'ǘ', 'ǚ', 'ǜ'}, 'Ü': {'Ǖ', 'Ǘ', 'Ǚ', 'Ǜ'},
+}
+
+// NumberedSyllables returns the syllables of pinyin written with tone
+// numbers ("ni3hao3" gives ni3 and hao3), or nil if it has none.
+func NumberedSyllables(s string) []string {
+	return numberedSyllable.FindAllString(s, -1)
+}
+
+// FromNumbers converts pinyin written with tone numbers to tone marks:
+// "ni3 hao3" becomes "nǐ hǎo", "lv4" and "lu:4" become "lǜ". The fifth
+// (neutral) tone drops its number. Text without tone numbers is returned
+// as is.
+func FromNumbers(s string) string {
+	return numberedSyllable.ReplaceAllStringFunc(s, markSyllable)
+}
+
+// markSyllable converts one numbered syllable.
+func markSyllable(syllable string) string {
+	tone := int(syllable[len(syllable)-1] - '0')
+	s := syllable[:len(syllable)-1]
+	s = strings.NewReplacer("u:", "ü", "U:", "Ü", "v", "ü", "V", "Ü").Replace(s)
+	if tone == 5 {
+		return s
+	}
+
+	runes := []rune(s)
+	lower := []rune(strings.ToLower(s))
+	// The mark goes on a or e, on the o of ou, and otherwise on the last
+	// vowel
+	at := indexRune(lower, 'a')
+	if at < 0 {
+		at = indexRune(lower, 'e')
+	}
+	if at < 0 && strings.Contains(string(lower), "ou") {
+		at = indexRune(lower, 'o')
+	}
+	for i := len(runes) - 1; at < 0 && i >= 0; i-- {
+		if _, ok := toneMarks[runes[i]]; ok {
+			at = i
+		}
+	}
+	if at < 0 {
+		return s
+	}
+
+	runes[at] = toneMarks[runes[at]][tone-1]
+	return string(runes)
+}
+
+// indexRune returns the index of the first r in runes, or -1.
+func indexRune(runes []rune, r rune) int {
+	for i, c := range runes {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -9,42 +9,70 @@ import (
 	gopinyin "github.com/mozillazg/go-pinyin"
 )
 
+// Backend is a romanization: where the readings of characters come from,
+// and how a syllable splits into its HMM initial, final, and tone.
+type Backend interface {
+	Readings(char string) []string
+	Parse(syllable string) ParsedPinyin
+	Tones() int // How many tones there are, numbered from 1
+}
+
+// backend is the romanization new parsers use.
+var backend Backend = NewMandarin()
+
+// SetBackend makes the parsers created from now on use b, such as
+// Jyutping for Cantonese instead of Mandarin pinyin. Call it before
+// creating any.
+func SetBackend(b Backend) {
+	backend = b
+}
+
 // Parser handles pinyin conversion and HMM mapping.
 type Parser struct {
-	args gopinyin.Args
+	backend Backend
 }
 
-// NewParser creates a new pinyin parser.
+// NewParser creates a new parser for the romanization set by SetBackend,
+// Mandarin pinyin by default.
 func NewParser() *Parser {
+	return &Parser{backend: backend}
+}
+
+// Mandarin is the default backend: Mandarin readings in pinyin with tone
+// marks, split by the HMM rules into 55 initials and 13 finals.
+type Mandarin struct {
+	args gopinyin.Args
+}
+
+// NewMandarin creates the Mandarin pinyin backend.
+func NewMandarin() *Mandarin {
 	args := gopinyin.NewArgs()
 	args.Style = gopinyin.Tone // Returns tone marks: zhōng
 	args.Heteronym = true      // Return all possible readings
-	return &Parser{args: args}
-}
-
-// ParsedPinyin contains the HMM-relevant parts of a pinyin syllable.
-type ParsedPinyin struct {
-	Full    string   // Full pinyin with tone mark (e.g., "hǎo")
-	Initial string   // HMM initial (e.g., "h")
-	Final   string   // HMM final (e.g., "ao")
-	Tone    hmm.Tone // Tone number (1-5)
+	return &Mandarin{args: args}
 }
 
-// GetPinyin returns all pinyin readings for a character.
-func (p *Parser) GetPinyin(char string) []string {
-	result := gopinyin.Pinyin(char, p.args)
+// Readings returns all pinyin readings for a character.
+func (m *Mandarin) Readings(char string) []string {
+	result := gopinyin.Pinyin(char, m.args)
 	if len(result) == 0 {
 		return nil
 	}
 	return result[0]
 }
 
+// Tones returns 5: the four tones and the neutral tone.
+func (m *Mandarin) Tones() int {
+	return 5
+}
+
 // Parse extracts HMM components from a pinyin syllable.
-func (p *Parser) Parse(pinyin string) ParsedPinyin {
+func (m *Mandarin) Parse(pinyin string) ParsedPinyin {
 	result := ParsedPinyin{Full: pinyin}
 
 	// Extract tone from tone mark
 	result.Tone, pinyin = extractTone(pinyin)
+	result.Toneless = pinyin
 
 	// Extract initial and final using HMM rules
 	result.Initial, result.Final = extractInitialFinal(pinyin)
@@ -52,6 +80,31 @@ func (p *Parser) Parse(pinyin string) ParsedPinyin {
 	return result
 }
 
+// ParsedPinyin contains the HMM-relevant parts of a syllable, in pinyin
+// or whichever romanization the backend uses.
+type ParsedPinyin struct {
+	Full     string   // Full pinyin with tone mark (e.g., "hǎo")
+	Toneless string   // Pinyin without tone mark (e.g., "hao")
+	Initial  string   // HMM initial (e.g., "h")
+	Final    string   // HMM final (e.g., "ao")
+	Tone     hmm.Tone // Tone number (1-5)
+}
+
+// GetPinyin returns all readings for a character.
+func (p *Parser) GetPinyin(char string) []string {
+	return p.backend.Readings(char)
+}
+
+// Parse extracts HMM components from a syllable.
+func (p *Parser) Parse(syllable string) ParsedPinyin {
+	return p.backend.Parse(syllable)
+}
+
+// Tones returns how many tones the romanization has.
+func (p *Parser) Tones() int {
+	return p.backend.Tones()
+}
+
 // ParseChar parses a character and returns all possible HMM breakdowns.
 func (p *Parser) ParseChar(char string) []ParsedPinyin {
 	readings := p.GetPinyin(char)
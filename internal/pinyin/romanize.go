@@ -0,0 +1,147 @@
+package pinyin
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Romanizer writes Mandarin syllables in a romanization other than
+// pinyin, for readers who learned from older textbooks or linguistics.
+type Romanizer interface {
+	// Name is how the romanization is chosen in the config, such as
+	// "wade-giles".
+	Name() string
+
+	// Label is how the romanization is shown, such as "Wade-Giles".
+	Label() string
+
+	// Syllable writes a syllable given by its pinyin initial ("" for
+	// none) and its final spelled out in full: the i, u, and ü of y and w
+	// spellings restored (ye is ie, wei is uei), iu, ui, and un as iou,
+	// uei, and uen, and the u after j, q, and x as ü.
+	Syllable(initial, final string, tone hmm.Tone) string
+}
+
+// romanizers are the registered romanizations by name.
+var romanizers = make(map[string]Romanizer)
+
+// RegisterRomanizer makes r available to GetRomanizer by its name.
+func RegisterRomanizer(r Romanizer) {
+	romanizers[r.Name()] = r
+}
+
+// GetRomanizer returns the romanization named name, or nil if there is
+// none.
+func GetRomanizer(name string) Romanizer {
+	return romanizers[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Romanizers returns the names of the registered romanizations, sorted.
+func Romanizers() []string {
+	var names []string
+	for name := range romanizers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterRomanizer(wadeGiles{})
+	RegisterRomanizer(gwoyeuRomatzyh{})
+	RegisterRomanizer(ipa{})
+}
+
+// Romanize writes pinyin, syllables with tone marks separated by spaces,
+// in r. It reports false if a syllable is not Mandarin pinyin, such as
+// Jyutping.
+func Romanize(r Romanizer, pinyin string) (string, bool) {
+	var out []string
+	for _, syllable := range strings.Fields(pinyin) {
+		tone, toneless := extractTone(syllable)
+		initial, final, ok := splitSyllable(toneless)
+		if !ok {
+			return "", false
+		}
+		out = append(out, r.Syllable(initial, final, tone))
+	}
+	return strings.Join(out, " "), len(out) > 0
+}
+
+// standardInitials are the pinyin initials, two-letter ones first.
+var standardInitials = []string{
+	"zh", "ch", "sh",
+	"b", "p", "m", "f", "d", "t", "n", "l", "g", "k", "h", "j", "q", "x", "r", "z", "c", "s",
+}
+
+// fullFinals are the finals of Mandarin, spelled out in full.
+var fullFinals = map[string]bool{
+	"a": true, "o": true, "e": true, "ai": true, "ei": true, "ao": true, "ou": true,
+	"an": true, "en": true, "ang": true, "eng": true, "ong": true, "er": true,
+	"i": true, "ia": true, "ie": true, "iao": true, "iou": true, "ian": true,
+	"in": true, "iang": true, "ing": true, "iong": true,
+	"u": true, "ua": true, "uo": true, "uai": true, "uei": true, "uan": true,
+	"uen": true, "uang": true, "ueng": true,
+	"ü": true, "üe": true, "üan": true, "ün": true,
+}
+
+// splitSyllable splits a toneless pinyin syllable into its initial and its
+// final spelled out in full, or reports false if it is not pinyin.
+func splitSyllable(syllable string) (initial, final string, ok bool) {
+	s := strings.NewReplacer("u:", "ü", "v", "ü").Replace(strings.ToLower(syllable))
+
+	switch {
+	case strings.HasPrefix(s, "y"):
+		rest := s[1:]
+		switch {
+		case rest == "i" || rest == "in" || rest == "ing":
+			final = rest
+		case strings.HasPrefix(rest, "u"):
+			final = "ü" + rest[1:]
+		default:
+			final = "i" + rest
+		}
+	case strings.HasPrefix(s, "w"):
+		final = "u" + s[1:]
+		if final == "uu" {
+			final = "u"
+		}
+	default:
+		for _, i := range standardInitials {
+			if strings.HasPrefix(s, i) {
+				initial = i
+				break
+			}
+		}
+		final = s[len(initial):]
+		switch final {
+		case "iu":
+			final = "iou"
+		case "ui":
+			final = "uei"
+		case "un":
+			final = "uen"
+		}
+		if initial == "j" || initial == "q" || initial == "x" {
+			if final == "uen" {
+				final = "ün"
+			} else if strings.HasPrefix(final, "u") {
+				final = "ü" + final[1:]
+			}
+		}
+	}
+
+	return initial, final, fullFinals[final]
+}
+
+// apical reports whether initial is followed by the buzzed i of zi, ci,
+// si, zhi, chi, shi, and ri rather than a true i.
+func apical(initial string) bool {
+	switch initial {
+	case "z", "c", "s", "zh", "ch", "sh", "r":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package pinyin
+
+import "github.com/f3rmion/hmm/internal/hmm"
+
+// wadeGiles is the Wade-Giles romanization of older textbooks and
+// Taiwanese names, with apostrophes for aspiration and tone numbers in
+// superscript.
+type wadeGiles struct{}
+
+func (wadeGiles) Name() string  { return "wade-giles" }
+func (wadeGiles) Label() string { return "Wade-Giles" }
+
+// wadeGilesInitials are the Wade-Giles spellings of the pinyin initials.
+var wadeGilesInitials = map[string]string{
+	"b": "p", "p": "p'", "m": "m", "f": "f",
+	"d": "t", "t": "t'", "n": "n", "l": "l",
+	"g": "k", "k": "k'", "h": "h",
+	"j": "ch", "q": "ch'", "x": "hs",
+	"zh": "ch", "ch": "ch'", "sh": "sh", "r": "j",
+	"z": "ts", "c": "ts'", "s": "s",
+}
+
+// wadeGilesFinals are the Wade-Giles spellings of the finals after an
+// initial.
+var wadeGilesFinals = map[string]string{
+	"a": "a", "o": "o", "e": "ê", "ai": "ai", "ei": "ei", "ao": "ao", "ou": "ou",
+	"an": "an", "en": "ên", "ang": "ang", "eng": "êng", "ong": "ung", "er": "êrh",
+	"i": "i", "ia": "ia", "ie": "ieh", "iao": "iao", "iou": "iu", "ian": "ien",
+	"in": "in", "iang": "iang", "ing": "ing", "iong": "iung",
+	"u": "u", "ua": "ua", "uo": "o", "uai": "uai", "uei": "ui", "uan": "uan",
+	"uen": "un", "uang": "uang", "ueng": "ung",
+	"ü": "ü", "üe": "üeh", "üan": "üan", "ün": "ün",
+}
+
+// wadeGilesAlone are the Wade-Giles spellings of the finals without an
+// initial, where pinyin writes y and w.
+var wadeGilesAlone = map[string]string{
+	"e": "o", "i": "i", "ia": "ya", "ie": "yeh", "iao": "yao", "iou": "yu",
+	"ian": "yen", "in": "yin", "iang": "yang", "ing": "ying", "iong": "yung",
+	"u": "wu", "ua": "wa", "uo": "wo", "uai": "wai", "uei": "wei", "uan": "wan",
+	"uen": "wên", "uang": "wang", "ueng": "wêng",
+	"ü": "yü", "üe": "yüeh", "üan": "yüan", "ün": "yün",
+}
+
+// wadeGilesApical are the Wade-Giles spellings of zi to ri.
+var wadeGilesApical = map[string]string{
+	"z": "tzŭ", "c": "tz'ŭ", "s": "ssŭ",
+	"zh": "chih", "ch": "ch'ih", "sh": "shih", "r": "jih",
+}
+
+// superscriptTones are the tone numbers Wade-Giles writes after a
+// syllable; the neutral tone has none.
+var superscriptTones = map[hmm.Tone]string{
+	hmm.Tone1: "¹", hmm.Tone2: "²", hmm.Tone3: "³", hmm.Tone4: "⁴",
+}
+
+func (wadeGiles) Syllable(initial, final string, tone hmm.Tone) string {
+	var s string
+	switch {
+	case initial == "":
+		s = wadeGilesFinals[final]
+		if alone, ok := wadeGilesAlone[final]; ok {
+			s = alone
+		}
+	case final == "i" && apical(initial):
+		s = wadeGilesApical[initial]
+	default:
+		f := wadeGilesFinals[final]
+		switch {
+		case final == "e" && (initial == "g" || initial == "k" || initial == "h"):
+			// ge, ke, and he are ko, k'o, and ho
+			f = "o"
+		case final == "uo" && (initial == "g" || initial == "k" || initial == "h" || initial == "sh"):
+			f = "uo"
+		}
+		s = wadeGilesInitials[initial] + f
+	}
+	return s + superscriptTones[tone]
+}
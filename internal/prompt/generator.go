@@ -17,6 +17,7 @@ type Generator struct {
 	props    map[string]*hmm.Prop
 	template *template.Template
 	style    Style
+	lang     Language
 }
 
 // Style configures the image generation output.
@@ -55,13 +56,15 @@ type SceneData struct {
 	Decomp      string
 }
 
-// NewGenerator creates a new prompt generator.
+// NewGenerator creates a new prompt generator, writing in the language
+// set by SetLanguage, English by default.
 func NewGenerator(actors []hmm.Actor, sets []hmm.Set, props []hmm.Prop) *Generator {
 	g := &Generator{
 		actors: make(map[string]*hmm.Actor),
 		sets:   make(map[string]*hmm.Set),
 		props:  make(map[string]*hmm.Prop),
-		style:  DefaultStyle(),
+		style:  language.style,
+		lang:   language,
 	}
 
 	for i := range actors {
@@ -75,7 +78,7 @@ func NewGenerator(actors []hmm.Actor, sets []hmm.Set, props []hmm.Prop) *Generat
 	}
 
 	// Default template
-	g.template = template.Must(template.New("prompt").Parse(defaultTemplate))
+	g.template = template.Must(template.New("prompt").Parse(g.lang.template))
 
 	return g
 }
@@ -85,6 +88,11 @@ func (g *Generator) SetStyle(style Style) {
 	g.style = style
 }
 
+// Language returns the language the generator writes prompts in.
+func (g *Generator) Language() Language {
+	return g.lang
+}
+
 // SetTemplate sets a custom prompt template.
 func (g *Generator) SetTemplate(tmpl string) error {
 	t, err := template.New("prompt").Parse(tmpl)
@@ -110,10 +118,11 @@ func (g *Generator) GetProp(component string) *hmm.Prop {
 	return g.props[component]
 }
 
-// GetToneRoom returns the room description for a tone within a set.
+// GetToneRoom returns the room description for a tone within a set, or
+// a default room in the generator's language if the set has none.
 func (g *Generator) GetToneRoom(set *hmm.Set, tone hmm.Tone) string {
 	if set == nil {
-		return getToneRoomDefault(tone)
+		return g.lang.room(tone)
 	}
 	for _, room := range set.Rooms {
 		if room.Tone == tone {
@@ -123,24 +132,7 @@ func (g *Generator) GetToneRoom(set *hmm.Set, tone hmm.Tone) string {
 			return room.Name
 		}
 	}
-	return getToneRoomDefault(tone)
-}
-
-func getToneRoomDefault(tone hmm.Tone) string {
-	switch tone {
-	case hmm.Tone1:
-		return "outside the entrance"
-	case hmm.Tone2:
-		return "in the kitchen"
-	case hmm.Tone3:
-		return "in the bedroom"
-	case hmm.Tone4:
-		return "in the bathroom"
-	case hmm.Tone5:
-		return "on the roof"
-	default:
-		return "inside"
-	}
+	return g.lang.room(tone)
 }
 
 // Generate creates an image prompt for a character scene.
@@ -157,18 +149,32 @@ func (g *Generator) Generate(data SceneData) (string, error) {
 
 // GenerateSimple creates a simple descriptive prompt without templates.
 func (g *Generator) GenerateSimple(data SceneData) string {
+	prompt := g.Describe(data)
+
+	// Add style suffix
+	if g.style.Suffix != "" {
+		prompt += ", " + g.style.Suffix
+	}
+
+	return prompt
+}
+
+// Describe tells the scene in plain words, as GenerateSimple does but
+// without the style, for the story of a scene.
+func (g *Generator) Describe(data SceneData) string {
 	var parts []string
+	words := g.lang.words
 
 	// Actor description
 	if data.Actor != nil && data.Actor.Name != "" {
 		parts = append(parts, data.Actor.Name)
 	} else {
-		parts = append(parts, "A person")
+		parts = append(parts, words.person)
 	}
 
 	// Location
 	if data.Set != nil && data.Set.Name != "" {
-		parts = append(parts, fmt.Sprintf("at %s", data.Set.Name))
+		parts = append(parts, words.at+" "+data.Set.Name)
 	}
 
 	// Tone room
@@ -185,23 +191,16 @@ func (g *Generator) GenerateSimple(data SceneData) string {
 			}
 		}
 		if len(propNames) > 0 {
-			parts = append(parts, fmt.Sprintf("with %s", strings.Join(propNames, " and ")))
+			parts = append(parts, words.with+" "+strings.Join(propNames, " "+words.and+" "))
 		}
 	}
 
 	// Meaning context
 	if data.Meaning != "" {
-		parts = append(parts, fmt.Sprintf("representing '%s'", data.Meaning))
-	}
-
-	prompt := strings.Join(parts, " ")
-
-	// Add style suffix
-	if g.style.Suffix != "" {
-		prompt += ", " + g.style.Suffix
+		parts = append(parts, fmt.Sprintf(words.representing, data.Meaning))
 	}
 
-	return prompt
+	return strings.Join(parts, " ")
 }
 
 // BuildSceneData constructs SceneData from HMM components.
@@ -241,7 +240,7 @@ func (g *Generator) BuildSceneData(
 	}
 }
 
-// Default prompt template
+// Default prompt template, in English; language.go has the others
 const defaultTemplate = `{{- /* HMM Image Prompt Template */ -}}
 {{- if .Actor }}{{if .Actor.Name}}{{ .Actor.Name }}{{else}}A person{{end}}{{else}}A person{{end}}
 {{- if .Set }}{{if .Set.Name}} at {{ .Set.Name }}{{end}}{{end}}
@@ -251,7 +250,9 @@ const defaultTemplate = `{{- /* HMM Image Prompt Template */ -}}
 {{- if .Etymology }}, etymology: {{ .Etymology }}{{end}}.
 {{ .Style.Name }}, {{ .Style.Suffix }}`
 
-// MidjourneyTemplate is optimized for Midjourney.
+// MidjourneyTemplate is optimized for Midjourney. It and the other
+// templates for an image generator are in English, whatever the language,
+// as their keywords are the ones the generators are trained on.
 const MidjourneyTemplate = `{{- if .Actor }}{{if .Actor.Name}}{{ .Actor.Name }}{{else}}person{{end}}{{else}}person{{end}}
 {{- if .Set }}{{if .Set.Name}} in {{ .Set.Name }}{{end}}{{end}}
 {{- if .ToneRoom }}, {{ .ToneRoom }} area{{end}}
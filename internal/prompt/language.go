@@ -0,0 +1,180 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Language is a language prompts are written in: the wording of the
+// default template and of GenerateSimple, the default style, and the
+// rooms of tones a set does not describe. Meanings from the dictionary
+// and the names in the config are used as they are.
+type Language struct {
+	Code string // ISO 639-1 code, such as "de"
+	Name string // Name in English, such as "German", to ask an LLM for it
+
+	template string
+	style    Style
+	rooms    map[hmm.Tone]string // Default rooms; ToneUnknown is the fallback
+	words    simpleWords
+}
+
+// simpleWords are the words GenerateSimple joins a scene with.
+type simpleWords struct {
+	person       string // Stands in for a missing actor
+	at           string // Before the set
+	with         string // Before the props
+	and          string // Between props
+	representing string // Format for the meaning
+}
+
+// English is the language prompts are written in by default.
+var English = Language{
+	Code:     "en",
+	Name:     "English",
+	template: defaultTemplate,
+	style:    DefaultStyle(),
+	rooms: map[hmm.Tone]string{
+		hmm.Tone1:       "outside the entrance",
+		hmm.Tone2:       "in the kitchen",
+		hmm.Tone3:       "in the bedroom",
+		hmm.Tone4:       "in the bathroom",
+		hmm.Tone5:       "on the roof",
+		hmm.Tone6:       "in the basement",
+		hmm.ToneUnknown: "inside",
+	},
+	words: simpleWords{"A person", "at", "with", "and", "representing '%s'"},
+}
+
+// languages are the languages prompts can be written in, English first.
+var languages = []Language{
+	English,
+	{
+		Code:     "de",
+		Name:     "German",
+		template: germanTemplate,
+		style: Style{
+			Name:        "cineastische Digitalkunst",
+			AspectRatio: "16:9",
+			Quality:     "hd",
+			Suffix:      "dramatische Beleuchtung, detailreich, einprägsame Szene, Gedächtnisbild",
+		},
+		rooms: map[hmm.Tone]string{
+			hmm.Tone1:       "vor dem Eingang",
+			hmm.Tone2:       "in der Küche",
+			hmm.Tone3:       "im Schlafzimmer",
+			hmm.Tone4:       "im Badezimmer",
+			hmm.Tone5:       "auf dem Dach",
+			hmm.Tone6:       "im Keller",
+			hmm.ToneUnknown: "drinnen",
+		},
+		words: simpleWords{"Eine Person", "in", "mit", "und", "steht für „%s“"},
+	},
+	{
+		Code:     "es",
+		Name:     "Spanish",
+		template: spanishTemplate,
+		style: Style{
+			Name:        "arte digital cinematográfico",
+			AspectRatio: "16:9",
+			Quality:     "hd",
+			Suffix:      "iluminación dramática, detallado, escena memorable, visualización mnemotécnica",
+		},
+		rooms: map[hmm.Tone]string{
+			hmm.Tone1:       "fuera, en la entrada",
+			hmm.Tone2:       "en la cocina",
+			hmm.Tone3:       "en el dormitorio",
+			hmm.Tone4:       "en el baño",
+			hmm.Tone5:       "en el tejado",
+			hmm.Tone6:       "en el sótano",
+			hmm.ToneUnknown: "dentro",
+		},
+		words: simpleWords{"Una persona", "en", "con", "y", "representa «%s»"},
+	},
+	{
+		Code:     "fr",
+		Name:     "French",
+		template: frenchTemplate,
+		style: Style{
+			Name:        "art numérique cinématographique",
+			AspectRatio: "16:9",
+			Quality:     "hd",
+			Suffix:      "éclairage dramatique, détaillé, scène mémorable, visualisation mnémotechnique",
+		},
+		rooms: map[hmm.Tone]string{
+			hmm.Tone1:       "devant l'entrée",
+			hmm.Tone2:       "dans la cuisine",
+			hmm.Tone3:       "dans la chambre",
+			hmm.Tone4:       "dans la salle de bain",
+			hmm.Tone5:       "sur le toit",
+			hmm.Tone6:       "à la cave",
+			hmm.ToneUnknown: "à l'intérieur",
+		},
+		words: simpleWords{"Une personne", "à", "avec", "et", "représente « %s »"},
+	},
+}
+
+// language is the language new generators write prompts in.
+var language = English
+
+// SetLanguage makes the generators created from now on write prompts in
+// l. Call it before creating any.
+func SetLanguage(l Language) {
+	language = l
+}
+
+// Languages returns the codes of the languages prompts can be written in.
+func Languages() []string {
+	codes := make([]string, len(languages))
+	for i, l := range languages {
+		codes[i] = l.Code
+	}
+	return codes
+}
+
+// LookupLanguage returns the language with the code or English name s,
+// in any case, such as "fr" or "French".
+func LookupLanguage(s string) (Language, bool) {
+	for _, l := range languages {
+		if strings.EqualFold(s, l.Code) || strings.EqualFold(s, l.Name) {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+// room returns the default room for tone.
+func (l Language) room(tone hmm.Tone) string {
+	if room, ok := l.rooms[tone]; ok {
+		return room
+	}
+	return l.rooms[hmm.ToneUnknown]
+}
+
+// germanTemplate is the default template in German.
+const germanTemplate = `{{- if .Actor }}{{if .Actor.Name}}{{ .Actor.Name }}{{else}}Eine Person{{end}}{{else}}Eine Person{{end}}
+{{- if .Set }}{{if .Set.Name}} in {{ .Set.Name }}{{end}}{{end}}
+{{- if .ToneRoom }} ({{ .ToneRoom }}){{end}}
+{{- if .Props }}, im Zusammenspiel mit {{ range $i, $p := .Props }}{{if $i}} und {{end}}{{if $p.Name}}{{ $p.Name }}{{else}}{{ $p.Component }}{{end}}{{ end }}{{end}}
+{{- if .Meaning }}, die Szene steht für „{{ .Meaning }}“{{end}}
+{{- if .Etymology }}, Etymologie: {{ .Etymology }}{{end}}.
+{{ .Style.Name }}, {{ .Style.Suffix }}`
+
+// spanishTemplate is the default template in Spanish.
+const spanishTemplate = `{{- if .Actor }}{{if .Actor.Name}}{{ .Actor.Name }}{{else}}Una persona{{end}}{{else}}Una persona{{end}}
+{{- if .Set }}{{if .Set.Name}} en {{ .Set.Name }}{{end}}{{end}}
+{{- if .ToneRoom }} ({{ .ToneRoom }}){{end}}
+{{- if .Props }}, interactuando con {{ range $i, $p := .Props }}{{if $i}} y {{end}}{{if $p.Name}}{{ $p.Name }}{{else}}{{ $p.Component }}{{end}}{{ end }}{{end}}
+{{- if .Meaning }}, la escena representa «{{ .Meaning }}»{{end}}
+{{- if .Etymology }}, etimología: {{ .Etymology }}{{end}}.
+{{ .Style.Name }}, {{ .Style.Suffix }}`
+
+// frenchTemplate is the default template in French.
+const frenchTemplate = `{{- if .Actor }}{{if .Actor.Name}}{{ .Actor.Name }}{{else}}Une personne{{end}}{{else}}Une personne{{end}}
+{{- if .Set }}{{if .Set.Name}} à {{ .Set.Name }}{{end}}{{end}}
+{{- if .ToneRoom }} ({{ .ToneRoom }}){{end}}
+{{- if .Props }}, interagissant avec {{ range $i, $p := .Props }}{{if $i}} et {{end}}{{if $p.Name}}{{ $p.Name }}{{else}}{{ $p.Component }}{{end}}{{ end }}{{end}}
+{{- if .Meaning }}, la scène représente « {{ .Meaning }} »{{end}}
+{{- if .Etymology }}, étymologie : {{ .Etymology }}{{end}}.
+{{ .Style.Name }}, {{ .Style.Suffix }}`
@@ -0,0 +1,361 @@
+// Package quiz builds tone, pinyin, meaning, and look-alike quizzes over a
+// set of characters, and tone-pair drills over two-character words, and
+// checks the answers given to them.
+package quiz
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/pinyin"
+)
+
+// Mode is what a quiz asks about a character.
+type Mode string
+
+const (
+	ModeTone    Mode = "tone"    // The tone of a toneless reading
+	ModePinyin  Mode = "pinyin"  // The reading, tone included
+	ModeMeaning Mode = "meaning" // The meaning, out of several choices
+	ModePair    Mode = "pair"    // The tones of a two-character word
+
+	// ModeConfusable asks for the character of a reading and meaning,
+	// out of it and the characters it is easily mistaken for.
+	ModeConfusable Mode = "confusable"
+)
+
+// Modes lists the quiz modes, in the order they are offered.
+var Modes = []Mode{ModeTone, ModePinyin, ModeMeaning, ModePair, ModeConfusable}
+
+// meaningChoices is how many meanings a meaning question offers.
+const meaningChoices = 4
+
+// confusableChoices is the most characters a look-alike question offers.
+const confusableChoices = 4
+
+// ParseMode returns the mode named s.
+func ParseMode(s string) (Mode, error) {
+	for _, m := range Modes {
+		if string(m) == strings.ToLower(s) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown quiz mode %q (use tone, pinyin, meaning, pair, or confusable)", s)
+}
+
+// Question is one character to answer for.
+type Question struct {
+	Char     string
+	Prompt   string                // What is shown besides the character, e.g. the toneless reading
+	Ask      string                // What the answer should be, e.g. "Tone (1-5)"
+	Choices  []string              // The meanings to choose from, numbered from 1
+	Solution string                // The right answer, shown after a wrong one
+	Readings []pinyin.ParsedPinyin // The readings of a tone-pair word, in order
+	Hidden   bool                  // Char is the answer, so it is not shown
+
+	accept []string // Normalized answers counted as right
+}
+
+// Check reports whether answer is right.
+func (q Question) Check(answer string) bool {
+	answer = normalize(answer)
+	for _, a := range q.accept {
+		if a == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// New builds a quiz of up to n questions (all of them if n <= 0) over
+// chars in random order. Characters the quiz cannot ask about, such as
+// ones without a dictionary meaning in a meaning quiz, are left out. A
+// tone-pair quiz is built with Pairs instead.
+func New(mode Mode, chars []string, parser *pinyin.Parser, dict *decomp.Dictionary, n int) ([]Question, error) {
+	if mode == ModePair {
+		return nil, fmt.Errorf("a tone-pair quiz is built from words")
+	}
+	chars = append([]string(nil), chars...)
+	rand.Shuffle(len(chars), func(i, j int) { chars[i], chars[j] = chars[j], chars[i] })
+
+	var meanings []string
+	if mode == ModeMeaning {
+		meanings = allMeanings(chars, dict)
+		if len(meanings) < 2 {
+			return nil, fmt.Errorf("a meaning quiz needs at least two characters with a dictionary meaning")
+		}
+	}
+
+	var questions []Question
+	for _, char := range chars {
+		if n > 0 && len(questions) == n {
+			break
+		}
+		readings := parser.ParseChar(char)
+		if len(readings) == 0 {
+			continue
+		}
+
+		var q Question
+		var ok bool
+		switch mode {
+		case ModeTone:
+			q, ok = toneQuestion(char, readings, parser.Tones()), true
+		case ModePinyin:
+			q, ok = pinyinQuestion(char, readings), true
+		case ModeMeaning:
+			q, ok = meaningQuestion(char, dict, meanings)
+		case ModeConfusable:
+			q, ok = confusableQuestion(char, readings, dict)
+		}
+		if ok {
+			questions = append(questions, q)
+		}
+	}
+
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("none of the %d characters can be quizzed", len(chars))
+	}
+	return questions, nil
+}
+
+// Pairs builds a tone-pair drill of up to n questions (all of them if
+// n <= 0) over two-character words. Words are drawn from each tone pair
+// in turn, so a short drill still covers as many of the pairs (20 in
+// Mandarin, 4 first tones by 5 second ones) as the words allow.
+func Pairs(words []string, parser *pinyin.Parser, n int) ([]Question, error) {
+	// Group the questions by tone pair, each group and the pairs shuffled
+	groups := make(map[string][]Question)
+	var pairs []string
+	for _, word := range words {
+		q, ok := pairQuestion(word, parser)
+		if !ok {
+			continue
+		}
+		key := q.accept[0]
+		if _, seen := groups[key]; !seen {
+			pairs = append(pairs, key)
+		}
+		groups[key] = append(groups[key], q)
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("none of the %d words is two characters with readings", len(words))
+	}
+	rand.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	for _, g := range groups {
+		rand.Shuffle(len(g), func(i, j int) { g[i], g[j] = g[j], g[i] })
+	}
+
+	var questions []Question
+	for round := 0; ; round++ {
+		added := false
+		for _, key := range pairs {
+			if n > 0 && len(questions) == n {
+				return questions, nil
+			}
+			if round < len(groups[key]) {
+				questions = append(questions, groups[key][round])
+				added = true
+			}
+		}
+		if !added {
+			return questions, nil
+		}
+	}
+}
+
+// pairQuestion asks for the tones of a two-character word, shown without
+// them. The tones of other readings of either character are right too.
+// It reports false if word is not two characters with readings.
+func pairQuestion(word string, parser *pinyin.Parser) (Question, bool) {
+	chars := []rune(word)
+	if len(chars) != 2 {
+		return Question{}, false
+	}
+	first := parser.ParseChar(string(chars[0]))
+	second := parser.ParseChar(string(chars[1]))
+	if len(first) == 0 || len(second) == 0 {
+		return Question{}, false
+	}
+
+	a, b := first[0], second[0]
+	q := Question{
+		Char:     word,
+		Prompt:   a.Toneless + " " + b.Toneless,
+		Ask:      fmt.Sprintf("Tones (1-%d each, e.g. 32)", parser.Tones()),
+		Solution: fmt.Sprintf("%s %s (%d-%d)", a.Full, b.Full, a.Tone, b.Tone),
+		Readings: []pinyin.ParsedPinyin{a, b},
+	}
+	for _, x := range first {
+		for _, y := range second {
+			if x.Toneless == a.Toneless && y.Toneless == b.Toneless {
+				q.accept = append(q.accept, fmt.Sprintf("%d%d", x.Tone, y.Tone))
+			}
+		}
+	}
+	return q, true
+}
+
+// toneQuestion asks for the tone of the first reading, shown without it,
+// out of the romanization's tones.
+// The tones of other readings spelled the same are right too.
+func toneQuestion(char string, readings []pinyin.ParsedPinyin, tones int) Question {
+	first := readings[0]
+	q := Question{
+		Char:     char,
+		Prompt:   first.Toneless,
+		Ask:      fmt.Sprintf("Tone (1-%d)", tones),
+		Solution: fmt.Sprintf("%s (tone %d)", first.Full, first.Tone),
+	}
+	for _, r := range readings {
+		if r.Toneless == first.Toneless {
+			q.accept = append(q.accept, strconv.Itoa(int(r.Tone)))
+		}
+	}
+	return q
+}
+
+// pinyinQuestion asks for the reading, right in any of the character's
+// readings, written with tone marks or numbers.
+func pinyinQuestion(char string, readings []pinyin.ParsedPinyin) Question {
+	q := Question{
+		Char: char,
+		Ask:  "Pinyin (hao3 or hǎo)",
+	}
+	var full []string
+	for _, r := range readings {
+		full = append(full, r.Full)
+		q.accept = append(q.accept, numbered(r.Toneless, r.Tone))
+	}
+	q.Solution = strings.Join(full, ", ")
+	return q
+}
+
+// meaningQuestion asks which meaning is char's, among others drawn from
+// meanings. It reports false if char has no meaning to ask for.
+func meaningQuestion(char string, dict *decomp.Dictionary, meanings []string) (Question, bool) {
+	meaning := shortMeaning(dict, char)
+	if meaning == "" {
+		return Question{}, false
+	}
+
+	choices := []string{meaning}
+	for _, i := range rand.Perm(len(meanings)) {
+		if len(choices) == meaningChoices {
+			break
+		}
+		if m := meanings[i]; m != meaning {
+			choices = append(choices, m)
+		}
+	}
+	rand.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+
+	q := Question{
+		Char:    char,
+		Ask:     fmt.Sprintf("Meaning (1-%d)", len(choices)),
+		Choices: choices,
+	}
+	for i, c := range choices {
+		if c == meaning {
+			q.accept = []string{strconv.Itoa(i + 1)}
+			q.Solution = fmt.Sprintf("%d. %s", i+1, meaning)
+		}
+	}
+	return q, true
+}
+
+// confusableQuestion asks which character has char's reading and meaning,
+// among char and the characters it is easily mistaken for. It reports
+// false if char has no look-alikes or no meaning.
+func confusableQuestion(char string, readings []pinyin.ParsedPinyin, dict *decomp.Dictionary) (Question, bool) {
+	meaning := shortMeaning(dict, char)
+	if meaning == "" {
+		return Question{}, false
+	}
+	confusables := dict.Confusables(char, confusableChoices-1)
+	if len(confusables) == 0 {
+		return Question{}, false
+	}
+
+	choices := []string{char}
+	for _, c := range confusables {
+		choices = append(choices, c.Entry.Character)
+	}
+	rand.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+
+	q := Question{
+		Char:    char,
+		Prompt:  readings[0].Full + ": " + meaning,
+		Ask:     fmt.Sprintf("Character (1-%d)", len(choices)),
+		Choices: choices,
+		Hidden:  true,
+	}
+	for i, c := range choices {
+		if c == char {
+			q.accept = []string{strconv.Itoa(i + 1)}
+			q.Solution = fmt.Sprintf("%d. %s", i+1, char)
+		}
+	}
+	return q, true
+}
+
+// allMeanings returns the distinct meanings of chars, the pool wrong
+// choices are drawn from.
+func allMeanings(chars []string, dict *decomp.Dictionary) []string {
+	var meanings []string
+	seen := make(map[string]bool)
+	for _, char := range chars {
+		if m := shortMeaning(dict, char); m != "" && !seen[m] {
+			seen[m] = true
+			meanings = append(meanings, m)
+		}
+	}
+	return meanings
+}
+
+// shortMeaning returns the first sense of char's dictionary definition,
+// or "" if there is none.
+func shortMeaning(dict *decomp.Dictionary, char string) string {
+	if dict == nil {
+		return ""
+	}
+	entry := dict.Lookup(char)
+	if entry == nil {
+		return ""
+	}
+	meaning, _, _ := strings.Cut(entry.Definition, ";")
+	return strings.TrimSpace(meaning)
+}
+
+// normalize turns an answer into the form accepted answers are kept in:
+// tone numbers for a tone or tone pair, and numbered pinyin for a reading,
+// so that hǎo, hao3, and HAO3 are all the same, as are 3-2 and 32.
+func normalize(answer string) string {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "" && strings.Trim(answer, "0123456789 -") == "" {
+		return strings.NewReplacer(" ", "", "-", "").Replace(answer)
+	}
+	answer = strings.NewReplacer("u:", "ü", "v", "ü").Replace(answer)
+	if answer == "" {
+		return ""
+	}
+	if _, err := strconv.Atoi(answer); err == nil {
+		return answer
+	}
+
+	last := answer[len(answer)-1]
+	if last >= '1' && last <= '5' {
+		return answer
+	}
+	r := pinyin.NewParser().Parse(answer)
+	return numbered(r.Toneless, r.Tone)
+}
+
+// numbered writes a reading with its tone as a number, as in hao3.
+func numbered(toneless string, tone hmm.Tone) string {
+	return fmt.Sprintf("%s%d", strings.ToLower(toneless), tone)
+}
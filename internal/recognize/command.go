@@ -0,0 +1,54 @@
+package recognize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Command is a recognizer run as a command. It is given the strokes as
+// JSON on stdin and writes its candidates to stdout.
+type Command struct {
+	argv []string
+}
+
+// NewCommand returns the recognizer run by argv.
+func NewCommand(argv []string) *Command {
+	return &Command{argv: argv}
+}
+
+// Name returns the name of the command.
+func (c *Command) Name() string {
+	if len(c.argv) == 0 {
+		return "command"
+	}
+	return c.argv[0]
+}
+
+// Recognize runs the command on strokes.
+func (c *Command) Recognize(ctx context.Context, strokes []Stroke) ([]Candidate, error) {
+	if len(c.argv) == 0 {
+		return nil, errors.New("no recognizer command given")
+	}
+	input, err := json.Marshal(strokes)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.argv[0], c.argv[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", c.Name(), err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", c.Name(), err)
+	}
+	return ParseCandidates(stdout.Bytes())
+}
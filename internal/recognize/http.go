@@ -0,0 +1,64 @@
+package recognize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTP is a recognizer reached over HTTP. The strokes are POSTed to it as
+// JSON, {"strokes": [...]}, and it replies with its candidates.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP returns the recognizer at endpoint.
+func NewHTTP(endpoint string) *HTTP {
+	return &HTTP{url: endpoint, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the host of the endpoint.
+func (h *HTTP) Name() string {
+	if u, err := url.Parse(h.url); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return h.url
+}
+
+// Recognize asks the endpoint what strokes are.
+func (h *HTTP) Recognize(ctx context.Context, strokes []Stroke) ([]Candidate, error) {
+	body, err := json.Marshal(struct {
+		Strokes []Stroke `json:"strokes"`
+	}{strokes})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", h.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", h.Name(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: status %d: %s", h.Name(), resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return ParseCandidates(data)
+}
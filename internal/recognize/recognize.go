@@ -0,0 +1,129 @@
+// Package recognize turns a character drawn by hand into the characters
+// it most likely is, so characters that cannot be typed can still be
+// looked up. Recognition itself is left to an external recognizer, such
+// as hanzi-lookup, run as a command or reached over HTTP.
+package recognize
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"strings"
+)
+
+// Stroke is one stroke of a drawn character, the points the pen passed
+// through as [x, y] pairs. A drawing is written as JSON as a list of its
+// strokes, the input hanzi-lookup takes.
+type Stroke [][2]float64
+
+// Candidate is a character a drawing may be, with the recognizer's score;
+// higher is better, and 0 if the recognizer gave none.
+type Candidate struct {
+	Char  string  `json:"char"`
+	Score float64 `json:"score,omitempty"`
+}
+
+// Provider recognizes drawn characters.
+type Provider interface {
+	// Name returns a short name for messages.
+	Name() string
+
+	// Recognize returns the characters strokes may be, best first.
+	Recognize(ctx context.Context, strokes []Stroke) ([]Candidate, error)
+}
+
+// New returns the provider spec names: an http:// or https:// URL is an
+// HTTP endpoint, anything else a command line to run.
+func New(spec string) (Provider, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.New("no recognizer given")
+	}
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		return NewHTTP(spec), nil
+	}
+	return NewCommand(strings.Fields(spec)), nil
+}
+
+// ParseStrokes reads a drawing written as JSON, either a list of strokes
+// or an object with them under "strokes".
+func ParseStrokes(data []byte) ([]Stroke, error) {
+	var strokes []Stroke
+	if err := json.Unmarshal(data, &strokes); err == nil {
+		return strokes, nil
+	}
+	var wrapped struct {
+		Strokes []Stroke `json:"strokes"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("parsing strokes: %w", err)
+	}
+	return wrapped.Strokes, nil
+}
+
+// ParseCandidates reads what a recognizer wrote. It understands the JSON
+// of hanzi-lookup, a list of {"hanzi": ..., "score": ...} objects, also
+// under "matches" or "candidates", and lists of plain characters. Anything
+// that is not JSON is read as text, its Chinese characters in order.
+func ParseCandidates(data []byte) ([]Candidate, error) {
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return nil, nil
+	}
+	if text[0] != '[' && text[0] != '{' {
+		return textCandidates(text), nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &list); err != nil {
+		var wrapped struct {
+			Matches    []json.RawMessage `json:"matches"`
+			Candidates []json.RawMessage `json:"candidates"`
+		}
+		if err := json.Unmarshal([]byte(text), &wrapped); err != nil {
+			return nil, fmt.Errorf("parsing candidates: %w", err)
+		}
+		list = append(wrapped.Matches, wrapped.Candidates...)
+	}
+
+	var candidates []Candidate
+	for _, raw := range list {
+		var char string
+		if err := json.Unmarshal(raw, &char); err == nil {
+			candidates = append(candidates, Candidate{Char: char})
+			continue
+		}
+		var match struct {
+			Hanzi     string  `json:"hanzi"`
+			Char      string  `json:"char"`
+			Character string  `json:"character"`
+			Score     float64 `json:"score"`
+		}
+		if err := json.Unmarshal(raw, &match); err != nil {
+			return nil, fmt.Errorf("parsing candidate %s: %w", raw, err)
+		}
+		c := Candidate{Char: match.Hanzi, Score: match.Score}
+		if c.Char == "" {
+			c.Char = match.Char
+		}
+		if c.Char == "" {
+			c.Char = match.Character
+		}
+		if c.Char != "" {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// textCandidates returns the Chinese characters of text as candidates, in
+// order and without repeats.
+func textCandidates(text string) []Candidate {
+	var candidates []Candidate
+	for _, char := range analyze.UniqueChineseChars(text) {
+		candidates = append(candidates, Candidate{Char: char})
+	}
+	return candidates
+}
@@ -0,0 +1,195 @@
+package scene
+
+import (
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/prompt"
+)
+
+// NoteTypeName is the Anki note type used for decks built from scenes.
+const NoteTypeName = "HMM Scene"
+
+// NoteFields are the fields of the scene note type, in order.
+var NoteFields = []string{
+	"Character",
+	"Pinyin",
+	"Keyword",
+	"Actor",
+	"Set",
+	"ToneRoom",
+	"Props",
+	"Story",
+	"ImagePrompt",
+	"LookAlikes",
+}
+
+// SyncResult counts what happened while syncing scenes into a deck.
+type SyncResult struct {
+	Written   int // Notes created or updated
+	Unmatched int // Notes in an existing deck with no approved scene
+}
+
+// NoteGUID returns the GUID of the Anki note for sc, assigning a stable
+// one derived from its key the first time the scene is synced.
+func NoteGUID(sc *hmm.Scene) string {
+	if sc.NoteGUID == "" {
+		sc.NoteGUID = anki.StableGUID(Key(*sc))
+	}
+	return sc.NoteGUID
+}
+
+// BuildDeck adds a note for every approved scene in the store to b and
+// records each note's GUID on its scene. Importing the built deck again
+// after editing scenes updates the same notes in Anki.
+func (s *Store) BuildDeck(b *anki.DeckBuilder, gen *prompt.Generator) (SyncResult, error) {
+	var result SyncResult
+
+	for _, key := range s.keys() {
+		sc := s.scenes[key]
+		if !sc.IsApproved() {
+			continue
+		}
+
+		names := resolveNames(*sc, gen)
+		fields := []string{
+			sc.Character,
+			sc.Pinyin,
+			sc.Keyword,
+			names.Actor,
+			names.Set,
+			names.ToneRoom,
+			names.Props,
+			sc.Script,
+			sc.ImagePrompt,
+			s.lookAlikes(*sc),
+		}
+		if err := b.AddNote(NoteGUID(sc), fields, "hmm"); err != nil {
+			return result, err
+		}
+		result.Written++
+	}
+
+	return result, nil
+}
+
+// SyncPackage writes the HMM fields of approved scenes into the matching
+// notes of an existing deck. A note matches a scene when it was synced to
+// it before (same GUID) or when field holds exactly the scene's character.
+// Newly matched notes are recorded on the scene so later syncs follow them.
+func (s *Store) SyncPackage(pkg *anki.Package, field string, gen *prompt.Generator) (SyncResult, error) {
+	var result SyncResult
+
+	byGUID := make(map[string]*hmm.Scene)
+	for _, sc := range s.scenes {
+		if sc.NoteGUID != "" && sc.IsApproved() {
+			byGUID[sc.NoteGUID] = sc
+		}
+	}
+
+	type match struct {
+		note  *anki.Note
+		scene *hmm.Scene
+	}
+	var matches []match
+	for _, note := range pkg.Notes {
+		sc := byGUID[note.GUID]
+		if sc == nil {
+			char := htmlutil.Text(pkg.GetFieldValue(note, field))
+			if char == "" {
+				continue
+			}
+			sc = s.approvedPtr(char)
+			if sc == nil {
+				result.Unmatched++
+				continue
+			}
+			if sc.NoteGUID == "" {
+				sc.NoteGUID = note.GUID
+			}
+		}
+		matches = append(matches, match{note: note, scene: sc})
+	}
+
+	updated := make(map[int64]bool)
+	for _, m := range matches {
+		if !updated[m.note.ModelID] {
+			if err := pkg.AddHMMFieldsToModel(m.note.ModelID); err != nil {
+				return result, err
+			}
+			updated[m.note.ModelID] = true
+		}
+
+		names := resolveNames(*m.scene, gen)
+		data := anki.AugmentedData{
+			Actor:       names.Actor,
+			Set:         names.Set,
+			ToneRoom:    names.ToneRoom,
+			Props:       names.Props,
+			ImagePrompt: m.scene.ImagePrompt,
+		}
+		if err := pkg.SetNoteHMMData(m.note, data); err != nil {
+			return result, err
+		}
+		result.Written++
+	}
+
+	return result, nil
+}
+
+// lookAlikes renders a scene's links as a single field value.
+func (s *Store) lookAlikes(sc hmm.Scene) string {
+	var lines []string
+	for _, l := range sc.Links {
+		line := s.LinkedCharacter(l)
+		if l.Note != "" {
+			line += " — " + l.Note
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// approvedPtr is like Approved but returns the stored scene so it can be
+// modified in place.
+func (s *Store) approvedPtr(char string) *hmm.Scene {
+	sc := s.Approved(char)
+	if sc == nil {
+		return nil
+	}
+	return s.scenes[Key(*sc)]
+}
+
+// sceneNames holds the display names of a scene's actor, set, and props.
+type sceneNames struct {
+	Actor    string
+	Set      string
+	ToneRoom string
+	Props    string
+}
+
+// resolveNames looks up the actor, set, room, and prop names for sc.
+func resolveNames(sc hmm.Scene, gen *prompt.Generator) sceneNames {
+	var names sceneNames
+
+	set := gen.GetSet(sc.SetID)
+	if actor := gen.GetActor(sc.ActorID); actor != nil {
+		names.Actor = actor.Name
+	}
+	if set != nil {
+		names.Set = set.Name
+	}
+	names.ToneRoom = gen.GetToneRoom(set, sc.Tone)
+
+	var props []string
+	for _, id := range sc.PropIDs {
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			props = append(props, p.Name)
+		}
+	}
+	names.Props = strings.Join(props, ", ")
+
+	return names
+}
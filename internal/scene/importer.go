@@ -0,0 +1,197 @@
+package scene
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"gopkg.in/yaml.v3"
+)
+
+// ConflictPolicy decides what happens when an imported scene's character
+// already has a scene in the store.
+type ConflictPolicy string
+
+const (
+	ConflictKeep      ConflictPolicy = "keep"      // Keep the existing scene, skip the import
+	ConflictOverwrite ConflictPolicy = "overwrite" // Replace the existing scene
+	ConflictDuplicate ConflictPolicy = "duplicate" // Store the import as an additional variant
+)
+
+// MergeResult counts what happened during a merge.
+type MergeResult struct {
+	Added       int
+	Overwritten int
+	Duplicated  int
+	Skipped     int
+}
+
+// ReadFile reads scenes from a YAML, CSV, or TSV file, chosen by extension.
+func ReadFile(path string) ([]hmm.Scene, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening import file: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ReadYAML(f)
+	case ".csv":
+		return ReadCSV(f, ',')
+	case ".tsv", ".txt":
+		return ReadCSV(f, '\t')
+	default:
+		return nil, fmt.Errorf("unsupported import file type: %s", filepath.Ext(path))
+	}
+}
+
+// ReadYAML reads scenes in the same layout as the scene store file.
+func ReadYAML(r io.Reader) ([]hmm.Scene, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading YAML: %w", err)
+	}
+
+	var file struct {
+		Scenes []hmm.Scene `yaml:"scenes"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+
+	for _, sc := range file.Scenes {
+		if sc.Character == "" {
+			continue
+		}
+		if err := checkKey(Key(sc)); err != nil {
+			return nil, err
+		}
+	}
+
+	return file.Scenes, nil
+}
+
+// ReadCSV reads scenes from a delimited file with a header row.
+// Column names match the YAML keys (character, pinyin, keyword, script,
+// image_prompt, ...); prop_ids are separated by semicolons.
+// Only the character column is required.
+func ReadCSV(r io.Reader, sep rune) ([]hmm.Scene, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = sep
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["character"]; !ok {
+		return nil, fmt.Errorf("missing required column: character")
+	}
+
+	get := func(record []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var scenes []hmm.Scene
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading row: %w", err)
+		}
+
+		sc := hmm.Scene{
+			ID:          get(record, "id"),
+			Character:   get(record, "character"),
+			Pinyin:      get(record, "pinyin"),
+			Initial:     get(record, "initial"),
+			Final:       get(record, "final"),
+			Keyword:     get(record, "keyword"),
+			ActorID:     get(record, "actor_id"),
+			SetID:       get(record, "set_id"),
+			Script:      get(record, "script"),
+			ImagePrompt: get(record, "image_prompt"),
+			Image:       get(record, "image"),
+		}
+		if sc.Character == "" {
+			continue
+		}
+		if err := checkKey(Key(sc)); err != nil {
+			return nil, err
+		}
+		if tone, err := strconv.Atoi(get(record, "tone")); err == nil {
+			sc.Tone = hmm.Tone(tone)
+		}
+		if props := get(record, "prop_ids"); props != "" {
+			for _, p := range strings.Split(props, ";") {
+				if p = strings.TrimSpace(p); p != "" {
+					sc.PropIDs = append(sc.PropIDs, p)
+				}
+			}
+		}
+
+		scenes = append(scenes, sc)
+	}
+
+	return scenes, nil
+}
+
+// Merge adds scenes to the store, resolving conflicts with policy.
+func (s *Store) Merge(scenes []hmm.Scene, policy ConflictPolicy) MergeResult {
+	var result MergeResult
+
+	for _, sc := range scenes {
+		if sc.Character == "" {
+			continue
+		}
+
+		key := Key(sc)
+		if s.Get(key) == nil {
+			s.Put(sc)
+			result.Added++
+			continue
+		}
+
+		switch policy {
+		case ConflictOverwrite:
+			s.Put(sc)
+			result.Overwritten++
+		case ConflictDuplicate:
+			sc.ID = s.nextVariantID(sc.Character)
+			s.Put(sc)
+			result.Duplicated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result
+}
+
+// nextVariantID returns an unused key for an extra scene of char.
+func (s *Store) nextVariantID(char string) string {
+	for n := 2; ; n++ {
+		id := fmt.Sprintf("%s-%d", char, n)
+		if s.Get(id) == nil {
+			return id
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package scene
+
+import (
+	"fmt"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Link connects the scenes stored under keys a and b, recording note on
+// both sides. Linking an already linked pair replaces the note.
+func (s *Store) Link(a, b, note string) error {
+	if a == b {
+		return fmt.Errorf("cannot link a scene to itself")
+	}
+	sa, sb := s.Get(a), s.Get(b)
+	if sa == nil {
+		return fmt.Errorf("no scene for %s", a)
+	}
+	if sb == nil {
+		return fmt.Errorf("no scene for %s", b)
+	}
+
+	setLink(sa, b, note)
+	setLink(sb, a, note)
+	return nil
+}
+
+// Unlink removes the link between the scenes stored under keys a and b.
+// It reports whether a link existed.
+func (s *Store) Unlink(a, b string) bool {
+	removed := false
+	if sc := s.Get(a); sc != nil {
+		removed = removeLink(sc, b) || removed
+	}
+	if sc := s.Get(b); sc != nil {
+		removed = removeLink(sc, a) || removed
+	}
+	return removed
+}
+
+// LinksFor returns the links of every scene stored for a character.
+// Links to scenes that no longer exist are skipped.
+func (s *Store) LinksFor(char string) []hmm.SceneLink {
+	var links []hmm.SceneLink
+	seen := make(map[string]bool)
+	for _, sc := range s.Variants(char) {
+		for _, l := range sc.Links {
+			if seen[l.Key] || s.Get(l.Key) == nil {
+				continue
+			}
+			seen[l.Key] = true
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// LinkedCharacter returns the character of the scene a link points to.
+func (s *Store) LinkedCharacter(l hmm.SceneLink) string {
+	if sc := s.Get(l.Key); sc != nil {
+		return sc.Character
+	}
+	return l.Key
+}
+
+func setLink(sc *hmm.Scene, key, note string) {
+	for i := range sc.Links {
+		if sc.Links[i].Key == key {
+			sc.Links[i].Note = note
+			return
+		}
+	}
+	sc.Links = append(sc.Links, hmm.SceneLink{Key: key, Note: note})
+}
+
+func removeLink(sc *hmm.Scene, key string) bool {
+	for i, l := range sc.Links {
+		if l.Key == key {
+			sc.Links = append(sc.Links[:i], sc.Links[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package scene
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// builtinLists holds the word lists shipped with hmm, one .txt file per
+// list.
+//
+//go:embed lists
+var builtinLists embed.FS
+
+// ListName turns how a list is written on the command line ("HSK 1",
+// "hsk1.txt") into the name its file has ("hsk1").
+func ListName(name string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".txt")
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// BuiltinLists returns the word lists shipped with hmm, sorted by name.
+func BuiltinLists() []TargetList {
+	paths, _ := fs.Glob(builtinLists, "lists/*.txt")
+	sort.Strings(paths)
+
+	var lists []TargetList
+	for _, p := range paths {
+		data, err := builtinLists.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		lists = append(lists, newTargetList(strings.TrimSuffix(path.Base(p), ".txt"), string(data), true))
+	}
+	return lists
+}
+
+// AllTargetLists returns the lists in dir and the built-in lists, sorted
+// by name. A list in dir replaces the built-in list of the same name.
+func AllTargetLists(dir string) ([]TargetList, error) {
+	lists, err := LoadTargetLists(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	own := make(map[string]bool, len(lists))
+	for _, l := range lists {
+		own[l.Name] = true
+	}
+	for _, l := range BuiltinLists() {
+		if !own[l.Name] {
+			lists = append(lists, l)
+		}
+	}
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	return lists, nil
+}
+
+// FindTargetList returns the list called name: name.txt in dir, or else
+// the built-in list of that name.
+func FindTargetList(dir, name string) (TargetList, error) {
+	name = ListName(name)
+	list, err := ReadTargetList(filepath.Join(dir, name+".txt"))
+	if err == nil {
+		return list, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return TargetList{}, err
+	}
+
+	for _, l := range BuiltinLists() {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return TargetList{}, fmt.Errorf("no list called %s: put %s.txt into %s", name, name, dir)
+}
+
+// Words returns the words on the list in order, without duplicates: the
+// runs of Chinese characters on its lines, # comments skipped.
+func (l TargetList) Words() []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(l.Text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, w := range analyze.ChineseRuns(line) {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	return words
+}
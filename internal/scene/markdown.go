@@ -0,0 +1,132 @@
+package scene
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatter is the YAML header written at the top of each Markdown note.
+type frontmatter struct {
+	Character string   `yaml:"character"`
+	Pinyin    string   `yaml:"pinyin"`
+	Tone      int      `yaml:"tone"`
+	Keyword   string   `yaml:"keyword,omitempty"`
+	Actor     string   `yaml:"actor,omitempty"`
+	ActorID   string   `yaml:"actor_id,omitempty"`
+	Set       string   `yaml:"set,omitempty"`
+	SetID     string   `yaml:"set_id,omitempty"`
+	Room      string   `yaml:"room,omitempty"`
+	Props     []string `yaml:"props,omitempty"`
+	Tags      []string `yaml:"tags"`
+}
+
+// ExportMarkdown writes one Markdown note per scene into dir.
+// Actor, set, and prop names are resolved through gen so the notes
+// reflect the current configuration. It returns the number of notes written.
+func ExportMarkdown(dir string, scenes []hmm.Scene, gen *prompt.Generator) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("creating output dir: %w", err)
+	}
+
+	written := 0
+	for _, sc := range scenes {
+		note, err := RenderMarkdown(sc, gen)
+		if err != nil {
+			return written, fmt.Errorf("rendering %s: %w", sc.Character, err)
+		}
+
+		key := Key(sc)
+		if err := checkKey(key); err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, key+".md")
+		if err := os.WriteFile(path, []byte(note), 0644); err != nil {
+			return written, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+// RenderMarkdown renders a single scene as a Markdown note with frontmatter.
+func RenderMarkdown(sc hmm.Scene, gen *prompt.Generator) (string, error) {
+	fm := frontmatter{
+		Character: sc.Character,
+		Pinyin:    sc.Pinyin,
+		Tone:      int(sc.Tone),
+		Keyword:   sc.Keyword,
+		ActorID:   sc.ActorID,
+		SetID:     sc.SetID,
+		Tags:      []string{"hanzi", "hmm"},
+	}
+
+	set := gen.GetSet(sc.SetID)
+	if actor := gen.GetActor(sc.ActorID); actor != nil {
+		fm.Actor = actor.Name
+	}
+	if set != nil {
+		fm.Set = set.Name
+	}
+	fm.Room = gen.GetToneRoom(set, sc.Tone)
+	for _, id := range sc.PropIDs {
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			fm.Props = append(fm.Props, p.Name)
+		} else {
+			fm.Props = append(fm.Props, id)
+		}
+	}
+
+	header, err := yaml.Marshal(&fm)
+	if err != nil {
+		return "", fmt.Errorf("marshaling frontmatter: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.Write(header)
+	b.WriteString("---\n\n")
+
+	b.WriteString(fmt.Sprintf("# %s (%s)\n\n", sc.Character, sc.Pinyin))
+	if sc.Keyword != "" {
+		b.WriteString(fmt.Sprintf("> %s\n\n", sc.Keyword))
+	}
+
+	if sc.Script != "" {
+		b.WriteString("## Story\n\n")
+		b.WriteString(sc.Script)
+		b.WriteString("\n\n")
+	}
+
+	if sc.ImagePrompt != "" {
+		b.WriteString("## Prompt\n\n")
+		b.WriteString("```\n")
+		b.WriteString(sc.ImagePrompt)
+		b.WriteString("\n```\n\n")
+	}
+
+	if len(sc.Links) > 0 {
+		b.WriteString("## Look-alikes\n\n")
+		for _, l := range sc.Links {
+			if l.Note != "" {
+				b.WriteString(fmt.Sprintf("- [[%s]] — %s\n", l.Key, l.Note))
+			} else {
+				b.WriteString(fmt.Sprintf("- [[%s]]\n", l.Key))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if sc.Image != "" {
+		b.WriteString("## Image\n\n")
+		b.WriteString(fmt.Sprintf("![%s](%s)\n", sc.Character, filepath.ToSlash(sc.Image)))
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n", nil
+}
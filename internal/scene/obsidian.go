@@ -0,0 +1,348 @@
+package scene
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"gopkg.in/yaml.v3"
+)
+
+// Markers around the part of a vault note that hmm writes. Everything
+// outside them belongs to the user and survives a re-sync.
+const (
+	vaultBegin = "<!-- hmm:begin -->"
+	vaultEnd   = "<!-- hmm:end -->"
+)
+
+// vaultFrontmatter is the frontmatter of a vault note. Every key is always
+// written, even when empty, so Obsidian queries can rely on them.
+type vaultFrontmatter struct {
+	Character  string   `yaml:"character"`
+	Pinyin     string   `yaml:"pinyin"`
+	Tone       int      `yaml:"tone"`
+	Keyword    string   `yaml:"keyword"`
+	Actor      string   `yaml:"actor"`
+	ActorID    string   `yaml:"actor_id"`
+	Set        string   `yaml:"set"`
+	SetID      string   `yaml:"set_id"`
+	Room       string   `yaml:"room"`
+	Props      []string `yaml:"props"`
+	Components []string `yaml:"components"`
+	Tags       []string `yaml:"tags"`
+}
+
+// VaultResult summarizes a vault sync.
+type VaultResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// SyncVault writes one note per character with an approved scene into dir,
+// as an Obsidian vault folder. Notes link to the notes of characters
+// sharing a component with them and to look-alikes.
+//
+// Existing notes are updated in place: hmm's frontmatter keys are set and
+// others kept, tags are merged, and only the part between the hmm markers
+// is replaced, so sections the user added are left alone.
+func (s *Store) SyncVault(dir string, gen *prompt.Generator) (VaultResult, error) {
+	var result VaultResult
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return result, fmt.Errorf("creating vault folder: %w", err)
+	}
+
+	var scenes []hmm.Scene
+	seen := make(map[string]bool)
+	for _, sc := range s.All() {
+		if seen[sc.Character] {
+			continue
+		}
+		if a := s.Approved(sc.Character); a != nil {
+			seen[sc.Character] = true
+			scenes = append(scenes, *a)
+		}
+	}
+
+	byComponent := make(map[string][]string)
+	for _, sc := range scenes {
+		for _, id := range uniqueStrings(sc.PropIDs) {
+			byComponent[id] = append(byComponent[id], sc.Character)
+		}
+	}
+
+	for _, sc := range scenes {
+		path := filepath.Join(dir, sc.Character+".md")
+		old, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return result, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		note, err := mergeVaultNote(string(old), vaultHeader(sc, gen), s.vaultBody(sc, dir, gen, byComponent))
+		if err != nil {
+			return result, fmt.Errorf("updating %s: %w", path, err)
+		}
+
+		switch {
+		case old == nil:
+			result.Created++
+		case string(old) == note:
+			result.Unchanged++
+			continue
+		default:
+			result.Updated++
+		}
+		if err := os.WriteFile(path, []byte(note), 0644); err != nil {
+			return result, fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+
+	return result, nil
+}
+
+// vaultHeader returns the frontmatter hmm writes for sc.
+func vaultHeader(sc hmm.Scene, gen *prompt.Generator) vaultFrontmatter {
+	fm := vaultFrontmatter{
+		Character:  sc.Character,
+		Pinyin:     sc.Pinyin,
+		Tone:       int(sc.Tone),
+		Keyword:    sc.Keyword,
+		ActorID:    sc.ActorID,
+		SetID:      sc.SetID,
+		Props:      []string{},
+		Components: []string{},
+		Tags:       []string{"hanzi", "hmm"},
+	}
+
+	set := gen.GetSet(sc.SetID)
+	if actor := gen.GetActor(sc.ActorID); actor != nil {
+		fm.Actor = actor.Name
+	}
+	if set != nil {
+		fm.Set = set.Name
+	}
+	fm.Room = gen.GetToneRoom(set, sc.Tone)
+	for _, id := range sc.PropIDs {
+		fm.Components = append(fm.Components, id)
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			fm.Props = append(fm.Props, p.Name)
+		} else {
+			fm.Props = append(fm.Props, id)
+		}
+	}
+	return fm
+}
+
+// vaultBody returns the part of sc's note that hmm writes, between the
+// markers.
+func (s *Store) vaultBody(sc hmm.Scene, dir string, gen *prompt.Generator, byComponent map[string][]string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s (%s)\n\n", sc.Character, sc.Pinyin)
+	if sc.Keyword != "" {
+		fmt.Fprintf(&b, "> %s\n\n", sc.Keyword)
+	}
+
+	if sc.Script != "" {
+		b.WriteString("## Story\n\n")
+		b.WriteString(sc.Script)
+		b.WriteString("\n\n")
+	}
+
+	if sc.ImagePrompt != "" {
+		b.WriteString("## Prompt\n\n")
+		b.WriteString("```\n")
+		b.WriteString(sc.ImagePrompt)
+		b.WriteString("\n```\n\n")
+	}
+
+	var shared []string
+	for _, id := range uniqueStrings(sc.PropIDs) {
+		var others []string
+		for _, char := range byComponent[id] {
+			if char != sc.Character {
+				others = append(others, "[["+char+"]]")
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		name := id
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			name = fmt.Sprintf("%s (%s)", id, p.Name)
+		}
+		shared = append(shared, fmt.Sprintf("- %s: %s\n", name, strings.Join(others, " ")))
+	}
+	if len(shared) > 0 {
+		b.WriteString("## Shares components\n\n")
+		for _, line := range shared {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	if links := s.LinksFor(sc.Character); len(links) > 0 {
+		b.WriteString("## Look-alikes\n\n")
+		for _, l := range links {
+			if l.Note != "" {
+				fmt.Fprintf(&b, "- [[%s]] — %s\n", s.LinkedCharacter(l), l.Note)
+			} else {
+				fmt.Fprintf(&b, "- [[%s]]\n", s.LinkedCharacter(l))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if image := s.ImagePath(sc); image != "" {
+		if abs, err := filepath.Abs(image); err == nil {
+			image = abs
+		}
+		if absDir, err := filepath.Abs(dir); err == nil {
+			if rel, err := filepath.Rel(absDir, image); err == nil {
+				image = rel
+			}
+		}
+		b.WriteString("## Image\n\n")
+		fmt.Fprintf(&b, "![%s](<%s>)\n", sc.Character, filepath.ToSlash(image))
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// mergeVaultNote returns the note old updated with hmm's frontmatter fm
+// and body. An empty old yields a new note.
+func mergeVaultNote(old string, fm vaultFrontmatter, body string) (string, error) {
+	userHeader, rest := splitFrontmatter(old)
+
+	header, err := mergeFrontmatter(userHeader, fm)
+	if err != nil {
+		return "", err
+	}
+
+	block := vaultBegin + "\n" + body + "\n" + vaultEnd
+	var content string
+	begin := strings.Index(rest, vaultBegin)
+	end := strings.Index(rest, vaultEnd)
+	switch {
+	case begin >= 0 && end > begin:
+		content = rest[:begin] + block + rest[end+len(vaultEnd):]
+	case strings.TrimSpace(rest) == "":
+		content = block + "\n"
+	default:
+		// A note the user wrote before the first sync: keep it and add
+		// hmm's part at the end.
+		content = strings.TrimRight(rest, "\n") + "\n\n" + block + "\n"
+	}
+
+	return "---\n" + header + "---\n\n" + strings.TrimLeft(content, "\n"), nil
+}
+
+// splitFrontmatter splits a note into its YAML frontmatter, without the
+// --- lines, and the rest.
+func splitFrontmatter(note string) (string, string) {
+	if !strings.HasPrefix(note, "---\n") {
+		return "", note
+	}
+	if strings.HasPrefix(note[4:], "---") {
+		return "", strings.TrimPrefix(strings.TrimPrefix(note[7:], "\r"), "\n")
+	}
+	end := strings.Index(note[4:], "\n---")
+	if end < 0 {
+		return "", note
+	}
+	header := note[4 : 4+end+1]
+	rest := strings.TrimPrefix(note[4+end+4:], "\n")
+	return header, rest
+}
+
+// mergeFrontmatter sets hmm's keys in the user's frontmatter, keeping the
+// user's other keys where they are, and merges the tags.
+func mergeFrontmatter(user string, fm vaultFrontmatter) (string, error) {
+	var doc yaml.Node
+	if strings.TrimSpace(user) != "" {
+		if err := yaml.Unmarshal([]byte(user), &doc); err != nil {
+			return "", fmt.Errorf("parsing frontmatter: %w", err)
+		}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := doc.Content[0]
+
+	var ours yaml.Node
+	if err := ours.Encode(&fm); err != nil {
+		return "", fmt.Errorf("encoding frontmatter: %w", err)
+	}
+
+	for i := 0; i+1 < len(ours.Content); i += 2 {
+		key, value := ours.Content[i], ours.Content[i+1]
+		existing := mappingValue(mapping, key.Value)
+		switch {
+		case existing == nil:
+			mapping.Content = append(mapping.Content, key, value)
+		case key.Value == "tags":
+			*existing = *mergeTags(existing, fm.Tags)
+		default:
+			*existing = *value
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("encoding frontmatter: %w", err)
+	}
+	enc.Close()
+	return buf.String(), nil
+}
+
+// mappingValue returns the value of key in a YAML mapping, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mergeTags returns the user's tags with any of hmm's tags they lack.
+func mergeTags(user *yaml.Node, tags []string) *yaml.Node {
+	var merged []string
+	switch user.Kind {
+	case yaml.SequenceNode:
+		for _, n := range user.Content {
+			merged = append(merged, n.Value)
+		}
+	case yaml.ScalarNode:
+		merged = strings.FieldsFunc(user.Value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	merged = uniqueStrings(append(merged, tags...))
+
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+	if user.Kind == yaml.SequenceNode {
+		node.Style = user.Style
+	}
+	for _, t := range merged {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: t})
+	}
+	return node
+}
+
+// uniqueStrings returns s without repeated elements, in order.
+func uniqueStrings(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	var out []string
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,224 @@
+package scene
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"gopkg.in/yaml.v3"
+)
+
+// Files inside a scene pack archive.
+const (
+	PackManifestFile = "pack.yaml"
+	PackScenesFile   = "scenes.yaml"
+)
+
+// Placeholders used in pack stories and prompts instead of personal names.
+const (
+	ActorPlaceholder = "{{actor}}"
+	SetPlaceholder   = "{{set}}"
+	RoomPlaceholder  = "{{room}}"
+)
+
+// PackInfo describes a scene pack.
+type PackInfo struct {
+	Name        string `yaml:"name"`
+	Author      string `yaml:"author,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version,omitempty"`
+}
+
+// Pack is a shareable set of scenes keyed by character. Stories and
+// prompts refer to actors, sets, rooms, and props only by placeholder, so
+// a pack carries nothing from its author's personal configuration.
+type Pack struct {
+	Info   PackInfo
+	Scenes []hmm.Scene
+}
+
+// PropPlaceholder returns the placeholder for the prop of a component.
+func PropPlaceholder(id string) string {
+	return "{{prop:" + id + "}}"
+}
+
+// ReadPack reads a scene pack archive.
+func ReadPack(path string) (*Pack, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening pack: %w", err)
+	}
+	defer r.Close()
+
+	pack := &Pack{}
+	foundScenes := false
+	for _, f := range r.File {
+		switch f.Name {
+		case PackManifestFile:
+			data, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if err := yaml.Unmarshal(data, &pack.Info); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", PackManifestFile, err)
+			}
+		case PackScenesFile:
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			pack.Scenes, err = ReadYAML(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			foundScenes = true
+		}
+	}
+
+	if !foundScenes {
+		return nil, fmt.Errorf("pack has no %s", PackScenesFile)
+	}
+	return pack, nil
+}
+
+// WritePack writes a scene pack archive.
+func WritePack(path string, pack *Pack) error {
+	manifest, err := yaml.Marshal(&pack.Info)
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+	scenes, err := yaml.Marshal(&struct {
+		Scenes []hmm.Scene `yaml:"scenes"`
+	}{Scenes: pack.Scenes})
+	if err != nil {
+		return fmt.Errorf("marshaling scenes: %w", err)
+	}
+
+	outFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating pack: %w", err)
+	}
+	defer outFile.Close()
+
+	zipWriter := zip.NewWriter(outFile)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{PackManifestFile, manifest},
+		{PackScenesFile, scenes},
+	} {
+		w, err := zipWriter.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("creating pack: %w", err)
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return fmt.Errorf("creating pack: %w", err)
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// Anonymize prepares a scene for sharing: the names of the author's
+// actor, set, room, and props are replaced by placeholders, and anything
+// tied to the author's setup (IDs, images, review status, Anki mapping)
+// is dropped.
+func Anonymize(sc hmm.Scene, gen *prompt.Generator) hmm.Scene {
+	var pairs []string
+	set := gen.GetSet(sc.SetID)
+	if actor := gen.GetActor(sc.ActorID); actor != nil && actor.Name != "" {
+		pairs = append(pairs, actor.Name, ActorPlaceholder)
+	}
+	if set != nil && set.Name != "" {
+		pairs = append(pairs, set.Name, SetPlaceholder)
+	}
+	if room := gen.GetToneRoom(set, sc.Tone); room != "" {
+		pairs = append(pairs, room, RoomPlaceholder)
+	}
+	for _, id := range sc.PropIDs {
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			pairs = append(pairs, p.Name, PropPlaceholder(id))
+		}
+	}
+	replacer := NewLongestFirstReplacer(pairs)
+
+	return hmm.Scene{
+		Character:   sc.Character,
+		Pinyin:      sc.Pinyin,
+		Tone:        sc.Tone,
+		Keyword:     sc.Keyword,
+		PropIDs:     sc.PropIDs,
+		Script:      replacer.Replace(sc.Script),
+		ImagePrompt: replacer.Replace(sc.ImagePrompt),
+		Links:       sc.Links,
+	}
+}
+
+// Resolve fills a pack scene's placeholders with the user's own actor,
+// set, room, and prop names. The scene's actor and set IDs must already
+// be derived from its reading.
+func Resolve(sc hmm.Scene, gen *prompt.Generator) hmm.Scene {
+	set := gen.GetSet(sc.SetID)
+
+	actorName := fmt.Sprintf("Actor [%s]", sc.ActorID)
+	if actor := gen.GetActor(sc.ActorID); actor != nil && actor.Name != "" {
+		actorName = actor.Name
+	}
+	setName := fmt.Sprintf("Set [%s]", sc.SetID)
+	if set != nil && set.Name != "" {
+		setName = set.Name
+	}
+
+	pairs := []string{
+		ActorPlaceholder, actorName,
+		SetPlaceholder, setName,
+		RoomPlaceholder, gen.GetToneRoom(set, sc.Tone),
+	}
+	for _, id := range sc.PropIDs {
+		name := id
+		if p := gen.GetProp(id); p != nil && p.Name != "" {
+			name = p.Name
+		}
+		pairs = append(pairs, PropPlaceholder(id), name)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	sc.Script = replacer.Replace(sc.Script)
+	sc.ImagePrompt = replacer.Replace(sc.ImagePrompt)
+	return sc
+}
+
+// NewLongestFirstReplacer builds a replacer that prefers longer names, so
+// a prop called "Red dress" wins over an actor called "Red".
+func NewLongestFirstReplacer(pairs []string) *strings.Replacer {
+	type pair struct{ old, new string }
+	var list []pair
+	for i := 0; i+1 < len(pairs); i += 2 {
+		list = append(list, pair{pairs[i], pairs[i+1]})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i].old) > len(list[j].old)
+	})
+
+	var sorted []string
+	for _, p := range list {
+		sorted = append(sorted, p.old, p.new)
+	}
+	return strings.NewReplacer(sorted...)
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,79 @@
+package scene
+
+import (
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Search returns the scenes whose story, prompt, keyword, or look-alike
+// notes contain every word of query, ignoring case.
+func (s *Store) Search(query string) []hmm.Scene {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []hmm.Scene
+	for _, sc := range s.All() {
+		text := strings.ToLower(searchText(sc))
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, sc)
+		}
+	}
+
+	return results
+}
+
+// Snippet returns a short excerpt of sc around the first word of query,
+// for showing why a scene matched.
+func Snippet(sc hmm.Scene, query string, width int) string {
+	terms := strings.Fields(strings.ToLower(query))
+	text := []rune(strings.Join(strings.Fields(searchText(sc)), " "))
+	if len(terms) == 0 || len(text) == 0 {
+		return ""
+	}
+
+	lower := strings.ToLower(string(text))
+	pos := 0
+	if i := strings.Index(lower, terms[0]); i >= 0 {
+		pos = len([]rune(lower[:i]))
+	}
+
+	start := pos - width/2
+	if start > len(text) {
+		start = len(text)
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(text) {
+		end = len(text)
+	}
+
+	snippet := string(text[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// searchText joins the searchable fields of a scene.
+func searchText(sc hmm.Scene) string {
+	parts := []string{sc.Keyword, sc.Script, sc.ImagePrompt}
+	for _, l := range sc.Links {
+		parts = append(parts, l.Note)
+	}
+	return strings.Join(parts, "\n")
+}
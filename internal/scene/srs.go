@@ -0,0 +1,118 @@
+package scene
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Grade is how well a scene was remembered during review.
+type Grade int
+
+const (
+	GradeAgain Grade = iota + 1 // Forgotten; show again this session
+	GradeHard                   // Remembered with effort
+	GradeGood                   // Remembered
+	GradeEasy                   // Remembered instantly
+)
+
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+)
+
+// IsDue reports whether an approved scene should be reviewed at now.
+// Scenes that were never reviewed are always due.
+func IsDue(sc hmm.Scene, now time.Time) bool {
+	if !sc.IsApproved() {
+		return false
+	}
+	return sc.Review == nil || !sc.Review.Due.After(now)
+}
+
+// Due returns the approved scenes due at now: scheduled reviews first,
+// oldest due date first, then scenes never reviewed.
+func (s *Store) Due(now time.Time) []hmm.Scene {
+	var due, fresh []hmm.Scene
+	for _, sc := range s.All() {
+		if !IsDue(sc, now) {
+			continue
+		}
+		if sc.Review == nil {
+			fresh = append(fresh, sc)
+		} else {
+			due = append(due, sc)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Review.Due.Before(due[j].Review.Due)
+	})
+	return append(due, fresh...)
+}
+
+// Grade records a review of the scene stored under key and schedules
+// its next review.
+func (s *Store) Grade(key string, grade Grade, now time.Time) error {
+	sc := s.Get(key)
+	if sc == nil {
+		return fmt.Errorf("no scene for %s", key)
+	}
+
+	next := Schedule(sc.Review, grade, now)
+	sc.Review = &next
+	return nil
+}
+
+// Schedule computes the review state after grading, using SM-2 style
+// intervals (1 day, 6 days, then interval × ease). A nil state is a scene
+// seen for the first time.
+func Schedule(state *hmm.ReviewState, grade Grade, now time.Time) hmm.ReviewState {
+	r := hmm.ReviewState{Ease: defaultEase}
+	if state != nil {
+		r = *state
+	}
+
+	switch grade {
+	case GradeAgain:
+		r.Ease -= 0.2
+		r.Reps = 0
+		r.Lapses++
+		r.Interval = 0
+	case GradeHard:
+		r.Ease -= 0.15
+		r.Interval = int(math.Max(1, math.Round(float64(r.Interval)*1.2)))
+		r.Reps++
+	case GradeGood, GradeEasy:
+		switch r.Reps {
+		case 0:
+			r.Interval = 1
+		case 1:
+			r.Interval = 6
+		default:
+			r.Interval = int(math.Round(float64(r.Interval) * r.Ease))
+		}
+		if grade == GradeEasy {
+			r.Ease += 0.15
+			r.Interval = int(math.Max(float64(r.Interval+1), math.Round(float64(r.Interval)*1.3)))
+		}
+		r.Reps++
+	}
+
+	if r.Ease < minEase {
+		r.Ease = minEase
+	}
+
+	// Again stays due now; everything else is due at the start of a later day
+	if r.Interval == 0 {
+		r.Due = now
+	} else {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		r.Due = day.AddDate(0, 0, r.Interval)
+	}
+
+	return r
+}
@@ -0,0 +1,126 @@
+package scene
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// ListsDirName is the directory inside the config directory holding
+// target lists (e.g. hsk1.txt), one file per list.
+const ListsDirName = "lists"
+
+// TargetList is a set of characters the user wants scenes for.
+type TargetList struct {
+	Name    string
+	Chars   []string
+	Text    string // The list as written, for the words on it
+	Builtin bool   // Shipped with hmm rather than read from the lists directory
+}
+
+// newTargetList makes the list called name out of its text.
+func newTargetList(name, text string, builtin bool) TargetList {
+	return TargetList{Name: name, Chars: analyze.UniqueChineseChars(text), Text: text, Builtin: builtin}
+}
+
+// Coverage reports how far a target list is covered by scenes.
+type Coverage struct {
+	Total     int
+	Complete  []string // Characters with an approved scene
+	DraftOnly []string // Characters with only unreviewed drafts
+	Missing   []string // Characters with no usable scene
+}
+
+// Percent returns the share of complete characters, 0-100.
+func (c Coverage) Percent() int {
+	if c.Total == 0 {
+		return 0
+	}
+	return len(c.Complete) * 100 / c.Total
+}
+
+// ListsDir returns the target list directory next to the store file.
+func (s *Store) ListsDir() string {
+	return filepath.Join(filepath.Dir(s.path), ListsDirName)
+}
+
+// Coverage sorts the characters of a target list by scene status.
+func (s *Store) Coverage(chars []string) Coverage {
+	c := Coverage{Total: len(chars)}
+
+	for _, char := range chars {
+		if s.Approved(char) != nil {
+			c.Complete = append(c.Complete, char)
+			continue
+		}
+
+		hasDraft := false
+		for _, sc := range s.Variants(char) {
+			if sc.Status == hmm.SceneDraft {
+				hasDraft = true
+				break
+			}
+		}
+		if hasDraft {
+			c.DraftOnly = append(c.DraftOnly, char)
+		} else {
+			c.Missing = append(c.Missing, char)
+		}
+	}
+
+	return c
+}
+
+// LoadTargetLists reads every .txt file in dir as a target list, sorted
+// by name. A missing directory yields no lists.
+func LoadTargetLists(dir string) ([]TargetList, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var lists []TargetList
+	for _, path := range paths {
+		list, err := ReadTargetList(path)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+// ReadTargetList reads the Chinese characters of a file, in order and
+// without duplicates. Any layout works: one word per line, a CSV export,
+// or running text.
+func ReadTargetList(path string) (TargetList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TargetList{}, fmt.Errorf("reading target list: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return newTargetList(name, string(data), false), nil
+}
+
+// HanWords returns the unique runs of exactly n Chinese characters in s,
+// in order: the words of that length in a word list.
+func HanWords(s string, n int) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, run := range analyze.ChineseRuns(s) {
+		if utf8.RuneCountInString(run) == n && !seen[run] {
+			seen[run] = true
+			words = append(words, run)
+		}
+	}
+	return words
+}
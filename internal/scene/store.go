@@ -0,0 +1,201 @@
+// Package scene stores and exports the user's HMM mnemonic scenes.
+package scene
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the scene store file inside the config directory.
+const FileName = "scenes.yaml"
+
+// Store holds all saved scenes, keyed by Key.
+type Store struct {
+	path   string
+	scenes map[string]*hmm.Scene
+}
+
+// DefaultPath returns the scene store path for a config directory.
+func DefaultPath(configDir string) string {
+	return filepath.Join(configDir, FileName)
+}
+
+// Load reads the scene store from a YAML file.
+// A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path:   path,
+		scenes: make(map[string]*hmm.Scene),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading scenes file: %w", err)
+	}
+
+	var file struct {
+		Scenes []hmm.Scene `yaml:"scenes"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing scenes file: %w", err)
+	}
+
+	for i := range file.Scenes {
+		sc := file.Scenes[i]
+		if sc.Character == "" {
+			continue
+		}
+		s.scenes[Key(sc)] = &sc
+	}
+
+	return s, nil
+}
+
+// Save writes the store back to its file.
+func (s *Store) Save() error {
+	data := struct {
+		Scenes []hmm.Scene `yaml:"scenes"`
+	}{Scenes: s.All()}
+
+	out, err := yaml.Marshal(&data)
+	if err != nil {
+		return fmt.Errorf("marshaling scenes: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating scenes dir: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, out, 0644); err != nil {
+		return fmt.Errorf("writing scenes file: %w", err)
+	}
+
+	return nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// ImagePath returns the path of sc's image, resolving a relative one
+// against the directory of the store file, or "" if sc has no image.
+func (s *Store) ImagePath(sc hmm.Scene) string {
+	if sc.Image == "" || filepath.IsAbs(sc.Image) {
+		return sc.Image
+	}
+	return filepath.Join(filepath.Dir(s.path), sc.Image)
+}
+
+// Key returns the store key for a scene: its ID if set, otherwise its character.
+func Key(sc hmm.Scene) string {
+	if sc.ID != "" {
+		return sc.ID
+	}
+	return sc.Character
+}
+
+// checkKey returns an error if key cannot be used as a file name as it
+// is, such as "a/b", "../x" or ".hidden", for exports name their files
+// after the keys of the scenes.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, ".") || strings.Contains(key, "..") ||
+		strings.ContainsAny(key, `/\`) || filepath.Base(key) != key {
+		return fmt.Errorf("invalid scene id %q: not a plain file name", key)
+	}
+	return nil
+}
+
+// Get returns the scene stored under key, or nil if none is stored.
+// For the primary scene of a character the key is the character itself.
+func (s *Store) Get(key string) *hmm.Scene {
+	return s.scenes[key]
+}
+
+// Put adds or replaces the scene under its key.
+func (s *Store) Put(sc hmm.Scene) {
+	s.scenes[Key(sc)] = &sc
+}
+
+// Delete removes the scene stored under key.
+func (s *Store) Delete(key string) {
+	delete(s.scenes, key)
+}
+
+// Variants returns every scene stored for a character, primary first.
+func (s *Store) Variants(char string) []hmm.Scene {
+	var result []hmm.Scene
+	for _, sc := range s.All() {
+		if sc.Character == char {
+			result = append(result, sc)
+		}
+	}
+	return result
+}
+
+// Approved returns the first approved scene for a character, or nil.
+// Drafts and rejected scenes are never returned.
+func (s *Store) Approved(char string) *hmm.Scene {
+	for _, sc := range s.Variants(char) {
+		if sc.IsApproved() {
+			return &sc
+		}
+	}
+	return nil
+}
+
+// Drafts returns every scene still awaiting review, sorted by key.
+func (s *Store) Drafts() []hmm.Scene {
+	var result []hmm.Scene
+	for _, sc := range s.All() {
+		if sc.Status == hmm.SceneDraft {
+			result = append(result, sc)
+		}
+	}
+	return result
+}
+
+// PutDraft stores sc as a draft unless an approved scene already exists
+// under its key. It reports whether the draft was stored.
+func (s *Store) PutDraft(sc hmm.Scene) bool {
+	if existing := s.Get(Key(sc)); existing != nil && existing.IsApproved() {
+		return false
+	}
+	sc.Status = hmm.SceneDraft
+	s.Put(sc)
+	return true
+}
+
+// Len returns the number of stored scenes.
+func (s *Store) Len() int {
+	return len(s.scenes)
+}
+
+// All returns every stored scene, sorted by key.
+func (s *Store) All() []hmm.Scene {
+	keys := s.keys()
+	result := make([]hmm.Scene, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *s.scenes[k])
+	}
+	return result
+}
+
+// keys returns every store key, sorted.
+func (s *Store) keys() []string {
+	keys := make([]string, 0, len(s.scenes))
+	for k := range s.scenes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
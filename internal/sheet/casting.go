@@ -0,0 +1,140 @@
+package sheet
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Casting is the reference poster of a configuration: who plays each
+// initial, where each final takes place, and what each component becomes.
+type Casting struct {
+	Title  string
+	Actors []hmm.Actor
+	Sets   []hmm.Set
+	Props  []hmm.Prop
+}
+
+// ActorGroup is the actors of one category.
+type ActorGroup struct {
+	Category string
+	Label    string
+	Actors   []hmm.Actor
+}
+
+// actorCategories are the actor categories in the order the poster shows
+// them, with the initials each one stands for.
+var actorCategories = []struct {
+	category hmm.ActorCategory
+	label    string
+}{
+	{hmm.ActorMale, "Men (consonant initials)"},
+	{hmm.ActorFemale, "Women (-i initials)"},
+	{hmm.ActorFictional, "Fictional (-u initials)"},
+	{hmm.ActorGodLeader, "Gods and leaders (-ü initials)"},
+	{hmm.ActorNull, "No initial"},
+}
+
+// GroupActors groups actors by category, in the order the categories are
+// introduced by the method. Actors of unknown categories come last.
+func GroupActors(actors []hmm.Actor) []ActorGroup {
+	var groups []ActorGroup
+	known := make(map[hmm.ActorCategory]bool)
+	for _, c := range actorCategories {
+		known[c.category] = true
+		g := ActorGroup{Category: string(c.category), Label: c.label}
+		for _, a := range actors {
+			if a.Category == c.category {
+				g.Actors = append(g.Actors, a)
+			}
+		}
+		if len(g.Actors) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	other := ActorGroup{Label: "Other"}
+	for _, a := range actors {
+		if !known[a.Category] {
+			other.Actors = append(other.Actors, a)
+		}
+	}
+	if len(other.Actors) > 0 {
+		groups = append(groups, other)
+	}
+	return groups
+}
+
+// WriteCastingHTML writes c as a self-contained HTML poster laid out for
+// printing on landscape pages.
+func WriteCastingHTML(w io.Writer, c Casting) error {
+	return castingTemplate.Execute(w, struct {
+		Casting
+		ActorGroups []ActorGroup
+	}{c, GroupActors(c.Actors)})
+}
+
+var castingTemplate = template.Must(template.New("casting").Funcs(template.FuncMap{
+	"toneColor": func(t hmm.Tone) string { return ToneColor(int(t)) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  @page { size: A4 landscape; margin: 1cm; }
+  body { font-family: system-ui, sans-serif; margin: 1.5em; color: #222; font-size: 11px; }
+  h1 { margin: 0 0 .4em; }
+  h2 { border-bottom: 2px solid #ccc; padding-bottom: .1em; margin: 1.2em 0 .5em; }
+  h3 { margin: .8em 0 .3em; font-size: 1.1em; color: #555; }
+  .grid { display: grid; gap: .4em; }
+  .actors { grid-template-columns: repeat(auto-fill, minmax(9em, 1fr)); }
+  .sets { grid-template-columns: repeat(auto-fill, minmax(16em, 1fr)); }
+  .props { grid-template-columns: repeat(auto-fill, minmax(7em, 1fr)); }
+  .card { border: 1px solid #ddd; border-radius: 4px; padding: .3em .5em; break-inside: avoid; page-break-inside: avoid; }
+  .key { font-weight: bold; font-size: 1.3em; }
+  .name { color: #444; }
+  .component { font-size: 1.8em; font-family: "Noto Serif CJK SC", "Songti SC", serif; }
+  ul { margin: .2em 0 0; padding: 0; list-style: none; }
+  li { margin: 0; }
+  .tone { font-weight: bold; }
+  section { break-before: page; page-break-before: always; }
+  section:first-of-type { break-before: auto; page-break-before: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .ActorGroups}}
+<section>
+<h2>Actors</h2>
+{{range .ActorGroups}}
+<h3>{{.Label}}</h3>
+<div class="grid actors">
+{{range .Actors}}  <div class="card"><div class="key">{{if eq .ID "null"}}∅{{else}}{{.ID}}{{end}}</div><div class="name">{{.Name}}</div></div>
+{{end}}</div>
+{{end}}
+</section>
+{{end}}
+{{if .Sets}}
+<section>
+<h2>Sets</h2>
+<div class="grid sets">
+{{range .Sets}}  <div class="card">
+    <div class="key">{{if eq .ID "null"}}∅{{else}}-{{.ID}}{{end}} <span class="name">{{.Name}}</span></div>
+    <ul>{{range .Rooms}}<li><span class="tone" style="color: {{toneColor .Tone}}">{{.Tone}}</span> {{.Name}}</li>{{end}}</ul>
+  </div>
+{{end}}</div>
+</section>
+{{end}}
+{{if .Props}}
+<section>
+<h2>Props</h2>
+<div class="grid props">
+{{range .Props}}  <div class="card"><div class="component">{{.Component}}</div><div class="name">{{.Name}}</div></div>
+{{end}}</div>
+</section>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,166 @@
+package sheet
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+)
+
+// Location is the map of one set: each of its tone rooms with the
+// characters whose scenes play there.
+type Location struct {
+	Group
+	Description string
+	Rooms       []Room
+}
+
+// Room is a tone room of a location and the characters placed in it.
+type Room struct {
+	Tone        int
+	Name        string
+	Description string
+	Entries     []Entry
+}
+
+// Locations lays entries out on the maps of their sets, with rooms for
+// tones 1 to tones. Room names come from the configured sets; a set
+// without them gets numbered rooms. With all, every configured set gets
+// a map, even one no entry is placed in.
+func Locations(sets []hmm.Set, entries []Entry, tones int, all bool) []Location {
+	configured := make(map[string]hmm.Set)
+	for _, s := range sets {
+		configured[s.ID] = s
+	}
+
+	groups := GroupBySet(entries)
+	if all {
+		placed := make(map[string]bool)
+		for _, g := range groups {
+			placed[g.SetID] = true
+		}
+		for _, s := range sets {
+			if !placed[s.ID] {
+				groups = append(groups, Group{SetID: s.ID, Set: s.Name})
+			}
+		}
+		sort.SliceStable(groups, func(i, j int) bool { return groups[i].SetID < groups[j].SetID })
+	}
+
+	var locations []Location
+	for _, g := range groups {
+		set := configured[g.SetID]
+		if g.Set == "" {
+			g.Set = set.Name
+		}
+		loc := Location{Group: g, Description: set.Description}
+
+		for tone := 1; tone <= tones; tone++ {
+			room := Room{Tone: tone, Name: fmt.Sprintf("Room %d", tone)}
+			for _, r := range set.Rooms {
+				if int(r.Tone) == tone {
+					if r.Name != "" {
+						room.Name = r.Name
+					}
+					room.Description = r.Description
+				}
+			}
+			for _, e := range g.Entries {
+				if e.Tone == tone {
+					room.Entries = append(room.Entries, e)
+				}
+			}
+			loc.Rooms = append(loc.Rooms, room)
+		}
+		locations = append(locations, loc)
+	}
+	return locations
+}
+
+// WriteMapsMarkdown writes the maps of locations as Markdown, a table of
+// rooms per set.
+func WriteMapsMarkdown(w io.Writer, title string, locations []Location) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for _, loc := range locations {
+		fmt.Fprintf(&b, "## %s\n\n", loc.title())
+		if loc.Description != "" {
+			fmt.Fprintf(&b, "> %s\n\n", loc.Description)
+		}
+
+		b.WriteString("| Tone | Room | Characters |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, room := range loc.Rooms {
+			name := room.Name
+			if room.Description != "" {
+				name += ": " + room.Description
+			}
+			var chars []string
+			for _, e := range room.Entries {
+				chars = append(chars, fmt.Sprintf("%s <span style=\"color:%s\">%s</span>", e.Character, ToneColor(e.Tone), e.Pinyin))
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s |\n", room.Tone, cell(name), cell(strings.Join(chars, ", ")))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+// WriteMapsHTML writes the maps of locations as a self-contained HTML
+// page, one set to a printed page.
+func WriteMapsHTML(w io.Writer, title string, locations []Location) error {
+	return mapsTemplate.Execute(w, struct {
+		Title     string
+		Locations []Location
+	}{title, locations})
+}
+
+var mapsTemplate = template.Must(template.New("maps").Funcs(template.FuncMap{
+	"toneColor": ToneColor,
+	"title":     func(l Location) string { return l.title() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+  h2 { border-bottom: 2px solid #ccc; padding-bottom: .2em; margin-top: 1.5em; }
+  .description { color: #555; font-style: italic; }
+  .rooms { display: grid; grid-template-columns: repeat(auto-fill, minmax(14em, 1fr)); gap: .6em; }
+  .room { border: 2px solid #ddd; border-radius: 6px; padding: .5em .7em; min-height: 8em; break-inside: avoid; page-break-inside: avoid; }
+  .room h3 { margin: 0; font-size: 1em; }
+  .room .tone { font-weight: bold; }
+  .room .about { color: #666; font-size: .85em; }
+  .chars { margin-top: .4em; display: flex; flex-wrap: wrap; gap: .3em .8em; }
+  .char { text-align: center; }
+  .char .hanzi { font-size: 2.2em; line-height: 1.1; font-family: "Noto Serif CJK SC", "Songti SC", serif; }
+  .char .pinyin { font-size: .85em; font-weight: bold; }
+  section { break-before: page; page-break-before: always; }
+  section:first-of-type { break-before: auto; page-break-before: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Locations}}
+<section>
+<h2>{{title .}}</h2>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+<div class="rooms">
+{{range .Rooms}}  <div class="room" style="border-color: {{toneColor .Tone}}">
+    <h3><span class="tone" style="color: {{toneColor .Tone}}">{{.Tone}}</span> {{.Name}}</h3>
+    {{if .Description}}<div class="about">{{.Description}}</div>{{end}}
+    <div class="chars">{{range .Entries}}<div class="char"><div class="hanzi">{{.Character}}</div><div class="pinyin" style="color: {{toneColor .Tone}}">{{.Pinyin}}</div></div>{{end}}</div>
+  </div>
+{{end}}</div>
+</section>
+{{end}}
+</body>
+</html>
+`))
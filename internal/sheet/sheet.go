@@ -0,0 +1,192 @@
+// Package sheet renders printable study sheets: one entry per character
+// with its pinyin, HMM breakdown, story, and image, grouped by set so the
+// sheet can be walked through like a memory palace. It also renders the
+// casting of a configuration as a reference poster, and maps of the sets
+// with the characters placed in each room.
+package sheet
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Entry is a character on a study sheet.
+type Entry struct {
+	Character string
+	Pinyin    string
+	Tone      int // 1-5, 0 if unknown
+	Meaning   string
+	Actor     string
+	Set       string
+	SetID     string
+	Room      string
+	Props     []string
+	Story     string // Story of the approved scene, "" if none
+	Image     string // Path or URL of the scene image, "" if none
+}
+
+// Group is the entries of one set.
+type Group struct {
+	SetID   string
+	Set     string
+	Entries []Entry
+}
+
+// toneColors are the pinyin colors of tones 1 to 5, as Pleco shows them.
+var toneColors = map[int]string{
+	1: "#e30000",
+	2: "#02b31c",
+	3: "#1510f0",
+	4: "#8900bf",
+	5: "#777777",
+}
+
+// ToneColor returns the color pinyin of tone is printed in.
+func ToneColor(tone int) string {
+	if c, ok := toneColors[tone]; ok {
+		return c
+	}
+	return toneColors[5]
+}
+
+// GroupBySet groups entries by set, sorted by set ID. Within a set the
+// entries are ordered by tone, so the rooms are visited in order, and
+// otherwise keep the order they were given in.
+func GroupBySet(entries []Entry) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, e := range entries {
+		i, ok := index[e.SetID]
+		if !ok {
+			i = len(groups)
+			index[e.SetID] = i
+			groups = append(groups, Group{SetID: e.SetID, Set: e.Set})
+		}
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].SetID < groups[j].SetID })
+	for _, g := range groups {
+		sort.SliceStable(g.Entries, func(i, j int) bool { return g.Entries[i].Tone < g.Entries[j].Tone })
+	}
+	return groups
+}
+
+// title returns the heading of a group: the set's name and its final.
+func (g Group) title() string {
+	if g.SetID == "" || g.SetID == "null" {
+		if g.Set != "" {
+			return g.Set
+		}
+		return "No final"
+	}
+	if g.Set == "" {
+		return "-" + g.SetID
+	}
+	return fmt.Sprintf("%s (-%s)", g.Set, g.SetID)
+}
+
+// WriteMarkdown writes a study sheet of entries as Markdown. Pinyin colors
+// and image sizes use inline HTML, which most Markdown viewers show.
+func WriteMarkdown(w io.Writer, title string, entries []Entry) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for _, g := range GroupBySet(entries) {
+		fmt.Fprintf(&b, "## %s\n\n", g.title())
+
+		for _, e := range g.Entries {
+			fmt.Fprintf(&b, "### %s <span style=\"color:%s\">%s</span>\n\n", e.Character, ToneColor(e.Tone), e.Pinyin)
+			if e.Meaning != "" {
+				fmt.Fprintf(&b, "> %s\n\n", e.Meaning)
+			}
+
+			b.WriteString("| Actor | Set | Room | Props |\n")
+			b.WriteString("|---|---|---|---|\n")
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n\n",
+				cell(e.Actor), cell(e.Set), cell(e.Room), cell(strings.Join(e.Props, ", ")))
+
+			if e.Story != "" {
+				b.WriteString(e.Story)
+				b.WriteString("\n\n")
+			}
+			if e.Image != "" {
+				fmt.Fprintf(&b, "<img src=\"%s\" alt=\"%s\" width=\"160\">\n\n", template.HTMLEscapeString(e.Image), e.Character)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+// cell makes s safe to put into a Markdown table cell.
+func cell(s string) string {
+	if s == "" {
+		return "–"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// WriteHTML writes a study sheet of entries as a self-contained HTML page
+// laid out for printing.
+func WriteHTML(w io.Writer, title string, entries []Entry) error {
+	return htmlTemplate.Execute(w, struct {
+		Title  string
+		Groups []Group
+	}{title, GroupBySet(entries)})
+}
+
+var htmlTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"toneColor": ToneColor,
+	"title":     Group.title,
+	"join":      strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+  h2 { border-bottom: 2px solid #ccc; padding-bottom: .2em; margin-top: 1.5em; }
+  .entry { display: flex; gap: 1.5em; align-items: flex-start; padding: 1em 0;
+           border-bottom: 1px solid #eee; break-inside: avoid; page-break-inside: avoid; }
+  .char { font-size: 96px; line-height: 1; font-family: "Noto Serif CJK SC", "Songti SC", serif; }
+  .pinyin { font-size: 1.4em; font-weight: bold; }
+  .meaning { color: #555; margin: .2em 0 .6em; }
+  table { border-collapse: collapse; font-size: .9em; }
+  th, td { border: 1px solid #ddd; padding: .2em .6em; text-align: left; }
+  th { background: #f5f5f5; }
+  .story { margin-top: .6em; max-width: 40em; }
+  .body { flex: 1; }
+  img { width: 160px; border-radius: 4px; }
+  @media print { body { margin: 0; } h2 { break-after: avoid; page-break-after: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Groups}}
+<h2>{{title .}}</h2>
+{{range .Entries}}
+<div class="entry">
+  <div class="char">{{.Character}}</div>
+  <div class="body">
+    <div class="pinyin" style="color: {{toneColor .Tone}}">{{.Pinyin}}</div>
+    {{if .Meaning}}<div class="meaning">{{.Meaning}}</div>{{end}}
+    <table>
+      <tr><th>Actor</th><th>Set</th><th>Room</th><th>Props</th></tr>
+      <tr><td>{{.Actor}}</td><td>{{.Set}}</td><td>{{.Room}}</td><td>{{join .Props ", "}}</td></tr>
+    </table>
+    {{if .Story}}<div class="story">{{.Story}}</div>{{end}}
+  </div>
+  {{if .Image}}<img src="{{.Image}}" alt="{{.Character}}">{{end}}
+</div>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,342 @@
+// Package site writes scenes as a self-contained static website: an index
+// by set, actor, and HSK level and one page per scene, readable offline
+// and on a phone.
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/sheet"
+)
+
+// Page is a scene as the site shows it.
+type Page struct {
+	Character string
+	Pinyin    string
+	Tone      int
+	Keyword   string
+	Actor     string
+	ActorID   string
+	Set       string
+	SetID     string
+	Room      string
+	Props     []string
+	Story     string
+	Prompt    string
+	Image     string   // Path of the scene image on disk, "" if none
+	HSK       int      // HSK level, 0 if not on any list
+	LookAlike []string // Characters easily confused with this one
+
+	imageFile string // Name of the copied image in the site's image folder
+}
+
+// Group is a heading of the index with the pages under it.
+type Group struct {
+	Name  string
+	Pages []*Page
+}
+
+// index is what the index page shows.
+type index struct {
+	Title   string
+	Count   int
+	BySet   []Group
+	ByActor []Group
+	ByHSK   []Group
+}
+
+// Write writes the site for pages into dir: index.html, one page per
+// scene in scenes/, and copies of the images in images/. It returns the
+// number of images copied.
+func Write(dir, title string, pages []Page) (int, error) {
+	for _, sub := range []string{"scenes", "images"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return 0, fmt.Errorf("creating %s: %w", sub, err)
+		}
+	}
+
+	ptrs := make([]*Page, len(pages))
+	for i := range pages {
+		ptrs[i] = &pages[i]
+	}
+	sort.SliceStable(ptrs, func(i, j int) bool {
+		a, b := ptrs[i], ptrs[j]
+		if a.SetID != b.SetID {
+			return a.SetID < b.SetID
+		}
+		return a.Tone < b.Tone
+	})
+
+	images := 0
+	for _, p := range ptrs {
+		if p.Image == "" {
+			continue
+		}
+		name := PageName(p.Character) + strings.ToLower(filepath.Ext(p.Image))
+		if err := copyFile(p.Image, filepath.Join(dir, "images", name)); err != nil {
+			return images, fmt.Errorf("copying image of %s: %w", p.Character, err)
+		}
+		p.imageFile = name
+		images++
+	}
+
+	idx := index{
+		Title:   title,
+		Count:   len(ptrs),
+		BySet:   groupBy(ptrs, setTitle),
+		ByActor: groupBy(ptrs, actorTitle),
+		ByHSK:   groupBy(ptrs, hskTitle),
+	}
+	sort.SliceStable(idx.ByActor, func(i, j int) bool {
+		return idx.ByActor[i].Pages[0].ActorID < idx.ByActor[j].Pages[0].ActorID
+	})
+	sort.SliceStable(idx.ByHSK, func(i, j int) bool {
+		return hskOrder(idx.ByHSK[i].Pages[0]) < hskOrder(idx.ByHSK[j].Pages[0])
+	})
+
+	if err := writeTemplate(filepath.Join(dir, "index.html"), "index", idx); err != nil {
+		return images, err
+	}
+
+	known := make(map[string]bool, len(ptrs))
+	for _, p := range ptrs {
+		known[p.Character] = true
+	}
+	for i, p := range ptrs {
+		data := struct {
+			Title     string
+			Page      *Page
+			Previous  *Page
+			Next      *Page
+			Known     map[string]bool
+			ImageFile string
+		}{Title: title, Page: p, Known: known, ImageFile: p.imageFile}
+		if i > 0 {
+			data.Previous = ptrs[i-1]
+		}
+		if i+1 < len(ptrs) {
+			data.Next = ptrs[i+1]
+		}
+		path := filepath.Join(dir, "scenes", PageName(p.Character)+".html")
+		if err := writeTemplate(path, "scene", data); err != nil {
+			return images, err
+		}
+	}
+
+	return images, nil
+}
+
+// PageName returns the file name, without extension, of a character's
+// page: its code points in hex, which every file system and phone
+// browser handles.
+func PageName(char string) string {
+	var parts []string
+	for _, r := range char {
+		parts = append(parts, fmt.Sprintf("%x", r))
+	}
+	return strings.Join(parts, "-")
+}
+
+// groupBy groups pages by key, keeping the order of first appearance.
+func groupBy(pages []*Page, key func(*Page) string) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, p := range pages {
+		k := key(p)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Name: k})
+		}
+		groups[i].Pages = append(groups[i].Pages, p)
+	}
+	return groups
+}
+
+func setTitle(p *Page) string {
+	switch {
+	case p.Set == "" && p.SetID == "":
+		return "No set"
+	case p.Set == "":
+		return "-" + p.SetID
+	case p.SetID == "" || p.SetID == "null":
+		return p.Set
+	}
+	return fmt.Sprintf("%s (-%s)", p.Set, p.SetID)
+}
+
+func actorTitle(p *Page) string {
+	switch {
+	case p.Actor == "" && p.ActorID == "":
+		return "No actor"
+	case p.Actor == "":
+		return p.ActorID
+	case p.ActorID == "" || p.ActorID == "null":
+		return p.Actor
+	}
+	return fmt.Sprintf("%s (%s-)", p.Actor, p.ActorID)
+}
+
+func hskTitle(p *Page) string {
+	if p.HSK == 0 {
+		return "Not in HSK"
+	}
+	return fmt.Sprintf("HSK %d", p.HSK)
+}
+
+// hskOrder sorts pages off the HSK lists last.
+func hskOrder(p *Page) int {
+	if p.HSK == 0 {
+		return 1 << 30
+	}
+	return p.HSK
+}
+
+func writeTemplate(path, name string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := templates.ExecuteTemplate(f, name, data); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+var templates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"toneColor": sheet.ToneColor,
+	"pageName":  PageName,
+	"join":      strings.Join,
+}).Parse(`
+{{define "style"}}<style>
+  body { font-family: system-ui, sans-serif; margin: 0 auto; padding: 1em; max-width: 48em; color: #222; background: #fff; }
+  a { color: inherit; text-decoration: none; }
+  h1 { font-size: 1.4em; }
+  h2 { border-bottom: 2px solid #ddd; padding-bottom: .2em; margin-top: 1.5em; font-size: 1.15em; }
+  nav { display: flex; gap: 1em; flex-wrap: wrap; margin: .5em 0 1em; }
+  nav a { border: 1px solid #ccc; border-radius: 1em; padding: .2em .8em; }
+  .grid { display: flex; flex-wrap: wrap; gap: .4em; }
+  .tile { display: flex; flex-direction: column; align-items: center; min-width: 3.2em; padding: .3em; border: 1px solid #eee; border-radius: 6px; }
+  .tile .char { font-size: 2em; }
+  .tile .py { font-size: .8em; }
+  .hanzi { font-size: 7em; line-height: 1; text-align: center; margin: .2em 0; font-family: "Noto Serif CJK SC", "Songti SC", serif; }
+  .pinyin { font-size: 1.8em; font-weight: bold; text-align: center; }
+  .keyword { text-align: center; color: #555; margin-bottom: 1em; }
+  img { display: block; max-width: 100%; margin: 1em auto; border-radius: 6px; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { border-bottom: 1px solid #eee; padding: .4em; text-align: left; vertical-align: top; }
+  th { width: 6em; color: #666; font-weight: normal; }
+  pre { white-space: pre-wrap; background: #f6f6f6; padding: .8em; border-radius: 6px; font-size: .9em; }
+  .story { white-space: pre-line; }
+  .pager { display: flex; justify-content: space-between; margin: 1.5em 0; }
+  @media (prefers-color-scheme: dark) {
+    body { background: #161616; color: #ddd; }
+    .tile, th, td { border-color: #333; }
+    pre { background: #222; }
+  }
+</style>{{end}}
+
+{{define "tiles"}}<div class="grid">
+{{range .}}  <a class="tile" href="scenes/{{pageName .Character}}.html"><span class="char">{{.Character}}</span><span class="py" style="color: {{toneColor .Tone}}">{{.Pinyin}}</span></a>
+{{end}}</div>{{end}}
+
+{{define "index"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{template "style"}}
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Count}} scenes</p>
+<nav><a href="#sets">By set</a><a href="#actors">By actor</a><a href="#hsk">By HSK level</a></nav>
+
+<section id="sets">
+<h2>By set</h2>
+{{range .BySet}}<h3>{{.Name}}</h3>
+{{template "tiles" .Pages}}
+{{end}}
+</section>
+
+<section id="actors">
+<h2>By actor</h2>
+{{range .ByActor}}<h3>{{.Name}}</h3>
+{{template "tiles" .Pages}}
+{{end}}
+</section>
+
+<section id="hsk">
+<h2>By HSK level</h2>
+{{range .ByHSK}}<h3>{{.Name}}</h3>
+{{template "tiles" .Pages}}
+{{end}}
+</section>
+</body>
+</html>
+{{end}}
+
+{{define "scene"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Page.Character}} · {{.Title}}</title>
+{{template "style"}}
+</head>
+<body>
+{{with .Page}}
+<nav><a href="../index.html">↑ {{$.Title}}</a></nav>
+<div class="hanzi">{{.Character}}</div>
+<div class="pinyin" style="color: {{toneColor .Tone}}">{{.Pinyin}}</div>
+{{if .Keyword}}<div class="keyword">{{.Keyword}}</div>{{end}}
+{{if $.ImageFile}}<img src="../images/{{$.ImageFile}}" alt="{{.Character}}">{{end}}
+{{if .Story}}<h2>Story</h2>
+<p class="story">{{.Story}}</p>{{end}}
+<h2>Breakdown</h2>
+<table>
+  <tr><th>Actor</th><td>{{.Actor}}</td></tr>
+  <tr><th>Set</th><td>{{.Set}}</td></tr>
+  <tr><th>Room</th><td>{{.Room}}</td></tr>
+  <tr><th>Props</th><td>{{join .Props ", "}}</td></tr>
+  {{if .HSK}}<tr><th>HSK</th><td>{{.HSK}}</td></tr>{{end}}
+</table>
+{{if .LookAlike}}<h2>Look-alikes</h2>
+<div class="grid">{{range .LookAlike}}{{if index $.Known .}}<a class="tile" href="{{pageName .}}.html"><span class="char">{{.}}</span></a>{{else}}<span class="tile"><span class="char">{{.}}</span></span>{{end}}{{end}}</div>{{end}}
+{{if .Prompt}}<h2>Prompt</h2>
+<pre>{{.Prompt}}</pre>{{end}}
+{{end}}
+<div class="pager">
+  <span>{{with .Previous}}<a href="{{pageName .Character}}.html">← {{.Character}}</a>{{end}}</span>
+  <span>{{with .Next}}<a href="{{pageName .Character}}.html">{{.Character}} →</a>{{end}}</span>
+</div>
+</body>
+</html>
+{{end}}
+`))
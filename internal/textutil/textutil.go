@@ -0,0 +1,24 @@
+// Package textutil shortens text for display in a terminal, where Chinese
+// characters take two columns and slicing a string by bytes can cut one in
+// half.
+package textutil
+
+import "github.com/mattn/go-runewidth"
+
+// ellipsis ends text that was cut short.
+const ellipsis = "…"
+
+// Truncate returns s cut to at most width terminal columns, ending in an
+// ellipsis if anything was left out. It never splits a character.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, ellipsis)
+}
+
+// PadRight returns s followed by spaces to fill width terminal columns,
+// as %-*s would if every character took one column.
+func PadRight(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
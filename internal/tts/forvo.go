@@ -0,0 +1,142 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// forvoAPIURL is the base of the Forvo API, to which the key and the
+// query are added as path segments.
+const forvoAPIURL = "https://apifree.forvo.com"
+
+// forvoCountries are the countries whose speakers are preferred for a
+// plain locale, as Forvo's ISO 3166-1 alpha-3 codes.
+var forvoCountries = map[string]string{
+	"zh-CN": "CHN",
+	"zh-TW": "TWN",
+	"zh-HK": "HKG",
+}
+
+// forvoEngine downloads recordings by native speakers from Forvo instead of
+// synthesizing them. It only has the words someone has recorded.
+type forvoEngine struct{}
+
+// forvoResponse is the part of a word-pronunciations reply that is used.
+type forvoResponse struct {
+	Items []struct {
+		PathMP3  string `json:"pathmp3"`
+		Username string `json:"username"`
+		Country  string `json:"country"`
+	} `json:"items"`
+}
+
+func (forvoEngine) Name() string    { return "forvo" }
+func (forvoEngine) Available() bool { return os.Getenv("FORVO_API_KEY") != "" }
+
+// Synthesize downloads the best rated recording of text. A locale voice
+// prefers speakers from its country, falling back to any speaker.
+func (forvoEngine) Synthesize(text, voice, out string) error {
+	key := strings.TrimSpace(os.Getenv("FORVO_API_KEY"))
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var mp3 string
+	countries := []string{""}
+	if c, ok := forvoCountries[voice]; ok {
+		countries = []string{c, ""}
+	}
+	for _, country := range countries {
+		items, err := forvoLookup(client, key, text, country)
+		if err != nil {
+			return err
+		}
+		if len(items.Items) > 0 {
+			mp3 = items.Items[0].PathMP3
+			break
+		}
+	}
+	if mp3 == "" {
+		return fmt.Errorf("no Forvo recording of %s", text)
+	}
+
+	resp, err := client.Get(mp3)
+	if err != nil {
+		return fmt.Errorf("downloading recording: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading recording: status %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// forvoLookup asks Forvo for the Mandarin recordings of word, best rated
+// first, by speakers from country if it is not "".
+func forvoLookup(client *http.Client, key, word, country string) (*forvoResponse, error) {
+	segments := []string{
+		"key", key,
+		"format", "json",
+		"action", "word-pronunciations",
+		"word", word,
+		"language", "zh",
+	}
+	if country != "" {
+		segments = append(segments, "country", country)
+	}
+	segments = append(segments, "order", "rate-desc", "limit", "1")
+
+	var path strings.Builder
+	path.WriteString(forvoAPIURL)
+	for _, s := range segments {
+		path.WriteString("/")
+		path.WriteString(url.PathEscape(s))
+	}
+
+	resp, err := client.Get(path.String())
+	if err != nil {
+		// The URL holds the key, so leave it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// Forvo answers errors such as a wrong key with a JSON array of messages
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		var msgs []string
+		if err := json.Unmarshal(body, &msgs); err == nil && len(msgs) > 0 {
+			return nil, fmt.Errorf("API error: %s", strings.Join(msgs, "; "))
+		}
+	}
+
+	var result forvoResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unmarshaling response: %w", err)
+	}
+	return &result, nil
+}
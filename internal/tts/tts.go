@@ -0,0 +1,253 @@
+// Package tts turns Chinese text into spoken MP3s with a text-to-speech
+// engine, or fetches native speakers' recordings from Forvo, and keeps
+// them where the audio package finds them.
+package tts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultVoice is the voice used when none is given: Mainland Mandarin.
+const DefaultVoice = "zh-CN"
+
+// Engine speaks text with a voice into an MP3 file.
+type Engine interface {
+	// Name is how the engine is chosen with --engine.
+	Name() string
+	// Available reports whether the engine can be used: its tool is
+	// installed or its API key is set.
+	Available() bool
+	// Synthesize writes text spoken with voice to out as MP3. The voice is
+	// a locale such as zh-CN or a name the engine knows.
+	Synthesize(text, voice, out string) error
+}
+
+// engines are the known engines, in the order one is picked by default.
+var engines = map[string]Engine{}
+
+// order is the preference among engines when none is asked for.
+// Forvo comes last, as it only has the words someone has recorded.
+var order = []string{"edge", "openai", "gtts", "forvo"}
+
+// Register makes engine available under its name, replacing any engine of
+// the same name.
+func Register(engine Engine) {
+	engines[engine.Name()] = engine
+}
+
+func init() {
+	Register(edgeEngine{})
+	Register(gttsEngine{})
+	Register(openAIEngine{})
+	Register(forvoEngine{})
+}
+
+// Names returns the names of the registered engines, sorted.
+func Names() []string {
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the engine called name, or with name "" the first available
+// one.
+func Get(name string) (Engine, error) {
+	if name != "" {
+		engine, ok := engines[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown TTS engine %q (use %s)", name, strings.Join(Names(), ", "))
+		}
+		if !engine.Available() {
+			return nil, fmt.Errorf("TTS engine %s is not available: %s", name, hint(name))
+		}
+		return engine, nil
+	}
+
+	for _, n := range order {
+		if engine, ok := engines[n]; ok && engine.Available() {
+			return engine, nil
+		}
+	}
+	return nil, fmt.Errorf("no TTS engine available: install edge-tts (pip install edge-tts), set OPENAI_API_KEY, or install gTTS (pip install gTTS), or set FORVO_API_KEY")
+}
+
+// hint says how to make the built-in engine name available.
+func hint(name string) string {
+	switch name {
+	case "edge":
+		return "install edge-tts (pip install edge-tts)"
+	case "gtts":
+		return "install gTTS (pip install gTTS)"
+	case "openai":
+		return "set OPENAI_API_KEY"
+	case "forvo":
+		return "set FORVO_API_KEY"
+	}
+	return "check its installation"
+}
+
+// FileName returns the name of the recording of text, as the audio
+// package looks it up (好.mp3).
+func FileName(text string) string {
+	return strings.NewReplacer("/", "_", `\`, "_", ":", "_").Replace(text) + ".mp3"
+}
+
+// Speak writes the recording of text into dir, unless it is there already
+// and force is false. It returns the recording's path and whether it was
+// already there.
+func Speak(engine Engine, text, voice, dir string, force bool) (string, bool, error) {
+	path := filepath.Join(dir, FileName(text))
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return path, true, nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("creating audio cache: %w", err)
+	}
+
+	// Write next to the recording and rename, so a failed run never
+	// leaves a truncated file that looks cached
+	tmp, err := os.CreateTemp(dir, ".tts-*.mp3")
+	if err != nil {
+		return "", false, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := engine.Synthesize(text, voice, tmp.Name()); err != nil {
+		return "", false, fmt.Errorf("%s: %w", engine.Name(), err)
+	}
+	if info, err := os.Stat(tmp.Name()); err != nil || info.Size() == 0 {
+		return "", false, fmt.Errorf("%s produced no audio for %s", engine.Name(), text)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", false, err
+	}
+	return path, false, nil
+}
+
+// run runs a TTS tool, returning its error output on failure.
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func installed(tool string) bool {
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+// edgeEngine uses Microsoft Edge's online voices through edge-tts.
+type edgeEngine struct{}
+
+// edgeVoices are the voices used for a plain locale.
+var edgeVoices = map[string]string{
+	"zh-CN": "zh-CN-XiaoxiaoNeural",
+	"zh-TW": "zh-TW-HsiaoChenNeural",
+	"zh-HK": "zh-HK-HiuMaanNeural",
+}
+
+func (edgeEngine) Name() string    { return "edge" }
+func (edgeEngine) Available() bool { return installed("edge-tts") }
+
+func (edgeEngine) Synthesize(text, voice, out string) error {
+	if v, ok := edgeVoices[voice]; ok {
+		voice = v
+	}
+	return run("edge-tts", "--voice", voice, "--text", text, "--write-media", out)
+}
+
+// gttsEngine uses Google Translate's voice through gTTS.
+type gttsEngine struct{}
+
+func (gttsEngine) Name() string    { return "gtts" }
+func (gttsEngine) Available() bool { return installed("gtts-cli") }
+
+func (gttsEngine) Synthesize(text, voice, out string) error {
+	// gTTS only knows languages, so zh-CN-XiaoxiaoNeural becomes zh-CN
+	if parts := strings.Split(voice, "-"); len(parts) > 2 {
+		voice = parts[0] + "-" + parts[1]
+	}
+	return run("gtts-cli", "--lang", voice, "--output", out, text)
+}
+
+// openAIEngine uses the OpenAI speech API.
+type openAIEngine struct{}
+
+const (
+	openAISpeechURL = "https://api.openai.com/v1/audio/speech"
+	openAIModel     = "tts-1"
+	openAIVoice     = "alloy"
+)
+
+func (openAIEngine) Name() string    { return "openai" }
+func (openAIEngine) Available() bool { return os.Getenv("OPENAI_API_KEY") != "" }
+
+func (openAIEngine) Synthesize(text, voice, out string) error {
+	// OpenAI voices speak any language; a locale picks the default voice
+	if voice == "" || strings.Contains(voice, "-") {
+		voice = openAIVoice
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"model":           openAIModel,
+		"input":           text,
+		"voice":           voice,
+		"response_format": "mp3",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", openAISpeechURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(os.Getenv("OPENAI_API_KEY")))
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
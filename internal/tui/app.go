@@ -1,15 +1,24 @@
 package tui
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
 	"github.com/f3rmion/hmm/internal/llm"
 	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tui/bigchar"
+	"github.com/f3rmion/hmm/internal/tui/components"
 	"github.com/f3rmion/hmm/internal/tui/views"
 )
 
@@ -22,6 +31,10 @@ const (
 	ViewLearn
 	ViewFilePicker
 	ViewSettings
+	ViewReview
+	ViewStats
+	ViewCompare
+	ViewWalk
 )
 
 // MenuItem represents a sidebar menu entry
@@ -32,6 +45,35 @@ type MenuItem struct {
 	Shortcut string
 }
 
+// SidebarMode is how much of the sidebar is shown.
+type SidebarMode int
+
+const (
+	SidebarFull   SidebarMode = iota // Shortcuts and labels
+	SidebarIcons                     // Icons only
+	SidebarHidden                    // Not shown unless focused
+)
+
+var sidebarModeNames = []string{"full", "icons", "hidden"}
+
+// iconSidebarWidth is the width of the sidebar in SidebarIcons mode.
+const iconSidebarWidth = 6
+
+// String returns the name the mode is saved under.
+func (s SidebarMode) String() string {
+	return sidebarModeNames[s]
+}
+
+// parseSidebarMode returns the mode called name, or SidebarFull.
+func parseSidebarMode(name string) SidebarMode {
+	for i, n := range sidebarModeNames {
+		if n == name {
+			return SidebarMode(i)
+		}
+	}
+	return SidebarFull
+}
+
 // ViewSwitchMsg requests a view change
 type ViewSwitchMsg struct {
 	View ViewType
@@ -49,6 +91,13 @@ type PackageLoadedMsg struct {
 	Err     error
 }
 
+// PackageProgressMsg is sent as each step of loading an Anki package starts
+type PackageProgressMsg struct {
+	Step        string
+	Done, Total int // Steps finished before this one, of all the steps
+	updates     <-chan tea.Msg
+}
+
 // AppModel is the main unified TUI model
 type AppModel struct {
 	// Core dependencies
@@ -57,13 +106,30 @@ type AppModel struct {
 	llmClient *llm.Client
 	parser    *pinyin.Parser
 	generator *prompt.Generator
+	scenes    *scene.Store
 
 	// Layout state
 	width        int
 	height       int
 	sidebarWidth int
+	sidebarMode  SidebarMode
+	compact      bool // Narrow terminal: menu on top instead of a sidebar
 	ready        bool
 
+	// Preferences remembered across sessions, saved here if set
+	uiStatePath string
+
+	// Where the last session left off, offered for resuming until the
+	// first key is pressed, and the session being resumed while its deck
+	// loads
+	session     *config.Session
+	resumeOffer *config.Session
+	resuming    *config.Session
+
+	// Notification shown in the status bar, replaced by newer ones
+	toast   views.ToastMsg
+	toastID int
+
 	// Navigation
 	currentView   ViewType
 	menuItems     []MenuItem
@@ -76,17 +142,46 @@ type AppModel struct {
 	learnView      views.LearnModel
 	filePickerView views.FilePickerModel
 	settingsView   views.SettingsModel
+	reviewView     views.ReviewModel
+	statsView      views.StatsModel
+	compareView    views.CompareModel
+	walkView       views.WalkModel
 
-	// Loaded Anki package
+	// Loaded Anki package, the one shown of the decks open in the
+	// workspace
 	ankiPackage *anki.Package
 	ankiPath    string
+	decks       []workspaceDeck
+	deckIndex   int
+
+	// Package loading in progress, and the error of the last failed load
+	loading      bool
+	loadingPath  string
+	loadingStep  string
+	loadingDone  int
+	loadingTotal int
+	loadErr      error
+	spinner      spinner.Model
 
 	// Help overlay
 	showHelp bool
+
+	// Onboarding tour: whether it is running, on which card, and from
+	// which view, and whether it has been taken or skipped before
+	touring  bool
+	tourStep int
+	tourFrom ViewType
+	tourSeen bool
+
+	// Characters shown this session, if it is logged
+	sessionLog *sessionLog
+
+	// Whether copying to the clipboard can work, shown in the status bar
+	clipboardOK bool
 }
 
 // NewApp creates a new unified TUI application
-func NewApp(dict *decomp.Dictionary, cfg *config.Config) AppModel {
+func NewApp(dict *decomp.Dictionary, cfg *config.Config, scenes *scene.Store) AppModel {
 	var gen *prompt.Generator
 	if cfg != nil {
 		gen = prompt.NewGenerator(cfg.Actors, cfg.Sets, cfg.Props)
@@ -95,6 +190,11 @@ func NewApp(dict *decomp.Dictionary, cfg *config.Config) AppModel {
 	}
 
 	llmClient, _ := llm.NewClient()
+	clipboardOK := clipboard.Available()
+
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = LoadingStyle
 
 	menuItems := []MenuItem{
 		{Label: "Lookup", Icon: "字", View: ViewLookup, Shortcut: "1"},
@@ -102,6 +202,10 @@ func NewApp(dict *decomp.Dictionary, cfg *config.Config) AppModel {
 		{Label: "Learn", Icon: "學", View: ViewLearn, Shortcut: "3"},
 		{Label: "Open Deck", Icon: "開", View: ViewFilePicker, Shortcut: "4"},
 		{Label: "Settings", Icon: "設", View: ViewSettings, Shortcut: "5"},
+		{Label: "Review", Icon: "審", View: ViewReview, Shortcut: "6"},
+		{Label: "Stats", Icon: "統", View: ViewStats, Shortcut: "7"},
+		{Label: "Compare", Icon: "比", View: ViewCompare, Shortcut: "8"},
+		{Label: "Walk", Icon: "宮", View: ViewWalk, Shortcut: "9"},
 	}
 
 	app := AppModel{
@@ -110,28 +214,109 @@ func NewApp(dict *decomp.Dictionary, cfg *config.Config) AppModel {
 		llmClient:    llmClient,
 		parser:       pinyin.NewParser(),
 		generator:    gen,
+		scenes:       scenes,
 		sidebarWidth: 18,
 		currentView:  ViewLookup,
 		menuItems:    menuItems,
 		sidebarActive: false,
+		spinner:       sp,
+		clipboardOK:   clipboardOK,
 
-		lookupView:     views.NewLookupModel(dict, cfg, gen, llmClient),
-		browseView:     views.NewBrowseModel(dict, cfg, gen, llmClient),
-		learnView:      views.NewLearnModel(dict, cfg, gen, llmClient),
+		lookupView:     views.NewLookupModel(dict, cfg, gen, llmClient, scenes),
+		browseView:     views.NewBrowseModel(dict, cfg, gen, llmClient, scenes),
+		learnView:      views.NewLearnModel(dict, cfg, gen, llmClient, scenes),
 		filePickerView: views.NewFilePickerModel(),
 		settingsView:   views.NewSettingsModel(cfg),
+		reviewView:     views.NewReviewModel(scenes, gen, llmClient),
+		statsView:      views.NewStatsModel(scenes),
+		compareView:    views.NewCompareModel(dict, gen, scenes),
+		walkView:       views.NewWalkModel(dict, gen, scenes),
 	}
 
 	return app
 }
 
+// LoadUIState restores preferences saved at path by an earlier session
+// and remembers them there from now on.
+func (m *AppModel) LoadUIState(path string) error {
+	m.uiStatePath = path
+	state, err := config.LoadUIState(path)
+	if err != nil {
+		return err
+	}
+	m.sidebarMode = parseSidebarMode(state.Sidebar)
+	m.lookupView.SetHistory(state.LookupHistory)
+	bigchar.SetMode(bigchar.ParseMode(state.BigChar))
+	m.lookupView.SetRomanizer(pinyin.GetRomanizer(state.AlsoRomanize))
+	m.tourSeen = state.TourSeen
+	m.offerResume(state.Session)
+	if !m.tourSeen && state.Session == nil {
+		// First launch
+		m.startTour()
+	}
+	return nil
+}
+
+// saveUIState remembers the current preferences. Failing to save only
+// loses them for the next session, so errors are ignored.
+func (m AppModel) saveUIState() {
+	if m.uiStatePath == "" {
+		return
+	}
+	config.SaveUIState(m.uiStatePath, config.UIState{
+		Sidebar:       m.sidebarMode.String(),
+		LookupHistory: m.lookupView.History(),
+		BigChar:       bigchar.CurrentMode().String(),
+		AlsoRomanize:  m.lookupView.RomanizerName(),
+		Session:       m.session,
+		TourSeen:      m.tourSeen,
+	})
+}
+
+// showView switches to view v and selects it in the sidebar.
+func (m *AppModel) showView(v ViewType) {
+	m.currentView = v
+	if m.currentView == ViewReview {
+		m.reviewView.Refresh()
+	}
+	if m.currentView == ViewStats {
+		m.statsView.Refresh()
+	}
+	if m.currentView == ViewWalk {
+		m.walkView.Refresh()
+	}
+	for i, item := range m.menuItems {
+		if item.View == v {
+			m.selectedMenu = i
+			break
+		}
+	}
+}
+
+// textInputActive reports whether the current view is capturing typed text.
+func (m AppModel) textInputActive() bool {
+	if m.sidebarActive {
+		return false
+	}
+	switch m.currentView {
+	case ViewLookup:
+		return m.lookupView.Searching() || m.lookupView.Editing() || m.lookupView.CopyMenuOpen()
+	case ViewBrowse:
+		return m.browseView.Searching() || m.browseView.Editing() || m.browseView.CopyMenuOpen()
+	case ViewLearn:
+		return m.learnView.Editing() || m.learnView.CopyMenuOpen()
+	case ViewReview:
+		return m.reviewView.Editing()
+	case ViewCompare:
+		return m.compareView.Focused()
+	}
+	return false
+}
+
 // NewAppWithPackage creates a new app with a pre-loaded Anki package
-func NewAppWithPackage(dict *decomp.Dictionary, cfg *config.Config, pkg *anki.Package, path string) AppModel {
-	app := NewApp(dict, cfg)
-	app.ankiPackage = pkg
-	app.ankiPath = path
-	app.browseView.SetPackage(pkg)
-	app.learnView.SetPackage(pkg)
+func NewAppWithPackage(dict *decomp.Dictionary, cfg *config.Config, scenes *scene.Store, pkg *anki.Package, path string) AppModel {
+	app := NewApp(dict, cfg, scenes)
+	app.openDeck(pkg, path)
 	app.currentView = ViewBrowse
 	app.selectedMenu = 1 // Browse
 	return app
@@ -142,60 +327,96 @@ func (m AppModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-// Update handles messages
+// Update handles messages, recording the character shown after each if
+// the session is logged.
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if app, ok := model.(AppModel); ok {
+		app.logShown()
+	}
+	return model, cmd
+}
+
+func (m AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
-		// Help overlay - any key closes it
+		// Help overlay - t starts the tour, any other key closes it
 		if m.showHelp {
 			m.showHelp = false
+			if msg.String() == "t" {
+				m.startTour()
+			}
+			return m, nil
+		}
+
+		// Onboarding tour - takes every key but ctrl+c
+		if m.touring && msg.String() != "ctrl+c" {
+			m.answerTour(msg.String())
+			return m, nil
+		}
+
+		// Resume offer - enter resumes, any other key but ctrl+c declines
+		if m.resumeOffer != nil && msg.String() != "ctrl+c" {
+			cmd := m.answerResume(msg.String())
+			m.resizeViews()
+			return m, cmd
+		}
+
+		// Load error banner - any key but ctrl+c dismisses it
+		if m.loadErr != nil && msg.String() != "ctrl+c" {
+			m.loadErr = nil
+			m.resizeViews()
 			return m, nil
 		}
 
+		// Text entry in a view takes every key except ctrl+c
+		if msg.String() != "ctrl+c" && m.textInputActive() {
+			break
+		}
+
 		// Global keys
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return m, tea.Quit
+			return m, m.quit()
 		case "?":
 			m.showHelp = true
 			return m, nil
 		case "esc":
 			// Esc goes back to sidebar or quits
 			if m.sidebarActive {
-				return m, tea.Quit
+				return m, m.quit()
 			}
 			m.sidebarActive = true
 			return m, nil
-		case "1":
-			m.currentView = ViewLookup
-			m.selectedMenu = 0
-			m.sidebarActive = false
-			return m, nil
-		case "2":
-			m.currentView = ViewBrowse
-			m.selectedMenu = 1
-			m.sidebarActive = false
-			return m, nil
-		case "3":
-			m.currentView = ViewLearn
-			m.selectedMenu = 2
-			m.sidebarActive = false
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			for _, item := range m.menuItems {
+				if item.Shortcut == msg.String() {
+					m.sidebarActive = false
+					m.showView(item.View)
+					break
+				}
+			}
 			return m, nil
-		case "4":
-			m.currentView = ViewFilePicker
-			m.selectedMenu = 3
-			m.sidebarActive = false
+		case "}":
+			m.switchDeck(m.deckIndex + 1)
 			return m, nil
-		case "5":
-			m.currentView = ViewSettings
-			m.selectedMenu = 4
-			m.sidebarActive = false
+		case "{":
+			m.switchDeck(m.deckIndex - 1)
 			return m, nil
 		case "tab":
 			m.sidebarActive = !m.sidebarActive
 			return m, nil
+		case "[":
+			// Cycle full → icons → hidden sidebar
+			m.sidebarMode = (m.sidebarMode + 1) % SidebarMode(len(sidebarModeNames))
+			m.resizeViews()
+			m.saveUIState()
+			return m, nil
 		}
 
 		// Sidebar navigation when active
@@ -212,8 +433,8 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "enter", "l", "right":
-				m.currentView = m.menuItems[m.selectedMenu].View
 				m.sidebarActive = false
+				m.showView(m.menuItems[m.selectedMenu].View)
 				return m, nil
 			}
 		}
@@ -221,47 +442,89 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.compact = msg.Width < compactWidth
 		m.ready = true
-
-		// Update view sizes
-		contentWidth := m.width - m.sidebarWidth - 4
-		contentHeight := m.height - 2
-
-		m.lookupView.SetSize(contentWidth, contentHeight)
-		m.browseView.SetSize(contentWidth, contentHeight)
-		m.learnView.SetSize(contentWidth, contentHeight)
-		m.filePickerView.SetSize(contentWidth, contentHeight)
-		m.settingsView.SetSize(contentWidth, contentHeight)
-
+		m.resizeViews()
 		return m, nil
 
 	case ViewSwitchMsg:
-		m.currentView = msg.View
-		for i, item := range m.menuItems {
-			if item.View == msg.View {
-				m.selectedMenu = i
-				break
-			}
-		}
+		m.showView(msg.View)
 		return m, nil
 
 	case FileSelectedMsg:
 		// Load the Anki package
-		return m, m.loadAnkiPackage(msg.Path)
+		return m, m.startLoading(msg.Path)
 
 	case views.FileSelectedMsg:
 		// Load the Anki package (from file picker view)
-		return m, m.loadAnkiPackage(msg.Path)
+		return m, m.startLoading(msg.Path)
+
+	case views.LearnListMsg:
+		m.learnView.SetList(msg.List)
+		m.showView(ViewLearn)
+		return m, nil
+
+	case views.LookupMsg:
+		cmd := m.lookupView.Show(msg.Char)
+		m.showView(ViewLookup)
+		return m, cmd
+
+	case views.CompareMsg:
+		m.compareView.SetPair(msg.A, msg.B)
+		m.currentView = ViewCompare
+		m.selectedMenu = 7
+		return m, nil
+
+	case views.HistoryChangedMsg:
+		m.saveUIState()
+		return m, nil
+
+	case views.ToastMsg:
+		return m, m.showToast(msg)
+
+	case toastExpiredMsg:
+		if msg.id == m.toastID {
+			m.toast = views.ToastMsg{}
+		}
+		return m, nil
+
+	case views.BackgroundMsg:
+		// Results go to the view that asked for them, on screen or not
+		var lookupCmd, browseCmd, learnCmd, reviewCmd tea.Cmd
+		m.lookupView, lookupCmd = m.lookupView.Update(msg)
+		m.browseView, browseCmd = m.browseView.Update(msg)
+		m.learnView, learnCmd = m.learnView.Update(msg)
+		m.reviewView, reviewCmd = m.reviewView.Update(msg)
+		return m, tea.Batch(lookupCmd, browseCmd, learnCmd, reviewCmd)
+
+	case PackageProgressMsg:
+		m.loadingStep = msg.Step
+		m.loadingDone, m.loadingTotal = msg.Done, msg.Total
+		return m, waitForLoad(msg.updates)
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 
 	case PackageLoadedMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.loadErr = fmt.Errorf("could not open %s: %w", filepath.Base(msg.Path), msg.Err)
+		}
+		m.resizeViews()
 		if msg.Err == nil && msg.Package != nil {
-			m.ankiPackage = msg.Package
-			m.ankiPath = msg.Path
-			m.browseView.SetPackage(msg.Package)
-			m.learnView.SetPackage(msg.Package)
+			m.openDeck(msg.Package, msg.Path)
 			m.currentView = ViewBrowse
 			m.selectedMenu = 1
+			if m.resuming != nil {
+				m.restoreSession(m.resuming)
+			}
 		}
+		m.resuming = nil
 		return m, nil
 	}
 
@@ -279,6 +542,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filePickerView, cmd = m.filePickerView.Update(msg)
 		case ViewSettings:
 			m.settingsView, cmd = m.settingsView.Update(msg)
+		case ViewReview:
+			m.reviewView, cmd = m.reviewView.Update(msg)
+		case ViewStats:
+			m.statsView, cmd = m.statsView.Update(msg)
+		case ViewCompare:
+			m.compareView, cmd = m.compareView.Update(msg)
+		case ViewWalk:
+			m.walkView, cmd = m.walkView.Update(msg)
 		}
 		if cmd != nil {
 			cmds = append(cmds, cmd)
@@ -303,84 +574,232 @@ func (m AppModel) View() string {
 	sidebar := m.renderSidebar()
 
 	// Render main content based on current view
-	var content string
+	content := m.contentView()
+
+	// Apply content styling
+	mainContent := ContentStyle.
+		Width(m.contentWidth()).
+		Height(m.bodyHeight() - 2).
+		Render(content)
+
+	// Join horizontally, above the status bar. The compact layout has the
+	// menu on top instead.
+	body := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, mainContent)
+	if m.compact {
+		body = lipgloss.JoinVertical(lipgloss.Left, m.renderTopBar(), mainContent)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, body, m.renderStatusBar())
+}
+
+// resizeViews fits every view into the space next to the sidebar and
+// below the status line.
+func (m *AppModel) resizeViews() {
+	contentWidth := m.contentWidth()
+	contentHeight := m.bodyHeight() - 2 - strings.Count(m.renderStatus(), "\n")
+
+	m.lookupView.SetSize(contentWidth, contentHeight)
+	m.browseView.SetSize(contentWidth, contentHeight)
+	m.learnView.SetSize(contentWidth, contentHeight)
+	m.filePickerView.SetSize(contentWidth, contentHeight)
+	m.settingsView.SetSize(contentWidth, contentHeight)
+	m.reviewView.SetSize(contentWidth, contentHeight)
+	m.statsView.SetSize(contentWidth, contentHeight)
+	m.compareView.SetSize(contentWidth, contentHeight)
+	m.walkView.SetSize(contentWidth, contentHeight)
+}
+
+// bodyHeight returns the height of the sidebar and content area, which is
+// everything above the status bar.
+func (m AppModel) bodyHeight() int {
+	return m.height - statusBarHeight - m.headerHeight()
+}
+
+// contentWidth returns the width of the content area.
+func (m AppModel) contentWidth() int {
+	switch m.visibleSidebarMode() {
+	case SidebarIcons:
+		return m.width - iconSidebarWidth - 4
+	case SidebarHidden:
+		return m.width - 3
+	}
+	return m.width - m.sidebarWidth - 4
+}
+
+// visibleSidebarMode returns how the sidebar is drawn: a hidden sidebar
+// shows as icons while it has focus.
+func (m AppModel) visibleSidebarMode() SidebarMode {
+	if m.compact {
+		// The top bar takes the place of the sidebar
+		return SidebarHidden
+	}
+	if m.sidebarMode == SidebarHidden && m.sidebarActive {
+		return SidebarIcons
+	}
+	return m.sidebarMode
+}
+
+// contentView renders the status line and current view without the
+// sidebar.
+func (m AppModel) contentView() string {
+	var view string
 	switch m.currentView {
 	case ViewLookup:
-		content = m.lookupView.View()
+		view = m.lookupView.View()
 	case ViewBrowse:
-		content = m.browseView.View()
+		view = m.browseView.View()
 	case ViewLearn:
-		content = m.learnView.View()
+		view = m.learnView.View()
 	case ViewFilePicker:
-		content = m.filePickerView.View()
+		view = m.filePickerView.View()
 	case ViewSettings:
-		content = m.settingsView.View()
+		view = m.settingsView.View()
+	case ViewReview:
+		view = m.reviewView.View()
+	case ViewStats:
+		view = m.statsView.View()
+	case ViewCompare:
+		view = m.compareView.View()
+	case ViewWalk:
+		view = m.walkView.View()
 	}
+	return m.renderStatus() + view
+}
 
-	// Apply content styling
-	contentWidth := m.width - m.sidebarWidth - 4
-	mainContent := ContentStyle.
-		Width(contentWidth).
-		Height(m.height - 2).
-		Render(content)
-
-	// Join horizontally
-	return lipgloss.JoinHorizontal(lipgloss.Top, sidebar, mainContent)
+// renderStatus renders the package loading spinner, the load error
+// banner, the tour, or the resume offer, or "" if there is none.
+func (m AppModel) renderStatus() string {
+	switch {
+	case m.loading:
+		status := fmt.Sprintf("%s Opening %s", m.spinner.View(), filepath.Base(m.loadingPath))
+		if m.loadingStep != "" {
+			status += ": " + strings.ToLower(m.loadingStep)
+		}
+		status = LoadingStyle.Render(status + "…")
+		if m.loadingTotal > 0 {
+			status += "  " + components.NewProgress(20, current).View(m.loadingDone, m.loadingTotal)
+		}
+		return status + "\n\n"
+	case m.loadErr != nil:
+		banner := m.loadErr.Error() + "\n"
+		if suggestion := anki.Suggestion(m.loadErr); suggestion != "" {
+			banner += suggestion + "\n"
+		}
+		banner += HelpStyle.Render("press any key to dismiss")
+		return ErrorBannerStyle.Width(m.contentWidth() - 6).Render(banner) + "\n\n"
+	case m.touring:
+		return m.renderTour()
+	case m.resumeOffer != nil:
+		return m.renderResumeOffer()
+	}
+	return ""
 }
 
 // renderSidebar renders the sidebar navigation
 func (m AppModel) renderSidebar() string {
+	mode := m.visibleSidebarMode()
+	if mode == SidebarHidden {
+		return ""
+	}
+
 	var items []string
 
 	// Title
 	title := SidebarTitleStyle.Render("  漢字 HMM  ")
+	if mode == SidebarIcons {
+		title = SidebarTitleStyle.Render("漢")
+	}
 	items = append(items, title)
 	items = append(items, "")
 
 	// Menu items
 	for i, item := range m.menuItems {
-		label := item.Shortcut + ". " + item.Label
-
-		var style lipgloss.Style
-		if i == m.selectedMenu {
-			if m.sidebarActive {
-				style = SidebarItemActiveStyle
-			} else {
-				// Indicate current view but not focused
-				style = SidebarItemStyle.Bold(true).Foreground(ColorSecondary)
-			}
-		} else {
-			style = SidebarItemStyle
-		}
+		items = append(items, m.menuItemStyle(i).Render(m.sidebarLabel(item)))
+	}
 
-		items = append(items, style.Render(label))
+	// Open decks
+	if mode == SidebarFull {
+		items = append(items, m.renderDeckList()...)
 	}
 
 	// Spacer
 	usedHeight := len(items) + 4 // account for borders and help
-	if m.height > usedHeight {
-		for i := 0; i < m.height-usedHeight-2; i++ {
+	if m.bodyHeight() > usedHeight {
+		for i := 0; i < m.bodyHeight()-usedHeight-2; i++ {
 			items = append(items, "")
 		}
 	}
 
 	// Help text at bottom
 	help := SidebarHelpStyle.Render("? Help  q Quit")
+	width := m.sidebarWidth
+	if mode == SidebarIcons {
+		help = SidebarHelpStyle.Render("?")
+		width = iconSidebarWidth
+	}
 	items = append(items, help)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, items...)
 
 	return SidebarStyle.
-		Width(m.sidebarWidth).
-		Height(m.height - 2).
+		Width(width).
+		Height(m.bodyHeight() - 2).
 		Render(content)
 }
 
-// loadAnkiPackage loads an Anki package asynchronously
+// menuItemStyle returns the style of menu item i in the sidebar or top bar.
+func (m AppModel) menuItemStyle(i int) lipgloss.Style {
+	if i != m.selectedMenu {
+		return SidebarItemStyle
+	}
+	if m.sidebarActive {
+		return SidebarItemActiveStyle
+	}
+	// Indicate current view but not focused
+	return current.Marked(SidebarItemStyle.Bold(true).Foreground(ColorSecondary))
+}
+
+// sidebarLabel returns the sidebar entry for item.
+func (m AppModel) sidebarLabel(item MenuItem) string {
+	if m.visibleSidebarMode() == SidebarIcons {
+		return item.Icon
+	}
+	return item.Shortcut + ". " + item.Label
+}
+
+// startLoading shows the loading spinner and starts loading an Anki package.
+// A request while another package is loading is ignored.
+func (m *AppModel) startLoading(path string) tea.Cmd {
+	if m.loading {
+		return nil
+	}
+	m.loading = true
+	m.loadingPath = path
+	m.loadingStep = ""
+	m.loadingDone, m.loadingTotal = 0, 0
+	m.loadErr = nil
+	m.resizeViews()
+	return tea.Batch(m.loadAnkiPackage(path), m.spinner.Tick)
+}
+
+// loadAnkiPackage loads an Anki package asynchronously, sending a
+// PackageProgressMsg for each step and a PackageLoadedMsg at the end
 func (m AppModel) loadAnkiPackage(path string) tea.Cmd {
+	updates := make(chan tea.Msg)
+	return func() tea.Msg {
+		go func() {
+			pkg, err := anki.OpenPackageWithProgress(path, func(step string, done, total int) {
+				updates <- PackageProgressMsg{Step: step, Done: done, Total: total, updates: updates}
+			})
+			updates <- PackageLoadedMsg{Package: pkg, Path: path, Err: err}
+		}()
+		return <-updates
+	}
+}
+
+// waitForLoad waits for the next message from a package load.
+func waitForLoad(updates <-chan tea.Msg) tea.Cmd {
 	return func() tea.Msg {
-		pkg, err := anki.OpenPackage(path)
-		return PackageLoadedMsg{Package: pkg, Path: path, Err: err}
+		return <-updates
 	}
 }
 
@@ -388,46 +807,92 @@ func (m AppModel) loadAnkiPackage(path string) tea.Cmd {
 func (m AppModel) renderHelp() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FF6B6B")).
+		Foreground(ColorPrimary).
 		MarginBottom(1)
 
 	sectionStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ECDC4")).
+		Foreground(ColorSecondary).
 		MarginTop(1)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFE66D")).
+		Foreground(ColorAccent).
 		Width(12)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#F1FAEE"))
+		Foreground(ColorText)
 
 	helpText := titleStyle.Render("HMM - Hanzi Movie Method") + "\n\n"
 
 	helpText += sectionStyle.Render("Global Keys") + "\n"
-	helpText += keyStyle.Render("1-5") + descStyle.Render("Switch views") + "\n"
+	helpText += keyStyle.Render("1-9") + descStyle.Render("Switch views") + "\n"
 	helpText += keyStyle.Render("tab") + descStyle.Render("Toggle sidebar focus") + "\n"
+	helpText += keyStyle.Render("[") + descStyle.Render("Collapse / hide sidebar") + "\n"
+	helpText += keyStyle.Render("{ / }") + descStyle.Render("Previous / next open deck") + "\n"
 	helpText += keyStyle.Render("?") + descStyle.Render("Show this help") + "\n"
+	helpText += keyStyle.Render("t") + descStyle.Render("Take the tour (from this help)") + "\n"
 	helpText += keyStyle.Render("q") + descStyle.Render("Quit") + "\n"
+	helpText += keyStyle.Render("mouse") + descStyle.Render("Click items, tabs, and help entries; wheel scrolls") + "\n"
 
 	helpText += sectionStyle.Render("Lookup View") + "\n"
 	helpText += keyStyle.Render("enter") + descStyle.Render("Analyze character(s)") + "\n"
+	helpText += keyStyle.Render("ctrl+v") + descStyle.Render("Paste from clipboard") + "\n"
+	helpText += keyStyle.Render("alt+v") + descStyle.Render("Analyze clipboard contents") + "\n"
 	helpText += keyStyle.Render("g") + descStyle.Render("Generate LLM prompt") + "\n"
 	helpText += keyStyle.Render("y") + descStyle.Render("Copy prompt to clipboard") + "\n"
+	helpText += keyStyle.Render("Y") + descStyle.Render("Copy part of the card") + "\n"
+	helpText += keyStyle.Render("R") + descStyle.Render("Ask for a different take") + "\n"
+	helpText += keyStyle.Render(",/.") + descStyle.Render("Cycle through takes") + "\n"
+	helpText += keyStyle.Render("e") + descStyle.Render("Edit prompt (ctrl+s saves)") + "\n"
 	helpText += keyStyle.Render("←/→") + descStyle.Render("Navigate characters") + "\n"
+	helpText += keyStyle.Render("↑/↓") + descStyle.Render("Recall earlier inputs") + "\n"
+	helpText += keyStyle.Render("j/k PgUp/Dn") + descStyle.Render("Scroll details") + "\n"
+	helpText += keyStyle.Render("/") + descStyle.Render("Search scenes, or m <meaning>") + "\n"
+	helpText += keyStyle.Render("C") + descStyle.Render("Compare with the next character") + "\n"
+	helpText += keyStyle.Render("v") + descStyle.Render("Play pronunciation (🔊)") + "\n"
 
 	helpText += sectionStyle.Render("Browse View") + "\n"
-	helpText += keyStyle.Render("j/k ↑/↓") + descStyle.Render("Navigate cards") + "\n"
+	helpText += keyStyle.Render("↑/↓") + descStyle.Render("Navigate cards") + "\n"
+	helpText += keyStyle.Render(":") + descStyle.Render("Go to card number") + "\n"
+	helpText += keyStyle.Render("G") + descStyle.Render("Go to last card") + "\n"
+	helpText += keyStyle.Render("o") + descStyle.Render("Cycle sort order") + "\n"
+	helpText += keyStyle.Render("f") + descStyle.Render("Show note fields / augment preview") + "\n"
 	helpText += keyStyle.Render("←/→") + descStyle.Render("Navigate characters") + "\n"
-	helpText += keyStyle.Render("/") + descStyle.Render("Search") + "\n"
+	helpText += keyStyle.Render("j/k PgUp/Dn") + descStyle.Render("Scroll details") + "\n"
+	helpText += keyStyle.Render("/") + descStyle.Render("Search (tone: actor: set: missing: tag: deck:)") + "\n"
 	helpText += keyStyle.Render("g") + descStyle.Render("Generate prompt") + "\n"
+	helpText += keyStyle.Render("R") + descStyle.Render("Ask for a different take") + "\n"
+	helpText += keyStyle.Render("e") + descStyle.Render("Edit prompt") + "\n"
 	helpText += keyStyle.Render("B") + descStyle.Render("Batch generate all") + "\n"
+	helpText += keyStyle.Render("D") + descStyle.Render("Batch generate the whole deck") + "\n"
+	helpText += keyStyle.Render("v") + descStyle.Render("Play pronunciation (🔊)") + "\n"
 
 	helpText += sectionStyle.Render("Learn View") + "\n"
-	helpText += keyStyle.Render("space") + descStyle.Render("Flip card") + "\n"
+	helpText += keyStyle.Render("space") + descStyle.Render("Flip card / reveal more") + "\n"
+	helpText += keyStyle.Render("enter") + descStyle.Render("Reveal whole back") + "\n"
 	helpText += keyStyle.Render("←/→") + descStyle.Render("Prev/next card") + "\n"
 	helpText += keyStyle.Render("r") + descStyle.Render("Reset to first card") + "\n"
+	helpText += keyStyle.Render("v") + descStyle.Render("Play pronunciation (🔊)") + "\n"
+	helpText += keyStyle.Render("j/k PgUp/Dn") + descStyle.Render("Scroll card") + "\n"
+	helpText += keyStyle.Render("s") + descStyle.Render("Review due scenes") + "\n"
+
+	helpText += sectionStyle.Render("Review View") + "\n"
+	helpText += keyStyle.Render("a / x") + descStyle.Render("Approve / reject draft") + "\n"
+	helpText += keyStyle.Render("r") + descStyle.Render("Regenerate draft prompt") + "\n"
+	helpText += keyStyle.Render("e") + descStyle.Render("Edit draft prompt") + "\n"
+
+	helpText += sectionStyle.Render("Stats View") + "\n"
+	helpText += keyStyle.Render("←/→") + descStyle.Render("Switch target list") + "\n"
+	helpText += keyStyle.Render("r") + descStyle.Render("Reload lists") + "\n"
+
+	helpText += sectionStyle.Render("Compare View") + "\n"
+	helpText += keyStyle.Render("enter") + descStyle.Render("Compare the two characters typed") + "\n"
+
+	helpText += sectionStyle.Render("Walk View") + "\n"
+	helpText += keyStyle.Render("→/space") + descStyle.Render("Walk on to the next character") + "\n"
+	helpText += keyStyle.Render("←") + descStyle.Render("Step back") + "\n"
+	helpText += keyStyle.Render("n/p") + descStyle.Render("Next / previous set") + "\n"
+	helpText += keyStyle.Render("L") + descStyle.Render("Look up the character") + "\n"
 
 	helpText += sectionStyle.Render("File Picker") + "\n"
 	helpText += keyStyle.Render("enter") + descStyle.Render("Select file/enter dir") + "\n"
@@ -435,13 +900,13 @@ func (m AppModel) renderHelp() string {
 	helpText += keyStyle.Render("~") + descStyle.Render("Go to home dir") + "\n"
 
 	helpText += "\n" + lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666666")).
+		Foreground(ColorMuted).
 		Italic(true).
-		Render("Press any key to close")
+		Render("Press t to take the tour, any other key to close")
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#4ECDC4")).
+		Border(current.BoxBorder()).
+		BorderForeground(ColorSecondary).
 		Padding(1, 2).
 		Width(50)
 
@@ -1,12 +1,17 @@
-// Package bigchar renders Chinese characters as large block art using half-block characters.
+// Package bigchar renders Chinese characters as large block art using
+// half-block or braille characters.
 package bigchar
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
@@ -15,25 +20,99 @@ import (
 
 var loadedFace font.Face
 
+// faceMu guards loadedFace while a glyph is drawn: a font.Face is not
+// safe for concurrent use, and renders run in tea.Cmd goroutines.
+var faceMu sync.Mutex
+
+// fontName is the file name of the loaded font, which the disk cache is
+// kept under so another font does not reuse its glyphs.
+var fontName string
+
+// Mode is how big characters are drawn.
+type Mode int
+
+const (
+	HalfBlock Mode = iota // ▀▄█, one pixel per half cell
+	Braille               // 2×4 dots per cell, dithered for sharper edges
+)
+
+var modeNames = []string{"halfblock", "braille"}
+
+// String returns the name the mode is configured with.
+func (m Mode) String() string {
+	return modeNames[m]
+}
+
+// ParseMode returns the mode called name, or HalfBlock.
+func ParseMode(name string) Mode {
+	for i, n := range modeNames {
+		if n == name {
+			return Mode(i)
+		}
+	}
+	return HalfBlock
+}
+
+// mode is the renderer GetCached uses, guarded by cacheMu as renders
+// run in tea.Cmd goroutines.
+var mode = HalfBlock
+
+// SetMode selects how big characters are drawn from now on.
+func SetMode(m Mode) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	mode = m
+}
+
+// CurrentMode returns how big characters are drawn.
+func CurrentMode() Mode {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return mode
+}
+
+// fontPaths returns where CJK fonts are commonly installed on this
+// system, the best first.
+func fontPaths() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{
+			"/System/Library/Fonts/STHeiti Light.ttc",
+			"/System/Library/Fonts/PingFang.ttc",
+			"/System/Library/Fonts/Hiragino Sans GB.ttc",
+			"/Library/Fonts/Arial Unicode.ttf",
+		}
+	case "windows":
+		// System fonts, then those installed for the user only
+		windir := os.Getenv("WINDIR")
+		if windir == "" {
+			windir = `C:\Windows`
+		}
+		dirs := []string{filepath.Join(windir, "Fonts")}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+
+		var paths []string
+		for _, dir := range dirs {
+			for _, name := range []string{"msyh.ttc", "Deng.ttf", "simhei.ttf", "simsun.ttc", "NotoSansCJK-Regular.ttc"} {
+				paths = append(paths, filepath.Join(dir, name))
+			}
+		}
+		return paths
+	default:
+		return []string{
+			"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/truetype/noto/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		}
+	}
+}
+
 func init() {
 	// Try to load a CJK font from common system locations
-	fontPaths := []string{
-		// macOS
-		"/System/Library/Fonts/STHeiti Light.ttc",
-		"/System/Library/Fonts/PingFang.ttc",
-		"/System/Library/Fonts/Hiragino Sans GB.ttc",
-		"/Library/Fonts/Arial Unicode.ttf",
-		// Linux
-		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
-		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
-		"/usr/share/fonts/truetype/noto/NotoSansCJK-Regular.ttc",
-		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
-		// Windows
-		"C:\\Windows\\Fonts\\msyh.ttc",
-		"C:\\Windows\\Fonts\\simsun.ttc",
-	}
-
-	for _, path := range fontPaths {
+	for _, path := range fontPaths() {
 		data, err := os.ReadFile(path)
 		if err != nil {
 			continue
@@ -47,6 +126,7 @@ func init() {
 					DPI:  72,
 				}); err == nil {
 					loadedFace = face
+					fontName = filepath.Base(path)
 					return
 				}
 			}
@@ -59,6 +139,7 @@ func init() {
 				DPI:  72,
 			}); err == nil {
 				loadedFace = face
+				fontName = filepath.Base(path)
 				return
 			}
 		}
@@ -68,13 +149,57 @@ func init() {
 // RenderBlock renders a character using half-block characters (▀▄█)
 // cols and rows define the output size in terminal cells
 func RenderBlock(char string, cols, rows int) string {
-	if char == "" || loadedFace == nil {
+	srcImg := renderGlyph(char)
+	if srcImg == nil {
 		return ""
 	}
 
+	// Scale down to target size (rows*2 because half-blocks)
+	scaledImg := scaleDown(srcImg, cols, rows*2)
+
+	// Convert to half-block characters
+	return imageToHalfBlocks(scaledImg, cols, rows)
+}
+
+// RenderBraille renders a character using braille dots, 2×4 per cell, so
+// it has four times the pixels of RenderBlock in the same space. Gray
+// edges are dithered rather than cut off at a threshold, which keeps thin
+// strokes visible. cols and rows define the output size in terminal cells.
+func RenderBraille(char string, cols, rows int) string {
+	srcImg := renderGlyph(char)
+	if srcImg == nil {
+		return ""
+	}
+
+	scaledImg := scaleDown(srcImg, cols*2, rows*4)
+	return imageToBraille(dither(scaledImg), cols, rows)
+}
+
+// RenderImage renders a picture, such as a photo, in braille dots the way
+// RenderBraille draws characters: light parts dotted, dark parts blank.
+// cols and rows define the output size in terminal cells.
+func RenderImage(img image.Image, cols, rows int) string {
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+
+	scaledImg := scaleDown(gray, cols*2, rows*4)
+	return imageToBraille(dither(scaledImg), cols, rows)
+}
+
+// renderGlyph draws a character white on black at the font's natural
+// size, or returns nil if there is no font or character.
+func renderGlyph(char string) *image.Gray {
+	if char == "" || loadedFace == nil {
+		return nil
+	}
+
 	// Get the character rune
 	r := []rune(char)[0]
 
+	faceMu.Lock()
+	defer faceMu.Unlock()
+
 	// Get font metrics for sizing
 	bounds, _, _ := loadedFace.GlyphBounds(r)
 	glyphWidth := (bounds.Max.X - bounds.Min.X).Ceil()
@@ -110,14 +235,7 @@ func RenderBlock(char string, cols, rows int) string {
 	}
 	d.DrawString(char)
 
-	// Scale down to target size (rows*2 because half-blocks)
-	targetWidth := cols
-	targetHeight := rows * 2
-
-	scaledImg := scaleDown(srcImg, targetWidth, targetHeight)
-
-	// Convert to half-block characters
-	return imageToHalfBlocks(scaledImg, cols, rows)
+	return srcImg
 }
 
 // scaleDown scales a grayscale image using area averaging
@@ -202,6 +320,83 @@ func imageToHalfBlocks(img *image.Gray, cols, rows int) string {
 	return result.String()
 }
 
+// dither turns a grayscale image into black and white with
+// Floyd-Steinberg error diffusion, so partly covered pixels at the edge of
+// a stroke come out as a mix of dots instead of all on or all off.
+func dither(img *image.Gray) *image.Gray {
+	b := img.Bounds()
+	w, h := b.Max.X, b.Max.Y
+
+	levels := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			levels[y*w+x] = float64(img.GrayAt(x, y).Y)
+		}
+	}
+
+	spread := func(x, y int, e float64) {
+		if x >= 0 && x < w && y < h {
+			levels[y*w+x] += e
+		}
+	}
+
+	out := image.NewGray(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			old := levels[y*w+x]
+			v := 0.0
+			if old >= 128 {
+				v = 255
+				out.SetGray(x, y, color.Gray{Y: 255})
+			}
+			e := old - v
+			spread(x+1, y, e*7/16)
+			spread(x-1, y+1, e*3/16)
+			spread(x, y+1, e*5/16)
+			spread(x+1, y+1, e*1/16)
+		}
+	}
+	return out
+}
+
+// brailleDots are the bits of the braille dots in a cell, by row and
+// column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// imageToBraille converts a black and white image to braille art, 2×4
+// pixels per cell. Empty cells are spaces.
+func imageToBraille(img *image.Gray, cols, rows int) string {
+	var result strings.Builder
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			var bits rune
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if getPixelBrightness(img, col*2+dx, row*4+dy) > 0 {
+						bits |= brailleDots[dy][dx]
+					}
+				}
+			}
+			if bits == 0 {
+				result.WriteRune(' ')
+			} else {
+				result.WriteRune(0x2800 + bits)
+			}
+		}
+		if row < rows-1 {
+			result.WriteRune('\n')
+		}
+	}
+
+	return result.String()
+}
+
 func getPixelBrightness(img *image.Gray, x, y int) uint8 {
 	if x < 0 || y < 0 || x >= img.Bounds().Max.X || y >= img.Bounds().Max.Y {
 		return 0
@@ -214,21 +409,102 @@ func IsAvailable() bool {
 	return loadedFace != nil
 }
 
-// cache for rendered characters
-var cache = make(map[string]string)
+// FontName returns the file name of the CJK font in use, or "" if none
+// was found.
+func FontName() string {
+	return fontName
+}
 
-// GetCached returns cached big character or renders new one
-func GetCached(char string, cols, rows int) string {
+// Rendered characters are kept in memory, the most recently used
+// cacheSize of them, and, once SetCacheDir has been called, on disk.
+// Rendering takes a moment, so callers on the UI thread should check
+// Cached and call Render from a tea.Cmd.
+var (
+	cacheMu  sync.Mutex
+	cache    = newRenderCache(cacheSize)
+	cacheDir string
+)
+
+// SetCacheDir keeps rendered characters in dir, so later sessions do not
+// render them again.
+func SetCacheDir(dir string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cacheDir = dir
+}
+
+func cacheKey(char string, cols, rows int, m Mode) string {
+	return fmt.Sprintf("%s:%dx%d:%s", char, cols, rows, m)
+}
+
+// cachePath returns the file a rendered character is kept in, or "" if
+// there is no disk cache.
+func cachePath(char string, cols, rows int, m Mode) string {
+	if cacheDir == "" || char == "" {
+		return ""
+	}
+	return filepath.Join(cacheDir, fontName, m.String(),
+		fmt.Sprintf("%dx%d", cols, rows), fmt.Sprintf("%x.txt", []rune(char)[0]))
+}
+
+// Cached returns the character rendered in the current mode if it is in
+// memory or on disk, without rendering it.
+func Cached(char string, cols, rows int) (string, bool) {
+	if !IsAvailable() {
+		return "", false
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	key := cacheKey(char, cols, rows, mode)
+	if cached, ok := cache.get(key); ok {
+		return cached, true
+	}
+	if path := cachePath(char, cols, rows, mode); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			cache.put(key, string(data))
+			return string(data), true
+		}
+	}
+	return "", false
+}
+
+// Render renders the character in the current mode and caches it. It may
+// be called from several goroutines at once; they take turns drawing the
+// glyph, and scale and cache it in parallel.
+func Render(char string, cols, rows int) string {
 	if !IsAvailable() {
 		return ""
 	}
 
-	key := char + string(rune(cols)) + string(rune(rows))
-	if cached, ok := cache[key]; ok {
-		return cached
+	// The mode may change while rendering; cache under the one rendered in
+	m := CurrentMode()
+	var rendered string
+	if m == Braille {
+		rendered = RenderBraille(char, cols, rows)
+	} else {
+		rendered = RenderBlock(char, cols, rows)
 	}
 
-	rendered := RenderBlock(char, cols, rows)
-	cache[key] = rendered
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cache.put(cacheKey(char, cols, rows, m), rendered)
+	if path := cachePath(char, cols, rows, m); path != "" {
+		// A failed write only costs rendering the character again
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			os.WriteFile(path, []byte(rendered), 0644)
+		}
+	}
 	return rendered
 }
+
+// GetCached returns cached big character or renders new one in the
+// current mode
+func GetCached(char string, cols, rows int) string {
+	if cached, ok := Cached(char, cols, rows); ok {
+		return cached
+	}
+	return Render(char, cols, rows)
+}
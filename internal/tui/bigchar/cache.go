@@ -0,0 +1,54 @@
+package bigchar
+
+import "container/list"
+
+// cacheSize is how many rendered characters are kept in memory. The disk
+// cache, once set, keeps the rest.
+const cacheSize = 512
+
+// renderCache keeps the characters rendered last, dropping the least
+// recently used once it holds size of them. It is not safe for concurrent
+// use; cacheMu guards the one in use.
+type renderCache struct {
+	size    int
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+type renderCacheEntry struct {
+	key      string
+	rendered string
+}
+
+func newRenderCache(size int) *renderCache {
+	return &renderCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the rendering cached under key, if any.
+func (c *renderCache) get(key string) (string, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*renderCacheEntry).rendered, true
+}
+
+// put caches rendered under key.
+func (c *renderCache) put(key, rendered string) {
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*renderCacheEntry).rendered = rendered
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&renderCacheEntry{key: key, rendered: rendered})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderCacheEntry).key)
+	}
+}
@@ -2,28 +2,31 @@ package tui
 
 import (
 	"fmt"
-	"regexp"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
 	"github.com/f3rmion/hmm/internal/anki"
 	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
-	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
 	"github.com/f3rmion/hmm/internal/llm"
-	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 )
 
 // BrowserModel is the Bubble Tea model for browsing Anki decks.
 type BrowserModel struct {
 	pkg       *anki.Package
-	parser    *pinyin.Parser
-	dict      *decomp.Dictionary
+	analyzer  *analyze.Analyzer
 	generator *prompt.Generator
 	config    *config.Config
 
@@ -33,7 +36,7 @@ type BrowserModel struct {
 	currentNote   int
 
 	// Character navigation within a note
-	characters []CharacterResult
+	characters []analyze.CharacterResult
 	selected   int
 
 	// Search
@@ -52,6 +55,7 @@ type BrowserModel struct {
 	batchGenerating   bool
 	batchTotal        int
 	batchCompleted    int
+	batchFailures     map[int]error // Why characters failed in the last batch, by index
 
 	// Clipboard
 	copied bool
@@ -75,32 +79,43 @@ func clearCopiedBrowserAfter(d time.Duration) tea.Cmd {
 
 // batchResultMsg is sent when a batch prompt generation completes for one character
 type batchResultMsg struct {
+	note   int64
 	index  int
+	char   string
 	prompt string
 	err    error
 }
 
 // Additional styles for browser
 var (
+	cardCountStyle  lipgloss.Style
+	deckNameStyle   lipgloss.Style
+	fieldLabelStyle lipgloss.Style
+	fieldValueStyle lipgloss.Style
+	searchBoxStyle  lipgloss.Style
+)
+
+// setBrowserStyles builds the browser styles from t.
+func setBrowserStyles(t theme.Theme) {
 	cardCountStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Padding(0, 1)
+		Foreground(t.Subtle).
+		Padding(0, 1)
 
 	deckNameStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true)
+		Foreground(t.Secondary).
+		Bold(true)
 
 	fieldLabelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888"))
+		Foreground(t.Subtle)
 
 	fieldValueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
 	searchBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#ffe66d")).
-			Padding(0, 1)
-)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1)
+}
 
 // NewBrowser creates a new browser TUI model.
 func NewBrowser(dict *decomp.Dictionary, cfg *config.Config, pkg *anki.Package) BrowserModel {
@@ -125,15 +140,14 @@ func NewBrowser(dict *decomp.Dictionary, cfg *config.Config, pkg *anki.Package)
 	var notes []*anki.Note
 	for _, note := range pkg.Notes {
 		value := pkg.GetFieldValue(note, chineseField)
-		if containsChineseChars(value) {
+		if analyze.ContainsChinese(value) {
 			notes = append(notes, note)
 		}
 	}
 
 	m := BrowserModel{
 		pkg:           pkg,
-		parser:        pinyin.NewParser(),
-		dict:          dict,
+		analyzer:      analyze.New(dict, gen),
 		generator:     gen,
 		config:        cfg,
 		notes:         notes,
@@ -264,11 +278,22 @@ func (m BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.llmError = fmt.Errorf("ANTHROPIC_API_KEY not set")
 					return m, nil
 				}
-				m.batchGenerating = true
-				m.batchTotal = len(m.characters)
-				m.batchCompleted = 0
-				m.llmError = nil
-				return m, m.generateBatchPrompts()
+				indexes := make([]int, len(m.characters))
+				for i := range indexes {
+					indexes[i] = i
+				}
+				return m, m.startBatch(indexes)
+			}
+			return m, nil
+		case "F":
+			// Retry the characters that failed in the last batch
+			if len(m.batchFailures) > 0 && !m.batchGenerating && !m.llmGenerating && m.llmClient != nil {
+				indexes := make([]int, 0, len(m.batchFailures))
+				for i := range m.batchFailures {
+					indexes = append(indexes, i)
+				}
+				sort.Ints(indexes)
+				return m, m.startBatch(indexes)
 			}
 			return m, nil
 		}
@@ -284,7 +309,19 @@ func (m BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case batchResultMsg:
+		if msg.err != nil {
+			slog.Warn("batch prompt failed", "char", msg.char, "error", msg.err)
+		}
+		// Ignore the rest of a batch on a card since left
+		if m.currentNote >= len(m.filteredNotes) || msg.note != m.filteredNotes[m.currentNote].ID {
+			return m, nil
+		}
 		m.batchCompleted++
+		if msg.err != nil {
+			m.batchFailures[msg.index] = msg.err
+		} else {
+			delete(m.batchFailures, msg.index)
+		}
 		if msg.err == nil && msg.prompt != "" {
 			m.charPrompts[msg.index] = msg.prompt
 			// Update llmPrompt if this is the currently selected character
@@ -322,7 +359,7 @@ func (m *BrowserModel) loadCurrentNote() {
 
 	note := m.filteredNotes[m.currentNote]
 	value := m.pkg.GetFieldValue(note, m.chineseField)
-	value = stripHTMLTags(value)
+	value = htmlutil.Text(value)
 
 	m.characters = nil
 	m.selected = 0
@@ -330,70 +367,9 @@ func (m *BrowserModel) loadCurrentNote() {
 	m.batchGenerating = false
 	m.batchCompleted = 0
 	m.batchTotal = 0
+	m.batchFailures = nil
 
-	for _, r := range value {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			char := string(r)
-			result := m.analyzeChar(char)
-			if result != nil {
-				m.characters = append(m.characters, *result)
-			}
-		}
-	}
-}
-
-// analyzeChar analyzes a single character.
-func (m *BrowserModel) analyzeChar(char string) *CharacterResult {
-	readings := m.parser.ParseChar(char)
-	if len(readings) == 0 {
-		return nil
-	}
-
-	reading := readings[0]
-
-	result := &CharacterResult{
-		Character: char,
-		Pinyin:    reading.Full,
-		Initial:   reading.Initial,
-		Final:     reading.Final,
-		Tone:      reading.Tone,
-		ActorID:   pinyin.GetActorID(reading.Initial),
-		SetID:     pinyin.GetSetID(reading.Final),
-	}
-
-	if m.dict != nil {
-		if entry := m.dict.Lookup(char); entry != nil {
-			result.Meaning = entry.Definition
-			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
-			result.Components = decomp.ExtractComponents(entry.Decomposition)
-			if entry.Etymology != nil {
-				if entry.Etymology.Hint != "" {
-					result.Etymology = entry.Etymology.Hint
-				} else {
-					result.Etymology = entry.Etymology.Type
-				}
-			}
-		}
-	}
-
-	if actor := m.generator.GetActor(result.ActorID); actor != nil {
-		result.ActorName = actor.Name
-	}
-	if set := m.generator.GetSet(result.SetID); set != nil {
-		result.SetName = set.Name
-	}
-	result.ToneRoom = m.generator.GetToneRoom(
-		m.generator.GetSet(result.SetID),
-		reading.Tone,
-	)
-
-	for _, comp := range result.Components {
-		if p := m.generator.GetProp(comp); p != nil && p.Name != "" {
-			result.PropNames = append(result.PropNames, p.Name)
-		}
-	}
-
-	return result
+	m.characters = m.analyzer.Text(value)
 }
 
 // applyFilter filters notes by search term.
@@ -406,7 +382,7 @@ func (m *BrowserModel) applyFilter() {
 		for _, note := range m.notes {
 			// Search all fields
 			for _, field := range note.Fields {
-				if strings.Contains(strings.ToLower(stripHTMLTags(field)), term) {
+				if strings.Contains(strings.ToLower(htmlutil.Text(field)), term) {
 					m.filteredNotes = append(m.filteredNotes, note)
 					break
 				}
@@ -430,36 +406,7 @@ func (m *BrowserModel) generateLLMPrompt() tea.Cmd {
 	r := m.characters[m.selected]
 	client := m.llmClient
 
-	elements := llm.SceneElements{
-		Character: r.Character,
-		Pinyin:    r.Pinyin,
-		Meaning:   r.Meaning,
-		ActorName: r.ActorName,
-		SetName:   r.SetName,
-		ToneRoom:  r.ToneRoom,
-		Props:     r.PropNames,
-	}
-
-	if m.config != nil {
-		for _, a := range m.config.Actors {
-			if a.ID == r.ActorID {
-				elements.ActorDesc = a.Description
-				break
-			}
-		}
-		for _, s := range m.config.Sets {
-			if s.ID == r.SetID {
-				elements.SetDesc = s.Description
-				for _, room := range s.Rooms {
-					if hmm.Tone(room.Tone) == r.Tone {
-						elements.ToneRoomDesc = room.Description
-						break
-					}
-				}
-				break
-			}
-		}
-	}
+	elements := m.analyzer.SceneElements(r)
 
 	return func() tea.Msg {
 		prompt, err := client.GenerateScene(elements)
@@ -467,61 +414,43 @@ func (m *BrowserModel) generateLLMPrompt() tea.Cmd {
 	}
 }
 
-// generateBatchPrompts creates commands to generate scenes for all characters.
-func (m *BrowserModel) generateBatchPrompts() tea.Cmd {
-	if len(m.characters) == 0 || m.llmClient == nil {
+// startBatch generates prompts for the characters at indexes, all of them
+// with B or those that failed with F.
+func (m *BrowserModel) startBatch(indexes []int) tea.Cmd {
+	m.batchGenerating = true
+	m.batchTotal = len(indexes)
+	m.batchCompleted = 0
+	m.batchFailures = make(map[int]error)
+	m.llmError = nil
+	return m.generateBatchPrompts(indexes)
+}
+
+// generateBatchPrompts creates commands to generate scenes for the
+// characters at indexes.
+func (m *BrowserModel) generateBatchPrompts(indexes []int) tea.Cmd {
+	if len(m.characters) == 0 || m.llmClient == nil || m.currentNote >= len(m.filteredNotes) {
 		return nil
 	}
 
 	var cmds []tea.Cmd
 	client := m.llmClient
+	note := m.filteredNotes[m.currentNote].ID
 
-	for i, r := range m.characters {
+	for _, i := range indexes {
+		r := m.characters[i]
 		// Skip if already generated
-		if _, exists := m.charPrompts[i]; exists {
+		if p, exists := m.charPrompts[i]; exists {
 			cmds = append(cmds, func() tea.Msg {
-				return batchResultMsg{index: i, prompt: m.charPrompts[i], err: nil}
+				return batchResultMsg{note: note, index: i, char: r.Character, prompt: p, err: nil}
 			})
 			continue
 		}
 
-		idx := i
-		char := r
-
-		elements := llm.SceneElements{
-			Character: char.Character,
-			Pinyin:    char.Pinyin,
-			Meaning:   char.Meaning,
-			ActorName: char.ActorName,
-			SetName:   char.SetName,
-			ToneRoom:  char.ToneRoom,
-			Props:     char.PropNames,
-		}
-
-		if m.config != nil {
-			for _, a := range m.config.Actors {
-				if a.ID == char.ActorID {
-					elements.ActorDesc = a.Description
-					break
-				}
-			}
-			for _, s := range m.config.Sets {
-				if s.ID == char.SetID {
-					elements.SetDesc = s.Description
-					for _, room := range s.Rooms {
-						if hmm.Tone(room.Tone) == char.Tone {
-							elements.ToneRoomDesc = room.Description
-							break
-						}
-					}
-					break
-				}
-			}
-		}
+		elements := m.analyzer.SceneElements(r)
 
 		cmds = append(cmds, func() tea.Msg {
 			prompt, err := client.GenerateScene(elements)
-			return batchResultMsg{index: idx, prompt: prompt, err: err}
+			return batchResultMsg{note: note, index: i, char: r.Character, prompt: prompt, err: err}
 		})
 	}
 
@@ -574,6 +503,9 @@ func (m BrowserModel) View() string {
 	if len(m.characters) > 1 {
 		helpText += " • B: batch"
 	}
+	if len(m.batchFailures) > 0 && !m.batchGenerating {
+		helpText += " • F: retry failed"
+	}
 	if m.llmPrompt != "" {
 		helpText += " • y: copy"
 	}
@@ -600,10 +532,7 @@ func (m BrowserModel) renderNoteView() string {
 		if i < len(fieldNames) {
 			fieldName = fieldNames[i]
 		}
-		cleanValue := stripHTMLTags(value)
-		if len(cleanValue) > 80 {
-			cleanValue = cleanValue[:80] + "..."
-		}
+		cleanValue := textutil.Truncate(htmlutil.TextWithRuby(value), 80)
 		b.WriteString("  ")
 		b.WriteString(fieldLabelStyle.Render(fieldName + ": "))
 		b.WriteString(fieldValueStyle.Render(cleanValue))
@@ -653,7 +582,7 @@ func (m BrowserModel) renderCharTabs() string {
 }
 
 // renderCharacterDetail renders HMM breakdown for a character.
-func (m BrowserModel) renderCharacterDetail(r CharacterResult) string {
+func (m BrowserModel) renderCharacterDetail(r analyze.CharacterResult) string {
 	var b strings.Builder
 
 	// HMM Breakdown
@@ -669,8 +598,11 @@ func (m BrowserModel) renderCharacterDetail(r CharacterResult) string {
 	// LLM prompt
 	if m.batchGenerating {
 		b.WriteString("\n")
-		progress := fmt.Sprintf("  Generating prompts... %d/%d", m.batchCompleted, m.batchTotal)
-		b.WriteString(loadingStyle.Render(progress))
+		b.WriteString(loadingStyle.Render("  Generating prompts "))
+		b.WriteString(components.NewProgress(20, current).View(m.batchCompleted, m.batchTotal))
+		if len(m.batchFailures) > 0 {
+			b.WriteString("  " + errorStyle.Render(fmt.Sprintf("%d failed", len(m.batchFailures))))
+		}
 		b.WriteString("\n")
 	} else if m.llmGenerating {
 		b.WriteString("\n")
@@ -707,12 +639,17 @@ func (m BrowserModel) renderCharacterDetail(r CharacterResult) string {
 		b.WriteString(helpStyle.Render(hint))
 		b.WriteString("\n")
 	}
+	if failed := llm.FailureSummary(m.batchFailures, m.batchTotal); failed != "" && !m.batchGenerating {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render("  Batch: " + failed + " • F: retry"))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
 
 // renderHMMBox renders the HMM breakdown box.
-func (m BrowserModel) renderHMMBox(r CharacterResult) string {
+func (m BrowserModel) renderHMMBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	initial := r.Initial
@@ -751,7 +688,7 @@ func (m BrowserModel) renderHMMBox(r CharacterResult) string {
 }
 
 // renderComponentsBox renders components/props.
-func (m BrowserModel) renderComponentsBox(r CharacterResult) string {
+func (m BrowserModel) renderComponentsBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	for i, comp := range r.Components {
@@ -781,7 +718,7 @@ func detectChineseFieldFromPkg(pkg *anki.Package) string {
 		}
 		fieldNames := pkg.GetFieldNames(note)
 		for j, value := range note.Fields {
-			if containsChineseChars(value) {
+			if analyze.ContainsChinese(value) {
 				if j < len(fieldNames) {
 					return fieldNames[j]
 				}
@@ -791,17 +728,3 @@ func detectChineseFieldFromPkg(pkg *anki.Package) string {
 	}
 	return ""
 }
-
-func containsChineseChars(s string) bool {
-	for _, r := range s {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			return true
-		}
-	}
-	return false
-}
-
-func stripHTMLTags(s string) string {
-	re := regexp.MustCompile(`<[^>]*>`)
-	return strings.TrimSpace(re.ReplaceAllString(s, ""))
-}
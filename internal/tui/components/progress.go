@@ -0,0 +1,61 @@
+// Package components provides shared UI components for the TUI.
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+)
+
+// Progress renders how far a long operation has come: loading a deck,
+// generating prompts for a batch, or augmenting a deck. It draws the same
+// bar as bubbles/progress without the animation, so it can be rendered
+// from any View or printed by a command.
+type Progress struct {
+	Width int // Cells of the bar, not counting the percentage
+	Full  lipgloss.Style
+	Empty lipgloss.Style
+	Plain bool // Percentage only, as screen readers spell out every block
+}
+
+// NewProgress returns a progress bar width cells wide, colored from t.
+func NewProgress(width int, t theme.Theme) Progress {
+	return Progress{
+		Width: width,
+		Full:  lipgloss.NewStyle().Foreground(t.Success),
+		Empty: lipgloss.NewStyle().Foreground(t.Faint),
+		Plain: t.Plain,
+	}
+}
+
+// Bar renders the bar filled to done of total, without the percentage.
+func (p Progress) Bar(done, total int) string {
+	if p.Plain {
+		return p.Full.Render(fmt.Sprintf("%d%%", percent(done, total)))
+	}
+	filled := 0
+	if total > 0 {
+		filled = min(done, total) * p.Width / total
+	}
+	return p.Full.Render(strings.Repeat("█", filled)) +
+		p.Empty.Render(strings.Repeat("░", p.Width-filled))
+}
+
+// View renders the bar filled to done of total, followed by the
+// percentage.
+func (p Progress) View(done, total int) string {
+	if p.Plain {
+		return p.Bar(done, total)
+	}
+	return p.Bar(done, total) + fmt.Sprintf(" %3d%%", percent(done, total))
+}
+
+// percent returns done as a whole percentage of total.
+func percent(done, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return min(done, total) * 100 / total
+}
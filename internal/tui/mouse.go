@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/mattn/go-runewidth"
+)
+
+// handleMouse turns mouse events into the key presses they stand for, so
+// every view gets mouse support without its own hit testing:
+//   - the wheel scrolls the details in Lookup, Browse, Learn, and Walk, and
+//     elsewhere moves like ↑/↓
+//   - clicking a sidebar or top bar item switches to its view, directly
+//     rather than by its shortcut, which a search box would take as text
+//   - clicking a "k: action" entry in a help line presses k
+//   - clicking a character selects its tab in Lookup and Browse
+func (m AppModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	if m.showHelp {
+		m.showHelp = false
+		return m, nil
+	}
+
+	if m.touring {
+		// A click moves on, like enter
+		m.answerTour("enter")
+		return m, nil
+	}
+
+	if m.loadErr != nil {
+		m.loadErr = nil
+		m.resizeViews()
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		if m.scrollDetail(-wheelLines) {
+			return m, nil
+		}
+		return m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case tea.MouseButtonWheelDown:
+		if m.scrollDetail(wheelLines) {
+			return m, nil
+		}
+		return m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	if m.compact && msg.Y < topBarHeight {
+		if i := m.topBarItemAt(msg.X); i >= 0 {
+			m.sidebarActive = false
+			m.showView(m.menuItems[i].View)
+			return m, nil
+		}
+		return m, nil
+	}
+
+	sidebar := m.renderSidebar()
+	sidebarWidth := lipgloss.Width(sidebar)
+
+	if msg.X < sidebarWidth {
+		lines := strings.Split(sidebar, "\n")
+		if msg.Y < 0 || msg.Y >= len(lines) {
+			return m, nil
+		}
+		line := ansi.Strip(lines[msg.Y])
+		for _, item := range m.menuItems {
+			if strings.Contains(line, m.sidebarLabel(item)) {
+				m.sidebarActive = false
+				m.showView(item.View)
+				return m, nil
+			}
+		}
+		if i := m.deckAt(line); i >= 0 {
+			m.switchDeck(i)
+		}
+		return m, nil
+	}
+
+	// Translate to content coordinates
+	top, _, _, left := ContentStyle.GetPadding()
+	x := msg.X - sidebarWidth - left
+	y := msg.Y - m.headerHeight() - top
+
+	lines := strings.Split(m.contentView(), "\n")
+	if y < 0 || y >= len(lines) || x < 0 {
+		return m, nil
+	}
+	line := ansi.Strip(lines[y])
+
+	if key := helpKeyAt(line, x); key != "" {
+		m.sidebarActive = false
+		return m.Update(keyFor(key))
+	}
+
+	if r := runeAt(line, x); analyze.IsChinese(r) {
+		switch m.currentView {
+		case ViewLookup:
+			return m, m.lookupView.SelectCharacter(string(r))
+		case ViewBrowse:
+			m.browseView.SelectCharacter(string(r))
+		}
+	}
+
+	return m, nil
+}
+
+// wheelLines is how far one wheel step scrolls a detail pane.
+const wheelLines = 3
+
+// scrollDetail scrolls the detail pane of the current view by n lines and
+// reports whether the view has one.
+func (m *AppModel) scrollDetail(n int) bool {
+	if m.sidebarActive {
+		return false
+	}
+	switch m.currentView {
+	case ViewLookup:
+		m.lookupView.ScrollDetail(n)
+	case ViewBrowse:
+		m.browseView.ScrollDetail(n)
+	case ViewLearn:
+		m.learnView.ScrollDetail(n)
+	case ViewWalk:
+		m.walkView.ScrollDetail(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// helpKeyAt returns the key of the "key: action" help entry at column x
+// of a help line like "g: generate • y: copy", or "" if there is none.
+// Entries for key pairs such as "←/→" are ignored.
+func helpKeyAt(line string, x int) string {
+	col := 0
+	for i, entry := range strings.Split(line, " • ") {
+		if i > 0 {
+			col += runewidth.StringWidth(" • ")
+		}
+		width := runewidth.StringWidth(entry)
+		if x >= col && x < col+width {
+			key, _, ok := strings.Cut(strings.TrimSpace(entry), ": ")
+			if !ok {
+				return ""
+			}
+			switch key {
+			case "space", "enter", "esc", "tab":
+				return key
+			}
+			if len([]rune(key)) == 1 {
+				return key
+			}
+			return ""
+		}
+		col += width
+	}
+	return ""
+}
+
+// runeAt returns the rune displayed at column x of line, or 0.
+func runeAt(line string, x int) rune {
+	col := 0
+	for _, r := range line {
+		w := runewidth.RuneWidth(r)
+		if x >= col && x < col+w {
+			return r
+		}
+		col += w
+	}
+	return 0
+}
+
+// keyFor builds the key message for a key name used in help lines.
+func keyFor(key string) tea.KeyMsg {
+	switch key {
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEscape}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/config"
+)
+
+// currentSession records where the user is, for the next session to
+// resume.
+func (m AppModel) currentSession() *config.Session {
+	s := &config.Session{
+		View:      m.viewName(m.currentView),
+		Deck:      m.ankiPath,
+		LearnCard: m.learnView.Position(),
+	}
+	if m.ankiPackage == nil {
+		s.Deck = ""
+	} else if abs, err := filepath.Abs(s.Deck); err == nil {
+		// The next session may start in another directory
+		s.Deck = abs
+	}
+	s.BrowseSearch, s.BrowseSort, s.BrowseCard = m.browseView.Position()
+	return s
+}
+
+// quit saves where the user left off and quits. A resume offer nobody
+// answered keeps the session it offers.
+func (m *AppModel) quit() tea.Cmd {
+	if m.resumeOffer == nil {
+		m.session = m.currentSession()
+	}
+	m.saveUIState()
+	return tea.Quit
+}
+
+// offerResume offers to resume s on launch, unless it left off where a
+// new session starts anyway.
+func (m *AppModel) offerResume(s *config.Session) {
+	m.session = s
+	if s == nil || m.ankiPackage != nil {
+		return
+	}
+	if s.Deck == "" && m.parseViewName(s.View) == ViewLookup {
+		return
+	}
+	m.resumeOffer = s
+}
+
+// answerResume resumes the offered session if key accepts the offer, and
+// drops the offer either way. With a deck to reopen, the views are
+// restored once it has loaded.
+func (m *AppModel) answerResume(key string) tea.Cmd {
+	s := m.resumeOffer
+	m.resumeOffer = nil
+	if key != "enter" && key != "y" {
+		return nil
+	}
+	if s.Deck != "" {
+		m.resuming = s
+		return m.startLoading(s.Deck)
+	}
+	m.restoreSession(s)
+	return nil
+}
+
+// restoreSession puts the views back where s left them.
+func (m *AppModel) restoreSession(s *config.Session) {
+	if m.ankiPackage != nil {
+		m.browseView.Restore(s.BrowseSearch, s.BrowseSort, s.BrowseCard)
+		m.learnView.Restore(s.LearnCard)
+	}
+	m.showView(m.parseViewName(s.View))
+	m.sidebarActive = false
+}
+
+// viewName returns the name a view is saved under: its sidebar label in
+// lower case.
+func (m AppModel) viewName(v ViewType) string {
+	return strings.ToLower(m.menuItemLabel(v))
+}
+
+// parseViewName returns the view saved as name, or Lookup if there is
+// none.
+func (m AppModel) parseViewName(name string) ViewType {
+	for _, item := range m.menuItems {
+		if strings.ToLower(item.Label) == name {
+			return item.View
+		}
+	}
+	return ViewLookup
+}
+
+// renderResumeOffer renders the banner offering to resume the last
+// session.
+func (m AppModel) renderResumeOffer() string {
+	s := m.resumeOffer
+	where := m.menuItemLabel(m.parseViewName(s.View))
+	if s.Deck != "" {
+		where += " in " + filepath.Base(s.Deck)
+		switch m.parseViewName(s.View) {
+		case ViewBrowse:
+			where += fmt.Sprintf(", card %d", s.BrowseCard+1)
+		case ViewLearn:
+			where += fmt.Sprintf(", card %d", s.LearnCard+1)
+		}
+	}
+	banner := "Resume where you left off? " + where + "\n" +
+		HelpStyle.Render("enter: resume • any other key: start fresh")
+	return BannerStyle.Width(m.contentWidth()-6).Render(banner) + "\n\n"
+}
+
+// menuItemLabel returns the sidebar label of a view.
+func (m AppModel) menuItemLabel(v ViewType) string {
+	for _, item := range m.menuItems {
+		if item.View == v {
+			return item.Label
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+)
+
+// sessionLog records the characters shown during a session, for a
+// summary a tutor and student can go over afterwards.
+type sessionLog struct {
+	dir     string
+	started time.Time
+	order   []string // Characters, first shown first
+	chars   map[string]*loggedChar
+	last    string // View and character shown last, so redraws count once
+}
+
+// loggedChar is a character shown during a session and where.
+type loggedChar struct {
+	result analyze.CharacterResult
+	views  []string       // Views it was shown in, first first
+	times  map[string]int // Times shown by view
+}
+
+// LogSession records every character shown from now on, in Lookup,
+// Browse, Learn, or Walk, for WriteSessionLog to summarize in dir.
+func (m *AppModel) LogSession(dir string) {
+	m.sessionLog = &sessionLog{
+		dir:     dir,
+		started: time.Now(),
+		chars:   make(map[string]*loggedChar),
+	}
+}
+
+// logShown records the character the current view shows, if the session
+// is logged and it was not the last one recorded.
+func (m AppModel) logShown() {
+	log := m.sessionLog
+	if log == nil {
+		return
+	}
+
+	var r *analyze.CharacterResult
+	switch m.currentView {
+	case ViewLookup:
+		r = m.lookupView.Shown()
+	case ViewBrowse:
+		r = m.browseView.Shown()
+	case ViewLearn:
+		r = m.learnView.Shown()
+	case ViewWalk:
+		r = m.walkView.Shown()
+	}
+	if r == nil {
+		return
+	}
+
+	view := m.menuItemLabel(m.currentView)
+	key := view + ":" + r.Character
+	if key == log.last {
+		return
+	}
+	log.last = key
+
+	c, ok := log.chars[r.Character]
+	if !ok {
+		c = &loggedChar{result: *r, times: make(map[string]int)}
+		log.chars[r.Character] = c
+		log.order = append(log.order, r.Character)
+	}
+	if c.times[view] == 0 {
+		c.views = append(c.views, view)
+	}
+	c.times[view]++
+}
+
+// WriteSessionLog writes the characters shown since LogSession as a
+// Markdown summary named after the date and time the session started,
+// and returns its path, or "" if the session is not logged or showed no
+// characters.
+func (m AppModel) WriteSessionLog() (string, error) {
+	log := m.sessionLog
+	if log == nil || len(log.order) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HMM session, %s\n\n", log.started.Format("Monday 2 January 2006"))
+	fmt.Fprintf(&b, "%s–%s · %d characters\n\n",
+		log.started.Format("15:04"), time.Now().Format("15:04"), len(log.order))
+
+	b.WriteString("| Character | Pinyin | Meaning | Actor | Set | Room | Seen in |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	var unpictured []string
+	for _, char := range log.order {
+		c := log.chars[char]
+		r := c.result
+
+		var seen []string
+		for _, view := range c.views {
+			if n := c.times[view]; n > 1 {
+				seen = append(seen, fmt.Sprintf("%s ×%d", view, n))
+			} else {
+				seen = append(seen, view)
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			char, logCell(r.Pinyin), logCell(r.Meaning), logCell(r.ActorName),
+			logCell(r.SetName), logCell(r.ToneRoom), strings.Join(seen, ", "))
+
+		if m.scenes != nil && m.scenes.Approved(char) == nil {
+			unpictured = append(unpictured, char)
+		}
+	}
+
+	if len(unpictured) > 0 {
+		fmt.Fprintf(&b, "\n## No scene yet\n\n%s\n", strings.Join(unpictured, " "))
+	}
+
+	if err := os.MkdirAll(log.dir, 0755); err != nil {
+		return "", fmt.Errorf("writing session log: %w", err)
+	}
+	path := filepath.Join(log.dir, "hmm-session-"+log.started.Format("2006-01-02-1504")+".md")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("writing session log: %w", err)
+	}
+	return path, nil
+}
+
+// logCell makes s safe to put into a Markdown table cell.
+func logCell(s string) string {
+	if s == "" {
+		return "–"
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "|", `\|`)), " ")
+}
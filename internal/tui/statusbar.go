@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// statusBarHeight is how many lines the status bar takes at the bottom of
+// the screen.
+const statusBarHeight = 1
+
+// statusItem is one entry of the status bar. Warnings point at something
+// that makes parts of the app fail quietly.
+type statusItem struct {
+	text string
+	warn bool
+}
+
+// statusItems collects the environment diagnostics shown in the status bar.
+func (m AppModel) statusItems() []statusItem {
+	var items []statusItem
+
+	if m.dict != nil && m.dict.Size() > 0 {
+		items = append(items, statusItem{text: fmt.Sprintf("dict: %d entries", m.dict.Size())})
+	} else {
+		items = append(items, statusItem{text: "dict: not loaded", warn: true})
+	}
+
+	if m.config == nil {
+		items = append(items, statusItem{text: "config: not loaded", warn: true})
+	} else {
+		items = append(items, statusItem{text: fmt.Sprintf("config: %d%%", m.config.Completeness())})
+	}
+
+	if m.llmClient != nil {
+		items = append(items, statusItem{text: "LLM: Anthropic"})
+	} else {
+		items = append(items, statusItem{text: "LLM: no ANTHROPIC_API_KEY", warn: true})
+	}
+
+	if m.ankiPackage != nil {
+		text := fmt.Sprintf("deck: %s (%d notes)", filepath.Base(m.ankiPath), len(m.ankiPackage.Notes))
+		if len(m.decks) > 1 {
+			text += fmt.Sprintf(", %d of %d open", m.deckIndex+1, len(m.decks))
+		}
+		items = append(items, statusItem{text: text})
+	} else {
+		items = append(items, statusItem{text: "deck: none"})
+	}
+
+	if m.clipboardOK {
+		items = append(items, statusItem{text: "clipboard: ok"})
+	} else {
+		items = append(items, statusItem{text: "clipboard: unavailable", warn: true})
+	}
+
+	return items
+}
+
+// renderStatusBar renders the status bar across the full width. A toast
+// takes the place of the diagnostics while it is shown.
+func (m AppModel) renderStatusBar() string {
+	line := StatusBarStyle.Render(" ")
+	if m.toast.Text != "" {
+		line += m.renderToast()
+	} else {
+		line += m.renderStatusItems()
+	}
+
+	line = ansi.Truncate(line, m.width, "…")
+	if pad := m.width - lipgloss.Width(line); pad > 0 {
+		line += StatusBarStyle.Render(strings.Repeat(" ", pad))
+	}
+	return line
+}
+
+// renderStatusItems renders the diagnostics, separated by bullets.
+func (m AppModel) renderStatusItems() string {
+	var parts []string
+	for _, item := range m.statusItems() {
+		if item.warn {
+			// Warnings stand out by color, which a plain theme spells out
+			text := item.text
+			if current.Plain {
+				text = "warning: " + text
+			}
+			parts = append(parts, StatusBarWarnStyle.Render(text))
+		} else {
+			parts = append(parts, StatusBarStyle.Render(item.text))
+		}
+	}
+	return strings.Join(parts, StatusBarStyle.Render(" • "))
+}
@@ -1,234 +1,349 @@
 // Package tui provides an interactive terminal UI for HMM.
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+	"github.com/f3rmion/hmm/internal/tui/views"
+)
+
+// current is the theme the TUI is styled with.
+var current = theme.Dark
 
-// Color palette
+// Color palette, set from the current theme by SetTheme
 var (
-	ColorPrimary   = lipgloss.Color("#FF6B6B") // Red - titles, actors
-	ColorSecondary = lipgloss.Color("#4ecdc4") // Teal - sets, subtitles
-	ColorAccent    = lipgloss.Color("#ffe66d") // Yellow - characters, props
-	ColorMuted     = lipgloss.Color("#666666") // Gray - help text
-	ColorSuccess   = lipgloss.Color("#a8e6cf") // Green - success, tones
-	ColorText      = lipgloss.Color("#f1faee") // Light text
-	ColorLabel     = lipgloss.Color("#a8dadc") // Label color
-	ColorBg        = lipgloss.Color("#1a1a2e") // Dark background
-	ColorBgAlt     = lipgloss.Color("#2d3436") // Alt background
-	ColorBorder    = lipgloss.Color("#3d5a80") // Border color
+	ColorPrimary   lipgloss.Color // Titles, actors
+	ColorSecondary lipgloss.Color // Sets, subtitles
+	ColorAccent    lipgloss.Color // Characters, props
+	ColorMuted     lipgloss.Color // Help text
+	ColorSuccess   lipgloss.Color // Success, tones
+	ColorText      lipgloss.Color // Body text
+	ColorLabel     lipgloss.Color // Labels
+	ColorBg        lipgloss.Color // Title background
+	ColorBgAlt     lipgloss.Color // Selection background
+	ColorBorder    lipgloss.Color // Borders
 )
 
 // Sidebar styles
 var (
+	SidebarStyle           lipgloss.Style
+	SidebarTitleStyle      lipgloss.Style
+	SidebarItemStyle       lipgloss.Style
+	SidebarItemActiveStyle lipgloss.Style
+	SidebarHelpStyle       lipgloss.Style
+)
+
+// Title styles
+var (
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
+)
+
+// Character display styles
+var (
+	CharacterLargeStyle   lipgloss.Style
+	CharacterPinyinStyle  lipgloss.Style
+	CharacterMeaningStyle lipgloss.Style
+)
+
+// Character tab styles (for multi-character words)
+var (
+	CharTabStyle       lipgloss.Style
+	CharTabActiveStyle lipgloss.Style
+	CharTabPinyinStyle lipgloss.Style
+	WordNavStyle       lipgloss.Style
+	WordDisplayStyle   lipgloss.Style
+)
+
+// HMM breakdown styles
+var (
+	LabelStyle lipgloss.Style
+	ValueStyle lipgloss.Style
+	ActorStyle lipgloss.Style
+	SetStyle   lipgloss.Style
+	PropStyle  lipgloss.Style
+	ToneStyle  lipgloss.Style
+)
+
+// Box styles
+var (
+	BoxStyle       lipgloss.Style
+	PromptBoxStyle lipgloss.Style
+	LLMPromptStyle lipgloss.Style
+	SearchBoxStyle lipgloss.Style
+)
+
+// Status styles
+var (
+	HelpStyle        lipgloss.Style
+	ErrorStyle       lipgloss.Style
+	ErrorBannerStyle lipgloss.Style
+	BannerStyle      lipgloss.Style
+	LoadingStyle     lipgloss.Style
+	CopiedStyle      lipgloss.Style
+	DividerStyle     lipgloss.Style
+
+	StatusBarStyle     lipgloss.Style
+	StatusBarWarnStyle lipgloss.Style
+	StatusBarOKStyle   lipgloss.Style
+)
+
+// File picker styles
+var (
+	FilePickerDirStyle      lipgloss.Style
+	FilePickerFileStyle     lipgloss.Style
+	FilePickerSelectedStyle lipgloss.Style
+	FilePickerPathStyle     lipgloss.Style
+)
+
+// Settings view styles
+var (
+	SettingsTabStyle       lipgloss.Style
+	SettingsTabActiveStyle lipgloss.Style
+	SettingsHeaderStyle    lipgloss.Style
+	SettingsRowStyle       lipgloss.Style
+)
+
+// Content area style
+var ContentStyle lipgloss.Style
+
+// Card count style (for browse view)
+var CardCountStyle lipgloss.Style
+
+func init() {
+	SetTheme(theme.Dark)
+}
+
+// SetTheme restyles the whole TUI with the colors of t. Call it before
+// creating the app, since some styles are copied into its inputs.
+func SetTheme(t theme.Theme) {
+	current = t
+	ColorPrimary = t.Primary
+	ColorSecondary = t.Secondary
+	ColorAccent = t.Accent
+	ColorMuted = t.Muted
+	ColorSuccess = t.Success
+	ColorText = t.Text
+	ColorLabel = t.Label
+	ColorBg = t.Bg
+	ColorBgAlt = t.BgAlt
+	ColorBorder = t.Border
+
 	SidebarStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderRight(true).
-			BorderForeground(ColorBorder).
-			Padding(1, 1)
+		BorderStyle(t.BoxBorder()).
+		BorderRight(true).
+		BorderForeground(ColorBorder).
+		Padding(1, 1)
 
 	SidebarTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorPrimary).
-				Background(ColorBg).
-				Padding(0, 1).
-				MarginBottom(1)
+		Bold(true).
+		Foreground(ColorPrimary).
+		Background(ColorBg).
+		Padding(0, 1).
+		MarginBottom(1)
 
 	SidebarItemStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				Padding(0, 1)
+		Foreground(ColorMuted).
+		Padding(0, 1)
 
-	SidebarItemActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorAccent).
-				Background(ColorBgAlt).
-				Padding(0, 1)
+	SidebarItemActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorAccent).
+		Background(ColorBgAlt).
+		Padding(0, 1))
 
 	SidebarHelpStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				MarginTop(1).
-				Padding(0, 1)
-)
+		Foreground(ColorMuted).
+		MarginTop(1).
+		Padding(0, 1)
 
-// Title styles
-var (
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorPrimary).
-			Background(ColorBg).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(ColorPrimary).
+		Background(ColorBg).
+		Padding(0, 1)
 
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(ColorSecondary)
-)
+		Foreground(ColorSecondary)
 
-// Character display styles
-var (
 	CharacterLargeStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorAccent).
-				Background(ColorBgAlt).
-				Padding(1, 4).
-				Margin(1, 0).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(ColorAccent).
+		Background(ColorBgAlt).
+		Padding(1, 4).
+		Margin(1, 0).
+		Align(lipgloss.Center)
 
 	CharacterPinyinStyle = lipgloss.NewStyle().
-				Foreground(ColorSecondary).
-				Italic(true).
-				Align(lipgloss.Center)
+		Foreground(ColorSecondary).
+		Italic(true).
+		Align(lipgloss.Center)
 
 	CharacterMeaningStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				Italic(true).
-				Align(lipgloss.Center)
-)
+		Foreground(ColorMuted).
+		Italic(true).
+		Align(lipgloss.Center)
 
-// Character tab styles (for multi-character words)
-var (
 	CharTabStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted).
-			Padding(0, 2).
-			Margin(0, 1)
+		Foreground(ColorMuted).
+		Padding(0, 2).
+		Margin(0, 1)
 
-	CharTabActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorAccent).
-				Background(ColorBgAlt).
-				Padding(0, 2).
-				Margin(0, 1)
+	CharTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorAccent).
+		Background(ColorBgAlt).
+		Padding(0, 2).
+		Margin(0, 1))
 
 	CharTabPinyinStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				Italic(true)
+		Foreground(ColorMuted).
+		Italic(true)
 
 	WordNavStyle = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(ColorSecondary).
+		Bold(true).
+		Padding(0, 1)
 
 	WordDisplayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(ColorAccent).
-				Padding(0, 2).
-				Margin(1, 0)
-)
+		Border(t.BoxBorder()).
+		BorderForeground(ColorAccent).
+		Padding(0, 2).
+		Margin(1, 0)
 
-// HMM breakdown styles
-var (
 	LabelStyle = lipgloss.NewStyle().
-			Foreground(ColorLabel).
-			Bold(true).
-			Width(12)
+		Foreground(ColorLabel).
+		Bold(true).
+		Width(12)
 
 	ValueStyle = lipgloss.NewStyle().
-			Foreground(ColorText)
+		Foreground(ColorText)
 
 	ActorStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
-			Bold(true)
+		Foreground(ColorPrimary).
+		Bold(true)
 
 	SetStyle = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Bold(true)
+		Foreground(ColorSecondary).
+		Bold(true)
 
 	PropStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true)
+		Foreground(ColorAccent).
+		Bold(true)
 
 	ToneStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess).
-			Bold(true)
-)
+		Foreground(ColorSuccess).
+		Bold(true)
 
-// Box styles
-var (
 	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorBorder).
-			Padding(1, 2)
+		Border(t.BoxBorder()).
+		BorderForeground(ColorBorder).
+		Padding(1, 2)
 
 	PromptBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorSecondary).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(ColorSecondary).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	LLMPromptStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorPrimary).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	SearchBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorAccent).
-			Padding(0, 1)
-)
+		Border(t.BoxBorder()).
+		BorderForeground(ColorAccent).
+		Padding(0, 1)
 
-// Status styles
-var (
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted)
+		Foreground(ColorMuted)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
-			Bold(true)
+		Foreground(ColorPrimary).
+		Bold(true)
+
+	ErrorBannerStyle = lipgloss.NewStyle().
+		Foreground(ColorPrimary).
+		Bold(true).
+		Border(t.BoxBorder()).
+		BorderForeground(ColorPrimary).
+		Padding(0, 1)
+
+	BannerStyle = lipgloss.NewStyle().
+		Foreground(ColorSecondary).
+		Border(t.BoxBorder()).
+		BorderForeground(ColorSecondary).
+		Padding(0, 1)
 
 	LoadingStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true).
-			Italic(true)
+		Foreground(ColorAccent).
+		Bold(true).
+		Italic(true)
 
 	CopiedStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess).
-			Bold(true)
+		Foreground(ColorSuccess).
+		Bold(true)
 
 	DividerStyle = lipgloss.NewStyle().
-			Foreground(ColorBorder)
-)
+		Foreground(ColorBorder)
+
+	StatusBarStyle = lipgloss.NewStyle().
+		Foreground(ColorText).
+		Background(ColorBgAlt)
+
+	StatusBarWarnStyle = lipgloss.NewStyle().
+		Foreground(ColorPrimary).
+		Background(ColorBgAlt).
+		Bold(true)
+
+	StatusBarOKStyle = lipgloss.NewStyle().
+		Foreground(ColorSuccess).
+		Background(ColorBgAlt).
+		Bold(true)
 
-// File picker styles
-var (
 	FilePickerDirStyle = lipgloss.NewStyle().
-				Foreground(ColorSecondary).
-				Bold(true)
+		Foreground(ColorSecondary).
+		Bold(true)
 
 	FilePickerFileStyle = lipgloss.NewStyle().
-				Foreground(ColorText)
+		Foreground(ColorText)
 
-	FilePickerSelectedStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorAccent).
-				Background(ColorBgAlt)
+	FilePickerSelectedStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorAccent).
+		Background(ColorBgAlt))
 
 	FilePickerPathStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				Italic(true)
-)
+		Foreground(ColorMuted).
+		Italic(true)
 
-// Settings view styles
-var (
 	SettingsTabStyle = lipgloss.NewStyle().
-				Foreground(ColorMuted).
-				Padding(0, 2)
+		Foreground(ColorMuted).
+		Padding(0, 2)
 
-	SettingsTabActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorAccent).
-				Background(ColorBgAlt).
-				Padding(0, 2)
+	SettingsTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorAccent).
+		Background(ColorBgAlt).
+		Padding(0, 2))
 
 	SettingsHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorLabel)
+		Bold(true).
+		Foreground(ColorLabel)
 
 	SettingsRowStyle = lipgloss.NewStyle().
-				Foreground(ColorText)
-)
+		Foreground(ColorText)
 
-// Content area style
-var ContentStyle = lipgloss.NewStyle().
-	Padding(1, 2)
+	ContentStyle = lipgloss.NewStyle().
+		Padding(1, 2)
 
-// Card count style (for browse view)
-var CardCountStyle = lipgloss.NewStyle().
-	Foreground(ColorMuted).
-	Padding(0, 1)
+	CardCountStyle = lipgloss.NewStyle().
+		Foreground(ColorMuted).
+		Padding(0, 1)
+
+	setModelStyles(t)
+	setBrowserStyles(t)
+	views.SetTheme(t)
+}
@@ -0,0 +1,214 @@
+// Package theme defines the color themes of the TUI.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the user theme file in the config directory.
+const FileName = "theme.yaml"
+
+// Theme is the color palette every TUI style is built from.
+type Theme struct {
+	Name      string         `yaml:"name,omitempty"`
+	Primary   lipgloss.Color `yaml:"primary,omitempty"`   // Titles, actors, errors
+	Secondary lipgloss.Color `yaml:"secondary,omitempty"` // Sets, subtitles
+	Accent    lipgloss.Color `yaml:"accent,omitempty"`    // Characters, props, selection
+	Muted     lipgloss.Color `yaml:"muted,omitempty"`     // Help text
+	Subtle    lipgloss.Color `yaml:"subtle,omitempty"`    // Secondary text, inactive tabs
+	Faint     lipgloss.Color `yaml:"faint,omitempty"`     // Empty progress bars
+	Success   lipgloss.Color `yaml:"success,omitempty"`   // Success, tones
+	Text      lipgloss.Color `yaml:"text,omitempty"`      // Body text
+	Label     lipgloss.Color `yaml:"label,omitempty"`     // Field labels
+	Bg        lipgloss.Color `yaml:"bg,omitempty"`        // Title and big character background
+	BgAlt     lipgloss.Color `yaml:"bg_alt,omitempty"`    // Selected item background
+	Border    lipgloss.Color `yaml:"border,omitempty"`    // Borders and dividers
+
+	// Plain drops box drawing and marks selections with text rather than
+	// color alone, for screen readers and limited color vision.
+	Plain bool `yaml:"plain,omitempty"`
+}
+
+// Built-in themes.
+var (
+	Dark = Theme{
+		Name:      "dark",
+		Primary:   "#FF6B6B",
+		Secondary: "#4ecdc4",
+		Accent:    "#ffe66d",
+		Muted:     "#666666",
+		Subtle:    "#888888",
+		Faint:     "#444444",
+		Success:   "#a8e6cf",
+		Text:      "#f1faee",
+		Label:     "#a8dadc",
+		Bg:        "#1a1a2e",
+		BgAlt:     "#2d3436",
+		Border:    "#3d5a80",
+	}
+
+	Light = Theme{
+		Name:      "light",
+		Primary:   "#c0392b",
+		Secondary: "#00796b",
+		Accent:    "#a04000",
+		Muted:     "#8a8a8a",
+		Subtle:    "#5f5f5f",
+		Faint:     "#d0d0d0",
+		Success:   "#2e7d32",
+		Text:      "#1b1b1b",
+		Label:     "#1f5f8b",
+		Bg:        "#f0f0f5",
+		BgAlt:     "#dde3ea",
+		Border:    "#7f95b3",
+	}
+
+	HighContrast = Theme{
+		Name:      "high-contrast",
+		Primary:   "#ff5f5f",
+		Secondary: "#00ffff",
+		Accent:    "#ffff00",
+		Muted:     "#c0c0c0",
+		Subtle:    "#e0e0e0",
+		Faint:     "#808080",
+		Success:   "#00ff00",
+		Text:      "#ffffff",
+		Label:     "#87d7ff",
+		Bg:        "#000000",
+		BgAlt:     "#303030",
+		Border:    "#ffffff",
+	}
+)
+
+var builtin = map[string]Theme{
+	Dark.Name:         Dark,
+	Light.Name:        Light,
+	HighContrast.Name: HighContrast,
+}
+
+// Names returns the names of the built-in themes.
+func Names() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builtin returns the built-in theme called name.
+func Builtin(name string) (Theme, bool) {
+	t, ok := builtin[name]
+	return t, ok
+}
+
+// Load reads a theme file. The file may name a built-in theme as its base
+// and override any of its colors; colors left out come from the base, or
+// from the dark theme if there is none.
+func Load(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Dark, fmt.Errorf("reading theme file: %w", err)
+	}
+
+	var file struct {
+		Base  string `yaml:"base"`
+		Theme `yaml:",inline"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Dark, fmt.Errorf("parsing theme file: %w", err)
+	}
+
+	t := Dark
+	if file.Base != "" {
+		base, ok := Builtin(file.Base)
+		if !ok {
+			return Dark, fmt.Errorf("unknown base theme %q in %s", file.Base, path)
+		}
+		t = base
+	}
+	t.merge(file.Theme)
+	if file.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return t, nil
+}
+
+// Resolve picks the theme for name, which is a built-in theme name or the
+// path of a theme file. An empty name uses the theme file in configDir if
+// there is one, and the dark theme otherwise.
+func Resolve(name, configDir string) (Theme, error) {
+	if name == "" {
+		path := filepath.Join(configDir, FileName)
+		if _, err := os.Stat(path); err != nil {
+			return Dark, nil
+		}
+		return Load(path)
+	}
+
+	if t, ok := Builtin(name); ok {
+		return t, nil
+	}
+	if _, err := os.Stat(name); err != nil {
+		return Dark, fmt.Errorf("unknown theme %q (built-in themes: %v)", name, Names())
+	}
+	return Load(name)
+}
+
+// merge overrides the colors of t with those set in o.
+func (t *Theme) merge(o Theme) {
+	if o.Name != "" {
+		t.Name = o.Name
+	}
+	if o.Plain {
+		t.Plain = true
+	}
+	for _, c := range []struct {
+		dst *lipgloss.Color
+		src lipgloss.Color
+	}{
+		{&t.Primary, o.Primary},
+		{&t.Secondary, o.Secondary},
+		{&t.Accent, o.Accent},
+		{&t.Muted, o.Muted},
+		{&t.Subtle, o.Subtle},
+		{&t.Faint, o.Faint},
+		{&t.Success, o.Success},
+		{&t.Text, o.Text},
+		{&t.Label, o.Label},
+		{&t.Bg, o.Bg},
+		{&t.BgAlt, o.BgAlt},
+		{&t.Border, o.Border},
+	} {
+		if c.src != "" {
+			*c.dst = c.src
+		}
+	}
+}
+
+// BoxBorder returns the border boxes are drawn with. A plain theme keeps
+// the spacing of the border but draws nothing.
+func (t Theme) BoxBorder() lipgloss.Border {
+	if t.Plain {
+		return lipgloss.HiddenBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// Marked returns s for a selected item. A plain theme also puts a marker
+// in front of the item, since the color of s may not be seen.
+func (t Theme) Marked(s lipgloss.Style) lipgloss.Style {
+	if !t.Plain {
+		return s
+	}
+	return s.Transform(func(str string) string {
+		return "> " + str
+	})
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/tui/views"
+)
+
+// How long a toast stays in the status bar. Errors stay longer, as they
+// usually need reading.
+const (
+	toastDuration      = 3 * time.Second
+	toastErrorDuration = 6 * time.Second
+)
+
+// toastExpiredMsg clears the toast with the given id, unless a newer one
+// has replaced it.
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast puts msg in the status bar and returns the command that clears
+// it again.
+func (m *AppModel) showToast(msg views.ToastMsg) tea.Cmd {
+	m.toast = msg
+	m.toastID++
+
+	id := m.toastID
+	d := toastDuration
+	if msg.Level == views.ToastError {
+		d = toastErrorDuration
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// renderToast renders the current toast as the text of the status bar.
+func (m AppModel) renderToast() string {
+	text := m.toast.Text
+	switch m.toast.Level {
+	case views.ToastError:
+		if current.Plain {
+			text = "error: " + text
+		}
+		return StatusBarWarnStyle.Render(text)
+	case views.ToastSuccess:
+		return StatusBarOKStyle.Render(text)
+	}
+	return StatusBarStyle.Render(text)
+}
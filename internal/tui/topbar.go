@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// compactWidth is the terminal width below which the app switches to its
+// compact layout: the sidebar becomes a bar across the top, so the views
+// get the whole width and their boxes no longer wrap.
+const compactWidth = 70
+
+// topBarHeight is how many lines the top bar of the compact layout takes.
+const topBarHeight = 2
+
+// headerHeight returns how many lines above the content the top bar
+// takes, which is none outside the compact layout.
+func (m AppModel) headerHeight() int {
+	if m.compact {
+		return topBarHeight
+	}
+	return 0
+}
+
+// topBarEntries renders the menu items of the top bar, each as its
+// shortcut and icon.
+func (m AppModel) topBarEntries() []string {
+	var entries []string
+	for i, item := range m.menuItems {
+		entries = append(entries, m.menuItemStyle(i).Render(item.Shortcut+" "+item.Icon))
+	}
+	return entries
+}
+
+// renderTopBar renders the menu of the compact layout on one line, the
+// current view named at its end, over a divider.
+func (m AppModel) renderTopBar() string {
+	line := strings.Join(m.topBarEntries(), "")
+	label := " " + HelpStyle.Render(m.menuItems[m.selectedMenu].Label)
+	if lipgloss.Width(line+label) <= m.width {
+		line += label
+	}
+	line = ansi.Truncate(line, m.width, "…")
+
+	divider := strings.Repeat("─", m.width)
+	if current.Plain {
+		divider = ""
+	}
+	return line + "\n" + DividerStyle.Render(divider)
+}
+
+// topBarItemAt returns the index of the menu item at column x of the top
+// bar, or -1 if there is none.
+func (m AppModel) topBarItemAt(x int) int {
+	col := 0
+	for i, entry := range m.topBarEntries() {
+		width := lipgloss.Width(entry)
+		if x >= col && x < col+width {
+			return i
+		}
+		col += width
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// tourStep is one card of the onboarding tour, shown over the view it
+// explains.
+type tourStep struct {
+	title string
+	body  string
+	view  ViewType
+	tab   int // Settings tab to show
+}
+
+// tourSteps walks a new user through the method and the main views.
+var tourSteps = []tourStep{
+	{
+		title: "Welcome to HMM",
+		body: "The Hanzi Movie Method turns every character into a short movie scene: " +
+			"an actor, in a set, in one of its rooms, with props. This tour shows how " +
+			"the pieces fit together and where to find them.",
+		view: ViewLookup,
+	},
+	{
+		title: "Actors",
+		body: "An actor stands for the initial of a reading (b-, zh-, …): the same " +
+			"person plays in every scene that starts with that sound. Pick people you " +
+			"know well and list them in actors.yaml.",
+		view: ViewSettings,
+		tab:  0,
+	},
+	{
+		title: "Sets and rooms",
+		body: "A set stands for the final (-an, -ing, …), a place the scene happens. " +
+			"Each of its rooms stands for a tone, so the same set covers mā, má, mǎ, " +
+			"and mà. Sets live in sets.yaml.",
+		view: ViewSettings,
+		tab:  1,
+	},
+	{
+		title: "Props",
+		body: "A prop stands for a component of the character (木, 口, 氵, …), an " +
+			"object the actor handles in the scene. Props live in props.yaml; " +
+			"`hmm stats --props` shows which ones your deck uses most.",
+		view: ViewSettings,
+		tab:  2,
+	},
+	{
+		title: "Look up a character",
+		body: "Type a character in Lookup and press enter to see its reading, " +
+			"actor, set, room, and props, and the look-alikes not to confuse it with.",
+		view: ViewLookup,
+	},
+	{
+		title: "Load a deck",
+		body: "Open Deck loads an Anki .apkg file; Browse then shows its cards with " +
+			"their breakdowns. You can also start with one: `hmm browse deck.apkg`.",
+		view: ViewFilePicker,
+	},
+	{
+		title: "Generate a prompt",
+		body: "In Lookup or Browse, g turns the breakdown into a scene prompt (with " +
+			"an LLM if ANTHROPIC_API_KEY is set), y copies it, and e edits it. " +
+			"B and D generate drafts for a whole deck, to approve in Review.",
+		view: ViewBrowse,
+	},
+	{
+		title: "That's it",
+		body: "Press ? for every key in every view. To take this tour again, press " +
+			"t in the help.",
+		view: ViewLookup,
+	},
+}
+
+// startTour shows the first card of the onboarding tour.
+func (m *AppModel) startTour() {
+	if !m.touring {
+		m.tourFrom = m.currentView
+	}
+	m.touring = true
+	m.tourStep = 0
+	m.resumeOffer = nil
+	m.showTourStep()
+}
+
+// showTourStep switches to the view the current tour card explains.
+func (m *AppModel) showTourStep() {
+	step := tourSteps[m.tourStep]
+	if step.view == ViewSettings {
+		m.settingsView.SetTab(step.tab)
+	}
+	m.showView(step.view)
+	m.sidebarActive = false
+	m.resizeViews()
+}
+
+// answerTour moves through the tour for key, and ends it when the last
+// card is passed or the user skips it, back in the view it started from.
+// Either way, it is not offered again.
+func (m *AppModel) answerTour(key string) {
+	switch key {
+	case "enter", " ", "right", "l", "n":
+		if m.tourStep < len(tourSteps)-1 {
+			m.tourStep++
+			m.showTourStep()
+			return
+		}
+	case "left", "h", "p", "backspace":
+		if m.tourStep > 0 {
+			m.tourStep--
+			m.showTourStep()
+		}
+		return
+	case "esc", "q":
+	default:
+		return
+	}
+	m.touring = false
+	m.tourSeen = true
+	m.showView(m.tourFrom)
+	m.resizeViews()
+	m.saveUIState()
+}
+
+// renderTour renders the current card of the onboarding tour.
+func (m AppModel) renderTour() string {
+	step := tourSteps[m.tourStep]
+	title := fmt.Sprintf("%s (%d/%d)", step.title, m.tourStep+1, len(tourSteps))
+	keys := "enter: next • ←: back • esc: skip the tour"
+	if m.tourStep == len(tourSteps)-1 {
+		keys = "enter: finish • ←: back"
+	}
+	banner := title + "\n\n" + step.body + "\n\n" + HelpStyle.Render(keys)
+	return BannerStyle.Width(m.contentWidth()-6).Render(banner) + "\n\n"
+}
@@ -9,122 +9,151 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
 	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
-	"github.com/f3rmion/hmm/internal/hmm"
 	"github.com/f3rmion/hmm/internal/llm"
-	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 	"github.com/mattn/go-runewidth"
 )
 
 // Styles
 var (
+	titleStyle    lipgloss.Style
+	subtitleStyle lipgloss.Style
+
+	// Character tab styles
+	charTabStyle       lipgloss.Style
+	charTabActiveStyle lipgloss.Style
+	charTabPinyinStyle lipgloss.Style
+	characterStyle     lipgloss.Style
+	labelStyle         lipgloss.Style
+	valueStyle         lipgloss.Style
+	actorStyle         lipgloss.Style
+	setStyle           lipgloss.Style
+	propStyle          lipgloss.Style
+	toneStyle          lipgloss.Style
+	promptBoxStyle     lipgloss.Style
+	helpStyle          lipgloss.Style
+	errorStyle         lipgloss.Style
+	boxStyle           lipgloss.Style
+	dividerStyle       lipgloss.Style
+	wordNavStyle       lipgloss.Style
+	wordDisplayStyle   lipgloss.Style
+	llmPromptStyle     lipgloss.Style
+	loadingStyle       lipgloss.Style
+	copiedStyle        lipgloss.Style
+)
+
+// setModelStyles builds the styles of the single-view Model from t.
+func setModelStyles(t theme.Theme) {
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF6B6B")).
-			Background(lipgloss.Color("#1a1a2e")).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(t.Primary).
+		Background(t.Bg).
+		Padding(0, 1)
 
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4"))
+		Foreground(t.Secondary)
 
 	// Character tab styles
 	charTabStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Padding(0, 2).
-			Margin(0, 1)
+		Foreground(t.Subtle).
+		Padding(0, 2).
+		Margin(0, 1)
 
-	charTabActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#ffe66d")).
-				Background(lipgloss.Color("#2d3436")).
-				Padding(0, 2).
-				Margin(0, 1)
+	charTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(0, 2).
+		Margin(0, 1))
 
 	charTabPinyinStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666")).
-				Italic(true)
+		Foreground(t.Muted).
+		Italic(true)
 
 	characterStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ffe66d")).
-			Background(lipgloss.Color("#2d3436")).
-			Padding(1, 4).
-			Margin(1, 0)
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(1, 4).
+		Margin(1, 0)
 
 	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8dadc")).
-			Bold(true).
-			Width(12)
+		Foreground(t.Label).
+		Bold(true).
+		Width(12)
 
 	valueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
 	actorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff6b6b")).
-			Bold(true)
+		Foreground(t.Primary).
+		Bold(true)
 
 	setStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true)
+		Foreground(t.Secondary).
+		Bold(true)
 
 	propStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffe66d")).
-			Bold(true)
+		Foreground(t.Accent).
+		Bold(true)
 
 	toneStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8e6cf")).
-			Bold(true)
+		Foreground(t.Success).
+		Bold(true)
 
 	promptBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#4ecdc4")).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Secondary).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666"))
+		Foreground(t.Muted)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff6b6b")).
-			Bold(true)
+		Foreground(t.Primary).
+		Bold(true)
 
 	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#3d5a80")).
-			Padding(1, 2)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Border).
+		Padding(1, 2)
 
 	dividerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#3d5a80"))
+		Foreground(t.Border)
 
 	wordNavStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Padding(0, 1)
 
 	wordDisplayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#ffe66d")).
-				Padding(0, 2).
-				Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 2).
+		Margin(1, 0)
 
 	llmPromptStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#ff6b6b")).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	loadingStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffe66d")).
-			Bold(true).
-			Italic(true)
+		Foreground(t.Accent).
+		Bold(true).
+		Italic(true)
 
 	copiedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8e6cf")).
-			Bold(true)
-)
+		Foreground(t.Success).
+		Bold(true)
+}
 
 // LLM generation messages
 type llmResultMsg struct {
@@ -144,13 +173,12 @@ func clearCopiedAfter(d time.Duration) tea.Cmd {
 // Model is the Bubble Tea model for the HMM TUI.
 type Model struct {
 	input     textinput.Model
-	parser    *pinyin.Parser
-	dict      *decomp.Dictionary
+	analyzer  *analyze.Analyzer
 	generator *prompt.Generator
 	config    *config.Config
 
 	// Multi-character support
-	characters []CharacterResult
+	characters []analyze.CharacterResult
 	selected   int // Currently selected character index
 	inputText  string
 
@@ -171,25 +199,6 @@ type Model struct {
 	ready  bool
 }
 
-// CharacterResult holds the analysis of a character.
-type CharacterResult struct {
-	Character  string
-	Pinyin     string
-	Meaning    string
-	Decomp     string
-	Components []string
-	Etymology  string
-	Initial    string
-	Final      string
-	Tone       hmm.Tone
-	ActorID    string
-	ActorName  string
-	SetID      string
-	SetName    string
-	ToneRoom   string
-	PropNames  []string
-}
-
 // New creates a new TUI model.
 func New(dict *decomp.Dictionary, cfg *config.Config) Model {
 	ti := textinput.New()
@@ -197,8 +206,8 @@ func New(dict *decomp.Dictionary, cfg *config.Config) Model {
 	ti.Focus()
 	ti.CharLimit = 50
 	ti.Width = 40
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ecdc4"))
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffe66d"))
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(ColorSecondary)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(ColorAccent)
 
 	var gen *prompt.Generator
 	if cfg != nil {
@@ -212,8 +221,7 @@ func New(dict *decomp.Dictionary, cfg *config.Config) Model {
 
 	return Model{
 		input:     ti,
-		parser:    pinyin.NewParser(),
-		dict:      dict,
+		analyzer:  analyze.New(dict, gen),
 		generator: gen,
 		config:    cfg,
 		llmClient: llmClient,
@@ -344,18 +352,7 @@ func (m *Model) analyzeInput() {
 	m.err = nil
 
 	// Extract and analyze each Chinese character
-	for _, r := range input {
-		// Skip non-Chinese characters
-		if r < 0x4E00 || r > 0x9FFF {
-			continue
-		}
-
-		char := string(r)
-		result := m.analyzeChar(char)
-		if result != nil {
-			m.characters = append(m.characters, *result)
-		}
-	}
+	m.characters = m.analyzer.Text(input)
 
 	if len(m.characters) == 0 {
 		m.err = fmt.Errorf("no Chinese characters found in: %s", input)
@@ -365,63 +362,6 @@ func (m *Model) analyzeInput() {
 	m.updatePrompt()
 }
 
-// analyzeChar analyzes a single character.
-func (m *Model) analyzeChar(char string) *CharacterResult {
-	readings := m.parser.ParseChar(char)
-	if len(readings) == 0 {
-		return nil
-	}
-
-	reading := readings[0]
-
-	result := &CharacterResult{
-		Character: char,
-		Pinyin:    reading.Full,
-		Initial:   reading.Initial,
-		Final:     reading.Final,
-		Tone:      reading.Tone,
-		ActorID:   pinyin.GetActorID(reading.Initial),
-		SetID:     pinyin.GetSetID(reading.Final),
-	}
-
-	// Get dictionary info
-	if m.dict != nil {
-		if entry := m.dict.Lookup(char); entry != nil {
-			result.Meaning = entry.Definition
-			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
-			result.Components = decomp.ExtractComponents(entry.Decomposition)
-			if entry.Etymology != nil {
-				if entry.Etymology.Hint != "" {
-					result.Etymology = entry.Etymology.Hint
-				} else {
-					result.Etymology = entry.Etymology.Type
-				}
-			}
-		}
-	}
-
-	// Get actor/set info from config
-	if actor := m.generator.GetActor(result.ActorID); actor != nil {
-		result.ActorName = actor.Name
-	}
-	if set := m.generator.GetSet(result.SetID); set != nil {
-		result.SetName = set.Name
-	}
-	result.ToneRoom = m.generator.GetToneRoom(
-		m.generator.GetSet(result.SetID),
-		reading.Tone,
-	)
-
-	// Get prop names
-	for _, comp := range result.Components {
-		if p := m.generator.GetProp(comp); p != nil && p.Name != "" {
-			result.PropNames = append(result.PropNames, p.Name)
-		}
-	}
-
-	return result
-}
-
 // updatePrompt generates the prompt for the current selection.
 func (m *Model) updatePrompt() {
 	if m.selected >= len(m.characters) {
@@ -429,18 +369,7 @@ func (m *Model) updatePrompt() {
 	}
 
 	r := m.characters[m.selected]
-	sceneData := m.generator.BuildSceneData(
-		r.Character,
-		r.Pinyin,
-		r.ActorID,
-		r.SetID,
-		r.Tone,
-		r.Components,
-		r.Meaning,
-		r.Etymology,
-		r.Decomp,
-	)
-	if p, err := m.generator.Generate(sceneData); err == nil {
+	if p, err := m.analyzer.Prompt(r); err == nil {
 		m.prompt = p
 	}
 }
@@ -454,46 +383,7 @@ func (m *Model) generateLLMPrompt() tea.Cmd {
 	r := m.characters[m.selected]
 	client := m.llmClient
 
-	// Build elements for LLM
-	elements := llm.SceneElements{
-		Character: r.Character,
-		Pinyin:    r.Pinyin,
-		Meaning:   r.Meaning,
-		ActorName: r.ActorName,
-		SetName:   r.SetName,
-		ToneRoom:  r.ToneRoom,
-		Props:     r.PropNames,
-	}
-
-	// Get descriptions from config
-	if m.config != nil {
-		for _, a := range m.config.Actors {
-			if a.ID == r.ActorID {
-				elements.ActorDesc = a.Description
-				break
-			}
-		}
-		for _, s := range m.config.Sets {
-			if s.ID == r.SetID {
-				elements.SetDesc = s.Description
-				for _, room := range s.Rooms {
-					if hmm.Tone(room.Tone) == r.Tone {
-						elements.ToneRoomDesc = room.Description
-						break
-					}
-				}
-				break
-			}
-		}
-		for _, comp := range r.Components {
-			for _, p := range m.config.Props {
-				if p.ID == comp || p.Component == comp {
-					elements.PropDescs = append(elements.PropDescs, p.Description)
-					break
-				}
-			}
-		}
-	}
+	elements := m.analyzer.SceneElements(r)
 
 	return func() tea.Msg {
 		prompt, err := client.GenerateScene(elements)
@@ -602,7 +492,7 @@ func (m Model) renderWordBar() string {
 }
 
 // renderCharacterDetail renders the detailed view for a single character.
-func (m Model) renderCharacterDetail(r CharacterResult) string {
+func (m Model) renderCharacterDetail(r analyze.CharacterResult) string {
 	var b strings.Builder
 
 	// Big character display (only if single char or want emphasis)
@@ -615,14 +505,11 @@ func (m Model) renderCharacterDetail(r CharacterResult) string {
 	// Pinyin and meaning
 	b.WriteString(m.renderRow("Pinyin", r.Pinyin))
 	if r.Meaning != "" {
-		meaning := r.Meaning
 		maxLen := 60
 		if m.width > 0 {
 			maxLen = m.width - 20
 		}
-		if len(meaning) > maxLen {
-			meaning = meaning[:maxLen] + "..."
-		}
+		meaning := textutil.Truncate(r.Meaning, maxLen)
 		b.WriteString(m.renderRow("Meaning", meaning))
 	}
 	b.WriteString("\n")
@@ -686,7 +573,7 @@ func (m Model) renderRow(label, value string) string {
 }
 
 // renderHMMBox renders the HMM breakdown in a nice box.
-func (m Model) renderHMMBox(r CharacterResult) string {
+func (m Model) renderHMMBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	// Initial → Actor
@@ -728,7 +615,7 @@ func (m Model) renderHMMBox(r CharacterResult) string {
 }
 
 // renderComponentsBox renders the components/props box.
-func (m Model) renderComponentsBox(r CharacterResult) string {
+func (m Model) renderComponentsBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	for i, comp := range r.Components {
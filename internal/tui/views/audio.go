@@ -0,0 +1,40 @@
+package views
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/audio"
+)
+
+// audioFor returns the recording to play for char: the sound of its note
+// in the deck if there is one, else its recording in the audio cache.
+func audioFor(pkg *anki.Package, note *anki.Note, char string) string {
+	if pkg != nil && note != nil {
+		if path := pkg.NoteAudio(note); path != "" {
+			return path
+		}
+	}
+	return audio.Cached(char)
+}
+
+// playAudio returns a command that plays the recording at path, and
+// reports it if it could not be played.
+func playAudio(path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := audio.Play(path); err != nil {
+			return toast(ToastError, "Could not play audio: %v", err)()
+		}
+		return nil
+	}
+}
+
+// speaker marks pinyin whose pronunciation can be played.
+func speaker(path string) string {
+	if path == "" {
+		return ""
+	}
+	if palette.Plain {
+		return " [audio]"
+	}
+	return " 🔊"
+}
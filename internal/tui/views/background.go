@@ -0,0 +1,17 @@
+package views
+
+// BackgroundMsg is the result of work a view started in the background,
+// such as an LLM request. The app hands it to every view rather than just
+// the one on screen, so results are not lost when the user moves on
+// before they arrive.
+type BackgroundMsg interface {
+	background()
+}
+
+func (llmResultMsg) background()         {}
+func (bigCharRenderedMsg) background()   {}
+func (browseLLMResultMsg) background()   {}
+func (browseBatchResultMsg) background() {}
+func (deckBatchResultMsg) background()   {}
+func (learnLLMResultMsg) background()    {}
+func (reviewLLMResultMsg) background()   {}
@@ -2,95 +2,120 @@ package views
 
 import (
 	"fmt"
-	"regexp"
+	"log/slog"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
 	"github.com/f3rmion/hmm/internal/anki"
 	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
-	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
 	"github.com/f3rmion/hmm/internal/llm"
 	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/textutil"
 	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 )
 
 // Browse view styles (reuse from lookup)
 var (
+	browseCharTabStyle       lipgloss.Style
+	browseCharTabActiveStyle lipgloss.Style
+	browseCharTabPinyinStyle lipgloss.Style
+	browseBigCharStyle       lipgloss.Style
+	browsePinyinUnderStyle   lipgloss.Style
+	browseWordNavStyle       lipgloss.Style
+	browseWordDisplayStyle   lipgloss.Style
+	browseCardCountStyle     lipgloss.Style
+	browseFieldLabelStyle    lipgloss.Style
+	browseFieldValueStyle    lipgloss.Style
+	browseSearchBoxStyle     lipgloss.Style
+	browseNoDataStyle        lipgloss.Style
+)
+
+// setBrowseStyles builds the Browse view styles from t.
+func setBrowseStyles(t theme.Theme) {
 	browseCharTabStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
-				Padding(0, 2).
-				Margin(0, 1)
+		Foreground(t.Subtle).
+		Padding(0, 2).
+		Margin(0, 1)
 
-	browseCharTabActiveStyle = lipgloss.NewStyle().
-					Bold(true).
-					Foreground(lipgloss.Color("#ffe66d")).
-					Background(lipgloss.Color("#2d3436")).
-					Padding(0, 2).
-					Margin(0, 1)
+	browseCharTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(0, 2).
+		Margin(0, 1))
 
 	browseCharTabPinyinStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#666666")).
-					Italic(true)
+		Foreground(t.Muted).
+		Italic(true)
 
 	browseBigCharStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#ffe66d")).
-				Background(lipgloss.Color("#1a1a2e")).
-				Padding(3, 12).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.Bg).
+		Padding(3, 12).
+		Align(lipgloss.Center)
 
 	browsePinyinUnderStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ecdc4")).
-				Bold(true).
-				Align(lipgloss.Center).
-				Padding(0, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Align(lipgloss.Center).
+		Padding(0, 1)
 
 	browseWordNavStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ecdc4")).
-				Bold(true).
-				Padding(0, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Padding(0, 1)
 
 	browseWordDisplayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#ffe66d")).
-				Padding(0, 2).
-				Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 2).
+		Margin(1, 0)
 
 	browseCardCountStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
-				Padding(0, 1)
+		Foreground(t.Subtle).
+		Padding(0, 1)
 
 	browseFieldLabelStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888"))
+		Foreground(t.Subtle)
 
 	browseFieldValueStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
 	browseSearchBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#ffe66d")).
-				Padding(0, 1)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1)
 
 	browseNoDataStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666")).
-				Italic(true).
-				Align(lipgloss.Center)
-)
+		Foreground(t.Muted).
+		Italic(true).
+		Align(lipgloss.Center)
+}
 
 // Message types for browse view
 type browseLLMResultMsg struct {
-	prompt string
-	err    error
+	key       promptKey
+	prompt    string
+	err       error
+	variation bool // A different take on the prompt shown when it was asked for
 }
 
 type browseBatchResultMsg struct {
-	index  int
+	key    promptKey
+	char   analyze.CharacterResult
 	prompt string
 	err    error
 }
@@ -106,39 +131,61 @@ func browseClearCopiedAfter(d time.Duration) tea.Cmd {
 // BrowseModel is the Anki deck browser view model.
 type BrowseModel struct {
 	pkg       *anki.Package
+	analyzer  *analyze.Analyzer
 	parser    *pinyin.Parser
 	dict      *decomp.Dictionary
 	generator *prompt.Generator
 	config    *config.Config
+	scenes    *scene.Store
 
 	// Card navigation
 	notes         []*anki.Note
 	filteredNotes []*anki.Note
 	currentNote   int
+	jumpInput     textinput.Model
+	jumping       bool
+	sort          browseSort
+	sortNote      string // Why the sort could not be applied
 
 	// Character navigation
-	characters []components.CharacterResult
+	characters []analyze.CharacterResult
 	selected   int
+	analyzed   *noteCache
 
 	// Search
 	searchInput textinput.Model
 	searching   bool
 	searchTerm  string
 
+	// The other decks open in the workspace, and how many of their cards
+	// match the search
+	others    []otherDeck
+	elsewhere []deckMatches
+
 	// LLM
 	llmClient     *llm.Client
 	llmPrompt     string
 	llmGenerating bool
 	llmError      error
+	editor        promptEditor
+	takes         promptTakes
 
 	// Batch generation
-	charPrompts     map[int]string
+	charPrompts     map[promptKey]string
 	batchGenerating bool
 	batchTotal      int
 	batchCompleted  int
+	batchDrafts     int
+	batchFailures   map[int]error // Why characters of the note failed in the last batch, by index
+	deck            deckBatch
 
 	// Clipboard
-	copied bool
+	copied   bool
+	copyMenu copyMenu
+	copyNote string // What the copy menu last copied, shown until cleared
+
+	detail scrollPane
+	fields fieldPanel
 
 	// Display
 	chineseField string
@@ -147,30 +194,41 @@ type BrowseModel struct {
 }
 
 // NewBrowseModel creates a new browse view model.
-func NewBrowseModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client) BrowseModel {
+func NewBrowseModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client, scenes *scene.Store) BrowseModel {
 	si := textinput.New()
-	si.Placeholder = "Search..."
+	si.Placeholder = "Search... (tone:3 tag:hsk2)"
 	si.CharLimit = 50
 	si.Width = 30
 
+	ji := textinput.New()
+	ji.Placeholder = "card number"
+	ji.CharLimit = 6
+	ji.Width = 12
+
 	return BrowseModel{
+		analyzer:    analyze.New(dict, gen),
 		parser:      pinyin.NewParser(),
 		dict:        dict,
 		generator:   gen,
 		config:      cfg,
+		scenes:      scenes,
 		searchInput: si,
+		jumpInput:   ji,
 		llmClient:   llmClient,
-		charPrompts: make(map[int]string),
+		charPrompts: make(map[promptKey]string),
+		analyzed:    newNoteCache(noteCacheSize),
 	}
 }
 
 // SetPackage sets the Anki package to browse.
 func (m *BrowseModel) SetPackage(pkg *anki.Package) {
 	m.pkg = pkg
-	m.charPrompts = make(map[int]string)
+	m.charPrompts = make(map[promptKey]string)
+	m.analyzed = newNoteCache(noteCacheSize)
 	m.llmPrompt = ""
 	m.searchTerm = ""
 	m.searchInput.SetValue("")
+	m.elsewhere = nil
 
 	if pkg == nil {
 		m.notes = nil
@@ -186,13 +244,14 @@ func (m *BrowseModel) SetPackage(pkg *anki.Package) {
 	var notes []*anki.Note
 	for _, note := range pkg.Notes {
 		value := pkg.GetFieldValue(note, m.chineseField)
-		if containsChineseChars(value) {
+		if analyze.ContainsChinese(value) {
 			notes = append(notes, note)
 		}
 	}
 
 	m.notes = notes
 	m.filteredNotes = notes
+	m.sortNotes()
 	m.currentNote = 0
 
 	if len(notes) > 0 {
@@ -215,8 +274,40 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.editor.active {
+		saved, cmd := m.editor.update(msg)
+		if saved {
+			m.keepEditedPrompt(m.editor.value())
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m, cmd
+		}
+		cmds = append(cmds, cmd)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.copyMenu.active {
+			note, err := m.copyMenu.choose(msg.String(), m.characters[m.selected], m.llmPrompt)
+			if err != nil {
+				return m, clipboardToast(err)
+			}
+			if note != "" {
+				m.copyNote = note
+				return m, browseClearCopiedAfter(2 * time.Second)
+			}
+			return m, nil
+		}
+
+		m.deck.summary = ""
+		switch {
+		case m.deck.state == deckBatchConfirming:
+			return m.updateDeckBatch(msg.String())
+		case m.deck.active() && !m.searching && !m.jumping && (msg.String() == "p" || msg.String() == "x"):
+			// Typed into search or the note number, p and x are letters
+			return m.updateDeckBatch(msg.String())
+		}
+
 		if m.searching {
 			switch msg.String() {
 			case "enter":
@@ -235,45 +326,67 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 			}
 		}
 
+		if m.jumping {
+			switch msg.String() {
+			case "enter":
+				n, err := strconv.Atoi(strings.TrimSpace(m.jumpInput.Value()))
+				if err != nil {
+					return m, nil
+				}
+				m.jumping = false
+				m.jumpInput.SetValue("")
+				m.goToNote(n - 1)
+				return m, nil
+			case "esc":
+				m.jumping = false
+				m.jumpInput.SetValue("")
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.jumpInput, cmd = m.jumpInput.Update(msg)
+				return m, cmd
+			}
+		}
+
 		switch msg.String() {
-		case "up", "k":
+		case "up":
 			if m.currentNote > 0 {
-				m.currentNote--
-				m.loadCurrentNote()
-				m.llmPrompt = ""
-				m.llmError = nil
+				m.goToNote(m.currentNote - 1)
 			}
 			return m, nil
-		case "down", "j":
+		case "down":
 			if m.currentNote < len(m.filteredNotes)-1 {
-				m.currentNote++
-				m.loadCurrentNote()
-				m.llmPrompt = ""
-				m.llmError = nil
+				m.goToNote(m.currentNote + 1)
+			}
+			return m, nil
+		case ":":
+			if len(m.filteredNotes) > 1 {
+				m.jumping = true
+				m.jumpInput.Focus()
+				return m, textinput.Blink
+			}
+			return m, nil
+		case "G":
+			if m.currentNote < len(m.filteredNotes)-1 {
+				m.goToNote(len(m.filteredNotes) - 1)
 			}
 			return m, nil
 		case "left", "h":
 			if len(m.characters) > 0 && m.selected > 0 {
 				m.selected--
-				if p, ok := m.charPrompts[m.selected]; ok {
-					m.llmPrompt = p
-				} else {
-					m.llmPrompt = ""
-				}
-				m.llmError = nil
+				m.showPrompt()
 			}
 			return m, nil
 		case "right", "l":
 			if len(m.characters) > 0 && m.selected < len(m.characters)-1 {
 				m.selected++
-				if p, ok := m.charPrompts[m.selected]; ok {
-					m.llmPrompt = p
-				} else {
-					m.llmPrompt = ""
-				}
-				m.llmError = nil
+				m.showPrompt()
 			}
 			return m, nil
+		case "j", "k", "pgup", "pgdown":
+			m.layoutDetail()
+			m.detail.scroll(msg.String())
+			return m, nil
 		case "/":
 			m.searching = true
 			m.searchInput.Focus()
@@ -281,11 +394,14 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		case "c":
 			m.searchTerm = ""
 			m.searchInput.SetValue("")
-			m.filteredNotes = m.notes
-			m.currentNote = 0
-			if len(m.filteredNotes) > 0 {
-				m.loadCurrentNote()
-			}
+			m.applyFilter()
+			return m, nil
+		case "o":
+			m.sort = m.sort.next()
+			m.resort()
+			return m, nil
+		case "f":
+			m.fields = m.fields.next()
 			return m, nil
 		case "g":
 			if len(m.characters) > 0 && !m.llmGenerating {
@@ -295,15 +411,48 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 				}
 				m.llmGenerating = true
 				m.llmError = nil
-				return m, m.generateLLMPrompt()
+				return m, m.generateLLMPrompt(nil)
 			}
 			return m, nil
 		case "y":
 			if m.llmPrompt != "" {
-				if err := clipboard.Write(m.llmPrompt); err == nil {
-					m.copied = true
-					return m, browseClearCopiedAfter(2 * time.Second)
+				if err := clipboard.Write(m.llmPrompt); err != nil {
+					return m, clipboardToast(err)
 				}
+				m.copied = true
+				return m, browseClearCopiedAfter(2 * time.Second)
+			}
+			return m, nil
+		case "v":
+			if path := m.currentAudio(); path != "" {
+				return m, playAudio(path)
+			}
+			return m, nil
+		case "Y":
+			if m.selected < len(m.characters) {
+				m.copyMenu.active = true
+			}
+			return m, nil
+		case "R":
+			if m.llmPrompt != "" && !m.llmGenerating && !m.batchGenerating && m.llmClient != nil {
+				m.llmGenerating = true
+				m.llmError = nil
+				return m, m.generateLLMPrompt(m.takes.all(m.llmPrompt))
+			}
+			return m, nil
+		case ",", ".":
+			delta := 1
+			if msg.String() == "," {
+				delta = -1
+			}
+			if p, ok := m.takes.cycle(m.llmPrompt, delta); ok {
+				m.llmPrompt = p
+				m.charPrompts[m.charKey(m.selected)] = p
+			}
+			return m, nil
+		case "e":
+			if m.llmPrompt != "" && !m.llmGenerating {
+				return m, m.editor.open(m.llmPrompt, m.width, m.height-lipgloss.Height(m.renderHeader()))
 			}
 			return m, nil
 		case "B":
@@ -312,12 +461,33 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 					m.llmError = fmt.Errorf("ANTHROPIC_API_KEY not set")
 					return m, nil
 				}
-				m.batchGenerating = true
-				m.batchTotal = len(m.characters)
-				m.batchCompleted = 0
-				m.llmError = nil
-				return m, m.generateBatchPrompts()
+				indexes := make([]int, len(m.characters))
+				for i := range indexes {
+					indexes[i] = i
+				}
+				return m, m.startBatch(indexes)
+			}
+			return m, nil
+		case "F":
+			if len(m.batchFailures) > 0 && !m.batchGenerating && !m.llmGenerating && m.llmClient != nil {
+				indexes := make([]int, 0, len(m.batchFailures))
+				for i := range m.batchFailures {
+					indexes = append(indexes, i)
+				}
+				sort.Ints(indexes)
+				return m, m.startBatch(indexes)
+			}
+			return m, nil
+		case "D":
+			if m.deck.active() || m.scenes == nil {
+				return m, nil
+			}
+			if m.llmClient == nil {
+				m.llmError = fmt.Errorf("ANTHROPIC_API_KEY not set")
+				return m, nil
 			}
+			m.llmError = nil
+			m.planDeckBatch()
 			return m, nil
 		}
 
@@ -326,113 +496,215 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		if msg.err != nil {
 			m.llmError = msg.err
 		} else {
-			m.llmPrompt = msg.prompt
-			m.charPrompts[m.selected] = msg.prompt
+			m.charPrompts[msg.key] = msg.prompt
+			// The user may have moved on to another character meanwhile
+			if msg.key == m.charKey(m.selected) {
+				if msg.variation {
+					m.takes.add(m.llmPrompt, msg.prompt)
+				}
+				m.llmPrompt = msg.prompt
+			}
 		}
-		return m, nil
+		return m, llmToast(msg.err)
+
+	case deckBatchResultMsg:
+		return m, m.handleDeckBatchResult(msg)
 
 	case browseBatchResultMsg:
-		m.batchCompleted++
+		// Results of a batch on a note since left only keep their prompt
+		current := msg.key.note == m.charKey(0).note
+		if current {
+			m.batchCompleted++
+			if msg.err != nil {
+				m.batchFailures[msg.key.index] = msg.err
+			} else {
+				delete(m.batchFailures, msg.key.index)
+			}
+		}
+		if msg.err != nil {
+			slog.Warn("batch prompt failed", "char", msg.char.Character, "error", msg.err)
+		}
 		if msg.err == nil && msg.prompt != "" {
-			m.charPrompts[msg.index] = msg.prompt
-			if msg.index == m.selected {
+			m.charPrompts[msg.key] = msg.prompt
+			if msg.key == m.charKey(m.selected) {
 				m.llmPrompt = msg.prompt
 			}
+			// Batch results are stored as drafts for review
+			if m.scenes != nil && m.scenes.PutDraft(msg.char.Scene(msg.prompt)) {
+				m.batchDrafts++
+			}
 		}
-		if m.batchCompleted >= m.batchTotal {
+		if current && m.batchCompleted >= m.batchTotal && m.batchGenerating {
 			m.batchGenerating = false
-			if p, ok := m.charPrompts[m.selected]; ok {
+			if p, ok := m.charPrompts[m.charKey(m.selected)]; ok {
 				m.llmPrompt = p
 			}
+			if m.scenes != nil && m.batchDrafts > 0 {
+				if err := m.scenes.Save(); err != nil {
+					m.llmError = err
+					return m, toast(ToastError, "Could not save batch drafts: %v", err)
+				}
+			}
+			return m, m.batchToast()
 		}
-		return m, nil
+		return m, llmToast(msg.err)
 
 	case browseClearCopiedMsg:
 		m.copied = false
+		m.copyNote = ""
 		return m, nil
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// goToNote shows the filtered note at index i, clamped to the notes
+// there are.
+func (m *BrowseModel) goToNote(i int) {
+	if len(m.filteredNotes) == 0 {
+		return
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i > len(m.filteredNotes)-1 {
+		i = len(m.filteredNotes) - 1
+	}
+	if i == m.currentNote {
+		return
+	}
+	m.currentNote = i
+	m.loadCurrentNote()
+}
+
+// Shown returns the character of the card selected, or nil if there is
+// none.
+func (m BrowseModel) Shown() *analyze.CharacterResult {
+	if m.currentNote >= len(m.filteredNotes) || m.selected >= len(m.characters) {
+		return nil
+	}
+	return &m.characters[m.selected]
+}
+
+// loadCurrentNote analyzes the characters of the note shown, or takes them
+// from the cache, and shows the prompt of its first character if one was
+// generated earlier.
 func (m *BrowseModel) loadCurrentNote() {
 	if m.currentNote >= len(m.filteredNotes) {
 		return
 	}
 
 	note := m.filteredNotes[m.currentNote]
-	value := m.pkg.GetFieldValue(note, m.chineseField)
-	value = stripHTMLTags(value)
 
-	m.characters = nil
 	m.selected = 0
-	m.charPrompts = make(map[int]string)
 	m.batchGenerating = false
 	m.batchCompleted = 0
 	m.batchTotal = 0
+	m.batchDrafts = 0
+	m.batchFailures = nil
 
-	for _, r := range value {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			char := string(r)
-			result := m.analyzeChar(char)
-			if result != nil {
-				m.characters = append(m.characters, *result)
-			}
-		}
+	characters, ok := m.analyzed.get(note.ID)
+	if !ok {
+		characters = m.analyzer.Text(htmlutil.Text(m.pkg.GetFieldValue(note, m.chineseField)))
+		m.analyzed.put(note.ID, characters)
 	}
+	m.characters = characters
+	m.showPrompt()
 }
 
-func (m *BrowseModel) analyzeChar(char string) *components.CharacterResult {
-	readings := m.parser.ParseChar(char)
-	if len(readings) == 0 {
-		return nil
+// charKey returns the key of the prompt of character i of the note shown.
+func (m *BrowseModel) charKey(i int) promptKey {
+	key := promptKey{index: i}
+	if m.currentNote < len(m.filteredNotes) {
+		key.note = m.filteredNotes[m.currentNote].ID
 	}
+	return key
+}
 
-	reading := readings[0]
-
-	result := &components.CharacterResult{
-		Character: char,
-		Pinyin:    reading.Full,
-		Initial:   reading.Initial,
-		Final:     reading.Final,
-		Tone:      reading.Tone,
-		ActorID:   pinyin.GetActorID(reading.Initial),
-		SetID:     pinyin.GetSetID(reading.Final),
-	}
-
-	if m.dict != nil {
-		if entry := m.dict.Lookup(char); entry != nil {
-			result.Meaning = entry.Definition
-			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
-			result.Components = decomp.ExtractComponents(entry.Decomposition)
-			if entry.Etymology != nil {
-				if entry.Etymology.Hint != "" {
-					result.Etymology = entry.Etymology.Hint
-				} else {
-					result.Etymology = entry.Etymology.Type
-				}
-			}
-		}
-	}
+// showPrompt shows the prompt generated for the selected character, if
+// any.
+func (m *BrowseModel) showPrompt() {
+	m.llmPrompt = m.charPrompts[m.charKey(m.selected)]
+	m.llmError = nil
+}
 
-	if actor := m.generator.GetActor(result.ActorID); actor != nil {
-		result.ActorName = actor.Name
-	}
-	if set := m.generator.GetSet(result.SetID); set != nil {
-		result.SetName = set.Name
+// generatedCount returns how many characters of the note shown have a
+// generated prompt.
+func (m *BrowseModel) generatedCount() int {
+	n := 0
+	for i := range m.characters {
+		if _, ok := m.charPrompts[m.charKey(i)]; ok {
+			n++
+		}
 	}
-	result.ToneRoom = m.generator.GetToneRoom(
-		m.generator.GetSet(result.SetID),
-		reading.Tone,
-	)
+	return n
+}
 
-	for _, comp := range result.Components {
-		if p := m.generator.GetProp(comp); p != nil && p.Name != "" {
-			result.PropNames = append(result.PropNames, p.Name)
+// SelectCharacter selects the tab for char within the current card, if any.
+func (m *BrowseModel) SelectCharacter(char string) {
+	for i, c := range m.characters {
+		if c.Character == char && i != m.selected {
+			m.selected = i
+			m.showPrompt()
+			return
 		}
 	}
+}
+
+// Position returns the search, sort, and card shown, so a later session
+// can resume there with Restore.
+func (m BrowseModel) Position() (search, sort string, card int) {
+	return m.searchTerm, m.sort.String(), m.currentNote
+}
 
-	return result
+// Restore searches and sorts the deck as saved by Position and shows the
+// card at index card of the result.
+func (m *BrowseModel) Restore(search, sort string, card int) {
+	m.searchTerm = search
+	m.searchInput.SetValue(search)
+	m.sort = parseBrowseSort(sort)
+	m.applyFilter()
+	m.goToNote(card)
+}
+
+// Searching reports whether the search or go-to box has focus, so the
+// app should pass every key through.
+func (m BrowseModel) Searching() bool {
+	return m.searching || m.jumping
+}
+
+// Editing reports whether the prompt editor is open, so the app should
+// pass every key through.
+func (m BrowseModel) Editing() bool {
+	return m.editor.active
+}
+
+// CopyMenuOpen reports whether the copy menu is waiting for a key, so the
+// app should pass every key through.
+func (m BrowseModel) CopyMenuOpen() bool {
+	return m.copyMenu.active
+}
+
+// keepEditedPrompt replaces the selected character's prompt with an
+// edited one. A draft saved from the old prompt by batch generation is
+// updated too, so the edit is what gets reviewed.
+func (m *BrowseModel) keepEditedPrompt(edited string) {
+	old := m.llmPrompt
+	m.takes.replace(old, edited)
+	m.llmPrompt = edited
+	m.charPrompts[m.charKey(m.selected)] = edited
+
+	if m.scenes == nil || m.selected >= len(m.characters) {
+		return
+	}
+	sc := m.scenes.Get(m.characters[m.selected].Character)
+	if sc == nil || sc.IsApproved() || sc.ImagePrompt != old {
+		return
+	}
+	sc.ImagePrompt = edited
+	if err := m.scenes.Save(); err != nil {
+		m.llmError = err
+	}
 }
 
 func (m *BrowseModel) applyFilter() {
@@ -440,16 +712,16 @@ func (m *BrowseModel) applyFilter() {
 		m.filteredNotes = m.notes
 	} else {
 		m.filteredNotes = nil
-		term := strings.ToLower(m.searchTerm)
+		q := parseBrowseQuery(m.searchTerm)
+		src := newBrowseSource(m.pkg, m.chineseField)
 		for _, note := range m.notes {
-			for _, field := range note.Fields {
-				if strings.Contains(strings.ToLower(stripHTMLTags(field)), term) {
-					m.filteredNotes = append(m.filteredNotes, note)
-					break
-				}
+			if m.matchNote(note, q, src) {
+				m.filteredNotes = append(m.filteredNotes, note)
 			}
 		}
 	}
+	m.sortNotes()
+	m.countElsewhere()
 	m.currentNote = 0
 	if len(m.filteredNotes) > 0 {
 		m.loadCurrentNote()
@@ -458,52 +730,55 @@ func (m *BrowseModel) applyFilter() {
 	}
 }
 
-func (m *BrowseModel) generateLLMPrompt() tea.Cmd {
+// resort applies a new sort and stays on the card shown.
+func (m *BrowseModel) resort() {
+	var current *anki.Note
+	if m.currentNote < len(m.filteredNotes) {
+		current = m.filteredNotes[m.currentNote]
+	}
+	m.applyFilter()
+	for i, note := range m.filteredNotes {
+		if note == current {
+			m.goToNote(i)
+			break
+		}
+	}
+}
+
+// generateLLMPrompt asks the LLM for an image prompt. With earlier takes
+// in previous it asks for a different take instead.
+func (m *BrowseModel) generateLLMPrompt(previous []string) tea.Cmd {
 	if m.selected >= len(m.characters) || m.llmClient == nil {
 		return nil
 	}
 
-	r := m.characters[m.selected]
+	key := m.charKey(m.selected)
+	elements := m.analyzer.SceneElements(m.characters[m.selected])
 	client := m.llmClient
 
-	elements := llm.SceneElements{
-		Character: r.Character,
-		Pinyin:    r.Pinyin,
-		Meaning:   r.Meaning,
-		ActorName: r.ActorName,
-		SetName:   r.SetName,
-		ToneRoom:  r.ToneRoom,
-		Props:     r.PropNames,
-	}
-
-	if m.config != nil {
-		for _, a := range m.config.Actors {
-			if a.ID == r.ActorID {
-				elements.ActorDesc = a.Description
-				break
-			}
-		}
-		for _, s := range m.config.Sets {
-			if s.ID == r.SetID {
-				elements.SetDesc = s.Description
-				for _, room := range s.Rooms {
-					if hmm.Tone(room.Tone) == r.Tone {
-						elements.ToneRoomDesc = room.Description
-						break
-					}
-				}
-				break
-			}
-		}
-	}
-
 	return func() tea.Msg {
+		if len(previous) > 0 {
+			prompt, err := client.GenerateVariation(elements, previous)
+			return browseLLMResultMsg{key: key, prompt: prompt, err: err, variation: true}
+		}
 		prompt, err := client.GenerateScene(elements)
-		return browseLLMResultMsg{prompt: prompt, err: err}
+		return browseLLMResultMsg{key: key, prompt: prompt, err: err}
 	}
 }
 
-func (m *BrowseModel) generateBatchPrompts() tea.Cmd {
+// startBatch generates prompts for the characters of the note at indexes,
+// all of them with B or those that failed with F.
+func (m *BrowseModel) startBatch(indexes []int) tea.Cmd {
+	m.batchGenerating = true
+	m.batchTotal = len(indexes)
+	m.batchCompleted = 0
+	m.batchDrafts = 0
+	m.batchFailures = make(map[int]error)
+	m.llmError = nil
+	return m.generateBatchPrompts(indexes)
+}
+
+func (m *BrowseModel) generateBatchPrompts(indexes []int) tea.Cmd {
 	if len(m.characters) == 0 || m.llmClient == nil {
 		return nil
 	}
@@ -511,57 +786,44 @@ func (m *BrowseModel) generateBatchPrompts() tea.Cmd {
 	var cmds []tea.Cmd
 	client := m.llmClient
 
-	for i, r := range m.characters {
-		if _, exists := m.charPrompts[i]; exists {
+	for _, i := range indexes {
+		r := m.characters[i]
+		key := m.charKey(i)
+		if p, exists := m.charPrompts[key]; exists {
 			cmds = append(cmds, func() tea.Msg {
-				return browseBatchResultMsg{index: i, prompt: m.charPrompts[i], err: nil}
+				return browseBatchResultMsg{key: key, char: r, prompt: p, err: nil}
 			})
 			continue
 		}
 
-		idx := i
-		char := r
-
-		elements := llm.SceneElements{
-			Character: char.Character,
-			Pinyin:    char.Pinyin,
-			Meaning:   char.Meaning,
-			ActorName: char.ActorName,
-			SetName:   char.SetName,
-			ToneRoom:  char.ToneRoom,
-			Props:     char.PropNames,
-		}
-
-		if m.config != nil {
-			for _, a := range m.config.Actors {
-				if a.ID == char.ActorID {
-					elements.ActorDesc = a.Description
-					break
-				}
-			}
-			for _, s := range m.config.Sets {
-				if s.ID == char.SetID {
-					elements.SetDesc = s.Description
-					for _, room := range s.Rooms {
-						if hmm.Tone(room.Tone) == char.Tone {
-							elements.ToneRoomDesc = room.Description
-							break
-						}
-					}
-					break
-				}
-			}
-		}
+		elements := m.analyzer.SceneElements(r)
 
 		cmds = append(cmds, func() tea.Msg {
 			prompt, err := client.GenerateScene(elements)
-			return browseBatchResultMsg{index: idx, prompt: prompt, err: err}
+			return browseBatchResultMsg{key: key, char: r, prompt: prompt, err: err}
 		})
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// batchToast sums up a finished batch, which may have finished after the
+// user moved on to another view.
+func (m BrowseModel) batchToast() tea.Cmd {
+	if failed := llm.FailureSummary(m.batchFailures, m.batchTotal); failed != "" {
+		text := "Batch finished: " + failed
+		if m.batchDrafts > 0 {
+			text += fmt.Sprintf(", %d drafts saved for review", m.batchDrafts)
+		}
+		return toast(ToastError, "%s; F retries them", text)
+	}
+	text := fmt.Sprintf("Batch finished: %d of %d prompts", m.batchTotal, m.batchTotal)
+	if m.batchDrafts > 0 {
+		text += fmt.Sprintf(", %d drafts saved for review", m.batchDrafts)
+	}
+	return toast(ToastSuccess, "%s", text)
+}
+
 // View renders the browse view.
 func (m BrowseModel) View() string {
 	// No package loaded
@@ -569,6 +831,39 @@ func (m BrowseModel) View() string {
 		return m.renderNoPackage()
 	}
 
+	if m.editor.active {
+		return m.renderHeader() + m.editor.View()
+	}
+	m.layoutDetail()
+	return m.renderHeader() + m.detail.View() + m.renderHelp()
+}
+
+// ScrollDetail scrolls the card details down n lines, or up if n is
+// negative.
+func (m *BrowseModel) ScrollDetail(n int) {
+	if m.pkg == nil {
+		return
+	}
+	m.layoutDetail()
+	m.detail.scrollBy(n)
+}
+
+// layoutDetail fits the current card's details between the header and
+// the help line.
+func (m *BrowseModel) layoutDetail() {
+	var detail string
+	if m.currentNote >= len(m.filteredNotes) {
+		detail = helpStyle.Render("No cards match your search") + "\n"
+	} else if m.selected < len(m.characters) {
+		detail = m.renderCharacterDetail(m.characters[m.selected])
+	}
+	id := fmt.Sprintf("%s:%d:%d", m.searchTerm, m.currentNote, m.selected)
+	m.detail.fit(id, detail, m.width, m.height, m.renderHeader(), m.renderHelp())
+}
+
+// renderHeader renders the search bar, deck batch progress, card counter,
+// and character tabs.
+func (m BrowseModel) renderHeader() string {
 	var b strings.Builder
 
 	// Search bar
@@ -578,45 +873,89 @@ func (m BrowseModel) View() string {
 	} else if m.searchTerm != "" {
 		b.WriteString(helpStyle.Render(fmt.Sprintf("Filter: \"%s\" (press 'c' to clear)", m.searchTerm)))
 		b.WriteString("\n\n")
+		b.WriteString(m.renderElsewhere())
+	}
+	if m.jumping {
+		b.WriteString(browseSearchBoxStyle.Render("Go to card: " + m.jumpInput.View()))
+		b.WriteString("\n\n")
 	}
 
-	// Card counter
+	b.WriteString(m.renderDeckBatch())
+
+	// Card counter, with a gauge of the position in long decks
 	if len(m.filteredNotes) > 0 {
 		counter := browseCardCountStyle.Render(
 			fmt.Sprintf("Card %d of %d", m.currentNote+1, len(m.filteredNotes)),
 		)
+		if len(m.filteredNotes) > 1 {
+			counter += "  " + renderBar(m.currentNote+1, len(m.filteredNotes), 20)
+		}
+		if m.sort != browseSortDeck {
+			counter += helpStyle.Render("  sorted by " + m.sort.String())
+			if m.sortNote != "" {
+				counter += errorStyle.Render(" (" + m.sortNote + ")")
+			}
+		}
 		b.WriteString(counter)
 		b.WriteString("\n\n")
 	}
 
-	// Current note
-	if m.currentNote < len(m.filteredNotes) {
-		b.WriteString(m.renderNoteView())
-	} else {
-		b.WriteString(helpStyle.Render("No cards match your search"))
+	// Character tabs for multi-character words
+	if m.currentNote < len(m.filteredNotes) && len(m.characters) > 1 {
+		b.WriteString(m.renderCharTabs())
 		b.WriteString("\n")
 	}
 
-	// Help
-	b.WriteString("\n")
-	helpText := "↑/↓: cards • ←/→: chars • /: search • g: generate"
+	return b.String()
+}
+
+// renderHelp renders the help line below the details.
+func (m BrowseModel) renderHelp() string {
+	if m.copyMenu.active {
+		return "\n" + helpStyle.Render(m.copyMenu.View())
+	}
+	if m.jumping {
+		return "\n" + helpStyle.Render(fmt.Sprintf("enter: go to card (1-%d) • esc: cancel", len(m.filteredNotes)))
+	}
+
+	helpText := "↑/↓: cards • :: go to • o: sort • ←/→: chars • /: search • f: fields • g: generate • Y: copy…"
+	if m.currentAudio() != "" {
+		helpText += " • v: play"
+	}
 	if len(m.characters) > 1 {
 		helpText += " • B: batch"
 	}
+	if len(m.batchFailures) > 0 && !m.batchGenerating {
+		helpText += " • F: retry failed"
+	}
+	if m.deck.state == deckBatchConfirming {
+		helpText = m.deckBatchHelp()
+	} else if batch := m.deckBatchHelp(); batch != "" {
+		helpText = batch + " • " + helpText
+	} else if m.scenes != nil {
+		helpText += " • D: deck batch"
+	}
 	if m.llmPrompt != "" {
-		helpText += " • y: copy"
+		helpText += " • R: variation • e: edit • y: copy"
+		if m.takes.label(m.llmPrompt) != "" {
+			helpText += " • ,/.: takes"
+		}
 	}
-	b.WriteString(helpStyle.Render(helpText))
-
-	return b.String()
+	if scroll := m.detail.help(); scroll != "" {
+		helpText += " • " + scroll
+	}
+	if m.copyNote != "" {
+		return "\n" + copiedStyle.Render(m.copyNote) + helpStyle.Render(" • "+helpText)
+	}
+	return "\n" + helpStyle.Render(helpText)
 }
 
 func (m BrowseModel) renderNoPackage() string {
 	var b strings.Builder
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3d5a80")).
+		Border(palette.BoxBorder()).
+		BorderForeground(palette.Border).
 		Padding(2, 4).
 		Align(lipgloss.Center)
 
@@ -629,23 +968,6 @@ func (m BrowseModel) renderNoPackage() string {
 	return b.String()
 }
 
-func (m BrowseModel) renderNoteView() string {
-	var b strings.Builder
-
-	// Character tabs for multi-character words
-	if len(m.characters) > 1 {
-		b.WriteString(m.renderCharTabs())
-		b.WriteString("\n")
-	}
-
-	// Selected character details
-	if m.selected < len(m.characters) {
-		b.WriteString(m.renderCharacterDetail(m.characters[m.selected]))
-	}
-
-	return b.String()
-}
-
 func (m BrowseModel) renderCharTabs() string {
 	var tabs []string
 
@@ -672,12 +994,21 @@ func (m BrowseModel) renderCharTabs() string {
 	return browseWordDisplayStyle.Render(combined)
 }
 
-func (m BrowseModel) renderCharacterDetail(r components.CharacterResult) string {
+// currentAudio returns the recording of the selected character, or "" if
+// there is none.
+func (m BrowseModel) currentAudio() string {
+	if m.selected >= len(m.characters) || m.currentNote >= len(m.filteredNotes) {
+		return ""
+	}
+	return audioFor(m.pkg, m.filteredNotes[m.currentNote], m.characters[m.selected].Character)
+}
+
+func (m BrowseModel) renderCharacterDetail(r analyze.CharacterResult) string {
 	var b strings.Builder
 
 	// Large centered character display
 	charDisplay := browseBigCharStyle.Render(r.Character)
-	pinyinDisplay := browsePinyinUnderStyle.Render(r.Pinyin)
+	pinyinDisplay := browsePinyinUnderStyle.Render(r.Pinyin + speaker(m.currentAudio()))
 
 	// Center the character block within view width
 	charBlock := lipgloss.JoinVertical(lipgloss.Center, charDisplay, pinyinDisplay)
@@ -696,12 +1027,9 @@ func (m BrowseModel) renderCharacterDetail(r components.CharacterResult) string
 
 	// Meaning (centered)
 	if r.Meaning != "" {
-		meaning := r.Meaning
-		if len(meaning) > 60 {
-			meaning = meaning[:60] + "..."
-		}
+		meaning := textutil.Truncate(r.Meaning, 60)
 		meaningStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee")).
+			Foreground(palette.Text).
 			Width(contentWidth).
 			Align(lipgloss.Center)
 		b.WriteString(meaningStyle.Render(meaning))
@@ -719,13 +1047,29 @@ func (m BrowseModel) renderCharacterDetail(r components.CharacterResult) string
 		b.WriteString("\n")
 	}
 
+	// Look-alikes
+	if box := renderLookAlikesBox(m.scenes, r.Character); box != "" {
+		b.WriteString(box)
+		b.WriteString("\n")
+	}
+	b.WriteString(renderConfusables(m.analyzer, m.scenes, r.Character, m.width))
+
+	// Note fields or the augment preview
+	if panel := m.renderFieldPanel(); panel != "" {
+		b.WriteString(panel)
+		b.WriteString("\n")
+	}
+
 	// LLM prompt
 	if m.batchGenerating {
 		b.WriteString("\n")
-		progress := fmt.Sprintf("Generating prompts... %d/%d", m.batchCompleted, m.batchTotal)
-		b.WriteString(loadingStyle.Render(progress))
+		b.WriteString(loadingStyle.Render("Generating prompts "))
+		b.WriteString(components.NewProgress(20, palette).View(m.batchCompleted, m.batchTotal))
+		if len(m.batchFailures) > 0 {
+			b.WriteString("  " + errorStyle.Render(fmt.Sprintf("%d failed", len(m.batchFailures))))
+		}
 		b.WriteString("\n")
-	} else if m.llmGenerating {
+	} else if m.llmGenerating && m.llmPrompt == "" {
 		b.WriteString("\n")
 		b.WriteString(loadingStyle.Render("Generating image prompt..."))
 		b.WriteString("\n")
@@ -742,8 +1086,16 @@ func (m BrowseModel) renderCharacterDetail(r components.CharacterResult) string
 		if m.copied {
 			headerText += "  " + copiedStyle.Render("✓ Copied!")
 		}
-		if len(m.charPrompts) > 1 {
-			headerText += "  " + helpStyle.Render(fmt.Sprintf("(%d/%d generated)", len(m.charPrompts), len(m.characters)))
+		if m.llmGenerating {
+			headerText += "  " + loadingStyle.Render("Generating another take...")
+		} else if label := m.takes.label(m.llmPrompt); label != "" {
+			headerText += "  " + helpStyle.Render("("+label+")")
+		}
+		if n := m.generatedCount(); n > 1 {
+			headerText += "  " + helpStyle.Render(fmt.Sprintf("(%d/%d generated)", n, len(m.characters)))
+		}
+		if m.batchDrafts > 0 {
+			headerText += "  " + helpStyle.Render(fmt.Sprintf("%d drafts saved for review", m.batchDrafts))
 		}
 		llmBox := llmPromptStyle.Width(width).Render(
 			headerText + "\n\n" + wordWrap(m.llmPrompt, width-6),
@@ -758,11 +1110,16 @@ func (m BrowseModel) renderCharacterDetail(r components.CharacterResult) string
 		b.WriteString(helpStyle.Render(hint))
 		b.WriteString("\n")
 	}
+	if failed := llm.FailureSummary(m.batchFailures, m.batchTotal); failed != "" && !m.batchGenerating {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render("Batch: " + failed))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
 
-func (m BrowseModel) renderHMMBox(r components.CharacterResult) string {
+func (m BrowseModel) renderHMMBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	initial := r.Initial
@@ -800,7 +1157,7 @@ func (m BrowseModel) renderHMMBox(r components.CharacterResult) string {
 	)
 }
 
-func (m BrowseModel) renderComponentsBox(r components.CharacterResult) string {
+func (m BrowseModel) renderComponentsBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	for i, comp := range r.Components {
@@ -830,7 +1187,7 @@ func detectChineseFieldFromPkg(pkg *anki.Package) string {
 		}
 		fieldNames := pkg.GetFieldNames(note)
 		for j, value := range note.Fields {
-			if containsChineseChars(value) {
+			if analyze.ContainsChinese(value) {
 				if j < len(fieldNames) {
 					return fieldNames[j]
 				}
@@ -840,17 +1197,3 @@ func detectChineseFieldFromPkg(pkg *anki.Package) string {
 	}
 	return ""
 }
-
-func containsChineseChars(s string) bool {
-	for _, r := range s {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			return true
-		}
-	}
-	return false
-}
-
-func stripHTMLTags(s string) string {
-	re := regexp.MustCompile(`<[^>]*>`)
-	return strings.TrimSpace(re.ReplaceAllString(s, ""))
-}
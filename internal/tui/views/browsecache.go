@@ -0,0 +1,65 @@
+package views
+
+import (
+	"container/list"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+)
+
+// noteCacheSize is how many notes Browse keeps the analyzed characters of,
+// enough to page back and forth through a stretch of the deck.
+const noteCacheSize = 128
+
+// noteCache keeps the analyzed characters of the notes Browse showed last,
+// so paging back to a note does not parse and look up its characters again.
+type noteCache struct {
+	size    int
+	order   *list.List // Most recently used first
+	entries map[int64]*list.Element
+}
+
+type noteCacheEntry struct {
+	id         int64
+	characters []analyze.CharacterResult
+}
+
+func newNoteCache(size int) *noteCache {
+	return &noteCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[int64]*list.Element),
+	}
+}
+
+// get returns the characters of note id, if cached.
+func (c *noteCache) get(id int64) ([]analyze.CharacterResult, bool) {
+	e, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*noteCacheEntry).characters, true
+}
+
+// put caches the characters of note id, dropping the least recently used
+// note when the cache is full.
+func (c *noteCache) put(id int64, characters []analyze.CharacterResult) {
+	if e, ok := c.entries[id]; ok {
+		e.Value.(*noteCacheEntry).characters = characters
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[id] = c.order.PushFront(&noteCacheEntry{id: id, characters: characters})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*noteCacheEntry).id)
+	}
+}
+
+// promptKey names a generated prompt by the note and the index of the
+// character within it, so prompts survive paging to other notes.
+type promptKey struct {
+	note  int64
+	index int
+}
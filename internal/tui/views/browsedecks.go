@@ -0,0 +1,76 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+)
+
+// OtherDeck is another deck open in the workspace. Browse counts the
+// cards in it that match a search, so one search covers every open deck.
+type OtherDeck struct {
+	Name string
+	Pkg  *anki.Package
+}
+
+// otherDeck is an OtherDeck prepared for searching.
+type otherDeck struct {
+	name string
+	src  browseSource
+}
+
+// deckMatches is how many cards of another deck match the search.
+type deckMatches struct {
+	name  string
+	count int
+}
+
+// SetOtherDecks sets the other decks open in the workspace, and counts
+// their matches for the current search.
+func (m *BrowseModel) SetOtherDecks(decks []OtherDeck) {
+	m.others = nil
+	for _, d := range decks {
+		m.others = append(m.others, otherDeck{
+			name: d.Name,
+			src:  newBrowseSource(d.Pkg, detectChineseFieldFromPkg(d.Pkg)),
+		})
+	}
+	m.countElsewhere()
+}
+
+// countElsewhere counts the cards of the other decks that match the
+// search, as Browse would list them there.
+func (m *BrowseModel) countElsewhere() {
+	m.elsewhere = nil
+	if m.searchTerm == "" {
+		return
+	}
+
+	q := parseBrowseQuery(m.searchTerm)
+	for _, d := range m.others {
+		n := 0
+		for _, note := range d.src.pkg.Notes {
+			if analyze.ContainsChinese(d.src.pkg.GetFieldValue(note, d.src.field)) && m.matchNote(note, q, d.src) {
+				n++
+			}
+		}
+		if n > 0 {
+			m.elsewhere = append(m.elsewhere, deckMatches{name: d.name, count: n})
+		}
+	}
+}
+
+// renderElsewhere renders the other decks with cards matching the
+// search, or "" if there are none.
+func (m BrowseModel) renderElsewhere() string {
+	if len(m.elsewhere) == 0 {
+		return ""
+	}
+	parts := make([]string, len(m.elsewhere))
+	for i, d := range m.elsewhere {
+		parts[i] = fmt.Sprintf("%s (%d)", d.name, d.count)
+	}
+	return helpStyle.Render("Also in "+strings.Join(parts, ", ")+" • }: next deck") + "\n\n"
+}
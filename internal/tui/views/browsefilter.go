@@ -0,0 +1,212 @@
+package views
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/pinyin"
+)
+
+// browseFilter is a structured search term such as tone:3 or
+// deck:"HSK 1".
+type browseFilter struct {
+	key   string
+	value string
+}
+
+// browseQuery is a Browse search split into structured filters and the
+// plain text left over, which is matched against the note fields.
+type browseQuery struct {
+	text    string
+	filters []browseFilter
+}
+
+// browseFilterKeys are the filters Browse search understands. Anything
+// else that looks like key:value is searched for as text.
+var browseFilterKeys = map[string]bool{
+	"tone":    true,
+	"actor":   true,
+	"set":     true,
+	"missing": true,
+	"tag":     true,
+	"deck":    true,
+}
+
+// parseBrowseQuery splits a search into filters and text. Filter values
+// may be quoted to include spaces, as in deck:"HSK 1".
+func parseBrowseQuery(s string) browseQuery {
+	var q browseQuery
+	var text []string
+
+	for _, word := range splitQuoted(s) {
+		key, value, ok := strings.Cut(word, ":")
+		key = strings.ToLower(key)
+		if !ok || !browseFilterKeys[key] {
+			text = append(text, strings.Trim(word, `"`))
+			continue
+		}
+		q.filters = append(q.filters, browseFilter{
+			key:   key,
+			value: strings.ToLower(strings.Trim(value, `"`)),
+		})
+	}
+
+	q.text = strings.ToLower(strings.Join(text, " "))
+	return q
+}
+
+// splitQuoted splits s at spaces outside double quotes.
+func splitQuoted(s string) []string {
+	var words []string
+	var word strings.Builder
+	quoted := false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			word.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// browseSource is a deck Browse searches: the package, the field with the
+// characters, and the decks of its notes.
+type browseSource struct {
+	pkg   *anki.Package
+	field string
+	decks map[int64][]string
+}
+
+// newBrowseSource prepares pkg for searching, with field holding the
+// characters.
+func newBrowseSource(pkg *anki.Package, field string) browseSource {
+	return browseSource{pkg: pkg, field: field, decks: noteDecks(pkg)}
+}
+
+// matchNote reports whether note of src passes every filter of q and
+// contains its text in one of its fields.
+func (m *BrowseModel) matchNote(note *anki.Note, q browseQuery, src browseSource) bool {
+	if q.text != "" {
+		found := false
+		for _, field := range note.Fields {
+			if strings.Contains(strings.ToLower(htmlutil.Text(field)), q.text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, f := range q.filters {
+		if !m.matchFilter(note, f, src) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilter reports whether note passes f. Character filters pass if
+// any character of the note does.
+func (m *BrowseModel) matchFilter(note *anki.Note, f browseFilter, src browseSource) bool {
+	switch f.key {
+	case "tag":
+		for _, tag := range strings.Fields(note.Tags) {
+			if strings.EqualFold(tag, f.value) {
+				return true
+			}
+		}
+		return false
+	case "deck":
+		for _, name := range src.decks[note.ID] {
+			name = strings.ToLower(name)
+			if name == f.value || strings.HasPrefix(name, f.value+"::") {
+				return true
+			}
+		}
+		return false
+	}
+
+	value := htmlutil.Text(src.pkg.GetFieldValue(note, src.field))
+	for _, r := range value {
+		if analyze.IsChinese(r) && m.matchChar(string(r), f) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchChar reports whether char passes one of the character filters.
+// An actor or set matches by its initial or final, or by its name.
+func (m *BrowseModel) matchChar(char string, f browseFilter) bool {
+	if f.key == "missing" {
+		switch f.value {
+		case "prompt":
+			if m.scenes == nil {
+				return true
+			}
+			sc := m.scenes.Get(char)
+			return sc == nil || sc.ImagePrompt == ""
+		case "meaning":
+			if m.dict == nil {
+				return true
+			}
+			entry := m.dict.Lookup(char)
+			return entry == nil || entry.Definition == ""
+		}
+		return false
+	}
+
+	readings := m.parser.ParseChar(char)
+	if len(readings) == 0 {
+		return false
+	}
+	reading := readings[0]
+
+	switch f.key {
+	case "tone":
+		tone, err := strconv.Atoi(f.value)
+		return err == nil && int(reading.Tone) == tone
+	case "actor":
+		if strings.EqualFold(reading.Initial, f.value) {
+			return true
+		}
+		actor := m.generator.GetActor(pinyin.GetActorID(reading.Initial))
+		return actor != nil && actor.Name != "" && strings.Contains(strings.ToLower(actor.Name), f.value)
+	case "set":
+		if strings.EqualFold(reading.Final, f.value) {
+			return true
+		}
+		set := m.generator.GetSet(pinyin.GetSetID(reading.Final))
+		return set != nil && set.Name != "" && strings.Contains(strings.ToLower(set.Name), f.value)
+	}
+	return false
+}
+
+// noteDecks maps each note to the names of the decks its cards are in.
+func noteDecks(pkg *anki.Package) map[int64][]string {
+	decks := make(map[int64][]string)
+	for _, card := range pkg.Cards {
+		if deck := pkg.GetDeck(card); deck != nil {
+			decks[card.NoteID] = append(decks[card.NoteID], deck.Name)
+		}
+	}
+	return decks
+}
@@ -0,0 +1,227 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/scene"
+)
+
+// browseSort is the order Browse shows the filtered notes in.
+type browseSort int
+
+const (
+	browseSortDeck browseSort = iota
+	browseSortPinyin
+	browseSortFrequency
+	browseSortStrokes
+	browseSortModified
+	browseSortDue
+	browseSortCount
+)
+
+// frequencyListName is the target list Browse ranks characters by when
+// sorting by frequency, most frequent first (lists/frequency.txt, or the
+// built-in list).
+const frequencyListName = "frequency"
+
+func (s browseSort) String() string {
+	switch s {
+	case browseSortPinyin:
+		return "pinyin"
+	case browseSortFrequency:
+		return "frequency"
+	case browseSortStrokes:
+		return "strokes"
+	case browseSortModified:
+		return "last modified"
+	case browseSortDue:
+		return "due date"
+	}
+	return "deck order"
+}
+
+// parseBrowseSort returns the sort named name, or deck order if there is
+// none.
+func parseBrowseSort(name string) browseSort {
+	for s := browseSortDeck; s < browseSortCount; s++ {
+		if s.String() == name {
+			return s
+		}
+	}
+	return browseSortDeck
+}
+
+// next returns the sort that o cycles to.
+func (s browseSort) next() browseSort {
+	return (s + 1) % browseSortCount
+}
+
+// sortNotes orders the filtered notes by the current sort. Notes that tie
+// keep their deck order.
+func (m *BrowseModel) sortNotes() {
+	m.sortNote = ""
+	if m.sort == browseSortDeck || len(m.filteredNotes) < 2 {
+		return
+	}
+
+	var less func(a, b *anki.Note) bool
+	switch m.sort {
+	case browseSortPinyin:
+		keys := make(map[int64]string, len(m.filteredNotes))
+		for _, note := range m.filteredNotes {
+			keys[note.ID] = m.pinyinKey(note)
+		}
+		less = func(a, b *anki.Note) bool { return keys[a.ID] < keys[b.ID] }
+	case browseSortFrequency:
+		ranks, err := m.frequencyRanks()
+		if err != nil {
+			m.sortNote = "no " + filepath.Join(scene.ListsDirName, frequencyListName+".txt")
+			return
+		}
+		keys := make(map[int64]int, len(m.filteredNotes))
+		for _, note := range m.filteredNotes {
+			keys[note.ID] = m.frequencyKey(note, ranks)
+		}
+		less = func(a, b *anki.Note) bool { return keys[a.ID] < keys[b.ID] }
+	case browseSortStrokes:
+		keys := make(map[int64]int, len(m.filteredNotes))
+		for _, note := range m.filteredNotes {
+			keys[note.ID] = m.strokeKey(note)
+		}
+		less = func(a, b *anki.Note) bool { return keys[a.ID] < keys[b.ID] }
+	case browseSortModified:
+		less = func(a, b *anki.Note) bool { return a.Mod > b.Mod }
+	case browseSortDue:
+		keys := noteDueKeys(m.pkg)
+		less = func(a, b *anki.Note) bool {
+			ka, kb := keys.get(a.ID), keys.get(b.ID)
+			if ka.rank != kb.rank {
+				return ka.rank < kb.rank
+			}
+			return ka.due < kb.due
+		}
+	}
+
+	// Sort a copy so the unfiltered notes keep their deck order
+	notes := make([]*anki.Note, len(m.filteredNotes))
+	copy(notes, m.filteredNotes)
+	sort.SliceStable(notes, func(i, j int) bool { return less(notes[i], notes[j]) })
+	m.filteredNotes = notes
+}
+
+// noteChars returns the Chinese characters of a note's Chinese field.
+func (m *BrowseModel) noteChars(note *anki.Note) []string {
+	return analyze.ChineseChars(htmlutil.Text(m.pkg.GetFieldValue(note, m.chineseField)))
+}
+
+// pinyinKey spells a note's characters without tone marks, each followed
+// by its tone, so hao3 sorts before hao4 and both before he2.
+func (m *BrowseModel) pinyinKey(note *anki.Note) string {
+	var parts []string
+	for _, char := range m.noteChars(note) {
+		if readings := m.parser.ParseChar(char); len(readings) > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d", readings[0].Toneless, readings[0].Tone))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// frequencyRanks reads the frequency list into a rank per character.
+func (m *BrowseModel) frequencyRanks() (map[string]int, error) {
+	if m.scenes == nil {
+		return nil, fmt.Errorf("no scene store")
+	}
+	list, err := scene.FindTargetList(m.scenes.ListsDir(), frequencyListName)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[string]int, len(list.Chars))
+	for i, char := range list.Chars {
+		ranks[char] = i + 1
+	}
+	return ranks, nil
+}
+
+// frequencyKey ranks a note by its rarest character, so a word is only as
+// common as its least common character. Unranked notes sort last.
+func (m *BrowseModel) frequencyKey(note *anki.Note, ranks map[string]int) int {
+	key := 0
+	for _, char := range m.noteChars(note) {
+		rank, ok := ranks[char]
+		if !ok {
+			return math.MaxInt
+		}
+		if rank > key {
+			key = rank
+		}
+	}
+	if key == 0 {
+		return math.MaxInt
+	}
+	return key
+}
+
+// strokeKey adds up the strokes of a note's characters. Notes with a
+// character missing from the dictionary sort last.
+func (m *BrowseModel) strokeKey(note *anki.Note) int {
+	total := 0
+	if m.dict == nil {
+		return math.MaxInt
+	}
+	for _, char := range m.noteChars(note) {
+		entry := m.dict.Lookup(char)
+		if entry == nil || entry.StrokeCount() == 0 {
+			return math.MaxInt
+		}
+		total += entry.StrokeCount()
+	}
+	return total
+}
+
+// dueKey orders notes by when their next card is due. Anki counts due
+// differently per card type, so cards in learning come first, then
+// review cards by due day, then new cards by their position in the new
+// queue, then suspended and buried cards.
+type dueKey struct {
+	rank int
+	due  int
+}
+
+type dueKeys map[int64]dueKey
+
+// get returns the key of a note, sorting notes without cards last.
+func (k dueKeys) get(id int64) dueKey {
+	if key, ok := k[id]; ok {
+		return key
+	}
+	return dueKey{rank: 4}
+}
+
+// noteDueKeys finds the earliest due card of every note.
+func noteDueKeys(pkg *anki.Package) dueKeys {
+	keys := make(dueKeys)
+	for _, card := range pkg.Cards {
+		var k dueKey
+		switch {
+		case card.Queue < 0:
+			k = dueKey{rank: 3, due: card.Due}
+		case card.Type == 1 || card.Type == 3:
+			k = dueKey{rank: 0, due: card.Due}
+		case card.Type == 2:
+			k = dueKey{rank: 1, due: card.Due}
+		default:
+			k = dueKey{rank: 2, due: card.Due}
+		}
+		if old, ok := keys[card.NoteID]; !ok || k.rank < old.rank || (k.rank == old.rank && k.due < old.due) {
+			keys[card.NoteID] = k
+		}
+	}
+	return keys
+}
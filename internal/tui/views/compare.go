@@ -0,0 +1,282 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+)
+
+// Compare view styles
+var (
+	compareTitleStyle lipgloss.Style
+	compareCharStyle  lipgloss.Style
+	compareSameStyle  lipgloss.Style
+	compareDiffStyle  lipgloss.Style
+	compareMarkStyle  lipgloss.Style
+)
+
+// setCompareStyles builds the Compare view styles from t.
+func setCompareStyles(t theme.Theme) {
+	compareTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
+
+	compareCharStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.Bg).
+		Padding(0, 2)
+
+	compareSameStyle = lipgloss.NewStyle().
+		Foreground(t.Subtle)
+
+	compareDiffStyle = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true)
+
+	compareMarkStyle = lipgloss.NewStyle().
+		Foreground(t.Primary).
+		Bold(true)
+}
+
+// CompareMsg asks the app to show two characters side by side in the
+// Compare view.
+type CompareMsg struct {
+	A, B string
+}
+
+// CompareModel shows two characters in parallel columns so what sets
+// look-alikes such as 买/卖 or 己/已 apart stands out.
+type CompareModel struct {
+	input     textinput.Model
+	analyzer  *analyze.Analyzer
+	generator *prompt.Generator
+	scenes    *scene.Store
+
+	pair []analyze.CharacterResult
+	err  error
+
+	width  int
+	height int
+}
+
+// NewCompareModel creates a new compare view model.
+func NewCompareModel(dict *decomp.Dictionary, gen *prompt.Generator, scenes *scene.Store) CompareModel {
+	ti := textinput.New()
+	ti.Placeholder = "Two characters, e.g. 买卖"
+	ti.Focus()
+	ti.CharLimit = 10
+	ti.Width = 30
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(palette.Secondary)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(palette.Accent)
+
+	return CompareModel{
+		input:     ti,
+		analyzer:  analyze.New(dict, gen),
+		generator: gen,
+		scenes:    scenes,
+	}
+}
+
+// SetSize updates the view dimensions.
+func (m *CompareModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetPair compares a and b, as if they had been typed in.
+func (m *CompareModel) SetPair(a, b string) {
+	m.input.SetValue(a + b)
+	m.compare()
+}
+
+// Focused reports whether the input is taking typed characters, so the
+// app should pass every key through.
+func (m CompareModel) Focused() bool {
+	return m.input.Focused()
+}
+
+// Update handles messages. While the input is focused every key goes into
+// it but enter, which compares, and esc, which leaves it so the app keys
+// work again; / or enter goes back into it.
+func (m CompareModel) Update(msg tea.Msg) (CompareModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		if !m.input.Focused() {
+			switch key.String() {
+			case "/", "enter":
+				m.input.Focus()
+				return m, textinput.Blink
+			}
+			return m, nil
+		}
+		switch key.String() {
+		case "enter":
+			m.compare()
+			return m, nil
+		case "esc":
+			m.input.Blur()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// compare analyzes the first two Chinese characters of the input.
+func (m *CompareModel) compare() {
+	chars := analyze.ChineseChars(m.input.Value())
+
+	m.pair = nil
+	m.err = nil
+	if len(chars) < 2 {
+		m.err = fmt.Errorf("enter two characters to compare")
+		return
+	}
+
+	for _, char := range chars[:2] {
+		result := m.analyzer.Character(char)
+		if result == nil {
+			m.pair = nil
+			m.err = fmt.Errorf("no pinyin found for %s", char)
+			return
+		}
+		m.pair = append(m.pair, *result)
+	}
+}
+
+// compareRow is one line of the comparison: a label and the value for
+// each of the two characters.
+type compareRow struct {
+	label string
+	a, b  string
+}
+
+// rows lists what the comparison shows for the current pair.
+func (m CompareModel) rows() []compareRow {
+	a, b := m.pair[0], m.pair[1]
+	return []compareRow{
+		{"Pinyin", a.Pinyin, b.Pinyin},
+		{"Meaning", a.Meaning, b.Meaning},
+		{"Actor", orNone(a.Initial) + " → " + formatActorName(a.ActorID, a.ActorName), orNone(b.Initial) + " → " + formatActorName(b.ActorID, b.ActorName)},
+		{"Set", orNone(a.Final) + " → " + formatSetName(a.SetID, a.SetName), orNone(b.Final) + " → " + formatSetName(b.SetID, b.SetName)},
+		{"Room", fmt.Sprintf("%d → %s", a.Tone, a.ToneRoom), fmt.Sprintf("%d → %s", b.Tone, b.ToneRoom)},
+		{"Components", a.Decomp, b.Decomp},
+		{"Props", strings.Join(a.PropNames, ", "), strings.Join(b.PropNames, ", ")},
+	}
+}
+
+// View renders the compare view.
+func (m CompareModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(compareTitleStyle.Render("Compare"))
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.pair) < 2 {
+		if m.input.Focused() {
+			b.WriteString(helpStyle.Render("Type two characters and press Enter to compare them • esc: done typing"))
+		} else {
+			b.WriteString(helpStyle.Render("/: type two characters to compare"))
+		}
+		return b.String()
+	}
+
+	labelWidth := lipgloss.Width(labelStyle.Render("")) + 2
+	colWidth := (m.width - labelWidth - 2) / 2
+	if colWidth < 16 {
+		colWidth = 16
+	}
+	col := lipgloss.NewStyle().Width(colWidth).PaddingRight(2)
+
+	header := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(labelWidth).Render(""),
+		col.Render(compareCharStyle.Render(m.pair[0].Character)),
+		col.Render(compareCharStyle.Render(m.pair[1].Character)),
+	)
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	differ := 0
+	for _, row := range m.rows() {
+		style := compareSameStyle
+		mark := "  "
+		if row.a != row.b {
+			style = compareDiffStyle
+			mark = compareMarkStyle.Render("≠ ")
+			differ++
+		}
+		line := lipgloss.JoinHorizontal(lipgloss.Top,
+			mark+labelStyle.Render(row.label+":"),
+			col.Render(style.Render(orDash(row.a))),
+			col.Render(style.Render(orDash(row.b))),
+		)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%d of %d differ", differ, len(m.rows()))))
+	b.WriteString("\n")
+
+	if note := m.linkNote(); note != "" {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("Your note:") + " " + valueStyle.Render(note))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.input.Focused() {
+		b.WriteString(helpStyle.Render("enter: compare • esc: done typing"))
+	} else {
+		b.WriteString(helpStyle.Render("/: compare two other characters"))
+	}
+	return b.String()
+}
+
+// linkNote returns the note on a look-alike link between the pair, if
+// one was saved with hmm scene link.
+func (m CompareModel) linkNote() string {
+	if m.scenes == nil {
+		return ""
+	}
+	for _, l := range m.scenes.LinksFor(m.pair[0].Character) {
+		if m.scenes.LinkedCharacter(l) == m.pair[1].Character {
+			return l.Note
+		}
+	}
+	return ""
+}
+
+// orNone shows an empty initial or final as Ø.
+func orNone(s string) string {
+	if s == "" {
+		return "Ø"
+	}
+	return s
+}
+
+// orDash shows a missing value as a dash.
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/clipboard"
+)
+
+// copyMenuItems are the parts of a card the copy menu offers, by key.
+var copyMenuItems = []struct {
+	key   string
+	label string
+}{
+	{"c", "character"},
+	{"p", "pinyin"},
+	{"m", "meaning"},
+	{"b", "breakdown"},
+	{"d", "markdown"},
+}
+
+// copyMenu is opened with Y to copy a part of the current card other than
+// the image prompt, which y copies directly.
+type copyMenu struct {
+	active bool
+}
+
+// choose closes the menu and copies the item picked with key. It returns
+// a note saying what was copied, or "" if key is not an item, and the
+// error if the clipboard is unavailable; any other key just closes the
+// menu.
+func (c *copyMenu) choose(key string, r analyze.CharacterResult, imagePrompt string) (string, error) {
+	c.active = false
+
+	var text string
+	switch key {
+	case "c":
+		text = r.Character
+	case "p":
+		text = r.Pinyin
+	case "m":
+		text = r.Meaning
+	case "b":
+		text = r.Breakdown()
+	case "d":
+		text = r.Markdown(imagePrompt)
+	default:
+		return "", nil
+	}
+
+	if err := clipboard.Write(text); err != nil {
+		return "", err
+	}
+	for _, item := range copyMenuItems {
+		if item.key == key {
+			return "Copied " + item.label, nil
+		}
+	}
+	return "", nil
+}
+
+// View renders the menu as a help line.
+func (c copyMenu) View() string {
+	parts := []string{"copy"}
+	for _, item := range copyMenuItems {
+		parts = append(parts, item.key+": "+item.label)
+	}
+	parts = append(parts, "esc: cancel")
+	return strings.Join(parts, " • ")
+}
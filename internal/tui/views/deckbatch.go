@@ -0,0 +1,281 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/tui/components"
+)
+
+const (
+	// deckBatchConcurrency limits how many prompts a deck batch requests
+	// from the LLM at once.
+	deckBatchConcurrency = 4
+
+	// deckBatchSaveEvery is how many new drafts a deck batch collects
+	// before writing the scene store, so an interrupted run keeps most
+	// of its work.
+	deckBatchSaveEvery = 10
+)
+
+type deckBatchState int
+
+const (
+	deckBatchIdle deckBatchState = iota
+	deckBatchConfirming
+	deckBatchRunning
+	deckBatchPaused
+	deckBatchStopping // Waiting for requests in flight
+)
+
+// deckBatch generates prompts for every character in the filtered notes
+// that has no scene yet. Results are stored as drafts for review, which
+// hmm scene sync or hmm anki augment later write into the deck.
+type deckBatch struct {
+	state    deckBatchState
+	queue    []analyze.CharacterResult // Not requested yet
+	notes    int                       // Notes the characters come from
+	total    int
+	done     int
+	failed   int
+	inFlight int
+	drafts   int
+	unsaved  int
+	err      error // Last failure, shown while running
+	summary  string
+}
+
+type deckBatchResultMsg struct {
+	char   analyze.CharacterResult
+	prompt string
+	err    error
+}
+
+// active reports whether the batch is asking for confirmation or has not
+// finished yet.
+func (b deckBatch) active() bool {
+	return b.state != deckBatchIdle
+}
+
+// planDeckBatch collects the characters a deck batch would generate and
+// asks for confirmation.
+func (m *BrowseModel) planDeckBatch() {
+	seen := make(map[string]bool)
+	var queue []analyze.CharacterResult
+	notes := 0
+
+	for _, note := range m.filteredNotes {
+		value := htmlutil.Text(m.pkg.GetFieldValue(note, m.chineseField))
+		found := false
+		for _, char := range analyze.ChineseChars(value) {
+			if seen[char] {
+				continue
+			}
+			seen[char] = true
+			if m.scenes.Get(char) != nil {
+				continue
+			}
+			if result := m.analyzer.Character(char); result != nil {
+				queue = append(queue, *result)
+				found = true
+			}
+		}
+		if found {
+			notes++
+		}
+	}
+
+	m.deck = deckBatch{
+		state: deckBatchConfirming,
+		queue: queue,
+		notes: notes,
+		total: len(queue),
+	}
+	if len(queue) == 0 {
+		m.deck = deckBatch{summary: "Every character in these cards already has a scene"}
+	}
+}
+
+// updateDeckBatch handles keys while a deck batch is active.
+func (m BrowseModel) updateDeckBatch(key string) (BrowseModel, tea.Cmd) {
+	switch m.deck.state {
+	case deckBatchConfirming:
+		switch key {
+		case "y", "enter":
+			m.deck.state = deckBatchRunning
+			return m, m.dispatchDeckBatch()
+		case "n":
+			m.deck = deckBatch{}
+		}
+	case deckBatchRunning:
+		switch key {
+		case "p":
+			m.deck.state = deckBatchPaused
+		case "x":
+			m.stopDeckBatch()
+		}
+	case deckBatchPaused:
+		switch key {
+		case "p":
+			m.deck.state = deckBatchRunning
+			return m, m.dispatchDeckBatch()
+		case "x":
+			m.stopDeckBatch()
+		}
+	}
+	return m, nil
+}
+
+// dispatchDeckBatch requests prompts until deckBatchConcurrency requests
+// are in flight.
+func (m *BrowseModel) dispatchDeckBatch() tea.Cmd {
+	client := m.llmClient
+	var cmds []tea.Cmd
+	for m.deck.state == deckBatchRunning && m.deck.inFlight < deckBatchConcurrency && len(m.deck.queue) > 0 {
+		char := m.deck.queue[0]
+		m.deck.queue = m.deck.queue[1:]
+		m.deck.inFlight++
+
+		elements := m.analyzer.SceneElements(char)
+		cmds = append(cmds, func() tea.Msg {
+			prompt, err := client.GenerateScene(elements)
+			return deckBatchResultMsg{char: char, prompt: prompt, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleDeckBatchResult stores a generated prompt as a draft and requests
+// the next one.
+func (m *BrowseModel) handleDeckBatchResult(msg deckBatchResultMsg) tea.Cmd {
+	if m.deck.inFlight > 0 {
+		m.deck.inFlight--
+	}
+	m.deck.done++
+
+	var notify tea.Cmd
+	if msg.err != nil {
+		m.deck.failed++
+		m.deck.err = msg.err
+		// Carrying on would only fail faster; pause until the user resumes
+		if errors.Is(msg.err, llm.ErrRateLimited) && m.deck.state == deckBatchRunning {
+			m.deck.state = deckBatchPaused
+			notify = toast(ToastError, "Anthropic API rate limit reached, deck batch paused (p to resume)")
+		}
+	} else if msg.prompt != "" && m.scenes.PutDraft(msg.char.Scene(msg.prompt)) {
+		m.deck.drafts++
+		m.deck.unsaved++
+		for i, r := range m.characters {
+			if r.Character == msg.char.Character {
+				m.charPrompts[m.charKey(i)] = msg.prompt
+				if i == m.selected {
+					m.llmPrompt = msg.prompt
+				}
+			}
+		}
+	}
+
+	if m.deck.unsaved >= deckBatchSaveEvery {
+		m.saveDeckBatch()
+	}
+
+	if m.deck.inFlight == 0 {
+		switch {
+		case m.deck.state == deckBatchStopping:
+			return m.finishDeckBatch("Deck batch stopped")
+		case len(m.deck.queue) == 0:
+			return m.finishDeckBatch("Deck batch done")
+		}
+	}
+	return tea.Batch(notify, m.dispatchDeckBatch())
+}
+
+// stopDeckBatch drops the characters not requested yet.
+func (m *BrowseModel) stopDeckBatch() {
+	m.deck.queue = nil
+	if m.deck.inFlight == 0 {
+		// Stopped by a key in Browse, where the summary is on screen
+		m.finishDeckBatch("Deck batch stopped")
+		return
+	}
+	m.deck.state = deckBatchStopping
+}
+
+// finishDeckBatch saves the drafts and leaves a summary of the run. It
+// returns a toast of the summary, for when the batch finishes after the
+// user has left Browse.
+func (m *BrowseModel) finishDeckBatch(verb string) tea.Cmd {
+	m.saveDeckBatch()
+	summary := fmt.Sprintf("%s: %d drafts saved for review", verb, m.deck.drafts)
+	level := ToastSuccess
+	if m.deck.failed > 0 {
+		summary += fmt.Sprintf(", %d failed", m.deck.failed)
+		level = ToastError
+	}
+	m.deck = deckBatch{summary: summary}
+	return toast(level, "%s", summary)
+}
+
+// saveDeckBatch writes the drafts collected so far.
+func (m *BrowseModel) saveDeckBatch() {
+	if m.deck.unsaved == 0 {
+		return
+	}
+	if err := m.scenes.Save(); err != nil {
+		m.deck.err = err
+		return
+	}
+	m.deck.unsaved = 0
+}
+
+// renderDeckBatch renders the confirmation prompt or progress of a deck
+// batch, or its summary once it has finished.
+func (m BrowseModel) renderDeckBatch() string {
+	b := m.deck
+	switch b.state {
+	case deckBatchConfirming:
+		return browseCardCountStyle.Render(fmt.Sprintf(
+			"Generate prompts for %d characters from %d cards?", b.total, b.notes)) + "\n" +
+			helpStyle.Render("Characters that already have a scene are skipped. Results are saved as drafts for review.") + "\n\n"
+	case deckBatchRunning, deckBatchPaused, deckBatchStopping:
+		status := ""
+		if b.failed > 0 {
+			status += fmt.Sprintf(" • %d failed", b.failed)
+		}
+		switch b.state {
+		case deckBatchPaused:
+			status += " • paused"
+		case deckBatchStopping:
+			status += " • stopping"
+		}
+		if b.state != deckBatchRunning && b.inFlight > 0 {
+			status += fmt.Sprintf(" (finishing %d)", b.inFlight)
+		}
+		out := components.NewProgress(30, palette).View(b.done, b.total) + helpStyle.Render(status) + "\n"
+		if b.err != nil {
+			out += errorStyle.Render(b.err.Error()) + "\n"
+		}
+		return out + "\n"
+	}
+	if b.summary != "" {
+		return copiedStyle.Render(b.summary) + "\n\n"
+	}
+	return ""
+}
+
+// deckBatchHelp returns the help line entries for the deck batch state.
+func (m BrowseModel) deckBatchHelp() string {
+	switch m.deck.state {
+	case deckBatchConfirming:
+		return "y: start • n: cancel"
+	case deckBatchRunning:
+		return "p: pause • x: stop"
+	case deckBatchPaused:
+		return "p: resume • x: stop"
+	}
+	return ""
+}
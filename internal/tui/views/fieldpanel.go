@@ -0,0 +1,151 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+)
+
+// fieldPanel is what the field panel below the card details shows.
+type fieldPanel int
+
+const (
+	fieldPanelOff fieldPanel = iota
+	fieldPanelNote
+	fieldPanelAugmented
+)
+
+// next returns the panel that f cycles to.
+func (p fieldPanel) next() fieldPanel {
+	return (p + 1) % 3
+}
+
+// augmentedData builds the HMM fields hmm anki augment would write into
+// the current note: the actors, sets, rooms, and props of every
+// character, and for single-character notes the approved scene's prompt
+// or else the template prompt.
+func (m BrowseModel) augmentedData() anki.AugmentedData {
+	var actors, sets, rooms, props []string
+	for _, r := range m.characters {
+		if r.ActorName != "" {
+			actors = append(actors, r.ActorName)
+		}
+		if r.SetName != "" {
+			sets = append(sets, r.SetName)
+		}
+		if r.ToneRoom != "" {
+			rooms = append(rooms, r.ToneRoom)
+		}
+		props = append(props, r.PropNames...)
+	}
+
+	data := anki.AugmentedData{
+		Actor:    joinUnique(actors),
+		Set:      joinUnique(sets),
+		ToneRoom: joinUnique(rooms),
+		Props:    joinUnique(props),
+	}
+	if len(m.characters) == 1 {
+		data.ImagePrompt = m.augmentedPrompt(m.characters[0])
+	}
+	return data
+}
+
+// augmentedPrompt returns the prompt augment writes for a character.
+func (m BrowseModel) augmentedPrompt(r analyze.CharacterResult) string {
+	if m.scenes != nil {
+		if sc := m.scenes.Approved(r.Character); sc != nil && sc.ImagePrompt != "" {
+			return sc.ImagePrompt
+		}
+	}
+	data := m.generator.BuildSceneData(r.Character, r.Pinyin, r.ActorID, r.SetID, r.Tone, r.Components, r.Meaning, "", "")
+	p, err := m.generator.Generate(data)
+	if err != nil {
+		return ""
+	}
+	return p
+}
+
+// joinUnique joins values with commas, dropping repeats.
+func joinUnique(values []string) string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// renderFieldPanel renders the current note's fields, or the HMM fields
+// augment would write with a mark on each one it adds or changes.
+func (m BrowseModel) renderFieldPanel() string {
+	if m.fields == fieldPanelOff || m.currentNote >= len(m.filteredNotes) {
+		return ""
+	}
+	note := m.filteredNotes[m.currentNote]
+
+	names := m.pkg.GetFieldNames(note)
+	existing := make(map[string]string)
+	for i, value := range note.Fields {
+		existing[fieldName(names, i)] = value
+	}
+
+	width := 70
+	if m.width > 0 && m.width-10 < width {
+		width = m.width - 10
+	}
+
+	var lines []string
+	title := "Note Fields"
+	if m.fields == fieldPanelNote {
+		for i, value := range note.Fields {
+			lines = append(lines, renderField(fieldName(names, i), htmlutil.TextWithRuby(value), "", width))
+		}
+	} else {
+		title = "Augmented Fields (preview)"
+		for i, value := range m.augmentedData().Values() {
+			name := anki.HMMFields[i]
+			old, ok := existing[name]
+			mark := ""
+			switch {
+			case !ok:
+				mark = "new"
+			case old != value:
+				mark = "changed"
+			}
+			lines = append(lines, renderField(name, value, mark, width))
+		}
+	}
+
+	return boxStyle.Render(
+		subtitleStyle.Render(title) + "\n\n" + strings.Join(lines, "\n"),
+	)
+}
+
+// fieldName returns the name of field i, or field_i if the note type
+// does not name it.
+func fieldName(names []string, i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+	return fmt.Sprintf("field_%d", i)
+}
+
+// renderField renders one field as its name over its wrapped value.
+func renderField(name, value, mark string, width int) string {
+	header := labelStyle.UnsetWidth().Render(name)
+	if mark != "" {
+		header += " " + copiedStyle.Render("("+mark+")")
+	}
+	if value == "" {
+		return header + "\n  " + helpStyle.Render("(empty)")
+	}
+	wrapped := wordWrap(value, width-6)
+	return header + "\n  " + valueStyle.Render(strings.ReplaceAll(wrapped, "\n", "\n  "))
+}
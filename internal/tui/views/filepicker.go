@@ -3,11 +3,14 @@ package views
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 )
 
 // FileSelectedMsg is sent when a file is selected
@@ -17,36 +20,47 @@ type FileSelectedMsg struct {
 
 // File picker styles
 var (
+	fpTitleStyle    lipgloss.Style
+	fpPathStyle     lipgloss.Style
+	fpDirStyle      lipgloss.Style
+	fpFileStyle     lipgloss.Style
+	fpSelectedStyle lipgloss.Style
+	fpHelpStyle     lipgloss.Style
+	fpErrorStyle    lipgloss.Style
+)
+
+// setFilePickerStyles builds the file picker styles from t.
+func setFilePickerStyles(t theme.Theme) {
 	fpTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF6B6B")).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
 
 	fpPathStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Italic(true).
-			MarginBottom(1)
+		Foreground(t.Muted).
+		Italic(true).
+		MarginBottom(1)
 
 	fpDirStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true)
+		Foreground(t.Secondary).
+		Bold(true)
 
 	fpFileStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
-	fpSelectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ffe66d")).
-			Background(lipgloss.Color("#2d3436"))
+	fpSelectedStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt))
 
 	fpHelpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			MarginTop(1)
+		Foreground(t.Muted).
+		MarginTop(1)
 
 	fpErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff6b6b")).
-			Bold(true)
-)
+		Foreground(t.Primary).
+		Bold(true)
+}
 
 // FileEntry represents a file or directory
 type FileEntry struct {
@@ -74,14 +88,19 @@ type FilePickerModel struct {
 func NewFilePickerModel() FilePickerModel {
 	home, _ := os.UserHomeDir()
 	if home == "" {
-		home = "/"
+		home = string(filepath.Separator)
+		if runtime.GOOS == "windows" {
+			home = os.Getenv("SystemDrive") + `\`
+		}
 	}
 
-	// Default to ~/.config/hmm/anki if it exists
-	ankiDir := filepath.Join(home, ".config", "hmm", "anki")
-	startDir := ankiDir
-	if _, err := os.Stat(ankiDir); os.IsNotExist(err) {
-		startDir = home
+	// Default to the anki directory in the config directory if it exists
+	startDir := home
+	if configDir, err := config.GetConfigDir(); err == nil {
+		ankiDir := filepath.Join(configDir, "anki")
+		if _, err := os.Stat(ankiDir); err == nil {
+			startDir = ankiDir
+		}
 	}
 
 	m := FilePickerModel{
@@ -111,13 +130,16 @@ func (m *FilePickerModel) loadDir() {
 		return
 	}
 
-	// Add parent directory entry
-	if m.currentDir != "/" {
+	// Add parent directory entry, or at the root of a Windows drive the
+	// other drives
+	if parent := filepath.Dir(m.currentDir); parent != m.currentDir {
 		m.entries = append(m.entries, FileEntry{
 			Name:  "..",
 			IsDir: true,
-			Path:  filepath.Dir(m.currentDir),
+			Path:  parent,
 		})
+	} else if runtime.GOOS == "windows" {
+		m.entries = append(m.entries, otherDrives(m.currentDir)...)
 	}
 
 	// Separate dirs and files
@@ -158,6 +180,23 @@ func (m *FilePickerModel) loadDir() {
 	m.entries = append(m.entries, files...)
 }
 
+// otherDrives returns the Windows drives other than the one root is the
+// root of. A and B, once floppy drives, are left out since looking at
+// them can hang.
+func otherDrives(root string) []FileEntry {
+	var drives []FileEntry
+	for letter := 'C'; letter <= 'Z'; letter++ {
+		drive := string(letter) + `:\`
+		if strings.EqualFold(drive, root) {
+			continue
+		}
+		if _, err := os.Stat(drive); err == nil {
+			drives = append(drives, FileEntry{Name: drive, IsDir: true, Path: drive})
+		}
+	}
+	return drives
+}
+
 func (m *FilePickerModel) matchesExtension(name string) bool {
 	if len(m.extensions) == 0 {
 		return true
@@ -293,7 +332,7 @@ func (m FilePickerModel) View() string {
 	}
 
 	// Separator
-	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#3d5a80")).Render(strings.Repeat("─", min(m.width-4, 60))))
+	b.WriteString(lipgloss.NewStyle().Foreground(palette.Border).Render(strings.Repeat("─", min(m.width-4, 60))))
 	b.WriteString("\n")
 
 	// File list
@@ -347,14 +386,14 @@ func (m FilePickerModel) View() string {
 
 	// Scrollbar indicator
 	if len(m.entries) > visibleHeight {
-		scrollInfo := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render(
+		scrollInfo := lipgloss.NewStyle().Foreground(palette.Muted).Render(
 			strings.Repeat(" ", 50) + "↕ scroll")
 		b.WriteString(scrollInfo)
 		b.WriteString("\n")
 	}
 
 	// Separator
-	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#3d5a80")).Render(strings.Repeat("─", min(m.width-4, 60))))
+	b.WriteString(lipgloss.NewStyle().Foreground(palette.Border).Render(strings.Repeat("─", min(m.width-4, 60))))
 	b.WriteString("\n")
 
 	// Help
@@ -7,55 +7,69 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
 	"github.com/f3rmion/hmm/internal/anki"
 	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
 	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
 	"github.com/f3rmion/hmm/internal/llm"
-	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
-	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 )
 
 // Learn view styles
 var (
+	learnBigCharStyle  lipgloss.Style
+	learnPinyinStyle   lipgloss.Style
+	learnMeaningStyle  lipgloss.Style
+	learnProgressStyle lipgloss.Style
+	learnFlipHintStyle lipgloss.Style
+	learnCardStyle     lipgloss.Style
+)
+
+// setLearnStyles builds the Learn view styles from t.
+func setLearnStyles(t theme.Theme) {
 	learnBigCharStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#ffe66d")).
-				Background(lipgloss.Color("#1a1a2e")).
-				Padding(4, 14).
-				Align(lipgloss.Center)
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.Bg).
+		Padding(4, 14).
+		Align(lipgloss.Center)
 
 	learnPinyinStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ecdc4")).
-				Bold(true).
-				Align(lipgloss.Center).
-				Padding(1, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Align(lipgloss.Center).
+		Padding(1, 1)
 
 	learnMeaningStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#f1faee")).
-				Align(lipgloss.Center)
+		Foreground(t.Text).
+		Align(lipgloss.Center)
 
 	learnProgressStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888"))
+		Foreground(t.Subtle)
 
 	learnFlipHintStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ecdc4")).
-				Bold(true).
-				Align(lipgloss.Center)
+		Foreground(t.Secondary).
+		Bold(true).
+		Align(lipgloss.Center)
 
 	learnCardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#3d5a80")).
-			Padding(2, 4).
-			Align(lipgloss.Center)
-)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Border).
+		Padding(2, 4).
+		Align(lipgloss.Center)
+}
 
 // Message types for learn view
 type learnLLMResultMsg struct {
-	prompt string
-	err    error
+	prompt    string
+	err       error
+	variation bool // A different take on the prompt shown when it was asked for
 }
 
 type learnClearCopiedMsg struct{}
@@ -66,30 +80,69 @@ func learnClearCopiedAfter(d time.Duration) tea.Cmd {
 	})
 }
 
+// revealStage is how much of the back of a card is shown. Space reveals
+// one more stage, so each can be recalled before it is checked.
+type revealStage int
+
+const (
+	revealPinyin revealStage = iota
+	revealMeaning
+	revealHMM
+	revealProps
+	revealPrompt
+)
+
+func (s revealStage) String() string {
+	switch s {
+	case revealMeaning:
+		return "meaning"
+	case revealHMM:
+		return "HMM breakdown"
+	case revealProps:
+		return "props"
+	case revealPrompt:
+		return "prompt"
+	}
+	return "pinyin"
+}
+
 // LearnModel is the flashcard learning view model.
 type LearnModel struct {
 	pkg       *anki.Package
-	parser    *pinyin.Parser
-	dict      *decomp.Dictionary
+	list      *scene.TargetList // Learned from instead of a deck, if set
+	analyzer  *analyze.Analyzer
 	generator *prompt.Generator
 	config    *config.Config
+	scenes    *scene.Store
+
+	// Scene review: study due scenes from the store instead of the deck
+	sceneMode  bool
+	sceneQueue []hmm.Scene
+	reviewed   int
 
 	// Card state
 	notes       []*anki.Note
 	currentNote int
 	flipped     bool
+	stage       revealStage // How much of the back is shown once flipped
 
 	// Current character data
-	character *components.CharacterResult
+	character *analyze.CharacterResult
 
 	// LLM
 	llmClient     *llm.Client
 	llmPrompt     string
 	llmGenerating bool
 	llmError      error
+	editor        promptEditor
+	takes         promptTakes
 
 	// Clipboard
-	copied bool
+	copied   bool
+	copyMenu copyMenu
+	copyNote string // What the copy menu last copied, shown until cleared
+
+	detail scrollPane
 
 	// Display
 	chineseField string
@@ -98,12 +151,12 @@ type LearnModel struct {
 }
 
 // NewLearnModel creates a new learn view model.
-func NewLearnModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client) LearnModel {
+func NewLearnModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client, scenes *scene.Store) LearnModel {
 	return LearnModel{
-		parser:    pinyin.NewParser(),
-		dict:      dict,
+		analyzer:  analyze.New(dict, gen),
 		generator: gen,
 		config:    cfg,
+		scenes:    scenes,
 		llmClient: llmClient,
 	}
 }
@@ -111,6 +164,7 @@ func NewLearnModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Gene
 // SetPackage sets the Anki package to learn from.
 func (m *LearnModel) SetPackage(pkg *anki.Package) {
 	m.pkg = pkg
+	m.list = nil
 	m.llmPrompt = ""
 	m.flipped = false
 
@@ -127,7 +181,7 @@ func (m *LearnModel) SetPackage(pkg *anki.Package) {
 	var notes []*anki.Note
 	for _, note := range pkg.Notes {
 		value := pkg.GetFieldValue(note, m.chineseField)
-		if containsChineseChars(value) {
+		if analyze.ContainsChinese(value) {
 			notes = append(notes, note)
 		}
 	}
@@ -140,6 +194,33 @@ func (m *LearnModel) SetPackage(pkg *anki.Package) {
 	}
 }
 
+// SetList learns the characters of a word list instead of a deck, one
+// card each.
+func (m *LearnModel) SetList(list scene.TargetList) {
+	m.pkg = nil
+	m.notes = nil
+	m.list = &list
+	m.llmPrompt = ""
+	m.llmError = nil
+	m.flipped = false
+	m.currentNote = 0
+	m.character = nil
+	m.setSceneMode(false)
+}
+
+// cardCount returns how many cards the deck or list has.
+func (m LearnModel) cardCount() int {
+	if m.list != nil {
+		return len(m.list.Chars)
+	}
+	return len(m.notes)
+}
+
+// hasCards reports whether a deck or list is loaded.
+func (m LearnModel) hasCards() bool {
+	return m.pkg != nil || m.list != nil
+}
+
 // SetSize updates the view dimensions.
 func (m *LearnModel) SetSize(width, height int) {
 	m.width = width
@@ -148,21 +229,54 @@ func (m *LearnModel) SetSize(width, height int) {
 
 // Update handles messages.
 func (m LearnModel) Update(msg tea.Msg) (LearnModel, tea.Cmd) {
-	// No package loaded
-	if m.pkg == nil {
+	if m.editor.active {
+		saved, cmd := m.editor.update(msg)
+		if saved {
+			m.takes.replace(m.llmPrompt, m.editor.value())
+			m.llmPrompt = m.editor.value()
+		}
+		return m, cmd
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok && m.copyMenu.active {
+		note, err := m.copyMenu.choose(key.String(), *m.character, m.llmPrompt)
+		if err != nil {
+			return m, clipboardToast(err)
+		}
+		if note != "" {
+			m.copyNote = note
+			return m, learnClearCopiedAfter(2 * time.Second)
+		}
+		return m, nil
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "s" && m.scenes != nil {
+		m.setSceneMode(!m.sceneMode)
+		return m, nil
+	}
+
+	if m.sceneMode {
+		return m.updateSceneReview(msg)
+	}
+
+	// No deck or list loaded
+	if !m.hasCards() {
 		return m, nil
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case " ", "enter":
-			// Flip card
-			m.flipped = !m.flipped
+		case " ":
+			// Flip card, then reveal the back a stage at a time
+			m.reveal(false)
+			return m, nil
+		case "enter":
+			m.reveal(true)
 			return m, nil
 		case "right", "l", "n":
 			// Next card
-			if m.currentNote < len(m.notes)-1 {
+			if m.currentNote < m.cardCount()-1 {
 				m.currentNote++
 				m.loadCurrentCard()
 				m.flipped = false
@@ -180,6 +294,12 @@ func (m LearnModel) Update(msg tea.Msg) (LearnModel, tea.Cmd) {
 				m.llmError = nil
 			}
 			return m, nil
+		case "j", "k", "up", "down", "pgup", "pgdown":
+			if m.character != nil {
+				m.layoutCard()
+				m.detail.scroll(msg.String())
+			}
+			return m, nil
 		case "r":
 			// Reset to beginning
 			m.currentNote = 0
@@ -195,15 +315,48 @@ func (m LearnModel) Update(msg tea.Msg) (LearnModel, tea.Cmd) {
 				}
 				m.llmGenerating = true
 				m.llmError = nil
-				return m, m.generateLLMPrompt()
+				m.stage = revealPrompt
+				return m, m.generateLLMPrompt(nil)
 			}
 			return m, nil
 		case "y":
 			if m.llmPrompt != "" {
-				if err := clipboard.Write(m.llmPrompt); err == nil {
-					m.copied = true
-					return m, learnClearCopiedAfter(2 * time.Second)
+				if err := clipboard.Write(m.llmPrompt); err != nil {
+					return m, clipboardToast(err)
 				}
+				m.copied = true
+				return m, learnClearCopiedAfter(2 * time.Second)
+			}
+			return m, nil
+		case "v":
+			if path := m.currentAudio(); path != "" {
+				return m, playAudio(path)
+			}
+			return m, nil
+		case "Y":
+			if m.flipped && m.character != nil {
+				m.copyMenu.active = true
+			}
+			return m, nil
+		case "R":
+			if m.flipped && m.llmPrompt != "" && !m.llmGenerating && m.llmClient != nil {
+				m.llmGenerating = true
+				m.llmError = nil
+				return m, m.generateLLMPrompt(m.takes.all(m.llmPrompt))
+			}
+			return m, nil
+		case ",", ".":
+			delta := 1
+			if msg.String() == "," {
+				delta = -1
+			}
+			if p, ok := m.takes.cycle(m.llmPrompt, delta); ok {
+				m.llmPrompt = p
+			}
+			return m, nil
+		case "e":
+			if m.flipped && m.llmPrompt != "" && !m.llmGenerating {
+				return m, m.editor.open(m.llmPrompt, m.width, m.height-lipgloss.Height(m.renderHeader()))
 			}
 			return m, nil
 		}
@@ -213,93 +366,186 @@ func (m LearnModel) Update(msg tea.Msg) (LearnModel, tea.Cmd) {
 		if msg.err != nil {
 			m.llmError = msg.err
 		} else {
+			if msg.variation {
+				m.takes.add(m.llmPrompt, msg.prompt)
+			}
 			m.llmPrompt = msg.prompt
 		}
-		return m, nil
+		return m, llmToast(msg.err)
 
 	case learnClearCopiedMsg:
 		m.copied = false
+		m.copyNote = ""
 		return m, nil
 	}
 
 	return m, nil
 }
 
-func (m *LearnModel) loadCurrentCard() {
-	if m.currentNote >= len(m.notes) {
+// reveal shows the next stage of the back of the card, or all of it, and
+// flips back to the front once everything is shown. Stages with nothing
+// to show are skipped.
+func (m *LearnModel) reveal(all bool) {
+	switch {
+	case !m.flipped:
+		m.flipped = true
+		m.stage = revealPinyin
+		if all {
+			m.stage = revealPrompt
+		}
+	case m.stage == revealPrompt:
+		m.flipped = false
 		return
+	case all:
+		m.stage = revealPrompt
+	default:
+		m.stage = m.nextStage()
 	}
 
-	note := m.notes[m.currentNote]
-	value := m.pkg.GetFieldValue(note, m.chineseField)
-	value = stripHTMLTags(value)
+	// Keep what was just revealed in view
+	m.layoutCard()
+	m.detail.end()
+}
+
+// nextStage returns the stage Space reveals next, skipping the meaning
+// and props when the character has none.
+func (m LearnModel) nextStage() revealStage {
+	next := m.stage + 1
+	if next == revealMeaning && m.character.Meaning == "" {
+		next++
+	}
+	if next == revealProps && len(m.character.Components) == 0 {
+		next++
+	}
+	return next
+}
 
+// setSceneMode switches between studying the deck and reviewing due scenes.
+func (m *LearnModel) setSceneMode(on bool) {
+	m.sceneMode = on
+	m.flipped = false
+	m.llmPrompt = ""
+	m.llmError = nil
 	m.character = nil
 
-	// Get first Chinese character
-	for _, r := range value {
-		if r >= 0x4E00 && r <= 0x9FFF {
-			char := string(r)
-			m.character = m.analyzeChar(char)
-			break
+	if !on {
+		if m.cardCount() > 0 {
+			m.loadCurrentCard()
 		}
+		return
 	}
+
+	m.sceneQueue = m.scenes.Due(time.Now())
+	m.reviewed = 0
+	m.loadSceneCard()
 }
 
-func (m *LearnModel) analyzeChar(char string) *components.CharacterResult {
-	readings := m.parser.ParseChar(char)
-	if len(readings) == 0 {
-		return nil
+// loadSceneCard shows the scene at the head of the review queue.
+func (m *LearnModel) loadSceneCard() {
+	m.character = nil
+	m.llmPrompt = ""
+	if len(m.sceneQueue) == 0 {
+		return
+	}
+	sc := m.sceneQueue[0]
+	m.character = m.analyzer.Character(sc.Character)
+	m.llmPrompt = sc.ImagePrompt
+}
+
+// updateSceneReview handles keys while reviewing scenes: space reveals
+// the scene, then grades it as good; a/h/e grade again/hard/easy.
+func (m LearnModel) updateSceneReview(msg tea.Msg) (LearnModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || len(m.sceneQueue) == 0 {
+		return m, nil
 	}
 
-	reading := readings[0]
-
-	result := &components.CharacterResult{
-		Character: char,
-		Pinyin:    reading.Full,
-		Initial:   reading.Initial,
-		Final:     reading.Final,
-		Tone:      reading.Tone,
-		ActorID:   pinyin.GetActorID(reading.Initial),
-		SetID:     pinyin.GetSetID(reading.Final),
-	}
-
-	if m.dict != nil {
-		if entry := m.dict.Lookup(char); entry != nil {
-			result.Meaning = entry.Definition
-			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
-			result.Components = decomp.ExtractComponents(entry.Decomposition)
-			if entry.Etymology != nil {
-				if entry.Etymology.Hint != "" {
-					result.Etymology = entry.Etymology.Hint
-				} else {
-					result.Etymology = entry.Etymology.Type
-				}
+	switch key.String() {
+	case "j", "k", "up", "down", "pgup", "pgdown":
+		m.layoutCard()
+		m.detail.scroll(key.String())
+		return m, nil
+	}
+
+	if !m.flipped {
+		if k := key.String(); k == " " || k == "enter" {
+			m.flipped = true
+		}
+		return m, nil
+	}
+
+	var grade scene.Grade
+	switch key.String() {
+	case " ", "enter":
+		grade = scene.GradeGood
+	case "a":
+		grade = scene.GradeAgain
+	case "h":
+		grade = scene.GradeHard
+	case "e":
+		grade = scene.GradeEasy
+	case "y":
+		if m.llmPrompt != "" {
+			if err := clipboard.Write(m.llmPrompt); err != nil {
+				return m, clipboardToast(err)
 			}
+			m.copied = true
+			return m, learnClearCopiedAfter(2 * time.Second)
 		}
+		return m, nil
+	default:
+		return m, nil
 	}
 
-	if actor := m.generator.GetActor(result.ActorID); actor != nil {
-		result.ActorName = actor.Name
+	sceneKey := scene.Key(m.sceneQueue[0])
+	if err := m.scenes.Grade(sceneKey, grade, time.Now()); err != nil {
+		m.llmError = err
+		return m, nil
 	}
-	if set := m.generator.GetSet(result.SetID); set != nil {
-		result.SetName = set.Name
+	if err := m.scenes.Save(); err != nil {
+		m.llmError = err
+		return m, nil
 	}
-	result.ToneRoom = m.generator.GetToneRoom(
-		m.generator.GetSet(result.SetID),
-		reading.Tone,
-	)
 
-	for _, comp := range result.Components {
-		if p := m.generator.GetProp(comp); p != nil && p.Name != "" {
-			result.PropNames = append(result.PropNames, p.Name)
+	m.reviewed++
+	m.sceneQueue = m.sceneQueue[1:]
+	// Forgotten scenes come back at the end of this session
+	if grade == scene.GradeAgain {
+		if sc := m.scenes.Get(sceneKey); sc != nil {
+			m.sceneQueue = append(m.sceneQueue, *sc)
 		}
 	}
+	m.flipped = false
+	m.llmError = nil
+	m.loadSceneCard()
 
-	return result
+	return m, nil
 }
 
-func (m *LearnModel) generateLLMPrompt() tea.Cmd {
+func (m *LearnModel) loadCurrentCard() {
+	if m.currentNote >= m.cardCount() {
+		return
+	}
+	if m.list != nil {
+		m.character = m.analyzer.Character(m.list.Chars[m.currentNote])
+		return
+	}
+
+	note := m.notes[m.currentNote]
+	value := m.pkg.GetFieldValue(note, m.chineseField)
+	value = htmlutil.Text(value)
+
+	m.character = nil
+
+	// Get first Chinese character
+	if chars := analyze.ChineseChars(value); len(chars) > 0 {
+		m.character = m.analyzer.Character(chars[0])
+	}
+}
+
+// generateLLMPrompt asks the LLM for an image prompt. With earlier takes
+// in previous it asks for a different take instead.
+func (m *LearnModel) generateLLMPrompt(previous []string) tea.Cmd {
 	if m.character == nil || m.llmClient == nil {
 		return nil
 	}
@@ -307,38 +553,13 @@ func (m *LearnModel) generateLLMPrompt() tea.Cmd {
 	r := m.character
 	client := m.llmClient
 
-	elements := llm.SceneElements{
-		Character: r.Character,
-		Pinyin:    r.Pinyin,
-		Meaning:   r.Meaning,
-		ActorName: r.ActorName,
-		SetName:   r.SetName,
-		ToneRoom:  r.ToneRoom,
-		Props:     r.PropNames,
-	}
-
-	if m.config != nil {
-		for _, a := range m.config.Actors {
-			if a.ID == r.ActorID {
-				elements.ActorDesc = a.Description
-				break
-			}
-		}
-		for _, s := range m.config.Sets {
-			if s.ID == r.SetID {
-				elements.SetDesc = s.Description
-				for _, room := range s.Rooms {
-					if hmm.Tone(room.Tone) == r.Tone {
-						elements.ToneRoomDesc = room.Description
-						break
-					}
-				}
-				break
-			}
-		}
-	}
+	elements := m.analyzer.SceneElements(*r)
 
 	return func() tea.Msg {
+		if len(previous) > 0 {
+			prompt, err := client.GenerateVariation(elements, previous)
+			return learnLLMResultMsg{prompt: prompt, err: err, variation: true}
+		}
 		prompt, err := client.GenerateScene(elements)
 		return learnLLMResultMsg{prompt: prompt, err: err}
 	}
@@ -346,50 +567,179 @@ func (m *LearnModel) generateLLMPrompt() tea.Cmd {
 
 // View renders the learn view.
 func (m LearnModel) View() string {
-	// No package loaded
-	if m.pkg == nil {
-		return m.renderNoPackage()
+	if m.sceneMode {
+		if len(m.sceneQueue) == 0 {
+			return m.renderNoScenesDue()
+		}
+	} else {
+		// No deck or list loaded
+		if !m.hasCards() {
+			return m.renderNoPackage()
+		}
+
+		if m.character == nil {
+			return helpStyle.Render("No characters found in deck")
+		}
 	}
 
-	if m.character == nil {
-		return helpStyle.Render("No characters found in deck")
+	if m.editor.active {
+		return m.renderHeader() + m.editor.View()
 	}
+	m.layoutCard()
+	return m.renderHeader() + m.detail.View() + m.renderHelp()
+}
 
-	var b strings.Builder
+// Shown returns the character of the card shown, or nil if there is none.
+func (m LearnModel) Shown() *analyze.CharacterResult {
+	return m.character
+}
 
-	// Progress
-	progress := learnProgressStyle.Render(
-		fmt.Sprintf("Card %d of %d", m.currentNote+1, len(m.notes)),
-	)
-	b.WriteString(progress)
-	b.WriteString("\n\n")
+// Position returns the index of the card shown, so a later session can
+// resume there with Restore.
+func (m LearnModel) Position() int {
+	return m.currentNote
+}
+
+// Restore shows the front of the card at index card, as saved by
+// Position.
+func (m *LearnModel) Restore(card int) {
+	if card < 0 || card >= m.cardCount() {
+		return
+	}
+	m.currentNote = card
+	m.loadCurrentCard()
+	m.flipped = false
+	m.llmPrompt = ""
+	m.llmError = nil
+}
+
+// Editing reports whether the prompt editor is open, so the app should
+// pass every key through.
+func (m LearnModel) Editing() bool {
+	return m.editor.active
+}
+
+// CopyMenuOpen reports whether the copy menu is waiting for a key, so the
+// app should pass every key through.
+func (m LearnModel) CopyMenuOpen() bool {
+	return m.copyMenu.active
+}
+
+// ScrollDetail scrolls the card down n lines, or up if n is negative.
+func (m *LearnModel) ScrollDetail(n int) {
+	if m.sceneMode && len(m.sceneQueue) == 0 || !m.sceneMode && m.character == nil {
+		return
+	}
+	m.layoutCard()
+	m.detail.scrollBy(n)
+}
 
-	// Card content
+// layoutCard fits the current card between the progress line and the
+// help line.
+func (m *LearnModel) layoutCard() {
+	id := fmt.Sprintf("deck:%d:%t", m.currentNote, m.flipped)
+	if m.sceneMode {
+		id = fmt.Sprintf("scene:%s:%t", m.sceneQueue[0].Character, m.flipped)
+	}
+	m.detail.fit(id, m.renderCard(), m.width, m.height, m.renderHeader(), m.renderHelp())
+}
+
+// renderHeader renders the progress line.
+func (m LearnModel) renderHeader() string {
+	if m.sceneMode {
+		return learnProgressStyle.Render(
+			fmt.Sprintf("Scene review • %d due • %d reviewed", len(m.sceneQueue), m.reviewed),
+		) + "\n\n"
+	}
+	progress := fmt.Sprintf("Card %d of %d", m.currentNote+1, m.cardCount())
+	if m.list != nil {
+		progress += " • " + m.list.Name
+	}
+	return learnProgressStyle.Render(progress) + "\n\n"
+}
+
+// renderCard renders the front or back of the current card.
+func (m LearnModel) renderCard() string {
 	contentWidth := m.width - 4
 	if contentWidth < 40 {
 		contentWidth = 40
 	}
 
+	if m.character == nil {
+		return learnBigCharStyle.Render(m.sceneQueue[0].Character)
+	}
 	if m.flipped {
-		b.WriteString(m.renderFlippedCard(contentWidth))
-	} else {
-		b.WriteString(m.renderFrontCard(contentWidth))
+		return m.renderFlippedCard(contentWidth)
 	}
+	return m.renderFrontCard(contentWidth)
+}
 
-	// Help
-	b.WriteString("\n\n")
-	if m.flipped {
-		helpText := "space: flip • ←/→: prev/next • r: reset"
+// renderHelp renders the help line below the card.
+func (m LearnModel) renderHelp() string {
+	if m.copyMenu.active {
+		return "\n\n" + helpStyle.Render(m.copyMenu.View())
+	}
+
+	var help string
+	switch {
+	case m.sceneMode && m.flipped:
+		help = "space: good • a: again • h: hard • e: easy"
 		if m.llmPrompt != "" {
-			helpText += " • y: copy"
-		} else {
-			helpText += " • g: generate"
+			help += " • y: copy"
+		}
+		help += " • s: back to deck"
+	case m.sceneMode:
+		help = "space: reveal • s: back to deck"
+	default:
+		help = "space: flip • ←/→: prev/next • r: reset"
+		if m.flipped && m.stage < revealPrompt {
+			help = "space: reveal more • enter: reveal all • ←/→: prev/next • r: reset"
+		}
+		if m.flipped {
+			if m.llmPrompt != "" {
+				help += " • R: variation • e: edit • y: copy"
+				if m.takes.label(m.llmPrompt) != "" {
+					help += " • ,/.: takes"
+				}
+			} else {
+				help += " • g: generate"
+			}
+			help += " • Y: copy…"
+			if m.currentAudio() != "" {
+				help += " • v: play"
+			}
+		}
+		if m.scenes != nil {
+			help += " • s: review scenes"
 		}
-		b.WriteString(helpStyle.Render(helpText))
-	} else {
-		b.WriteString(helpStyle.Render("space: flip • ←/→: prev/next • r: reset"))
 	}
 
+	if scroll := m.detail.help(); scroll != "" {
+		help += " • " + scroll
+	}
+	if m.copyNote != "" {
+		return "\n\n" + copiedStyle.Render(m.copyNote) + helpStyle.Render(" • "+help)
+	}
+	return "\n\n" + helpStyle.Render(help)
+}
+
+// renderNoScenesDue renders the end of a scene review session.
+func (m LearnModel) renderNoScenesDue() string {
+	var b strings.Builder
+
+	box := lipgloss.NewStyle().
+		Border(palette.BoxBorder()).
+		BorderForeground(palette.Border).
+		Padding(2, 4).
+		Align(lipgloss.Center)
+
+	content := browseNoDataStyle.Render("No Scenes Due") + "\n\n" +
+		helpStyle.Render(fmt.Sprintf("Reviewed %d scenes this session", m.reviewed))
+
+	b.WriteString("\n\n")
+	b.WriteString(box.Render(content))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("s: back to deck"))
 	return b.String()
 }
 
@@ -397,13 +747,17 @@ func (m LearnModel) renderNoPackage() string {
 	var b strings.Builder
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#3d5a80")).
+		Border(palette.BoxBorder()).
+		BorderForeground(palette.Border).
 		Padding(2, 4).
 		Align(lipgloss.Center)
 
+	hint := "Load a deck in Browse or Open Deck first,\nor press L on a list in Stats"
+	if m.scenes != nil {
+		hint += "\nor press 's' to review your scenes"
+	}
 	content := browseNoDataStyle.Render("No Anki Deck Loaded") + "\n\n" +
-		helpStyle.Render("Load a deck in Browse or Open Deck first")
+		helpStyle.Render(hint)
 
 	b.WriteString("\n\n")
 	b.WriteString(box.Render(content))
@@ -422,18 +776,27 @@ func (m LearnModel) renderFrontCard(contentWidth int) string {
 		Align(lipgloss.Center).
 		Render(charDisplay)
 
-	hint := learnFlipHintStyle.Width(contentWidth).Render("Press SPACE to reveal")
+	hint := learnFlipHintStyle.Width(contentWidth).Render("Press SPACE to reveal the pinyin, ENTER for everything")
 
 	return charBlock + "\n\n" + hint
 }
 
+// currentAudio returns the recording of the card's character, or "" if
+// there is none.
+func (m LearnModel) currentAudio() string {
+	if m.sceneMode || m.character == nil || m.pkg == nil || m.currentNote >= len(m.notes) {
+		return ""
+	}
+	return audioFor(m.pkg, m.notes[m.currentNote], m.character.Character)
+}
+
 func (m LearnModel) renderFlippedCard(contentWidth int) string {
 	var b strings.Builder
 	r := m.character
 
 	// Character with pinyin
 	charDisplay := learnBigCharStyle.Render(r.Character)
-	pinyinDisplay := learnPinyinStyle.Render(r.Pinyin)
+	pinyinDisplay := learnPinyinStyle.Render(r.Pinyin + speaker(m.currentAudio()))
 
 	charBlock := lipgloss.JoinVertical(lipgloss.Center, charDisplay, pinyinDisplay)
 	centered := lipgloss.NewStyle().
@@ -445,11 +808,8 @@ func (m LearnModel) renderFlippedCard(contentWidth int) string {
 	b.WriteString("\n")
 
 	// Meaning
-	if r.Meaning != "" {
-		meaning := r.Meaning
-		if len(meaning) > 60 {
-			meaning = meaning[:60] + "..."
-		}
+	if m.stage >= revealMeaning && r.Meaning != "" {
+		meaning := textutil.Truncate(r.Meaning, 60)
 		meaningDisplay := learnMeaningStyle.Width(contentWidth).Render(meaning)
 		b.WriteString(meaningDisplay)
 		b.WriteString("\n")
@@ -457,17 +817,22 @@ func (m LearnModel) renderFlippedCard(contentWidth int) string {
 	b.WriteString("\n")
 
 	// HMM Breakdown
-	b.WriteString(m.renderHMMBox(r))
-	b.WriteString("\n")
+	if m.stage >= revealHMM {
+		b.WriteString(m.renderHMMBox(r))
+		b.WriteString("\n")
+	}
 
 	// Components
-	if len(r.Components) > 0 {
+	if m.stage >= revealProps && len(r.Components) > 0 {
 		b.WriteString(m.renderComponentsBox(r))
 		b.WriteString("\n")
 	}
 
 	// LLM prompt
-	if m.llmGenerating {
+	if m.stage < revealPrompt {
+		b.WriteString("\n")
+		b.WriteString(learnFlipHintStyle.Width(contentWidth).Render("Press SPACE to reveal the " + m.nextStage().String()))
+	} else if m.llmGenerating && m.llmPrompt == "" {
 		b.WriteString("\n")
 		b.WriteString(loadingStyle.Render("Generating image prompt..."))
 	} else if m.llmError != nil {
@@ -482,6 +847,11 @@ func (m LearnModel) renderFlippedCard(contentWidth int) string {
 		if m.copied {
 			headerText += "  " + copiedStyle.Render("Copied!")
 		}
+		if m.llmGenerating {
+			headerText += "  " + loadingStyle.Render("Generating another take...")
+		} else if label := m.takes.label(m.llmPrompt); label != "" {
+			headerText += "  " + helpStyle.Render("("+label+")")
+		}
 		llmBox := llmPromptStyle.Width(width).Render(
 			headerText + "\n\n" + wordWrap(m.llmPrompt, width-6),
 		)
@@ -491,7 +861,7 @@ func (m LearnModel) renderFlippedCard(contentWidth int) string {
 	return b.String()
 }
 
-func (m LearnModel) renderHMMBox(r *components.CharacterResult) string {
+func (m LearnModel) renderHMMBox(r *analyze.CharacterResult) string {
 	var lines []string
 
 	initial := r.Initial
@@ -529,7 +899,7 @@ func (m LearnModel) renderHMMBox(r *components.CharacterResult) string {
 	)
 }
 
-func (m LearnModel) renderComponentsBox(r *components.CharacterResult) string {
+func (m LearnModel) renderComponentsBox(r *analyze.CharacterResult) string {
 	var lines []string
 
 	for i, comp := range r.Components {
@@ -9,137 +9,203 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/audio"
 	"github.com/f3rmion/hmm/internal/clipboard"
 	"github.com/f3rmion/hmm/internal/config"
 	"github.com/f3rmion/hmm/internal/decomp"
-	"github.com/f3rmion/hmm/internal/hmm"
 	"github.com/f3rmion/hmm/internal/llm"
 	"github.com/f3rmion/hmm/internal/pinyin"
 	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/textutil"
 	"github.com/f3rmion/hmm/internal/tui/bigchar"
-	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 	"github.com/mattn/go-runewidth"
 )
 
 // Styles (use from parent package or define locally)
 var (
+	titleStyle         lipgloss.Style
+	subtitleStyle      lipgloss.Style
+	charTabStyle       lipgloss.Style
+	charTabActiveStyle lipgloss.Style
+	charTabPinyinStyle lipgloss.Style
+	characterStyle     lipgloss.Style
+	bigCharStyle       lipgloss.Style
+	pinyinUnderStyle   lipgloss.Style
+	labelStyle         lipgloss.Style
+	valueStyle         lipgloss.Style
+	actorStyle         lipgloss.Style
+	setStyle           lipgloss.Style
+	propStyle          lipgloss.Style
+	toneStyle          lipgloss.Style
+	helpStyle          lipgloss.Style
+	errorStyle         lipgloss.Style
+	boxStyle           lipgloss.Style
+	wordNavStyle       lipgloss.Style
+	wordDisplayStyle   lipgloss.Style
+	llmPromptStyle     lipgloss.Style
+	loadingStyle       lipgloss.Style
+	copiedStyle        lipgloss.Style
+)
+
+// setLookupStyles builds the shared and Lookup view styles from t.
+func setLookupStyles(t theme.Theme) {
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FF6B6B")).
-			Background(lipgloss.Color("#1a1a2e")).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(t.Primary).
+		Background(t.Bg).
+		Padding(0, 1)
 
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4"))
+		Foreground(t.Secondary)
 
 	charTabStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Padding(0, 2).
-			Margin(0, 1)
+		Foreground(t.Subtle).
+		Padding(0, 2).
+		Margin(0, 1)
 
-	charTabActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#ffe66d")).
-				Background(lipgloss.Color("#2d3436")).
-				Padding(0, 2).
-				Margin(0, 1)
+	charTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(0, 2).
+		Margin(0, 1))
 
 	charTabPinyinStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666")).
-				Italic(true)
+		Foreground(t.Muted).
+		Italic(true)
 
 	characterStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ffe66d")).
-			Background(lipgloss.Color("#2d3436")).
-			Padding(2, 6).
-			Margin(1, 0).
-			Align(lipgloss.Center)
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(2, 6).
+		Margin(1, 0).
+		Align(lipgloss.Center)
 
 	bigCharStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ffe66d")).
-			Background(lipgloss.Color("#1a1a2e")).
-			Padding(3, 12).
-			Align(lipgloss.Center)
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.Bg).
+		Padding(3, 12).
+		Align(lipgloss.Center)
 
 	pinyinUnderStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ecdc4")).
-				Bold(true).
-				Align(lipgloss.Center).
-				Padding(0, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Align(lipgloss.Center).
+		Padding(0, 1)
 
 	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8dadc")).
-			Bold(true).
-			Width(12)
+		Foreground(t.Label).
+		Bold(true).
+		Width(12)
 
 	valueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
 	actorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff6b6b")).
-			Bold(true)
+		Foreground(t.Primary).
+		Bold(true)
 
 	setStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true)
+		Foreground(t.Secondary).
+		Bold(true)
 
 	propStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffe66d")).
-			Bold(true)
+		Foreground(t.Accent).
+		Bold(true)
 
 	toneStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8e6cf")).
-			Bold(true)
+		Foreground(t.Success).
+		Bold(true)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666"))
+		Foreground(t.Muted)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff6b6b")).
-			Bold(true)
+		Foreground(t.Primary).
+		Bold(true)
 
 	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#3d5a80")).
-			Padding(1, 2)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Border).
+		Padding(1, 2)
 
 	wordNavStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4ecdc4")).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(t.Secondary).
+		Bold(true).
+		Padding(0, 1)
 
 	wordDisplayStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#ffe66d")).
-				Padding(0, 2).
-				Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 2).
+		Margin(1, 0)
 
 	llmPromptStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#ff6b6b")).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(t.BoxBorder()).
+		BorderForeground(t.Primary).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	loadingStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffe66d")).
-			Bold(true).
-			Italic(true)
+		Foreground(t.Accent).
+		Bold(true).
+		Italic(true)
 
 	copiedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#a8e6cf")).
-			Bold(true)
-)
+		Foreground(t.Success).
+		Bold(true)
+}
 
 // Message types
 type llmResultMsg struct {
-	prompt string
-	err    error
+	prompt    string
+	err       error
+	variation bool // A different take on the prompt shown when it was asked for
+}
+
+// storyResultMsg carries the story linking the analyzed characters.
+type storyResultMsg struct {
+	story string
+	err   error
 }
 
 type clearCopiedMsg struct{}
 
+// bigCharRenderedMsg reports that a big character finished rendering in
+// the background and is now cached.
+type bigCharRenderedMsg struct {
+	char string
+}
+
+// Size of the rendered big character in the detail pane
+const (
+	bigCharCols = 30
+	bigCharRows = 15
+)
+
+// HistoryChangedMsg is sent when an input is added to the Lookup history.
+type HistoryChangedMsg struct{}
+
+// maxHistory is how many past inputs the Lookup history keeps.
+const maxHistory = 100
+
+// meaningSearchPrefix starts a search of dictionary definitions instead
+// of scenes, as in "m water".
+const meaningSearchPrefix = "m "
+
+// maxMeaningResults is how many characters a meaning search shows, the
+// closest matches first.
+const maxMeaningResults = 8
+
+// lookupCharLimit is how long the Lookup input may get, enough for a
+// sentence pasted from elsewhere.
+const lookupCharLimit = 200
+
 func clearCopiedAfter(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(time.Time) tea.Msg {
 		return clearCopiedMsg{}
@@ -149,16 +215,30 @@ func clearCopiedAfter(d time.Duration) tea.Cmd {
 // LookupModel is the character lookup view model.
 type LookupModel struct {
 	input     textinput.Model
-	parser    *pinyin.Parser
+	analyzer  *analyze.Analyzer
 	dict      *decomp.Dictionary
 	generator *prompt.Generator
 	config    *config.Config
+	scenes    *scene.Store
 
 	// Multi-character support
-	characters []components.CharacterResult
+	characters []analyze.CharacterResult
 	selected   int
 	inputText  string
 
+	// Input history, oldest first. historyPos is the entry being shown,
+	// or len(history) while editing a new input, which is kept in draft.
+	history    []string
+	historyPos int
+	draft      string
+
+	// Scene and meaning search
+	searchInput   textinput.Model
+	searching     bool
+	searchTerm    string
+	searchHits    map[string]string // Character to matching snippet
+	searchMeaning bool              // Searched definitions rather than scenes
+
 	prompt string
 	err    error
 
@@ -167,31 +247,59 @@ type LookupModel struct {
 	llmPrompt     string
 	llmGenerating bool
 	llmError      error
+	editor        promptEditor
+	takes         promptTakes
+
+	// Story linking the scenes of all analyzed characters
+	story           string
+	storyGenerating bool
+
+	// Etymology shown in depth, with the phonetic series, not as one row
+	etymologyOpen bool
+
+	// Romanization shown under the pinyin, or nil
+	romanizer pinyin.Romanizer
 
 	// Clipboard
-	copied bool
+	copied   bool
+	copyMenu copyMenu
+	copyNote string // What the copy menu last copied, shown until cleared
+
+	detail scrollPane
+
+	// Big characters being rendered in the background
+	bigCharPending map[string]bool
 
 	width  int
 	height int
 }
 
 // NewLookupModel creates a new lookup view model.
-func NewLookupModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client) LookupModel {
+func NewLookupModel(dict *decomp.Dictionary, cfg *config.Config, gen *prompt.Generator, llmClient *llm.Client, scenes *scene.Store) LookupModel {
 	ti := textinput.New()
 	ti.Placeholder = "Enter Chinese characters..."
 	ti.Focus()
-	ti.CharLimit = 50
+	ti.CharLimit = lookupCharLimit
 	ti.Width = 40
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ecdc4"))
-	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffe66d"))
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(palette.Secondary)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(palette.Accent)
+
+	si := textinput.New()
+	si.Placeholder = "Words from a story, or m <meaning>..."
+	si.CharLimit = 50
+	si.Width = 30
 
 	return LookupModel{
-		input:     ti,
-		parser:    pinyin.NewParser(),
-		dict:      dict,
-		generator: gen,
-		config:    cfg,
-		llmClient: llmClient,
+		input:       ti,
+		searchInput: si,
+		analyzer:    analyze.New(dict, gen),
+		dict:        dict,
+		generator:   gen,
+		config:      cfg,
+		scenes:      scenes,
+		llmClient:   llmClient,
+
+		bigCharPending: make(map[string]bool),
 	}
 }
 
@@ -201,17 +309,122 @@ func (m *LookupModel) SetSize(width, height int) {
 	m.height = height
 }
 
-// Update handles messages.
+// Update handles messages, then starts rendering the selected big
+// character if it is not cached yet.
 func (m LookupModel) Update(msg tea.Msg) (LookupModel, tea.Cmd) {
+	if msg, ok := msg.(bigCharRenderedMsg); ok {
+		delete(m.bigCharPending, msg.char)
+		return m, nil
+	}
+
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.renderBigChar())
+}
+
+// renderBigChar returns a command that renders the selected character in
+// the background, or nil if it is cached or already being rendered.
+func (m LookupModel) renderBigChar() tea.Cmd {
+	if !bigchar.IsAvailable() || m.selected >= len(m.characters) {
+		return nil
+	}
+	char := m.characters[m.selected].Character
+	if m.bigCharPending[char] {
+		return nil
+	}
+	if _, ok := bigchar.Cached(char, bigCharCols, bigCharRows); ok {
+		return nil
+	}
+
+	m.bigCharPending[char] = true
+	return func() tea.Msg {
+		bigchar.Render(char, bigCharCols, bigCharRows)
+		return bigCharRenderedMsg{char: char}
+	}
+}
+
+func (m LookupModel) update(msg tea.Msg) (LookupModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.editor.active {
+		saved, cmd := m.editor.update(msg)
+		if saved {
+			m.takes.replace(m.llmPrompt, m.editor.value())
+			m.llmPrompt = m.editor.value()
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m, cmd
+		}
+		cmds = append(cmds, cmd)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.copyMenu.active {
+			note, err := m.copyMenu.choose(msg.String(), m.characters[m.selected], m.llmPrompt)
+			if err != nil {
+				return m, clipboardToast(err)
+			}
+			if note != "" {
+				m.copyNote = note
+				return m, clearCopiedAfter(2 * time.Second)
+			}
+			return m, nil
+		}
+
+		if m.searching {
+			switch msg.String() {
+			case "enter":
+				m.searching = false
+				m.searchInput.Blur()
+				m.input.Focus()
+				m.search(m.searchInput.Value())
+				m.llmPrompt = ""
+				m.llmError = nil
+				return m, nil
+			case "esc":
+				m.searching = false
+				m.searchInput.Blur()
+				m.searchInput.SetValue("")
+				m.input.Focus()
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.searchInput, cmd = m.searchInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		if msg.Paste {
+			return m, m.paste(string(msg.Runes))
+		}
+
 		switch msg.String() {
+		case "ctrl+v":
+			text, err := clipboard.Read()
+			if err != nil {
+				return m, toast(ToastError, "Could not paste: %v", err)
+			}
+			return m, m.paste(text)
+		case "alt+v":
+			text, err := clipboard.Read()
+			if err != nil {
+				return m, toast(ToastError, "Could not paste: %v", err)
+			}
+			m.input.SetValue("")
+			return m, tea.Batch(m.paste(text), m.submit())
+		case "/":
+			m.searching = true
+			m.input.Blur()
+			m.searchInput.SetValue("")
+			m.searchInput.Focus()
+			return m, textinput.Blink
 		case "enter":
-			m.analyzeInput()
-			m.llmPrompt = ""
-			m.llmError = nil
+			return m, m.submit()
+		case "up":
+			m.recallHistory(-1)
+			return m, nil
+		case "down":
+			m.recallHistory(1)
 			return m, nil
 		case "left", "h":
 			if len(m.characters) > 0 {
@@ -243,17 +456,81 @@ func (m LookupModel) Update(msg tea.Msg) (LookupModel, tea.Cmd) {
 				}
 				m.llmGenerating = true
 				m.llmError = nil
-				return m, m.generateLLMPrompt()
+				return m, m.generateLLMPrompt(nil)
 			}
 			return m, nil
 		case "y":
 			if m.llmPrompt != "" {
-				if err := clipboard.Write(m.llmPrompt); err == nil {
-					m.copied = true
-					return m, clearCopiedAfter(2 * time.Second)
+				if err := clipboard.Write(m.llmPrompt); err != nil {
+					return m, clipboardToast(err)
+				}
+				m.copied = true
+				return m, clearCopiedAfter(2 * time.Second)
+			}
+			return m, nil
+		case "T":
+			if len(m.characters) > 1 && !m.storyGenerating {
+				if m.llmClient == nil {
+					m.story = analyze.Story(m.characters)
+					return m, nil
 				}
+				m.storyGenerating = true
+				return m, m.generateStory()
+			}
+			return m, nil
+		case "E":
+			if len(m.characters) > 0 {
+				m.etymologyOpen = !m.etymologyOpen
+			}
+			return m, nil
+		case "C":
+			if len(m.characters) > 1 {
+				a := m.characters[m.selected].Character
+				b := m.characters[(m.selected+1)%len(m.characters)].Character
+				return m, func() tea.Msg { return CompareMsg{A: a, B: b} }
+			}
+			return m, nil
+		case "v":
+			if path := m.currentAudio(); path != "" {
+				return m, playAudio(path)
 			}
 			return m, nil
+		case "Y":
+			if len(m.characters) > 0 {
+				m.copyMenu.active = true
+			}
+			return m, nil
+		case "S":
+			if len(m.characters) > 0 && m.scenes != nil && !m.llmGenerating {
+				return m, m.saveScene()
+			}
+			return m, nil
+		case "R":
+			if m.llmPrompt != "" && !m.llmGenerating && m.llmClient != nil {
+				m.llmGenerating = true
+				m.llmError = nil
+				return m, m.generateLLMPrompt(m.takes.all(m.llmPrompt))
+			}
+			return m, nil
+		case ",", ".":
+			delta := 1
+			if msg.String() == "," {
+				delta = -1
+			}
+			if p, ok := m.takes.cycle(m.llmPrompt, delta); ok {
+				m.llmPrompt = p
+				return m, nil
+			}
+		case "e":
+			if m.llmPrompt != "" && !m.llmGenerating {
+				return m, m.editor.open(m.llmPrompt, m.width, m.height-lipgloss.Height(m.renderHeader()))
+			}
+		case "j", "k", "pgup", "pgdown":
+			if len(m.characters) > 0 {
+				m.layoutDetail()
+				m.detail.scroll(msg.String())
+				return m, nil
+			}
 		}
 
 	case llmResultMsg:
@@ -261,12 +538,23 @@ func (m LookupModel) Update(msg tea.Msg) (LookupModel, tea.Cmd) {
 		if msg.err != nil {
 			m.llmError = msg.err
 		} else {
+			if msg.variation {
+				m.takes.add(m.llmPrompt, msg.prompt)
+			}
 			m.llmPrompt = msg.prompt
 		}
-		return m, nil
+		return m, llmToast(msg.err)
+
+	case storyResultMsg:
+		m.storyGenerating = false
+		if msg.err == nil {
+			m.story = msg.story
+		}
+		return m, llmToast(msg.err)
 
 	case clearCopiedMsg:
 		m.copied = false
+		m.copyNote = ""
 		return m, nil
 	}
 
@@ -279,11 +567,48 @@ func (m LookupModel) Update(msg tea.Msg) (LookupModel, tea.Cmd) {
 
 // View renders the lookup view.
 func (m LookupModel) View() string {
+	if m.editor.active {
+		return m.renderHeader() + m.editor.View()
+	}
+	m.layoutDetail()
+	return m.renderHeader() + m.detail.View() + m.renderHelp()
+}
+
+// layoutDetail fits the selected character's details between the header
+// and the help line.
+func (m *LookupModel) layoutDetail() {
+	var id, detail string
+	if m.selected < len(m.characters) {
+		id = m.characters[m.selected].Character
+		detail = m.renderCharacterDetail(m.characters[m.selected])
+	}
+	m.detail.fit(id, detail, m.width, m.height, m.renderHeader(), m.renderHelp())
+}
+
+// renderHeader renders the input, status lines, and character tabs.
+func (m LookupModel) renderHeader() string {
 	var b strings.Builder
 
 	// Input
-	b.WriteString(m.input.View())
+	if m.searching {
+		b.WriteString(browseSearchBoxStyle.Render("Search: " + m.searchInput.View()))
+	} else {
+		b.WriteString(m.input.View())
+	}
 	b.WriteString("\n")
+	if m.searchTerm != "" && len(m.characters) > 0 {
+		matches := "scenes match"
+		switch {
+		case m.searchMeaning && len(m.characters) == 1:
+			matches = "character means"
+		case m.searchMeaning:
+			matches = "characters mean"
+		case len(m.characters) == 1:
+			matches = "scene matches"
+		}
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%d %s \"%s\"", len(m.characters), matches, m.searchTerm)))
+		b.WriteString("\n")
+	}
 
 	// Error
 	if m.err != nil {
@@ -292,30 +617,62 @@ func (m LookupModel) View() string {
 		b.WriteString("\n")
 	}
 
-	// Results
-	if len(m.characters) > 0 {
-		b.WriteString(m.renderMultiCharView())
+	// Character tabs for multi-character words
+	if len(m.characters) > 1 {
+		b.WriteString(m.renderWordBar())
+		b.WriteString("\n")
 	}
 
-	// Help
-	b.WriteString("\n")
-	if len(m.characters) > 0 {
-		var helpParts []string
-		if len(m.characters) > 1 {
-			helpParts = append(helpParts, "←/→: navigate")
-		}
-		helpParts = append(helpParts, "g: generate")
-		if m.llmPrompt != "" {
-			helpParts = append(helpParts, "y: copy")
+	return b.String()
+}
+
+// renderHelp renders the help line below the details.
+func (m LookupModel) renderHelp() string {
+	if len(m.characters) == 0 {
+		hint := "Type characters and press Enter to analyze"
+		if m.scenes != nil {
+			hint += " • /: search scenes, or m <meaning>"
+		} else {
+			hint += " • /: m <meaning> to search by meaning"
 		}
-		help := helpStyle.Render(strings.Join(helpParts, " • "))
-		b.WriteString(help)
-	} else {
-		help := helpStyle.Render("Type characters and press Enter to analyze")
-		b.WriteString(help)
+		return "\n" + helpStyle.Render(hint)
 	}
 
-	return b.String()
+	if m.copyMenu.active {
+		return "\n" + helpStyle.Render(m.copyMenu.View())
+	}
+
+	var helpParts []string
+	if len(m.characters) > 1 {
+		helpParts = append(helpParts, "←/→: navigate", "C: compare", "T: story")
+	}
+	helpParts = append(helpParts, "g: generate", "Y: copy…")
+	if m.characters[m.selected].Etymology != "" {
+		if m.etymologyOpen {
+			helpParts = append(helpParts, "E: less etymology")
+		} else {
+			helpParts = append(helpParts, "E: etymology")
+		}
+	}
+	if m.scenes != nil {
+		helpParts = append(helpParts, "S: save scene")
+	}
+	if m.currentAudio() != "" {
+		helpParts = append(helpParts, "v: play")
+	}
+	if m.llmPrompt != "" {
+		helpParts = append(helpParts, "R: variation", "e: edit", "y: copy")
+		if m.takes.label(m.llmPrompt) != "" {
+			helpParts = append(helpParts, ",/.: takes")
+		}
+	}
+	if scroll := m.detail.help(); scroll != "" {
+		helpParts = append(helpParts, scroll)
+	}
+	if m.copyNote != "" {
+		return "\n" + copiedStyle.Render(m.copyNote) + helpStyle.Render(" • "+strings.Join(helpParts, " • "))
+	}
+	return "\n" + helpStyle.Render(strings.Join(helpParts, " • "))
 }
 
 func (m *LookupModel) analyzeInput() {
@@ -326,20 +683,14 @@ func (m *LookupModel) analyzeInput() {
 
 	m.inputText = input
 	m.characters = nil
+	m.story = ""
 	m.selected = 0
 	m.err = nil
+	m.searchTerm = ""
+	m.searchHits = nil
+	m.searchMeaning = false
 
-	for _, r := range input {
-		if r < 0x4E00 || r > 0x9FFF {
-			continue
-		}
-
-		char := string(r)
-		result := m.analyzeChar(char)
-		if result != nil {
-			m.characters = append(m.characters, *result)
-		}
-	}
+	m.characters = m.analyzer.Text(input)
 
 	if len(m.characters) == 0 {
 		m.err = fmt.Errorf("no Chinese characters found in: %s", input)
@@ -349,57 +700,255 @@ func (m *LookupModel) analyzeInput() {
 	m.updatePrompt()
 }
 
-func (m *LookupModel) analyzeChar(char string) *components.CharacterResult {
-	readings := m.parser.ParseChar(char)
-	if len(readings) == 0 {
+// ScrollDetail scrolls the character details down n lines, or up if n
+// is negative.
+func (m *LookupModel) ScrollDetail(n int) {
+	m.layoutDetail()
+	m.detail.scrollBy(n)
+}
+
+// SetHistory replaces the input history, oldest first.
+func (m *LookupModel) SetHistory(history []string) {
+	m.history = history
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+	m.historyPos = len(m.history)
+}
+
+// History returns the input history, oldest first.
+func (m LookupModel) History() []string {
+	return m.history
+}
+
+// Shown returns the character shown, or nil if there is none.
+func (m LookupModel) Shown() *analyze.CharacterResult {
+	if m.selected >= len(m.characters) {
 		return nil
 	}
+	return &m.characters[m.selected]
+}
 
-	reading := readings[0]
-
-	result := &components.CharacterResult{
-		Character: char,
-		Pinyin:    reading.Full,
-		Initial:   reading.Initial,
-		Final:     reading.Final,
-		Tone:      reading.Tone,
-		ActorID:   pinyin.GetActorID(reading.Initial),
-		SetID:     pinyin.GetSetID(reading.Final),
-	}
-
-	if m.dict != nil {
-		if entry := m.dict.Lookup(char); entry != nil {
-			result.Meaning = entry.Definition
-			result.Decomp = decomp.FormatDecomposition(entry.Decomposition)
-			result.Components = decomp.ExtractComponents(entry.Decomposition)
-			if entry.Etymology != nil {
-				if entry.Etymology.Hint != "" {
-					result.Etymology = entry.Etymology.Hint
-				} else {
-					result.Etymology = entry.Etymology.Type
-				}
-			}
+// SetRomanizer shows the pinyin of characters in r as well, or only in
+// pinyin if r is nil.
+func (m *LookupModel) SetRomanizer(r pinyin.Romanizer) {
+	m.romanizer = r
+}
+
+// RomanizerName returns the name of the romanization shown under the
+// pinyin, or "" for none.
+func (m LookupModel) RomanizerName() string {
+	if m.romanizer == nil {
+		return ""
+	}
+	return m.romanizer.Name()
+}
+
+// addHistory records an analyzed input, moving a repeated input to the
+// end, and reports whether the history changed.
+func (m *LookupModel) addHistory(input string) bool {
+	input = strings.TrimSpace(input)
+	m.historyPos = len(m.history)
+	m.draft = ""
+	if input == "" || (len(m.history) > 0 && m.history[len(m.history)-1] == input) {
+		return false
+	}
+
+	history := make([]string, 0, len(m.history)+1)
+	for _, h := range m.history {
+		if h != input {
+			history = append(history, h)
 		}
 	}
+	m.SetHistory(append(history, input))
+	return true
+}
 
-	if actor := m.generator.GetActor(result.ActorID); actor != nil {
-		result.ActorName = actor.Name
+// recallHistory shows the history entry delta steps away, like ↑/↓ in a
+// shell; stepping past the newest entry brings back the unsent input.
+func (m *LookupModel) recallHistory(delta int) {
+	pos := m.historyPos + delta
+	if pos < 0 || pos > len(m.history) || pos == m.historyPos {
+		return
 	}
-	if set := m.generator.GetSet(result.SetID); set != nil {
-		result.SetName = set.Name
+
+	if m.historyPos == len(m.history) {
+		m.draft = m.input.Value()
 	}
-	result.ToneRoom = m.generator.GetToneRoom(
-		m.generator.GetSet(result.SetID),
-		reading.Tone,
-	)
+	m.historyPos = pos
+	if pos == len(m.history) {
+		m.input.SetValue(m.draft)
+	} else {
+		m.input.SetValue(m.history[pos])
+	}
+	m.input.CursorEnd()
+}
+
+// submit analyzes the input and adds it to the history.
+func (m *LookupModel) submit() tea.Cmd {
+	m.analyzeInput()
+	m.llmPrompt = ""
+	m.llmError = nil
+	if len(m.characters) > 0 && m.addHistory(m.input.Value()) {
+		return func() tea.Msg { return HistoryChangedMsg{} }
+	}
+	return nil
+}
+
+// paste inserts text at the cursor, with line breaks and runs of spaces
+// collapsed to single spaces, and reports if it had to be cut short.
+func (m *LookupModel) paste(text string) tea.Cmd {
+	pasted := []rune(strings.Join(strings.Fields(text), " "))
+	value := []rune(m.input.Value())
+	pos := m.input.Position()
+
+	room := lookupCharLimit - len(value)
+	cut := len(pasted) > room
+	if cut {
+		pasted = pasted[:max(room, 0)]
+	}
+
+	m.input.SetValue(string(value[:pos]) + string(pasted) + string(value[pos:]))
+	m.input.SetCursor(pos + len(pasted))
+	if cut {
+		return toast(ToastInfo, "Pasted text was cut to fit the %d-character limit", lookupCharLimit)
+	}
+	return nil
+}
 
-	for _, comp := range result.Components {
-		if p := m.generator.GetProp(comp); p != nil && p.Name != "" {
-			result.PropNames = append(result.PropNames, p.Name)
+// Show analyzes text as if it had been typed and entered, and returns a
+// command rendering the big character of the first one.
+func (m *LookupModel) Show(text string) tea.Cmd {
+	m.input.SetValue(text)
+	m.input.CursorEnd()
+	cmd := m.submit()
+	if len(m.characters) == 0 {
+		return cmd
+	}
+	return tea.Batch(cmd, m.renderBigChar())
+}
+
+// saveScene saves the selected character's scene with the LLM prompt, or
+// the template prompt if none was generated.
+func (m *LookupModel) saveScene() tea.Cmd {
+	r := m.characters[m.selected]
+	imagePrompt := m.llmPrompt
+	if imagePrompt == "" {
+		imagePrompt = m.prompt
+	}
+	m.scenes.Put(r.Scene(imagePrompt))
+	if err := m.scenes.Save(); err != nil {
+		return toast(ToastError, "Could not save scene: %v", err)
+	}
+	return toast(ToastSuccess, "Saved scene for %s", r.Character)
+}
+
+// SelectCharacter selects the tab for char among the analyzed characters, if
+// any, and returns a command rendering its big character.
+func (m *LookupModel) SelectCharacter(char string) tea.Cmd {
+	for i, c := range m.characters {
+		if c.Character == char && i != m.selected {
+			m.selected = i
+			m.updatePrompt()
+			m.llmPrompt = ""
+			m.llmError = nil
+			return m.renderBigChar()
 		}
 	}
+	return nil
+}
+
+// Searching reports whether the scene search box has focus, so the app
+// should pass every key through.
+func (m LookupModel) Searching() bool {
+	return m.searching
+}
 
-	return result
+// Editing reports whether the prompt editor is open, so the app should
+// pass every key through.
+func (m LookupModel) Editing() bool {
+	return m.editor.active
+}
+
+// CopyMenuOpen reports whether the copy menu is waiting for a key, so the
+// app should pass every key through.
+func (m LookupModel) CopyMenuOpen() bool {
+	return m.copyMenu.active
+}
+
+// searchScenes shows every character whose scene matches query.
+// search runs a search typed after /: a meaning search if it starts with
+// meaningSearchPrefix, else a scene search.
+func (m *LookupModel) search(query string) {
+	if meaning, ok := strings.CutPrefix(strings.TrimLeft(query, " "), meaningSearchPrefix); ok {
+		m.searchMeanings(meaning)
+		return
+	}
+	if m.scenes == nil {
+		m.err = fmt.Errorf("no scenes to search; type m <meaning> to search the dictionary")
+		return
+	}
+	m.searchScenes(query)
+}
+
+// searchMeanings shows the characters whose dictionary definition
+// matches query, such as "water" or "to eat", closest first.
+func (m *LookupModel) searchMeanings(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" || m.dict == nil {
+		return
+	}
+
+	m.searchTerm = query
+	m.searchHits = nil
+	m.searchMeaning = true
+	m.characters = nil
+	m.selected = 0
+	m.err = nil
+
+	for _, entry := range m.dict.SearchDefinitions(query, maxMeaningResults) {
+		if result := m.analyzer.Character(entry.Character); result != nil {
+			m.characters = append(m.characters, *result)
+		}
+	}
+
+	if len(m.characters) == 0 {
+		m.err = fmt.Errorf("no character means: %s", query)
+		return
+	}
+
+	m.updatePrompt()
+}
+
+func (m *LookupModel) searchScenes(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+
+	m.searchTerm = query
+	m.searchHits = make(map[string]string)
+	m.searchMeaning = false
+	m.characters = nil
+	m.selected = 0
+	m.err = nil
+
+	for _, sc := range m.scenes.Search(query) {
+		if _, ok := m.searchHits[sc.Character]; ok {
+			continue
+		}
+		m.searchHits[sc.Character] = scene.Snippet(sc, query, 60)
+		if result := m.analyzer.Character(sc.Character); result != nil {
+			m.characters = append(m.characters, *result)
+		}
+	}
+
+	if len(m.characters) == 0 {
+		m.err = fmt.Errorf("no scenes match: %s", query)
+		return
+	}
+
+	m.updatePrompt()
 }
 
 func (m *LookupModel) updatePrompt() {
@@ -408,23 +957,14 @@ func (m *LookupModel) updatePrompt() {
 	}
 
 	r := m.characters[m.selected]
-	sceneData := m.generator.BuildSceneData(
-		r.Character,
-		r.Pinyin,
-		r.ActorID,
-		r.SetID,
-		r.Tone,
-		r.Components,
-		r.Meaning,
-		r.Etymology,
-		r.Decomp,
-	)
-	if p, err := m.generator.Generate(sceneData); err == nil {
+	if p, err := m.analyzer.Prompt(r); err == nil {
 		m.prompt = p
 	}
 }
 
-func (m *LookupModel) generateLLMPrompt() tea.Cmd {
+// generateLLMPrompt asks the LLM for an image prompt. With earlier takes
+// in previous it asks for a different take instead.
+func (m *LookupModel) generateLLMPrompt(previous []string) tea.Cmd {
 	if m.selected >= len(m.characters) || m.llmClient == nil {
 		return nil
 	}
@@ -432,66 +972,32 @@ func (m *LookupModel) generateLLMPrompt() tea.Cmd {
 	r := m.characters[m.selected]
 	client := m.llmClient
 
-	elements := llm.SceneElements{
-		Character: r.Character,
-		Pinyin:    r.Pinyin,
-		Meaning:   r.Meaning,
-		ActorName: r.ActorName,
-		SetName:   r.SetName,
-		ToneRoom:  r.ToneRoom,
-		Props:     r.PropNames,
-	}
-
-	if m.config != nil {
-		for _, a := range m.config.Actors {
-			if a.ID == r.ActorID {
-				elements.ActorDesc = a.Description
-				break
-			}
-		}
-		for _, s := range m.config.Sets {
-			if s.ID == r.SetID {
-				elements.SetDesc = s.Description
-				for _, room := range s.Rooms {
-					if hmm.Tone(room.Tone) == r.Tone {
-						elements.ToneRoomDesc = room.Description
-						break
-					}
-				}
-				break
-			}
-		}
-		for _, comp := range r.Components {
-			for _, p := range m.config.Props {
-				if p.ID == comp || p.Component == comp {
-					elements.PropDescs = append(elements.PropDescs, p.Description)
-					break
-				}
-			}
-		}
-	}
+	elements := m.analyzer.SceneElements(r)
 
 	return func() tea.Msg {
+		if len(previous) > 0 {
+			prompt, err := client.GenerateVariation(elements, previous)
+			return llmResultMsg{prompt: prompt, err: err, variation: true}
+		}
 		prompt, err := client.GenerateScene(elements)
 		return llmResultMsg{prompt: prompt, err: err}
 	}
 }
 
-func (m LookupModel) renderMultiCharView() string {
-	var b strings.Builder
-
-	// Character tabs for multi-character words
-	if len(m.characters) > 1 {
-		b.WriteString(m.renderWordBar())
-		b.WriteString("\n")
+// generateStory asks the LLM for one story through the scenes of all
+// analyzed characters, in order.
+func (m *LookupModel) generateStory() tea.Cmd {
+	client := m.llmClient
+	text := m.inputText
+	elements := make([]llm.SceneElements, len(m.characters))
+	for i, r := range m.characters {
+		elements[i] = m.analyzer.SceneElements(r)
 	}
 
-	// Selected character details
-	if m.selected < len(m.characters) {
-		b.WriteString(m.renderCharacterDetail(m.characters[m.selected]))
+	return func() tea.Msg {
+		story, err := client.GenerateStory(text, "", elements)
+		return storyResultMsg{story: story, err: err}
 	}
-
-	return b.String()
 }
 
 func (m LookupModel) renderWordBar() string {
@@ -520,7 +1026,16 @@ func (m LookupModel) renderWordBar() string {
 	return wordDisplayStyle.Render(combined)
 }
 
-func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string {
+// currentAudio returns the cached recording of the selected character, or
+// "" if there is none.
+func (m LookupModel) currentAudio() string {
+	if m.selected >= len(m.characters) {
+		return ""
+	}
+	return audio.Cached(m.characters[m.selected].Character)
+}
+
+func (m LookupModel) renderCharacterDetail(r analyze.CharacterResult) string {
 	var b strings.Builder
 
 	contentWidth := m.width - 4
@@ -528,13 +1043,13 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 		contentWidth = 40
 	}
 
-	// Try ASCII art rendering first
+	// Try ASCII art rendering first, once it has rendered in the background
 	var charDisplay string
 	if bigchar.IsAvailable() {
-		asciiChar := bigchar.GetCached(r.Character, 30, 15)
+		asciiChar, _ := bigchar.Cached(r.Character, bigCharCols, bigCharRows)
 		if asciiChar != "" {
 			charDisplay = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#ffe66d")).
+				Foreground(palette.Accent).
 				Render(asciiChar)
 		}
 	}
@@ -544,10 +1059,16 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 		charDisplay = bigCharStyle.Render(r.Character)
 	}
 
-	pinyinDisplay := pinyinUnderStyle.Render(r.Pinyin)
+	pinyinDisplay := pinyinUnderStyle.Render(r.Pinyin + speaker(m.currentAudio()))
 
 	// Center the character block within view width
 	charBlock := lipgloss.JoinVertical(lipgloss.Center, charDisplay, pinyinDisplay)
+	if m.romanizer != nil {
+		if also, ok := pinyin.Romanize(m.romanizer, r.Pinyin); ok {
+			charBlock = lipgloss.JoinVertical(lipgloss.Center, charBlock,
+				helpStyle.Render(m.romanizer.Label()+": "+also))
+		}
+	}
 	centeredChar := lipgloss.NewStyle().
 		Width(contentWidth).
 		Align(lipgloss.Center).
@@ -557,24 +1078,31 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 	b.WriteString(centeredChar)
 	b.WriteString("\n")
 
-	// Meaning (centered)
-	if r.Meaning != "" {
-		meaning := r.Meaning
+	// Meaning (centered), after the RTH keyword if there is one
+	if r.Meaning != "" || r.Keyword != "" {
 		maxLen := 60
 		if m.width > 0 {
 			maxLen = m.width - 20
 		}
-		if len(meaning) > maxLen {
-			meaning = meaning[:maxLen] + "..."
+		meaning := r.Meaning
+		if r.Keyword != "" {
+			meaning = strings.TrimSuffix("RTH: "+r.Keyword+" · "+meaning, " · ")
 		}
+		meaning = textutil.Truncate(meaning, maxLen)
 		meaningStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f1faee")).
+			Foreground(palette.Text).
 			Width(contentWidth).
 			Align(lipgloss.Center)
 		b.WriteString(meaningStyle.Render(meaning))
 		b.WriteString("\n")
 	}
 
+	// Why this character matched a scene search
+	if hit := m.searchHits[r.Character]; hit != "" {
+		b.WriteString(helpStyle.Render("Scene: " + hit))
+		b.WriteString("\n")
+	}
+
 	// HMM Breakdown Box
 	hmmBox := m.renderHMMBox(r)
 	b.WriteString(hmmBox)
@@ -588,13 +1116,25 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 	}
 
 	// Etymology
-	if r.Etymology != "" {
+	if m.etymologyOpen {
+		if box := m.renderEtymologyBox(r.Character); box != "" {
+			b.WriteString(box)
+			b.WriteString("\n")
+		}
+	} else if r.Etymology != "" {
 		b.WriteString(m.renderRow("Etymology", r.Etymology))
 		b.WriteString("\n")
 	}
 
+	// Look-alikes
+	if box := renderLookAlikesBox(m.scenes, r.Character); box != "" {
+		b.WriteString(box)
+		b.WriteString("\n")
+	}
+	b.WriteString(renderConfusables(m.analyzer, m.scenes, r.Character, m.width))
+
 	// LLM-generated image prompt
-	if m.llmGenerating {
+	if m.llmGenerating && m.llmPrompt == "" {
 		b.WriteString("\n")
 		b.WriteString(loadingStyle.Render("Generating image prompt with Claude..."))
 		b.WriteString("\n")
@@ -613,6 +1153,11 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 		if m.copied {
 			header += "  " + copiedStyle.Render("Copied!")
 		}
+		if m.llmGenerating {
+			header += "  " + loadingStyle.Render("Generating another take...")
+		} else if label := m.takes.label(m.llmPrompt); label != "" {
+			header += "  " + helpStyle.Render("("+label+")")
+		}
 		llmBox := llmPromptStyle.Width(width).Render(
 			header + "\n\n" +
 				wordWrap(m.llmPrompt, width-6),
@@ -624,6 +1169,27 @@ func (m LookupModel) renderCharacterDetail(r components.CharacterResult) string
 		b.WriteString("\n")
 	}
 
+	// Story through the scenes of all characters
+	if m.storyGenerating {
+		b.WriteString("\n")
+		b.WriteString(loadingStyle.Render("Writing a story through all scenes with Claude..."))
+		b.WriteString("\n")
+	} else if m.story != "" {
+		width := 70
+		if m.width > 0 && m.width-10 < width {
+			width = m.width - 10
+		}
+		// Keep the story's scenes on lines of their own
+		var scenes []string
+		for _, line := range strings.Split(m.story, "\n") {
+			scenes = append(scenes, wordWrap(line, width-6))
+		}
+		b.WriteString("\n")
+		b.WriteString(llmPromptStyle.Width(width).Render(
+			actorStyle.Render("Story") + "\n\n" + strings.Join(scenes, "\n"),
+		))
+	}
+
 	return b.String()
 }
 
@@ -631,7 +1197,7 @@ func (m LookupModel) renderRow(label, value string) string {
 	return labelStyle.Render(label+":") + " " + valueStyle.Render(value) + "\n"
 }
 
-func (m LookupModel) renderHMMBox(r components.CharacterResult) string {
+func (m LookupModel) renderHMMBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	initial := r.Initial
@@ -669,7 +1235,7 @@ func (m LookupModel) renderHMMBox(r components.CharacterResult) string {
 	)
 }
 
-func (m LookupModel) renderComponentsBox(r components.CharacterResult) string {
+func (m LookupModel) renderComponentsBox(r analyze.CharacterResult) string {
 	var lines []string
 
 	for i, comp := range r.Components {
@@ -695,6 +1261,59 @@ func (m LookupModel) renderComponentsBox(r components.CharacterResult) string {
 	)
 }
 
+// renderEtymologyBox shows how char was formed: its semantic and phonetic
+// components with their readings and meanings, and the characters of its
+// phonetic series. It is empty if the dictionary has no etymology.
+func (m LookupModel) renderEtymologyBox(char string) string {
+	e := m.analyzer.Etymology(char)
+	if e == nil {
+		return ""
+	}
+
+	width := 70
+	if m.width > 0 && m.width-10 < width {
+		width = m.width - 10
+	}
+
+	lines := []string{valueStyle.Render(e.Type)}
+	if e.Hint != "" {
+		lines = append(lines, helpStyle.Render(wordWrap(e.Hint, width-6)))
+	}
+	part := func(label string, p *analyze.EtymologyPart) {
+		if p == nil {
+			return
+		}
+		line := fmt.Sprintf("  %s %s", labelStyle.Render(label), propStyle.Render(p.Character))
+		if p.Pinyin != "" {
+			line += " " + valueStyle.Render(p.Pinyin)
+		}
+		if p.Meaning != "" {
+			line += "  " + helpStyle.Render(textutil.Truncate(p.Meaning, width-20))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	part("Meaning:", e.Semantic)
+	part("Sound:", e.Phonetic)
+
+	if len(e.Series) > 0 {
+		var series []string
+		// Keep each character with its pinyin when wrapping
+		for _, s := range e.Series {
+			series = append(series, s.Character+"\u00a0"+s.Pinyin)
+		}
+		if e.More > 0 {
+			series = append(series, fmt.Sprintf("+%d more", e.More))
+		}
+		lines = append(lines, "", subtitleStyle.Render("Phonetic series of "+e.Phonetic.Character))
+		lines = append(lines, valueStyle.Render(wordWrap(strings.Join(series, " · "), width-6)))
+	}
+
+	return boxStyle.Render(
+		subtitleStyle.Render("Etymology") + "\n\n" + strings.Join(lines, "\n"),
+	)
+}
+
 func formatActorName(id, name string) string {
 	if name != "" {
 		return name
@@ -709,6 +1328,60 @@ func formatSetName(id, name string) string {
 	return fmt.Sprintf("Set [%s]", id)
 }
 
+// renderLookAlikesBox shows the scenes linked to char as easily confused,
+// or nothing if there are none.
+func renderLookAlikesBox(scenes *scene.Store, char string) string {
+	if scenes == nil {
+		return ""
+	}
+	links := scenes.LinksFor(char)
+	if len(links) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, l := range links {
+		line := "  " + propStyle.Render(scenes.LinkedCharacter(l))
+		if l.Note != "" {
+			line += " → " + valueStyle.Render(l.Note)
+		}
+		lines = append(lines, line)
+	}
+
+	return boxStyle.Render(
+		subtitleStyle.Render("Look-alikes") + "\n\n" + strings.Join(lines, "\n"),
+	)
+}
+
+// renderConfusables shows the characters that look like char, other than
+// the look-alikes already linked to its scene, or nothing if there are
+// none.
+func renderConfusables(analyzer *analyze.Analyzer, scenes *scene.Store, char string, width int) string {
+	linked := make(map[string]bool)
+	if scenes != nil {
+		for _, l := range scenes.LinksFor(char) {
+			linked[scenes.LinkedCharacter(l)] = true
+		}
+	}
+
+	var confusables []analyze.Confusable
+	for _, c := range analyzer.Confusables(char) {
+		if !linked[c.Character] {
+			confusables = append(confusables, c)
+		}
+	}
+	if len(confusables) == 0 {
+		return ""
+	}
+
+	label := "Don't confuse with:"
+	if width <= 0 {
+		width = 80
+	}
+	line := wordWrap(analyze.ConfusablesLine(confusables), max(width-len(label)-6, 30))
+	return labelStyle.Render(label) + " " + valueStyle.Render(line) + "\n"
+}
+
 func wordWrap(s string, width int) string {
 	if width <= 0 {
 		width = 60
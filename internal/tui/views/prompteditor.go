@@ -0,0 +1,82 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxEditorHeight keeps the editor from filling a tall terminal with
+// empty lines; longer prompts scroll.
+const maxEditorHeight = 12
+
+// promptEditor lets the user rework a generated image prompt in place
+// before copying or saving it.
+type promptEditor struct {
+	textarea textarea.Model
+	active   bool
+}
+
+// open starts editing prompt in an editor whose box and help line fit
+// width and height.
+func (e *promptEditor) open(prompt string, width, height int) tea.Cmd {
+	width -= llmPromptStyle.GetHorizontalFrameSize()
+	height -= llmPromptStyle.GetVerticalFrameSize() + 4 // Title and help line
+
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+	ta.CharLimit = 0
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.FocusedStyle.Text = lipgloss.NewStyle().Foreground(palette.Text)
+
+	if width < 20 {
+		width = 20
+	}
+	if height > maxEditorHeight {
+		height = maxEditorHeight
+	}
+	if height < 3 {
+		height = 3
+	}
+	ta.SetWidth(width)
+	ta.SetHeight(height)
+	ta.SetValue(prompt)
+
+	e.textarea = ta
+	e.active = true
+	return e.textarea.Focus()
+}
+
+// update handles a message while editing. It reports saved when the user
+// keeps the edit with ctrl+s; esc throws it away. Either closes the editor.
+func (e *promptEditor) update(msg tea.Msg) (saved bool, cmd tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+s":
+			e.active = false
+			return true, nil
+		case "esc":
+			e.active = false
+			return false, nil
+		}
+	}
+
+	e.textarea, cmd = e.textarea.Update(msg)
+	return false, cmd
+}
+
+// value returns the edited prompt.
+func (e promptEditor) value() string {
+	return strings.TrimSpace(e.textarea.Value())
+}
+
+// View renders the editor in a prompt box with its help line.
+func (e promptEditor) View() string {
+	box := llmPromptStyle.Render(
+		actorStyle.Render("Edit Prompt") + "\n\n" + e.textarea.View(),
+	)
+	return box + "\n" + helpStyle.Render("ctrl+s: save • esc: cancel")
+}
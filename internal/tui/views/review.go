@@ -0,0 +1,348 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/llm"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+)
+
+// Review view styles
+var (
+	reviewTitleStyle  lipgloss.Style
+	reviewCharStyle   lipgloss.Style
+	reviewStatusStyle lipgloss.Style
+)
+
+// setReviewStyles builds the Review view styles from t.
+func setReviewStyles(t theme.Theme) {
+	reviewTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
+
+	reviewCharStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.Bg).
+		Padding(1, 6).
+		Align(lipgloss.Center)
+
+	reviewStatusStyle = lipgloss.NewStyle().
+		Foreground(t.Success).
+		Italic(true)
+}
+
+// Message types for review view
+type reviewLLMResultMsg struct {
+	key    string
+	prompt string
+	err    error
+}
+
+// ReviewModel lets the user approve, reject, or regenerate draft scenes
+// produced by batch generation before they are written into decks.
+type ReviewModel struct {
+	scenes    *scene.Store
+	generator *prompt.Generator
+	llmClient *llm.Client
+
+	drafts  []hmm.Scene
+	current int
+
+	generating bool
+	editor     promptEditor
+	err        error
+	status     string
+
+	width  int
+	height int
+}
+
+// NewReviewModel creates a new review view model.
+func NewReviewModel(scenes *scene.Store, gen *prompt.Generator, llmClient *llm.Client) ReviewModel {
+	m := ReviewModel{
+		scenes:    scenes,
+		generator: gen,
+		llmClient: llmClient,
+	}
+	m.Refresh()
+	return m
+}
+
+// SetSize updates the view dimensions.
+func (m *ReviewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Refresh reloads the list of drafts from the scene store.
+func (m *ReviewModel) Refresh() {
+	m.drafts = nil
+	if m.scenes != nil {
+		m.drafts = m.scenes.Drafts()
+	}
+	if m.current >= len(m.drafts) {
+		m.current = len(m.drafts) - 1
+	}
+	if m.current < 0 {
+		m.current = 0
+	}
+}
+
+// Update handles messages.
+func (m ReviewModel) Update(msg tea.Msg) (ReviewModel, tea.Cmd) {
+	if m.editor.active {
+		saved, cmd := m.editor.update(msg)
+		if saved {
+			m.savePrompt(m.editor.value())
+		}
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if len(m.drafts) == 0 {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "right", "l", "j", "down":
+			if m.current < len(m.drafts)-1 {
+				m.current++
+				m.err = nil
+			}
+			return m, nil
+		case "left", "h", "k", "up":
+			if m.current > 0 {
+				m.current--
+				m.err = nil
+			}
+			return m, nil
+		case "a":
+			m.setStatus(hmm.SceneApproved)
+			return m, nil
+		case "x":
+			m.setStatus(hmm.SceneRejected)
+			return m, nil
+		case "r":
+			if m.generating {
+				return m, nil
+			}
+			if m.llmClient == nil {
+				m.err = fmt.Errorf("ANTHROPIC_API_KEY not set")
+				return m, nil
+			}
+			m.generating = true
+			m.err = nil
+			return m, m.regenerate()
+		case "e":
+			if m.generating {
+				return m, nil
+			}
+			header := reviewTitleStyle.Render("Review Drafts") + "\n\n\n"
+			return m, m.editor.open(m.drafts[m.current].ImagePrompt, m.width, m.height-lipgloss.Height(header))
+		}
+
+	case reviewLLMResultMsg:
+		m.generating = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, llmToast(msg.err)
+		}
+		if sc := m.scenes.Get(msg.key); sc != nil {
+			updated := *sc
+			updated.ImagePrompt = msg.prompt
+			m.scenes.Put(updated)
+			if err := m.scenes.Save(); err != nil {
+				m.err = err
+			}
+			m.status = "Regenerated " + updated.Character
+			m.Refresh()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// setStatus records a review decision for the current draft.
+func (m *ReviewModel) setStatus(status hmm.SceneStatus) {
+	sc := m.drafts[m.current]
+	sc.Status = status
+	m.scenes.Put(sc)
+	if err := m.scenes.Save(); err != nil {
+		m.err = err
+		return
+	}
+
+	verb := "Approved"
+	if status == hmm.SceneRejected {
+		verb = "Rejected"
+	}
+	m.status = verb + " " + sc.Character
+	m.err = nil
+	m.Refresh()
+}
+
+// savePrompt replaces the current draft's prompt with an edited one.
+func (m *ReviewModel) savePrompt(edited string) {
+	sc := m.drafts[m.current]
+	if edited == "" || edited == sc.ImagePrompt {
+		return
+	}
+	sc.ImagePrompt = edited
+	m.scenes.Put(sc)
+	if err := m.scenes.Save(); err != nil {
+		m.err = err
+		return
+	}
+	m.status = "Edited " + sc.Character
+	m.err = nil
+	m.Refresh()
+}
+
+// Editing reports whether the prompt editor is open, so the app should
+// pass every key through.
+func (m ReviewModel) Editing() bool {
+	return m.editor.active
+}
+
+// regenerate asks the LLM for a new prompt for the current draft.
+func (m *ReviewModel) regenerate() tea.Cmd {
+	sc := m.drafts[m.current]
+	key := scene.Key(sc)
+	client := m.llmClient
+
+	set := m.generator.GetSet(sc.SetID)
+	elements := llm.SceneElements{
+		Character: sc.Character,
+		Pinyin:    sc.Pinyin,
+		Meaning:   sc.Keyword,
+		ToneRoom:  m.generator.GetToneRoom(set, sc.Tone),
+	}
+	if actor := m.generator.GetActor(sc.ActorID); actor != nil {
+		elements.ActorName = actor.Name
+		elements.ActorDesc = actor.Description
+	}
+	if set != nil {
+		elements.SetName = set.Name
+		elements.SetDesc = set.Description
+		for _, room := range set.Rooms {
+			if room.Tone == sc.Tone {
+				elements.ToneRoomDesc = room.Description
+				break
+			}
+		}
+	}
+	for _, id := range sc.PropIDs {
+		if p := m.generator.GetProp(id); p != nil && p.Name != "" {
+			elements.Props = append(elements.Props, p.Name)
+			elements.PropDescs = append(elements.PropDescs, p.Description)
+		}
+	}
+
+	return func() tea.Msg {
+		prompt, err := client.GenerateScene(elements)
+		return reviewLLMResultMsg{key: key, prompt: prompt, err: err}
+	}
+}
+
+// View renders the review view.
+func (m ReviewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(reviewTitleStyle.Render("Review Drafts"))
+	b.WriteString("\n")
+
+	if m.status != "" {
+		b.WriteString(reviewStatusStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+
+	if len(m.drafts) == 0 {
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("No drafts waiting for review.\nPress 'B' in Browse to batch-generate prompts."))
+		return b.String()
+	}
+
+	sc := m.drafts[m.current]
+
+	b.WriteString(browseCardCountStyle.Render(fmt.Sprintf("Draft %d of %d", m.current+1, len(m.drafts))))
+	b.WriteString("\n\n")
+
+	if m.editor.active {
+		b.WriteString(m.editor.View())
+		return b.String()
+	}
+
+	contentWidth := m.width - 4
+	if contentWidth < 40 {
+		contentWidth = 40
+	}
+	charBlock := lipgloss.JoinVertical(lipgloss.Center,
+		reviewCharStyle.Render(sc.Character),
+		pinyinUnderStyle.Render(sc.Pinyin),
+	)
+	b.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(charBlock))
+	b.WriteString("\n")
+
+	if sc.Keyword != "" {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(palette.Text).
+			Width(contentWidth).
+			Align(lipgloss.Center).
+			Render(sc.Keyword))
+		b.WriteString("\n")
+	}
+
+	set := m.generator.GetSet(sc.SetID)
+	actorName := ""
+	if actor := m.generator.GetActor(sc.ActorID); actor != nil {
+		actorName = actor.Name
+	}
+	setName := ""
+	if set != nil {
+		setName = set.Name
+	}
+	b.WriteString("\n")
+	b.WriteString(labelStyle.Render("Actor:") + " " + actorStyle.Render(formatActorName(sc.ActorID, actorName)) + "\n")
+	b.WriteString(labelStyle.Render("Set:") + " " + setStyle.Render(formatSetName(sc.SetID, setName)) + "\n")
+	b.WriteString(labelStyle.Render("Room:") + " " + toneStyle.Render(m.generator.GetToneRoom(set, sc.Tone)) + "\n")
+
+	if box := renderLookAlikesBox(m.scenes, sc.Character); box != "" {
+		b.WriteString(box)
+		b.WriteString("\n")
+	}
+
+	width := 70
+	if m.width > 0 && m.width-10 < width {
+		width = m.width - 10
+	}
+	if m.generating {
+		b.WriteString("\n")
+		b.WriteString(loadingStyle.Render("Regenerating image prompt..."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(llmPromptStyle.Width(width).Render(
+			actorStyle.Render("Draft Prompt") + "\n\n" + wordWrap(sc.ImagePrompt, width-6),
+		))
+		b.WriteString("\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("a: approve • x: reject • r: regenerate • e: edit • ←/→: prev/next"))
+
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minScrollHeight keeps a detail pane usable on very small terminals.
+const minScrollHeight = 5
+
+// scrollPane scrolls the detail pane of a view while the header above it
+// and the help line below it stay in place.
+type scrollPane struct {
+	viewport viewport.Model
+	content  string
+	id       string // What the pane shows; showing something else scrolls back to the top
+}
+
+// fit fills the pane with content, sized to what is left of a view of
+// the given height once header and footer are drawn.
+func (p *scrollPane) fit(id, content string, width, height int, header, footer string) {
+	h := height - lipgloss.Height(header) - lipgloss.Height(footer)
+	if h < minScrollHeight {
+		h = minScrollHeight
+	}
+
+	p.viewport.Width = width
+	p.viewport.Height = h
+	p.content = content
+	p.viewport.SetContent(content)
+	if id != p.id {
+		p.id = id
+		p.viewport.GotoTop()
+	}
+	p.viewport.SetYOffset(p.viewport.YOffset)
+}
+
+// scroll moves the pane for the scroll keys j/k, ↑/↓, and pgup/pgdown,
+// and reports whether key was one of them.
+func (p *scrollPane) scroll(key string) bool {
+	switch key {
+	case "j", "down":
+		p.viewport.ScrollDown(1)
+	case "k", "up":
+		p.viewport.ScrollUp(1)
+	case "pgdown":
+		p.viewport.PageDown()
+	case "pgup":
+		p.viewport.PageUp()
+	default:
+		return false
+	}
+	return true
+}
+
+// scrollBy scrolls the pane down n lines, or up if n is negative.
+func (p *scrollPane) scrollBy(n int) {
+	if n < 0 {
+		p.viewport.ScrollUp(-n)
+	} else {
+		p.viewport.ScrollDown(n)
+	}
+}
+
+// end scrolls to the bottom of the content.
+func (p *scrollPane) end() {
+	p.viewport.GotoBottom()
+}
+
+// overflows reports whether the content is taller than the pane.
+func (p scrollPane) overflows() bool {
+	return p.viewport.TotalLineCount() > p.viewport.Height
+}
+
+// View renders the visible part of the content. Content that fits is
+// returned as is rather than padded to the pane height.
+func (p scrollPane) View() string {
+	if !p.overflows() {
+		return p.content
+	}
+	return p.viewport.View()
+}
+
+// help returns the scroll hint for a help line, or "" if the content fits.
+func (p scrollPane) help() string {
+	if !p.overflows() {
+		return ""
+	}
+	return fmt.Sprintf("j/k: scroll (%d%%)", int(p.viewport.ScrollPercent()*100))
+}
@@ -2,50 +2,71 @@ package views
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/f3rmion/hmm/internal/config"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/photo"
+	"github.com/f3rmion/hmm/internal/tui/bigchar"
+	"github.com/f3rmion/hmm/internal/tui/theme"
 )
 
 // Settings view styles
 var (
+	settingsTitleStyle     lipgloss.Style
+	settingsPathStyle      lipgloss.Style
+	settingsTabStyle       lipgloss.Style
+	settingsTabActiveStyle lipgloss.Style
+	settingsHeaderStyle    lipgloss.Style
+	settingsRowStyle       lipgloss.Style
+	settingsMutedStyle     lipgloss.Style
+	settingsHelpStyle      lipgloss.Style
+)
+
+// setSettingsStyles builds the Settings view styles from t.
+func setSettingsStyles(t theme.Theme) {
 	settingsTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FF6B6B")).
-				MarginBottom(1)
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
 
 	settingsPathStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666")).
-				Italic(true).
-				MarginBottom(1)
+		Foreground(t.Muted).
+		Italic(true).
+		MarginBottom(1)
 
 	settingsTabStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
-				Padding(0, 2)
+		Foreground(t.Subtle).
+		Padding(0, 2)
 
-	settingsTabActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#ffe66d")).
-				Background(lipgloss.Color("#2d3436")).
-				Padding(0, 2)
+	settingsTabActiveStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Background(t.BgAlt).
+		Padding(0, 2))
 
 	settingsHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#a8dadc"))
+		Bold(true).
+		Foreground(t.Label)
 
 	settingsRowStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#f1faee"))
+		Foreground(t.Text)
 
 	settingsMutedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666"))
+		Foreground(t.Muted)
 
 	settingsHelpStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666666")).
-				MarginTop(1)
+		Foreground(t.Muted).
+		MarginTop(1)
+}
+
+// Width of an actor photo in terminal cells, and the most rows it takes
+const (
+	settingsPhotoCols = 24
+	settingsPhotoRows = 16
 )
 
 // SettingsModel is the settings view model.
@@ -57,20 +78,22 @@ type SettingsModel struct {
 	tab     int
 	scrollY int
 
+	// Actor photos fetched with 'hmm config photos', drawn once by ID
+	photoDir string
+	photos   map[string]string
+
 	width  int
 	height int
 }
 
 // NewSettingsModel creates a new settings model.
 func NewSettingsModel(cfg *config.Config) SettingsModel {
-	configDir := os.Getenv("HOME") + "/.config/hmm"
-	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
-		configDir = filepath.Join(xdg, "hmm")
-	}
-
+	configDir, _ := config.GetConfigDir()
 	return SettingsModel{
 		config:    cfg,
 		configDir: configDir,
+		photoDir:  filepath.Join(configDir, photo.DirName),
+		photos:    make(map[string]string),
 	}
 }
 
@@ -80,6 +103,12 @@ func (m *SettingsModel) SetSize(width, height int) {
 	m.height = height
 }
 
+// SetTab shows tab (0=Actors, 1=Sets, 2=Props) from the top.
+func (m *SettingsModel) SetTab(tab int) {
+	m.tab = tab
+	m.scrollY = 0
+}
+
 // Update handles messages.
 func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -138,7 +167,7 @@ func (m SettingsModel) View() string {
 	}
 	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabViews...))
 	b.WriteString("\n")
-	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#3d5a80")).Render(strings.Repeat("─", minInt(m.width-4, 60))))
+	b.WriteString(lipgloss.NewStyle().Foreground(palette.Border).Render(strings.Repeat("─", minInt(m.width-4, 60))))
 	b.WriteString("\n\n")
 
 	// Content based on tab
@@ -172,12 +201,13 @@ func (m SettingsModel) renderActors() string {
 	b.WriteString("\n\n")
 
 	// Header row
-	headerFmt := "%-6s %-12s %-15s %s"
-	header := fmt.Sprintf(headerFmt, "ID", "Initial", "Category", "Name")
-	b.WriteString(settingsMutedStyle.Render(header))
-	b.WriteString("\n")
-	b.WriteString(settingsMutedStyle.Render(strings.Repeat("─", 50)))
-	b.WriteString("\n")
+	headerFmt := "%-6s %-12s %-15s %-5s %s"
+	header := fmt.Sprintf(headerFmt, "ID", "Initial", "Category", "Photo", "Name")
+	var table strings.Builder
+	table.WriteString(settingsMutedStyle.Render(header))
+	table.WriteString("\n")
+	table.WriteString(settingsMutedStyle.Render(strings.Repeat("─", 56)))
+	table.WriteString("\n")
 
 	// Calculate visible range
 	visibleHeight := m.height - 12
@@ -200,9 +230,25 @@ func (m SettingsModel) renderActors() string {
 		if initial == "" {
 			initial = "(null)"
 		}
-		row := fmt.Sprintf("%-6s %-12s %-15s %s", a.ID, initial, a.Category, a.Name)
-		b.WriteString(settingsRowStyle.Render(row))
+		hasPhoto := ""
+		if photo.Find(m.photoDir, a.ID) != "" {
+			hasPhoto = "✓"
+		}
+		row := fmt.Sprintf(headerFmt, a.ID, initial, a.Category, hasPhoto, a.Name)
+		table.WriteString(settingsRowStyle.Render(row))
+		table.WriteString("\n")
+	}
+
+	// The photo of the actor at the top, beside the table if it fits
+	preview := ""
+	if start < end && m.width >= 90 {
+		preview = m.actorPhoto(m.config.Actors[start])
+	}
+	if preview != "" {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, strings.TrimSuffix(table.String(), "\n"), "  ", preview))
 		b.WriteString("\n")
+	} else {
+		b.WriteString(table.String())
 	}
 
 	// Scroll indicator
@@ -214,6 +260,27 @@ func (m SettingsModel) renderActors() string {
 	return b.String()
 }
 
+// actorPhoto returns the photo of a, drawn in braille with its name
+// below, or "" if it has none.
+func (m SettingsModel) actorPhoto(a hmm.Actor) string {
+	drawn, ok := m.photos[a.ID]
+	if !ok {
+		if p := photo.Find(m.photoDir, a.ID); p != "" {
+			if img, err := photo.Load(p); err == nil && !img.Bounds().Empty() {
+				// A cell is twice as high as wide
+				b := img.Bounds()
+				rows := min(settingsPhotoCols*b.Dy()/b.Dx()/2, settingsPhotoRows)
+				drawn = bigchar.RenderImage(img, settingsPhotoCols, max(rows, 1))
+			}
+		}
+		m.photos[a.ID] = drawn
+	}
+	if drawn == "" {
+		return ""
+	}
+	return settingsRowStyle.Render(drawn) + "\n" + settingsMutedStyle.Render(a.Name)
+}
+
 func (m SettingsModel) renderSets() string {
 	var b strings.Builder
 
@@ -228,12 +295,12 @@ func (m SettingsModel) renderSets() string {
 	b.WriteString("\n\n")
 
 	// Table styles
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#4ecdc4"))
-	idStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffe66d")).Width(6)
-	finalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff6b6b")).Width(6)
-	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f1faee"))
-	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#3d5a80"))
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(palette.Secondary)
+	idStyle := lipgloss.NewStyle().Foreground(palette.Accent).Width(6)
+	finalStyle := lipgloss.NewStyle().Foreground(palette.Primary).Width(6)
+	nameStyle := lipgloss.NewStyle().Foreground(palette.Text)
+	descStyle := lipgloss.NewStyle().Foreground(palette.Subtle).Italic(true)
+	borderStyle := lipgloss.NewStyle().Foreground(palette.Border)
 
 	// Header row
 	b.WriteString(headerStyle.Render(fmt.Sprintf("%-6s %-6s %s", "Final", "ID", "Name / Description")))
@@ -261,8 +328,8 @@ func (m SettingsModel) renderSets() string {
 	}
 
 	// Tone label styles
-	toneMarkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff6b6b")).Bold(true)
-	toneNameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#a8e6cf"))
+	toneMarkStyle := lipgloss.NewStyle().Foreground(palette.Primary).Bold(true)
+	toneNameStyle := lipgloss.NewStyle().Foreground(palette.Success)
 
 	// Set rows
 	for i := start; i < end; i++ {
@@ -314,13 +381,6 @@ func (m SettingsModel) renderSets() string {
 	return b.String()
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
-	}
-	return s[:max-1] + "…"
-}
-
 // applyToneMark adds a tone mark to a pinyin final
 func applyToneMark(final string, tone int) string {
 	if final == "" {
@@ -0,0 +1,283 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tui/components"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+)
+
+// Stats view styles
+var (
+	statsTitleStyle    lipgloss.Style
+	statsCompleteStyle lipgloss.Style
+	statsDraftStyle    lipgloss.Style
+	statsMissingStyle  lipgloss.Style
+)
+
+// setStatsStyles builds the Stats view styles from t.
+func setStatsStyles(t theme.Theme) {
+	statsTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
+
+	statsCompleteStyle = lipgloss.NewStyle().
+		Foreground(t.Success)
+
+	statsDraftStyle = lipgloss.NewStyle().
+		Foreground(t.Accent)
+
+	statsMissingStyle = lipgloss.NewStyle().
+		Foreground(t.Primary)
+}
+
+// LookupMsg asks the app to look up Char in the Lookup view.
+type LookupMsg struct {
+	Char string
+}
+
+// LearnListMsg asks the app to learn the characters of List in the
+// Learn view.
+type LearnListMsg struct {
+	List scene.TargetList
+}
+
+// StatsModel shows how well target lists (a loaded deck, HSK lists, ...)
+// are covered by scenes.
+type StatsModel struct {
+	scenes *scene.Store
+	pkg    *anki.Package
+
+	targets []scene.TargetList
+	current int
+	err     error
+
+	width  int
+	height int
+}
+
+// NewStatsModel creates a new stats view model.
+func NewStatsModel(scenes *scene.Store) StatsModel {
+	m := StatsModel{scenes: scenes}
+	m.Refresh()
+	return m
+}
+
+// SetSize updates the view dimensions.
+func (m *StatsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetPackage adds the loaded deck as a target list.
+func (m *StatsModel) SetPackage(pkg *anki.Package) {
+	m.pkg = pkg
+	m.Refresh()
+}
+
+// Refresh reloads target lists from disk, the built-in lists, and the
+// loaded deck.
+func (m *StatsModel) Refresh() {
+	m.targets = nil
+	m.err = nil
+
+	if m.pkg != nil {
+		if field := detectChineseFieldFromPkg(m.pkg); field != "" {
+			var text strings.Builder
+			for _, note := range m.pkg.Notes {
+				text.WriteString(htmlutil.Text(m.pkg.GetFieldValue(note, field)))
+			}
+			m.targets = append(m.targets, scene.TargetList{
+				Name:  "Deck",
+				Chars: analyze.UniqueChineseChars(text.String()),
+			})
+		}
+	}
+
+	if m.scenes != nil {
+		lists, err := scene.AllTargetLists(m.scenes.ListsDir())
+		if err != nil {
+			m.err = err
+		}
+		m.targets = append(m.targets, lists...)
+	}
+
+	if m.current >= len(m.targets) {
+		m.current = 0
+	}
+}
+
+// Update handles messages.
+func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "right", "l", "j", "down":
+			if m.current < len(m.targets)-1 {
+				m.current++
+			}
+		case "left", "h", "k", "up":
+			if m.current > 0 {
+				m.current--
+			}
+		case "r":
+			m.Refresh()
+		case "L":
+			if m.current < len(m.targets) {
+				list := m.targets[m.current]
+				return m, func() tea.Msg { return LearnListMsg{List: list} }
+			}
+		case "t":
+			if char := m.today(); char != "" {
+				return m, func() tea.Msg { return LookupMsg{Char: char} }
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the stats view.
+func (m StatsModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(statsTitleStyle.Render("Scene Stats"))
+	b.WriteString("\n")
+
+	if m.scenes == nil {
+		b.WriteString(helpStyle.Render("Scene store not available."))
+		return b.String()
+	}
+
+	// Overall store counts
+	var approved, drafts, rejected int
+	for _, sc := range m.scenes.All() {
+		switch {
+		case sc.IsApproved():
+			approved++
+		case sc.Status == hmm.SceneDraft:
+			drafts++
+		case sc.Status == hmm.SceneRejected:
+			rejected++
+		}
+	}
+	b.WriteString(valueStyle.Render(fmt.Sprintf("%d scenes: %d approved, %d drafts, %d rejected",
+		m.scenes.Len(), approved, drafts, rejected)))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+
+	if len(m.targets) == 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf(
+			"No target lists. Open a deck, or put word lists (e.g. hsk1.txt)\ninto %s", m.scenes.ListsDir())))
+		return b.String()
+	}
+
+	// Target tabs
+	var tabs []string
+	for i, t := range m.targets {
+		if i == m.current {
+			tabs = append(tabs, settingsTabActiveStyle.Render(t.Name))
+		} else {
+			tabs = append(tabs, settingsTabStyle.Render(t.Name))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	b.WriteString("\n\n")
+
+	cov := m.scenes.Coverage(m.targets[m.current].Chars)
+
+	b.WriteString(renderBar(len(cov.Complete), cov.Total, 40))
+	b.WriteString(fmt.Sprintf(" %d%%\n\n", cov.Percent()))
+
+	b.WriteString(statsCompleteStyle.Render(fmt.Sprintf("Complete:    %d/%d", len(cov.Complete), cov.Total)))
+	b.WriteString("\n")
+	b.WriteString(statsDraftStyle.Render(fmt.Sprintf("Drafts only: %d", len(cov.DraftOnly))))
+	b.WriteString("\n")
+	b.WriteString(statsMissingStyle.Render(fmt.Sprintf("Missing:     %d", len(cov.Missing))))
+	b.WriteString("\n")
+	if len(cov.Missing) > 0 {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("Today: "))
+		b.WriteString(statsMissingStyle.Bold(true).Render(cov.Missing[0]))
+		b.WriteString("\n")
+	}
+
+	width := 60
+	if m.width > 0 && m.width-10 < width {
+		width = m.width - 10
+	}
+	if len(cov.DraftOnly) > 0 {
+		b.WriteString("\n")
+		b.WriteString(subtitleStyle.Render("Drafts only"))
+		b.WriteString("\n")
+		b.WriteString(statsDraftStyle.Render(charGrid(cov.DraftOnly, width, 5)))
+		b.WriteString("\n")
+	}
+	if len(cov.Missing) > 0 {
+		b.WriteString("\n")
+		b.WriteString(subtitleStyle.Render("Missing"))
+		b.WriteString("\n")
+		b.WriteString(statsMissingStyle.Render(charGrid(cov.Missing, width, 5)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	help := "←/→: switch list • r: reload lists • L: learn this list"
+	if len(cov.Missing) > 0 {
+		help += " • t: look up today's character"
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
+}
+
+// today returns the character of the day: the first one of the current
+// list without a scene, or "" if there is none.
+func (m StatsModel) today() string {
+	if m.scenes == nil || m.current >= len(m.targets) {
+		return ""
+	}
+	if missing := m.scenes.Coverage(m.targets[m.current].Chars).Missing; len(missing) > 0 {
+		return missing[0]
+	}
+	return ""
+}
+
+// charGrid lays characters out in rows that fit width, cutting off after
+// maxRows with a count of the rest.
+func charGrid(chars []string, width, maxRows int) string {
+	perRow := width / 3 // Each character is two cells wide plus a space
+	if perRow < 1 {
+		perRow = 1
+	}
+
+	var rows []string
+	for i := 0; i < len(chars); i += perRow {
+		if len(rows) == maxRows {
+			rows = append(rows, fmt.Sprintf("… and %d more", len(chars)-i))
+			break
+		}
+		end := i + perRow
+		if end > len(chars) {
+			end = len(chars)
+		}
+		rows = append(rows, strings.Join(chars[i:end], " "))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderBar renders a progress bar width cells wide, filled to done/total.
+func renderBar(done, total, width int) string {
+	return components.NewProgress(width, palette).Bar(done, total)
+}
@@ -0,0 +1,56 @@
+package views
+
+import "fmt"
+
+// promptTakes keeps every take generated for the shown prompt, so asking
+// for a variation does not throw away the one before it. The takes belong
+// to whatever prompt is current; once the view shows a prompt that is not
+// among them (another character, a fresh generation), they start over.
+type promptTakes struct {
+	list    []string
+	current int
+}
+
+// holds reports whether prompt is the take being shown.
+func (t promptTakes) holds(prompt string) bool {
+	return t.current < len(t.list) && t.list[t.current] == prompt
+}
+
+// all returns the takes so far, starting over from shown if needed.
+func (t promptTakes) all(shown string) []string {
+	if !t.holds(shown) {
+		return []string{shown}
+	}
+	return t.list
+}
+
+// add records next as a new take after shown and makes it current.
+func (t *promptTakes) add(shown, next string) {
+	t.list = append(t.all(shown), next)
+	t.current = len(t.list) - 1
+}
+
+// cycle moves delta takes away from shown, wrapping around, and returns
+// the take to show. It reports false if shown has no alternatives.
+func (t *promptTakes) cycle(shown string, delta int) (string, bool) {
+	if !t.holds(shown) || len(t.list) < 2 {
+		return "", false
+	}
+	t.current = (t.current + delta + len(t.list)) % len(t.list)
+	return t.list[t.current], true
+}
+
+// replace swaps shown for an edited version of it.
+func (t *promptTakes) replace(shown, edited string) {
+	if t.holds(shown) {
+		t.list[t.current] = edited
+	}
+}
+
+// label returns "take 2 of 3" for shown, or "" if it has no alternatives.
+func (t promptTakes) label(shown string) string {
+	if !t.holds(shown) || len(t.list) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("take %d of %d", t.current+1, len(t.list))
+}
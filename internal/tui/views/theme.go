@@ -0,0 +1,25 @@
+package views
+
+import "github.com/f3rmion/hmm/internal/tui/theme"
+
+// palette is the theme the views are currently styled with.
+var palette = theme.Dark
+
+func init() {
+	SetTheme(theme.Dark)
+}
+
+// SetTheme restyles every view with the colors of t. Call it before
+// creating the views, since some styles are copied into their inputs.
+func SetTheme(t theme.Theme) {
+	palette = t
+	setLookupStyles(t)
+	setBrowseStyles(t)
+	setLearnStyles(t)
+	setFilePickerStyles(t)
+	setSettingsStyles(t)
+	setReviewStyles(t)
+	setStatsStyles(t)
+	setCompareStyles(t)
+	setWalkStyles(t)
+}
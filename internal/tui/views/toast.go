@@ -0,0 +1,45 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/f3rmion/hmm/internal/llm"
+)
+
+// ToastLevel is how a toast is styled.
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastError
+)
+
+// ToastMsg asks the app to show a short notification in the status bar,
+// for outcomes of background work that the view on screen may not show.
+type ToastMsg struct {
+	Text  string
+	Level ToastLevel
+}
+
+// toast returns a command that shows a notification.
+func toast(level ToastLevel, format string, args ...any) tea.Cmd {
+	msg := ToastMsg{Text: fmt.Sprintf(format, args...), Level: level}
+	return func() tea.Msg { return msg }
+}
+
+// clipboardToast reports a failed copy, which would otherwise go unnoticed.
+func clipboardToast(err error) tea.Cmd {
+	return toast(ToastError, "Could not copy: %v", err)
+}
+
+// llmToast reports a failed LLM request the user should act on, or
+// returns nil for errors the view shows on its own.
+func llmToast(err error) tea.Cmd {
+	if errors.Is(err, llm.ErrRateLimited) {
+		return toast(ToastError, "Anthropic API rate limit reached, wait a moment before generating more")
+	}
+	return nil
+}
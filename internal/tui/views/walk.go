@@ -0,0 +1,353 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/pinyin"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+	"github.com/f3rmion/hmm/internal/tui/theme"
+)
+
+// Walk view styles
+var (
+	walkTitleStyle    lipgloss.Style
+	walkRoomStyle     lipgloss.Style
+	walkHereStyle     lipgloss.Style
+	walkCharStyle     lipgloss.Style
+	walkCharHereStyle lipgloss.Style
+)
+
+// setWalkStyles builds the Walk view styles from t.
+func setWalkStyles(t theme.Theme) {
+	walkTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Primary).
+		MarginBottom(1)
+
+	walkRoomStyle = lipgloss.NewStyle().
+		Foreground(t.Muted)
+
+	walkHereStyle = t.Marked(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Secondary))
+
+	walkCharStyle = lipgloss.NewStyle().
+		Foreground(t.Text)
+
+	walkCharHereStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Accent).
+		Underline(true)
+}
+
+// walkStop is one character met on a walk through a set, in the room of
+// its tone.
+type walkStop struct {
+	char  analyze.CharacterResult
+	scene *hmm.Scene // Approved scene, if any
+}
+
+// walkSet is a set of the memory palace with the characters whose scenes
+// play in it, in the order of its rooms.
+type walkSet struct {
+	id    string
+	name  string
+	stops []walkStop
+}
+
+// WalkModel walks through one set of the memory palace room by room,
+// stopping at each character whose scene plays there, like a review walk
+// through the real place. It only shows scenes; nothing is changed.
+type WalkModel struct {
+	analyzer  *analyze.Analyzer
+	parser    *pinyin.Parser
+	generator *prompt.Generator
+	scenes    *scene.Store
+	pkg       *anki.Package
+
+	sets    []walkSet
+	current int // Set walked through
+	stop    int // Stop reached in that set
+
+	detail scrollPane
+
+	width  int
+	height int
+}
+
+// NewWalkModel creates a new walk view model.
+func NewWalkModel(dict *decomp.Dictionary, gen *prompt.Generator, scenes *scene.Store) WalkModel {
+	m := WalkModel{
+		analyzer:  analyze.New(dict, gen),
+		parser:    pinyin.NewParser(),
+		generator: gen,
+		scenes:    scenes,
+	}
+	m.Refresh()
+	return m
+}
+
+// SetSize updates the view dimensions.
+func (m *WalkModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetPackage adds the characters of the loaded deck to the walk.
+func (m *WalkModel) SetPackage(pkg *anki.Package) {
+	m.pkg = pkg
+	m.Refresh()
+}
+
+// Refresh gathers the characters of the loaded deck and the scene store
+// by set, staying in the set walked through.
+func (m *WalkModel) Refresh() {
+	var text strings.Builder
+	if m.pkg != nil {
+		if field := detectChineseFieldFromPkg(m.pkg); field != "" {
+			for _, note := range m.pkg.Notes {
+				text.WriteString(htmlutil.Text(m.pkg.GetFieldValue(note, field)))
+			}
+		}
+	}
+	if m.scenes != nil {
+		for _, sc := range m.scenes.All() {
+			if sc.Status != hmm.SceneRejected {
+				text.WriteString(sc.Character)
+			}
+		}
+	}
+
+	bySet := make(map[string]*walkSet)
+	for _, char := range analyze.UniqueChineseChars(text.String()) {
+		r := m.analyzer.Character(char)
+		if r == nil || r.SetID == "" {
+			continue
+		}
+		ws, ok := bySet[r.SetID]
+		if !ok {
+			ws = &walkSet{id: r.SetID, name: r.SetName}
+			bySet[r.SetID] = ws
+		}
+		stop := walkStop{char: *r}
+		if m.scenes != nil {
+			stop.scene = m.scenes.Approved(char)
+		}
+		ws.stops = append(ws.stops, stop)
+	}
+
+	walked := ""
+	if m.current < len(m.sets) {
+		walked = m.sets[m.current].id
+	}
+
+	m.sets = nil
+	for _, ws := range bySet {
+		// Room by room, the characters of a room in the order met
+		sort.SliceStable(ws.stops, func(i, j int) bool {
+			return ws.stops[i].char.Tone < ws.stops[j].char.Tone
+		})
+		m.sets = append(m.sets, *ws)
+	}
+	sort.Slice(m.sets, func(i, j int) bool { return m.sets[i].id < m.sets[j].id })
+
+	m.current, m.stop = 0, 0
+	for i, ws := range m.sets {
+		if ws.id == walked {
+			m.current = i
+		}
+	}
+}
+
+// Update handles messages.
+func (m WalkModel) Update(msg tea.Msg) (WalkModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || len(m.sets) == 0 {
+		return m, nil
+	}
+
+	stops := m.sets[m.current].stops
+	switch key.String() {
+	case "right", "l", " ", "enter":
+		// Past the last room, the walk goes on into the next set
+		if m.stop < len(stops)-1 {
+			m.stop++
+		} else if m.current < len(m.sets)-1 {
+			m.current++
+			m.stop = 0
+		}
+	case "left", "h", "backspace":
+		if m.stop > 0 {
+			m.stop--
+		} else if m.current > 0 {
+			m.current--
+			m.stop = len(m.sets[m.current].stops) - 1
+		}
+	case "n":
+		if m.current < len(m.sets)-1 {
+			m.current++
+			m.stop = 0
+		}
+	case "p":
+		if m.current > 0 {
+			m.current--
+			m.stop = 0
+		}
+	case "r":
+		m.Refresh()
+	case "L":
+		char := stops[m.stop].char.Character
+		return m, func() tea.Msg { return LookupMsg{Char: char} }
+	default:
+		m.layoutStop()
+		m.detail.scroll(key.String())
+	}
+	return m, nil
+}
+
+// View renders the walk view.
+func (m WalkModel) View() string {
+	if len(m.sets) == 0 {
+		return walkTitleStyle.Render("Memory Palace Walk") + "\n" +
+			helpStyle.Render("Nothing to walk through yet. Open a deck, or save scenes\nwith 'hmm generate --save' or in Review.")
+	}
+	m.layoutStop()
+	return m.renderHeader() + m.detail.View() + m.renderHelp()
+}
+
+// Shown returns the character at the current stop, or nil if there is
+// nothing to walk through.
+func (m WalkModel) Shown() *analyze.CharacterResult {
+	if len(m.sets) == 0 {
+		return nil
+	}
+	return &m.sets[m.current].stops[m.stop].char
+}
+
+// ScrollDetail scrolls the stop down n lines, or up if n is negative.
+func (m *WalkModel) ScrollDetail(n int) {
+	if len(m.sets) == 0 {
+		return
+	}
+	m.layoutStop()
+	m.detail.scrollBy(n)
+}
+
+// layoutStop fits the current stop between the rooms and the help line.
+func (m *WalkModel) layoutStop() {
+	id := fmt.Sprintf("%s:%d", m.sets[m.current].id, m.stop)
+	m.detail.fit(id, m.renderStop(), m.width, m.height, m.renderHeader(), m.renderHelp())
+}
+
+// renderHeader renders the set walked through and its rooms, each with
+// its characters, the current room and character marked.
+func (m WalkModel) renderHeader() string {
+	var b strings.Builder
+	ws := m.sets[m.current]
+	here := ws.stops[m.stop]
+
+	b.WriteString(walkTitleStyle.Render("Memory Palace Walk"))
+	b.WriteString("\n")
+
+	name := ws.name
+	if name == "" {
+		name = "Set [" + ws.id + "]"
+	}
+	b.WriteString(fmt.Sprintf("%s %s\n\n",
+		subtitleStyle.Render(name),
+		helpStyle.Render(fmt.Sprintf("set %d of %d • stop %d of %d", m.current+1, len(m.sets), m.stop+1, len(ws.stops)))))
+
+	var set *hmm.Set
+	if m.generator != nil {
+		set = m.generator.GetSet(ws.id)
+	}
+	for tone := 1; tone <= m.parser.Tones(); tone++ {
+		var chars []string
+		for i, stop := range ws.stops {
+			if int(stop.char.Tone) != tone {
+				continue
+			}
+			style := walkCharStyle
+			if i == m.stop {
+				style = walkCharHereStyle
+			}
+			chars = append(chars, style.Render(stop.char.Character))
+		}
+
+		room := fmt.Sprintf("%d %s", tone, m.roomName(set, hmm.Tone(tone)))
+		if int(here.char.Tone) == tone {
+			room = walkHereStyle.Render("▸ " + room)
+		} else {
+			room = walkRoomStyle.Render("  " + room)
+		}
+		b.WriteString(room)
+		if len(chars) > 0 {
+			b.WriteString("  " + strings.Join(chars, " "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// roomName returns the room of tone in set.
+func (m WalkModel) roomName(set *hmm.Set, tone hmm.Tone) string {
+	if m.generator == nil {
+		return ""
+	}
+	return m.generator.GetToneRoom(set, tone)
+}
+
+// renderStop renders the character at the current stop and its scene.
+func (m WalkModel) renderStop() string {
+	var b strings.Builder
+	stop := m.sets[m.current].stops[m.stop]
+	r := stop.char
+
+	b.WriteString(walkCharHereStyle.UnsetUnderline().Render(r.Character))
+	b.WriteString("  " + valueStyle.Render(r.Pinyin))
+	if r.Meaning != "" {
+		b.WriteString("  " + helpStyle.Render(r.Meaning))
+	}
+	b.WriteString("\n\n")
+
+	actor := r.ActorName
+	if actor == "" {
+		actor = "Actor [" + r.ActorID + "]"
+	}
+	b.WriteString(labelStyle.Render("Actor:") + " " + valueStyle.Render(actor) + "\n")
+	if len(r.PropNames) > 0 {
+		b.WriteString(labelStyle.Render("Props:") + " " + valueStyle.Render(strings.Join(r.PropNames, ", ")) + "\n")
+	}
+	b.WriteString("\n")
+
+	width := m.width - 2
+	if width < 20 {
+		width = 20
+	}
+	switch {
+	case stop.scene != nil && stop.scene.Script != "":
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(stop.scene.Script))
+	case stop.scene != nil && stop.scene.ImagePrompt != "":
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(stop.scene.ImagePrompt))
+	default:
+		b.WriteString(helpStyle.Render("No scene yet: picture one here, or press L to generate one in Lookup."))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderHelp renders the help line below the stop.
+func (m WalkModel) renderHelp() string {
+	return "\n" + helpStyle.Render("→/space: walk on • ←: back • n/p: next/previous set • L: look up • r: reload")
+}
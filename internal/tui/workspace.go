@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/textutil"
+	"github.com/f3rmion/hmm/internal/tui/views"
+)
+
+// workspaceDeck is a deck open in the workspace, and the Browse search and
+// card it was left on.
+type workspaceDeck struct {
+	pkg    *anki.Package
+	path   string
+	search string
+	card   int
+}
+
+// name returns the name the deck is shown under.
+func (d workspaceDeck) name() string {
+	return filepath.Base(d.path)
+}
+
+// openDeck adds a loaded deck to the workspace and shows it. A deck that
+// is open already is replaced by the new copy, so opening it again picks
+// up changes made to the file since.
+func (m *AppModel) openDeck(pkg *anki.Package, path string) {
+	for i, d := range m.decks {
+		if samePath(d.path, path) {
+			m.decks[i] = workspaceDeck{pkg: pkg, path: path}
+			m.useDeck(i, false)
+			return
+		}
+	}
+	m.decks = append(m.decks, workspaceDeck{pkg: pkg, path: path})
+	m.useDeck(len(m.decks)-1, false)
+}
+
+// switchDeck shows the open deck i, or the next or previous one for an i
+// past either end. The Browse search and sort carry over, so a search
+// can be followed from deck to deck.
+func (m *AppModel) switchDeck(i int) {
+	if len(m.decks) < 2 {
+		return
+	}
+	i = (i + len(m.decks)) % len(m.decks)
+	if i == m.deckIndex {
+		return
+	}
+	search, _, card := m.browseView.Position()
+	m.decks[m.deckIndex].search = search
+	m.decks[m.deckIndex].card = card
+	m.useDeck(i, true)
+}
+
+// useDeck makes the open deck i the one the views show. With carry, the
+// Browse search and sort shown before are applied to it, back on the card
+// it was left on if it was left with the same search.
+func (m *AppModel) useDeck(i int, carry bool) {
+	search, sort, _ := m.browseView.Position()
+
+	m.deckIndex = i
+	d := m.decks[i]
+	m.ankiPackage = d.pkg
+	m.ankiPath = d.path
+
+	m.browseView.SetPackage(d.pkg)
+	m.browseView.SetOtherDecks(m.otherDecks())
+	m.learnView.SetPackage(d.pkg)
+	m.statsView.SetPackage(d.pkg)
+	m.walkView.SetPackage(d.pkg)
+
+	if carry {
+		card := 0
+		if d.search == search {
+			card = d.card
+		}
+		m.browseView.Restore(search, sort, card)
+	}
+}
+
+// otherDecks returns the open decks but the one shown.
+func (m AppModel) otherDecks() []views.OtherDeck {
+	var others []views.OtherDeck
+	for i, d := range m.decks {
+		if i != m.deckIndex {
+			others = append(others, views.OtherDeck{Name: d.name(), Pkg: d.pkg})
+		}
+	}
+	return others
+}
+
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return absA == absB
+}
+
+// deckLabel returns the sidebar entry of open deck i.
+func (m AppModel) deckLabel(i int) string {
+	return textutil.Truncate(m.decks[i].name(), m.sidebarWidth-4)
+}
+
+// renderDeckList renders the open decks for the sidebar, the one shown
+// marked, or nothing while only one is open.
+func (m AppModel) renderDeckList() []string {
+	if len(m.decks) < 2 {
+		return nil
+	}
+	items := []string{"", SidebarItemStyle.Render("Decks  {/}")}
+	for i := range m.decks {
+		style := SidebarItemStyle
+		if i == m.deckIndex {
+			style = current.Marked(SidebarItemStyle.Bold(true).Foreground(ColorSecondary))
+		}
+		items = append(items, style.Render(m.deckLabel(i)))
+	}
+	return items
+}
+
+// deckAt returns the open deck whose sidebar entry is line, with styling
+// stripped, or -1.
+func (m AppModel) deckAt(line string) int {
+	if len(m.decks) < 2 {
+		return -1
+	}
+	for i := range m.decks {
+		if strings.Contains(line, m.deckLabel(i)) {
+			return i
+		}
+	}
+	return -1
+}
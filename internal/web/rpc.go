@@ -0,0 +1,326 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/hmm"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/scene"
+)
+
+// maxRPCBody limits the size of a JSON-RPC request body.
+const maxRPCBody = 1 << 20
+
+// JSON-RPC error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcNotFound       = -32004 // Server error: the scene, deck, or card does not exist
+)
+
+// rpcRequest is a JSON-RPC 2.0 request, or a notification when ID is
+// absent.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response with either a result or an error.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// StoredScene is a scene of the store, as the RPC API returns it.
+type StoredScene struct {
+	Key       string   `json:"key"`
+	Character string   `json:"character"`
+	Pinyin    string   `json:"pinyin"`
+	Tone      int      `json:"tone"`
+	Keyword   string   `json:"keyword,omitempty"`
+	ActorID   string   `json:"actor_id"`
+	SetID     string   `json:"set_id"`
+	PropIDs   []string `json:"prop_ids,omitempty"`
+	Script    string   `json:"script,omitempty"`
+	Prompt    string   `json:"prompt,omitempty"`
+	ImageURL  string   `json:"image_url,omitempty"`
+	Status    string   `json:"status"`
+}
+
+// rpcMethods are the methods of the hmm.v1.Hmm service in api/hmm/v1/hmm.proto.
+var rpcMethods = map[string]func(*Server, json.RawMessage) (any, error){
+	"Lookup":     (*Server).rpcLookup,
+	"GetScene":   (*Server).rpcGetScene,
+	"ListScenes": (*Server).rpcListScenes,
+	"GetDeck":    (*Server).rpcGetDeck,
+	"ListCards":  (*Server).rpcListCards,
+	"GetCard":    (*Server).rpcGetCard,
+}
+
+// handleRPC answers JSON-RPC 2.0 requests, single or batched, posted to
+// /rpc. Methods are the RPCs of the hmm.v1.Hmm service, with or without
+// the service prefix (Lookup or hmm.v1.Hmm/Lookup).
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRPCBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+			writeJSON(w, parseError(err))
+			return
+		}
+		responses := []rpcResponse{}
+		for _, raw := range batch {
+			if resp, ok := s.rpcCall(raw); ok {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, responses)
+		return
+	}
+
+	resp, ok := s.rpcCall(body)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// rpcCall answers one request. It reports false for a notification, which
+// gets no answer.
+func (s *Server) rpcCall(raw json.RawMessage) (rpcResponse, bool) {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return parseError(err), true
+	}
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if len(req.ID) == 0 {
+		resp.ID = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "not a JSON-RPC 2.0 request"}
+		return resp, true
+	}
+
+	method, ok := rpcMethods[strings.TrimPrefix(req.Method, "hmm.v1.Hmm/")]
+	if !ok {
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: "unknown method: " + req.Method}
+		return resp, len(req.ID) > 0
+	}
+
+	result, err := method(s, req.Params)
+	if len(req.ID) == 0 {
+		return resp, false
+	}
+	if err != nil {
+		if e, ok := err.(*rpcError); ok {
+			resp.Error = e
+		} else {
+			resp.Error = &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		return resp, true
+	}
+	resp.Result = result
+	return resp, true
+}
+
+func parseError(err error) rpcResponse {
+	msg := "invalid JSON"
+	if err != nil {
+		msg = err.Error()
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+		Error: &rpcError{Code: rpcParseError, Message: msg}}
+}
+
+// params decodes the params of a request into v; absent params leave v
+// as it is.
+func params(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
+
+func notFound(format string, args ...any) error {
+	return &rpcError{Code: rpcNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) rpcLookup(raw json.RawMessage) (any, error) {
+	var p struct {
+		Text string `json:"text"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	return map[string]any{"characters": s.analyze(p.Text)}, nil
+}
+
+func (s *Server) rpcGetScene(raw json.RawMessage) (any, error) {
+	var p struct {
+		Character string `json:"character"`
+		Key       string `json:"key"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	if s.scenes == nil {
+		return nil, notFound("no scene store")
+	}
+
+	var sc *hmm.Scene
+	switch {
+	case p.Key != "":
+		sc = s.scenes.Get(p.Key)
+	case p.Character != "":
+		sc = s.scenes.Approved(p.Character)
+	default:
+		return nil, fmt.Errorf("character or key is required")
+	}
+	if sc == nil {
+		return nil, notFound("no scene for %s%s", p.Character, p.Key)
+	}
+	return storedScene(*sc), nil
+}
+
+func (s *Server) rpcListScenes(raw json.RawMessage) (any, error) {
+	var p struct {
+		Status string `json:"status"`
+		Query  string `json:"query"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+
+	scenes := []StoredScene{}
+	if s.scenes == nil {
+		return map[string]any{"scenes": scenes}, nil
+	}
+
+	all := s.scenes.All()
+	if p.Query != "" {
+		all = s.scenes.Search(p.Query)
+	}
+	for _, sc := range all {
+		st := storedScene(sc)
+		if p.Status != "" && st.Status != p.Status {
+			continue
+		}
+		scenes = append(scenes, st)
+	}
+	return map[string]any{"scenes": scenes}, nil
+}
+
+func (s *Server) rpcGetDeck(raw json.RawMessage) (any, error) {
+	if s.pkg == nil {
+		return map[string]any{"open": false}, nil
+	}
+	return map[string]any{
+		"open":  true,
+		"name":  filepath.Base(s.pkg.Path()),
+		"field": s.field,
+		"cards": len(s.pkg.Notes),
+	}, nil
+}
+
+func (s *Server) rpcListCards(raw json.RawMessage) (any, error) {
+	var p struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	if s.pkg == nil {
+		return nil, notFound("no deck open")
+	}
+	if p.Limit <= 0 || p.Limit > maxCards {
+		p.Limit = maxCards
+	}
+
+	query := strings.TrimSpace(p.Query)
+	cards := []Card{}
+	for i, note := range s.pkg.Notes {
+		if query != "" && !s.noteMatches(note, query) {
+			continue
+		}
+		cards = append(cards, Card{Index: i, Text: htmlutil.Text(s.pkg.GetFieldValue(note, s.field))})
+		if len(cards) == p.Limit {
+			break
+		}
+	}
+	return map[string]any{"cards": cards}, nil
+}
+
+func (s *Server) rpcGetCard(raw json.RawMessage) (any, error) {
+	var p struct {
+		Index int `json:"index"`
+	}
+	if err := params(raw, &p); err != nil {
+		return nil, err
+	}
+	if s.pkg == nil {
+		return nil, notFound("no deck open")
+	}
+	if p.Index < 0 || p.Index >= len(s.pkg.Notes) {
+		return nil, notFound("no card %d", p.Index)
+	}
+	return s.card(p.Index), nil
+}
+
+// storedScene returns sc as the RPC API shows it.
+func storedScene(sc hmm.Scene) StoredScene {
+	st := StoredScene{
+		Key:       scene.Key(sc),
+		Character: sc.Character,
+		Pinyin:    sc.Pinyin,
+		Tone:      int(sc.Tone),
+		Keyword:   sc.Keyword,
+		ActorID:   sc.ActorID,
+		SetID:     sc.SetID,
+		PropIDs:   sc.PropIDs,
+		Script:    sc.Script,
+		Prompt:    sc.ImagePrompt,
+		Status:    string(sc.Status),
+	}
+	if st.Status == "" {
+		st.Status = string(hmm.SceneApproved)
+	}
+	if sc.Image != "" {
+		st.ImageURL = "/scene-image/" + url.PathEscape(st.Key)
+	}
+	return st
+}
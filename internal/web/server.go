@@ -0,0 +1,287 @@
+// Package web serves a local web page mirroring the Lookup and Browse
+// views of the TUI, for those who prefer a browser over a terminal.
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/f3rmion/hmm/internal/analyze"
+	"github.com/f3rmion/hmm/internal/anki"
+	"github.com/f3rmion/hmm/internal/decomp"
+	"github.com/f3rmion/hmm/internal/htmlutil"
+	"github.com/f3rmion/hmm/internal/prompt"
+	"github.com/f3rmion/hmm/internal/scene"
+)
+
+//go:embed static
+var static embed.FS
+
+// maxCards is how many cards one request for the card list returns.
+const maxCards = 200
+
+// Server answers the page's requests from the user's dictionary, config,
+// scene store, and optionally an open deck.
+type Server struct {
+	analyzer  *analyze.Analyzer
+	generator *prompt.Generator
+	scenes    *scene.Store
+	pkg       *anki.Package
+	field     string // Deck field holding the Chinese text
+}
+
+// NewServer creates a server. scenes and pkg may be nil; without a deck the
+// page only offers Lookup.
+func NewServer(dict *decomp.Dictionary, gen *prompt.Generator, scenes *scene.Store, pkg *anki.Package, field string) *Server {
+	return &Server{
+		analyzer:  analyze.New(dict, gen),
+		generator: gen,
+		scenes:    scenes,
+		pkg:       pkg,
+		field:     field,
+	}
+}
+
+// Handler returns the handler for the page, its assets, its API, and the
+// JSON-RPC API at /rpc.
+func (s *Server) Handler() http.Handler {
+	assets, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(assets))
+	mux.HandleFunc("GET /api/lookup", s.handleLookup)
+	mux.HandleFunc("GET /api/deck", s.handleDeck)
+	mux.HandleFunc("GET /api/cards", s.handleCards)
+	mux.HandleFunc("GET /api/cards/{index}", s.handleCard)
+	mux.HandleFunc("GET /scene-image/{key}", s.handleSceneImage)
+	mux.HandleFunc("GET /media/{name}", s.handleMedia)
+	mux.HandleFunc("POST /rpc", s.handleRPC)
+	return mux
+}
+
+// Character is the breakdown of one character, as the page shows it.
+type Character struct {
+	Character  string      `json:"character"`
+	Pinyin     string      `json:"pinyin"`
+	Meaning    string      `json:"meaning,omitempty"`
+	Keyword    string      `json:"keyword,omitempty"`
+	Decomp     string      `json:"decomposition,omitempty"`
+	Etymology  string      `json:"etymology,omitempty"`
+	Tone       int         `json:"tone"`
+	ActorID    string      `json:"actor_id"`
+	ActorName  string      `json:"actor_name,omitempty"`
+	SetID      string      `json:"set_id"`
+	SetName    string      `json:"set_name,omitempty"`
+	ToneRoom   string      `json:"tone_room"`
+	Components []Component `json:"components,omitempty"`
+	Prompt     string      `json:"prompt,omitempty"`
+	Scene      *Scene      `json:"scene,omitempty"`
+}
+
+// Component is a component of a character and its prop, if configured.
+type Component struct {
+	Component string `json:"component"`
+	Prop      string `json:"prop,omitempty"`
+}
+
+// Scene is the approved scene of a character.
+type Scene struct {
+	Script   string `json:"script,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// Card is a deck card, with the breakdown of its characters when it is
+// the one asked for.
+type Card struct {
+	Index      int         `json:"index"`
+	Text       string      `json:"text"`
+	Fields     []Field     `json:"fields,omitempty"`
+	Images     []string    `json:"images,omitempty"`
+	Characters []Character `json:"characters,omitempty"`
+}
+
+// Field is a note field with its HTML stripped.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s *Server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.analyze(r.URL.Query().Get("q")))
+}
+
+func (s *Server) handleDeck(w http.ResponseWriter, r *http.Request) {
+	if s.pkg == nil {
+		writeJSON(w, map[string]any{"open": false})
+		return
+	}
+	writeJSON(w, map[string]any{
+		"open":  true,
+		"name":  filepath.Base(s.pkg.Path()),
+		"field": s.field,
+		"cards": len(s.pkg.Notes),
+	})
+}
+
+func (s *Server) handleCards(w http.ResponseWriter, r *http.Request) {
+	if s.pkg == nil {
+		http.Error(w, "no deck open", http.StatusNotFound)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	cards := []Card{}
+	for i, note := range s.pkg.Notes {
+		text := htmlutil.Text(s.pkg.GetFieldValue(note, s.field))
+		if query != "" && !s.noteMatches(note, query) {
+			continue
+		}
+		cards = append(cards, Card{Index: i, Text: text})
+		if len(cards) == maxCards {
+			break
+		}
+	}
+	writeJSON(w, cards)
+}
+
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	i, err := strconv.Atoi(r.PathValue("index"))
+	if s.pkg == nil || err != nil || i < 0 || i >= len(s.pkg.Notes) {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, s.card(i))
+}
+
+// card returns the i-th card of the open deck with its fields, images,
+// and the breakdown of its characters.
+func (s *Server) card(i int) Card {
+	note := s.pkg.Notes[i]
+	card := Card{Index: i, Text: htmlutil.Text(s.pkg.GetFieldValue(note, s.field))}
+	names := s.pkg.GetFieldNames(note)
+	for j, value := range note.Fields {
+		if j < len(names) {
+			card.Fields = append(card.Fields, Field{Name: names[j], Value: htmlutil.TextWithRuby(value)})
+		}
+		for _, m := range imgTag.FindAllStringSubmatch(value, -1) {
+			if s.pkg.HasMedia(m[1]) {
+				card.Images = append(card.Images, "/media/"+url.PathEscape(m[1]))
+			}
+		}
+	}
+	card.Characters = s.analyze(card.Text)
+	return card
+}
+
+// handleSceneImage serves the image of a scene. Only files named by a
+// scene are served, relative paths resolved against the store.
+func (s *Server) handleSceneImage(w http.ResponseWriter, r *http.Request) {
+	if s.scenes == nil {
+		http.NotFound(w, r)
+		return
+	}
+	sc := s.scenes.Get(r.PathValue("key"))
+	if sc == nil || sc.Image == "" {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, s.scenes.ImagePath(*sc))
+}
+
+// handleMedia serves a media file of the open deck.
+func (s *Server) handleMedia(w http.ResponseWriter, r *http.Request) {
+	if s.pkg == nil {
+		http.NotFound(w, r)
+		return
+	}
+	path, ok := s.pkg.Media(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// analyze breaks down every character of text that has a reading.
+func (s *Server) analyze(text string) []Character {
+	chars := []Character{}
+	for _, char := range analyze.UniqueChineseChars(text) {
+		if c := s.analyzeChar(char); c != nil {
+			chars = append(chars, *c)
+		}
+	}
+	return chars
+}
+
+func (s *Server) analyzeChar(char string) *Character {
+	r := s.analyzer.Character(char)
+	if r == nil {
+		return nil
+	}
+
+	c := &Character{
+		Character: char,
+		Pinyin:    r.Pinyin,
+		Meaning:   r.Meaning,
+		Keyword:   r.Keyword,
+		Decomp:    r.Decomp,
+		Etymology: r.Etymology,
+		Tone:      int(r.Tone),
+		ActorID:   r.ActorID,
+		ActorName: r.ActorName,
+		SetID:     r.SetID,
+		SetName:   r.SetName,
+		ToneRoom:  r.ToneRoom,
+	}
+
+	for _, comp := range r.Components {
+		component := Component{Component: comp}
+		if p := s.generator.GetProp(comp); p != nil {
+			component.Prop = p.Name
+		}
+		c.Components = append(c.Components, component)
+	}
+
+	if p, err := s.analyzer.Prompt(*r); err == nil {
+		c.Prompt = p
+	}
+
+	if s.scenes != nil {
+		if sc := s.scenes.Approved(char); sc != nil {
+			c.Scene = &Scene{Script: sc.Script, Prompt: sc.ImagePrompt}
+			if sc.Image != "" {
+				c.Scene.ImageURL = "/scene-image/" + url.PathEscape(scene.Key(*sc))
+			}
+		}
+	}
+
+	return c
+}
+
+// noteMatches reports whether any field of note contains query.
+func (s *Server) noteMatches(note *anki.Note, query string) bool {
+	query = strings.ToLower(query)
+	for _, value := range note.Fields {
+		if strings.Contains(strings.ToLower(htmlutil.Text(value)), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// imgTag matches the images Anki puts in fields.
+var imgTag = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,173 @@
+// The page talks to the hmm web server's JSON API; see internal/web/server.go.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs)) {
+    if (k === "onclick") node.onclick = v;
+    else node.setAttribute(k, v);
+  }
+  for (const child of children) {
+    if (child == null) continue;
+    node.append(child);
+  }
+  return node;
+}
+
+async function getJSON(url) {
+  const res = await fetch(url);
+  if (!res.ok) throw new Error(res.statusText);
+  return res.json();
+}
+
+// Views
+
+function showView(name) {
+  for (const button of document.querySelectorAll("nav button")) {
+    button.classList.toggle("active", button.dataset.view === name);
+  }
+  $("lookup").hidden = name !== "lookup";
+  $("browse").hidden = name !== "browse";
+}
+
+for (const button of document.querySelectorAll("nav button")) {
+  button.onclick = () => showView(button.dataset.view);
+}
+
+// Character breakdown, shared by Lookup and Browse
+
+function renderTabs(tabs, detail, chars) {
+  tabs.replaceChildren();
+  detail.replaceChildren();
+  if (chars.length === 0) {
+    detail.append(el("p", { class: "muted" }, "No characters with a reading."));
+    return;
+  }
+
+  const buttons = chars.map((c, i) =>
+    el("button", { onclick: () => select(i) }, c.character, el("small", {}, c.pinyin)));
+  tabs.append(...buttons);
+
+  function select(i) {
+    buttons.forEach((b, j) => b.classList.toggle("active", i === j));
+    detail.replaceChildren(renderCharacter(chars[i]));
+  }
+  select(0);
+}
+
+function renderCharacter(c) {
+  const node = el("div", {},
+    el("div", { class: "char-head" },
+      el("div", { class: "hanzi" }, c.character),
+      el("div", {},
+        el("div", { class: "pinyin" }, c.pinyin),
+        el("div", { class: "muted" }, c.meaning || ""))));
+
+  const facts = el("dl", {});
+  const fact = (name, value) => {
+    if (value) facts.append(el("dt", {}, name), el("dd", {}, value));
+  };
+  fact("RTH keyword", c.keyword);
+  fact("Actor", named(c.actor_id, c.actor_name));
+  fact("Set", named(c.set_id, c.set_name));
+  fact("Room", c.tone_room);
+  fact("Decomposition", c.decomposition);
+  fact("Etymology", c.etymology);
+  node.append(facts);
+
+  if (c.components && c.components.length > 0) {
+    const list = el("dl", {});
+    for (const comp of c.components) {
+      list.append(
+        el("dt", {}, el("span", { class: "component", title: "Look up " + comp.component,
+          onclick: () => lookup(comp.component) }, comp.component)),
+        el("dd", {}, comp.prop || el("span", { class: "muted" }, "no prop")));
+    }
+    node.append(el("h2", {}, "Components"), list);
+  }
+
+  if (c.scene) {
+    node.append(el("h2", {}, "Scene"));
+    if (c.scene.image_url) {
+      node.append(el("img", { class: "preview", src: c.scene.image_url, alt: "Scene for " + c.character }));
+    }
+    if (c.scene.script) node.append(el("pre", {}, c.scene.script));
+  }
+
+  if (c.prompt) node.append(el("h2", {}, "Prompt"), el("pre", {}, c.prompt));
+  return node;
+}
+
+function named(id, name) {
+  if (!id) return "";
+  return name ? `${name} (${id})` : id;
+}
+
+// Lookup
+
+async function lookup(text) {
+  showView("lookup");
+  $("lookup-input").value = text;
+  try {
+    renderTabs($("lookup-tabs"), $("lookup-detail"), await getJSON("/api/lookup?q=" + encodeURIComponent(text)));
+  } catch (err) {
+    $("lookup-detail").replaceChildren(el("p", { class: "muted" }, "Lookup failed: " + err.message));
+  }
+}
+
+$("lookup-form").onsubmit = (e) => {
+  e.preventDefault();
+  const text = $("lookup-input").value.trim();
+  if (text) lookup(text);
+};
+
+// Browse
+
+let searchTimer;
+
+async function loadCards(query) {
+  const cards = await getJSON("/api/cards?q=" + encodeURIComponent(query));
+  const list = $("card-list");
+  list.replaceChildren(...cards.map((card) => {
+    const item = el("li", {}, card.text || "(empty)");
+    item.onclick = () => {
+      for (const li of list.children) li.classList.remove("active");
+      item.classList.add("active");
+      openCard(card.index);
+    };
+    return item;
+  }));
+}
+
+async function openCard(index) {
+  const card = await getJSON("/api/cards/" + index);
+  const head = $("card-head");
+  head.replaceChildren();
+  for (const src of card.images || []) {
+    head.append(el("img", { class: "preview", src, alt: "" }));
+  }
+  const fields = el("dl", {});
+  for (const f of card.fields || []) {
+    if (f.value) fields.append(el("dt", {}, f.name), el("dd", {}, f.value));
+  }
+  head.append(fields);
+  renderTabs($("card-tabs"), $("card-detail"), card.characters || []);
+}
+
+$("card-search").oninput = (e) => {
+  clearTimeout(searchTimer);
+  searchTimer = setTimeout(() => loadCards(e.target.value.trim()), 200);
+};
+
+async function init() {
+  const deck = await getJSON("/api/deck");
+  if (!deck.open) return;
+  document.querySelector('nav button[data-view="browse"]').hidden = false;
+  $("deck-name").textContent = `${deck.name} · ${deck.cards} cards · field ${deck.field}`;
+  await loadCards("");
+  showView("browse");
+}
+
+init();
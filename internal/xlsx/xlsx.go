@@ -0,0 +1,225 @@
+// Package xlsx writes Excel workbooks of plain text tables, so spreadsheet
+// users get one cell per value without the quoting troubles of CSV. Only
+// what a table export needs is written: sheets of text cells with a bold,
+// frozen header row.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSheetName is the longest sheet name Excel accepts.
+const maxSheetName = 31
+
+// Sheet is a table: a header row and the rows under it.
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+// Workbook is a list of sheets.
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// AddSheet adds a sheet. Its name is made valid and unique for Excel.
+func (wb *Workbook) AddSheet(name string, header []string, rows [][]string) {
+	wb.Sheets = append(wb.Sheets, Sheet{Name: wb.sheetName(name), Header: header, Rows: rows})
+}
+
+// sheetName returns name without the characters Excel forbids, cut to
+// its length limit, and numbered if another sheet has it already.
+func (wb *Workbook) sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Sheet"
+	}
+	name = truncate(name, maxSheetName)
+
+	base := name
+	for n := 2; wb.hasSheet(name); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		name = truncate(base, maxSheetName-len(suffix)) + suffix
+	}
+	return name
+}
+
+func (wb *Workbook) hasSheet(name string) bool {
+	for _, s := range wb.Sheets {
+		if strings.EqualFold(s.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate cuts s to at most n runes.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// Write writes the workbook as an .xlsx file to w.
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.Sheets) == 0 {
+		wb.AddSheet("Sheet", nil, nil)
+	}
+
+	z := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for i, s := range wb.Sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheet(s)})
+	}
+
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles has two cell formats: 0 plain, 1 bold for the header.
+const styles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+func (wb *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range wb.Sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.Name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.Sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// worksheet returns the XML of a sheet, with the header row bold and
+// frozen so it stays in view while scrolling.
+func worksheet(s Sheet) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(s.Header) > 0 {
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+		b.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+		b.WriteString(`<selection pane="bottomLeft" activeCell="A2" sqref="A2"/>`)
+		b.WriteString(`</sheetView></sheetViews>`)
+	}
+	b.WriteString(`<sheetData>`)
+
+	row := 1
+	if len(s.Header) > 0 {
+		writeRow(&b, row, s.Header, 1)
+		row++
+	}
+	for _, r := range s.Rows {
+		writeRow(&b, row, r, 0)
+		row++
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// writeRow writes the cells of a row as inline strings with style.
+func writeRow(b *strings.Builder, row int, cells []string, style int) {
+	fmt.Fprintf(b, `<row r="%d">`, row)
+	for i, c := range cells {
+		if c == "" {
+			continue
+		}
+		fmt.Fprintf(b, `<c r="%s%d" t="inlineStr"`, column(i), row)
+		if style != 0 {
+			fmt.Fprintf(b, ` s="%d"`, style)
+		}
+		fmt.Fprintf(b, `><is><t xml:space="preserve">%s</t></is></c>`, escape(c))
+	}
+	b.WriteString(`</row>`)
+}
+
+// column returns the letters of the i-th column, from 0: A, B, ..., AA.
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// escape escapes s for XML text and attributes, replacing characters XML
+// cannot hold.
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}